@@ -0,0 +1,949 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminCommands lists the commands that may only be used by administrators.
+var AdminCommands = map[string]bool{
+	"hide":         true,
+	"reveal":       true,
+	"purge":        true,
+	"rename":       true,
+	"shortdesc":    true,
+	"connectivity": true,
+	"force":        true,
+	"snoop":        true,
+	"@teleport":    true,
+	"@spawn":       true,
+	"@role":        true,
+	"@ignored":     true,
+	"motd":         true,
+	"@reports":     true,
+	"@renamechar":  true,
+	"@transfer":    true,
+	"reload":       true,
+	"loglevel":     true,
+	"auditlog":     true,
+	"@channel":     true,
+}
+
+// BuilderCommands lists the commands that may be used by builders as well as administrators.
+var BuilderCommands = map[string]bool{
+	"@create":     true,
+	"@dig":        true,
+	"@describe":   true,
+	"@roomcreate": true,
+	"@roomedit":   true,
+	"@exitlink":   true,
+	"@exitremove": true,
+	"@ambience":   true,
+}
+
+// Player roles, in increasing order of permission.
+const (
+	RolePlayer  = "player"
+	RoleBuilder = "builder"
+	RoleAdmin   = "admin"
+)
+
+// roleLevels maps each role to its permission level.
+var roleLevels = map[string]int{
+	RolePlayer:  0,
+	RoleBuilder: 1,
+	RoleAdmin:   2,
+}
+
+// BuilderHelp is appended to the help output for builders and administrators.
+var BuilderHelp = "\n\rBuilder Commands:" +
+	"\n\r@create item <prototype> - Create an item from a prototype name or ID in this room" +
+	"\n\r@dig <direction> <roomID> - Link this room to a room, creating it if it does not exist" +
+	"\n\r@describe <text> - Replace the description of this room" +
+	"\n\r@roomcreate [title] - Create a new room in this area" +
+	"\n\r@roomedit <title|desc|area> <text> - Change the title, description or area of this room" +
+	"\n\r@exitlink <direction> <roomID> - Add or retarget a one-way exit from this room" +
+	"\n\r@exitremove <direction> - Remove an exit from this room" +
+	"\n\r@ambience <list|add <text>|remove <number>|on|off> - Edit the ambient messages of this room"
+
+// AdminHelp is appended to the help output for administrators.
+var AdminHelp = "\n\rAdmin Commands:" +
+	"\n\rhide <direction> - Make an exit in this room hidden" +
+	"\n\rreveal <direction> - Make an exit in this room visible" +
+	"\n\rpurge [item] - Destroy all items, or one item, lying in this room" +
+	"\n\rrename <item> <new name> - Rename an item you hold or that is in this room" +
+	"\n\rshortdesc <item> <text|clear> - Set the short description shown for an item in room listings" +
+	"\n\rconnectivity - Report rooms unreachable from the start room and one-way exits" +
+	"\n\rforce <character> <command> - Run a command as another online character" +
+	"\n\rsnoop <character|off> - Watch everything another online character sees" +
+	"\n\r@teleport <roomID|character> - Move yourself to a room or to another character" +
+	"\n\r@teleport <character> <roomID> - Move another online character to a room" +
+	"\n\r@spawn npc <npcID> - Spawn an NPC in this room" +
+	"\n\r@role <character> <player|builder|admin> - Set the role of an online character's player" +
+	"\n\r@ignored - List characters ignored by many players" +
+	"\n\rmotd <list|add <message>|deactivate <id>> - Manage the messages of the day shown at login" +
+	"\n\r@reports [all|resolve <id>] - List open bug, typo and idea reports, or resolve one" +
+	"\n\r@renamechar <character> <new name> - Rename a logged out character" +
+	"\n\r@transfer <character> <player> - Move a logged out character to another player's account" +
+	"\n\rreload - Reread the configuration and reload archetypes and prototypes" +
+	"\n\rloglevel [subsystem] [debug|info|warn|error|default] - Show or change the log level of the server or a subsystem" +
+	"\n\rauditlog [filter] [count] - Show recent admin commands, password changes, deletions, item spawns and failed logins" +
+	"\n\r@channel <name> - Open a new chat channel until the server restarts"
+
+// PermissionLevel returns the player's permission level. Players listed as administrators
+// in the configuration are always administrators, whatever their stored role.
+func (p *Player) PermissionLevel() int {
+	if p == nil {
+		return roleLevels[RolePlayer]
+	}
+
+	if p.Server != nil {
+		for _, admin := range p.Server.Settings().Server.Admins {
+			if strings.EqualFold(admin, p.PlayerID) {
+				return roleLevels[RoleAdmin]
+			}
+		}
+	}
+
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+	return roleLevels[p.Role]
+}
+
+// IsAdmin checks if the player is an administrator.
+func (p *Player) IsAdmin() bool {
+	return p.PermissionLevel() >= roleLevels[RoleAdmin]
+}
+
+// IsBuilder checks if the player may use the builder commands.
+func (p *Player) IsBuilder() bool {
+	return p.PermissionLevel() >= roleLevels[RoleBuilder]
+}
+
+// CanUseCommand reports whether the player's role permits the command.
+func (p *Player) CanUseCommand(verb string) bool {
+	if AdminCommands[verb] {
+		return p.IsAdmin()
+	}
+	if BuilderCommands[verb] {
+		return p.IsBuilder()
+	}
+	return true
+}
+
+// setExitVisibility changes the visibility of an exit in the character's room and saves the room.
+func setExitVisibility(character *Character, tokens []string, visible bool) bool {
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: %s <direction>\n\r", strings.ToLower(tokens[0]))
+		return false
+	}
+
+	direction := strings.ToLower(tokens[1])
+	room := character.Room
+
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	if exists {
+		exit.Visible = visible
+		exit.LastEdited = time.Now()
+		room.LastEdited = time.Now()
+	}
+	room.Mutex.Unlock()
+
+	if !exists {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no exit %s here.\n\r", direction)
+		return false
+	}
+
+	Logger.Info("Admin changed exit visibility", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "direction", direction, "visible", visible)
+
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving room after changing exit visibility", "room_id", room.RoomID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe exit was changed but could not be saved.\n\r"
+		return false
+	}
+
+	state := "hidden"
+	if visible {
+		state = "visible"
+	}
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThe exit %s is now %s.\n\r", direction, state)
+	return false
+}
+
+func ExecuteHideCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is hiding an exit", "playerName", character.Player.PlayerID)
+
+	return setExitVisibility(character, tokens, false)
+}
+
+func ExecuteRevealCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is revealing an exit", "playerName", character.Player.PlayerID)
+
+	return setExitVisibility(character, tokens, true)
+}
+
+func ExecutePurgeCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is purging items from the room", "playerName", character.Player.PlayerID)
+
+	room := character.Room
+	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
+
+	room.Mutex.Lock()
+	targets := make([]*Item, 0, len(room.Items))
+	for _, item := range room.Items {
+		if item == nil {
+			continue
+		}
+		if itemName == "" || strings.Contains(strings.ToLower(item.Name), itemName) {
+			targets = append(targets, item)
+			if itemName != "" {
+				break
+			}
+		}
+	}
+	room.Mutex.Unlock()
+
+	if len(targets) == 0 {
+		if itemName == "" {
+			character.Player.ToPlayer <- "\n\rThere is nothing here to purge.\n\r"
+		} else {
+			character.Player.ToPlayer <- "\n\rYou don't see that item here.\n\r"
+		}
+		return false
+	}
+
+	destroyed := 0
+	for _, item := range targets {
+		if err := character.Server.DestroyItem(item); err != nil {
+			Logger.Error("Error purging item", "itemName", item.Name, "itemID", item.ID, "room_id", room.RoomID, "error", err)
+			continue
+		}
+		destroyed++
+	}
+
+	Logger.Info("Admin purged items from room", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "destroyed", destroyed)
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rPurged %d item(s).\n\r", destroyed)
+	SendRoomMessage(room, "\n\rA surge of energy cleanses the room.\n\r")
+	return false
+}
+
+// Limits for builder supplied item text.
+const (
+	MaxItemNameLength      = 40
+	MaxItemShortDescLength = 80
+)
+
+// findItemNearby looks for an item in the character's inventory and then in their room.
+func findItemNearby(character *Character, itemName string) *Item {
+	if item := character.FindInInventory(itemName); item != nil {
+		return item
+	}
+
+	character.Room.Mutex.Lock()
+	defer character.Room.Mutex.Unlock()
+
+	lowercaseName := strings.ToLower(itemName)
+	for _, item := range character.Room.Items {
+		if item != nil && strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func ExecuteRenameCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is renaming an item", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: rename <item> <new name>\n\r"
+		return false
+	}
+
+	item := findItemNearby(character, tokens[1])
+	if item == nil {
+		character.Player.ToPlayer <- "\n\rYou don't see that item here.\n\r"
+		return false
+	}
+
+	newName, err := SanitizeText(strings.Join(tokens[2:], " "), MaxItemNameLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid name: %v\n\r", err)
+		return false
+	}
+
+	item.Mutex.Lock()
+	oldName := item.Name
+	item.Name = newName
+	item.LastEdited = time.Now()
+	item.Mutex.Unlock()
+
+	if err := character.Server.Database.WriteItem(character.Context(), item); err != nil {
+		Logger.Error("Error saving renamed item", "itemID", item.ID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe item was renamed but could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Admin renamed item", "playerName", character.Player.PlayerID, "itemID", item.ID, "oldName", oldName, "newName", newName)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is now known as %s.\n\r", oldName, newName)
+	return false
+}
+
+func ExecuteShortDescCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is setting an item short description", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: shortdesc <item> <text|clear>\n\r"
+		return false
+	}
+
+	item := findItemNearby(character, tokens[1])
+	if item == nil {
+		character.Player.ToPlayer <- "\n\rYou don't see that item here.\n\r"
+		return false
+	}
+
+	shortDesc := ""
+	if !(len(tokens) == 3 && strings.EqualFold(tokens[2], "clear")) {
+		var err error
+		shortDesc, err = SanitizeText(strings.Join(tokens[2:], " "), MaxItemShortDescLength)
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid short description: %v\n\r", err)
+			return false
+		}
+	}
+
+	item.Mutex.Lock()
+	item.ShortDesc = shortDesc
+	item.LastEdited = time.Now()
+	item.Mutex.Unlock()
+
+	if err := character.Server.Database.WriteItem(character.Context(), item); err != nil {
+		Logger.Error("Error saving item short description", "itemID", item.ID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe short description was changed but could not be saved.\n\r"
+		return false
+	}
+
+	if shortDesc == "" {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rCleared the short description of %s.\n\r", item.Name)
+	} else {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s will now appear as: %s\n\r", item.Name, shortDesc)
+	}
+	return false
+}
+
+// formatRoomIDs joins room IDs for display.
+func formatRoomIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func ExecuteConnectivityCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is checking world connectivity", "playerName", character.Player.PlayerID)
+
+	report, err := character.Server.CheckConnectivity(character.Server.Settings().Game.StartRoom)
+	if err != nil {
+		Logger.Error("Error checking connectivity", "error", err)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUnable to check connectivity: %v\n\r", err)
+		return false
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\rConnectivity from room %d: %d of %d rooms reachable.\n\r", report.StartRoom, report.Reachable, report.TotalRooms))
+
+	if len(report.Unreachable) > 0 {
+		output.WriteString(fmt.Sprintf("Unreachable (%d): %s\n\r", len(report.Unreachable), formatRoomIDs(report.Unreachable)))
+	}
+	if len(report.DeadEnds) > 0 {
+		output.WriteString(fmt.Sprintf("Dead ends (%d): %s\n\r", len(report.DeadEnds), formatRoomIDs(report.DeadEnds)))
+	}
+	if len(report.OneWay) > 0 {
+		output.WriteString(fmt.Sprintf("One-way exits (%d):\n\r", len(report.OneWay)))
+		for _, exit := range report.OneWay {
+			output.WriteString(fmt.Sprintf("  %s\n\r", exit))
+		}
+	}
+	if len(report.Broken) > 0 {
+		output.WriteString(fmt.Sprintf("Broken exits (%d):\n\r", len(report.Broken)))
+		for _, exit := range report.Broken {
+			output.WriteString(fmt.Sprintf("  %s\n\r", exit))
+		}
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
+
+// force dispatches through CommandHandlers, so it is registered here to avoid an initialization cycle.
+func init() {
+	CommandHandlers["force"] = ExecuteForceCommand
+}
+
+// MaxForcedCommands is how many forced commands may wait for a character's input loop.
+const MaxForcedCommands = 5
+
+// ForcedCommand is a command an admin has forced a character to run. It has already been
+// validated, so it is run as given without expanding the character's aliases.
+type ForcedCommand struct {
+	Verb   string
+	Tokens []string
+	By     string // name of the admin who forced the command
+}
+
+// unforceableCommands may not be forced because they end the target's session.
+var unforceableCommands = map[string]bool{
+	"quit":  true,
+	"quit!": true,
+	"q!":    true,
+}
+
+func ExecuteForceCommand(character *Character, tokens []string) bool {
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: force <character> <command>\n\r"
+		return false
+	}
+
+	target := character.Server.FindOnlineCharacter(tokens[1])
+	if target == nil || !target.IsActive() {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not online.\n\r", tokens[1])
+		return false
+	}
+
+	verb, forcedTokens, err := ValidateCommand(character.Server, strings.Join(tokens[2:], " "))
+	if err != nil {
+		character.Player.ToPlayer <- "\n\rThat is not a valid command.\n\r"
+		return false
+	}
+
+	// Never lend a privileged or sensitive command to another character
+	if AdminCommands[verb] || BuilderCommands[verb] || SensitiveCommands[verb] || unforceableCommands[verb] {
+		Logger.Warn("Refused to force privileged command", "playerName", character.Player.PlayerID, "target", target.Name, "verb", verb)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s command cannot be forced.\n\r", verb)
+		return false
+	}
+
+	// The command runs on the target's own input loop, in turn with what they type
+	select {
+	case target.Player.Forced <- ForcedCommand{Verb: verb, Tokens: forcedTokens, By: character.Name}:
+	default:
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s already has commands waiting to be forced.\n\r", target.Name)
+		return false
+	}
+
+	Logger.Info("Admin is forcing a command", "playerName", character.Player.PlayerID, "target", target.Name, "command", RedactCommand(verb, forcedTokens))
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou force %s to %s.\n\r", target.Name, verb)
+	return false
+}
+
+// Limits for builder supplied room text.
+const (
+	MaxRoomTitleLength       = 80
+	MaxRoomAreaLength        = 40
+	MaxRoomDescriptionLength = 1000
+)
+
+// Placeholder text for rooms created in game before a builder describes them.
+const (
+	UnfinishedRoomTitle       = "An Unfinished Room"
+	UnfinishedRoomDescription = "Bare earth waits to be shaped."
+)
+
+// OppositeDirections maps each direction to the one leading back.
+var OppositeDirections = map[string]string{
+	"north":     "south",
+	"south":     "north",
+	"east":      "west",
+	"west":      "east",
+	"northeast": "southwest",
+	"southwest": "northeast",
+	"northwest": "southeast",
+	"southeast": "northwest",
+	"up":        "down",
+	"down":      "up",
+	"in":        "out",
+	"out":       "in",
+}
+
+// parseDirection checks that a builder named one of the OppositeDirections, so exits are only
+// made in directions players can walk.
+func parseDirection(character *Character, token string) (string, bool) {
+	direction := strings.ToLower(token)
+	if _, known := OppositeDirections[direction]; known {
+		return direction, true
+	}
+
+	directions := make([]string, 0, len(OppositeDirections))
+	for name := range OppositeDirections {
+		directions = append(directions, name)
+	}
+	sort.Strings(directions)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not a direction. Use one of: %s\n\r", token, strings.Join(directions, ", "))
+	return "", false
+}
+
+// parseRoomID looks up a loaded room by its numeric ID.
+func parseRoomID(server *Server, token string) (*Room, bool) {
+	roomID, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return server.Room(roomID)
+}
+
+func ExecuteTeleportCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is teleporting", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 || len(tokens) > 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @teleport <roomID|character> or @teleport <character> <roomID>\n\r"
+		return false
+	}
+
+	traveller := character
+	destination := tokens[1]
+
+	if len(tokens) == 3 {
+		traveller = character.Server.FindOnlineCharacter(tokens[1])
+		if traveller == nil || !traveller.IsActive() {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not online.\n\r", tokens[1])
+			return false
+		}
+		destination = tokens[2]
+	}
+
+	room, found := parseRoomID(character.Server, destination)
+	if !found && len(tokens) == 2 {
+		if target := character.Server.FindOnlineCharacter(destination); target != nil && target.IsActive() {
+			target.Mutex.Lock()
+			room = target.Room
+			target.Mutex.Unlock()
+			found = room != nil
+		}
+	}
+
+	if !found {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no room or online character called %s.\n\r", destination)
+		return false
+	}
+
+	traveller.ExitCombat()
+	traveller.Teleport(room)
+
+	Logger.Info("Admin teleported character", "playerName", character.Player.PlayerID, "target", traveller.Name, "room_id", room.RoomID)
+
+	if traveller != character {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou teleport %s to room %d.\n\r", traveller.Name, room.RoomID)
+	}
+	return false
+}
+
+// findPrototype looks up a prototype by its ID or, failing that, by name.
+func findPrototype(server *Server, name string) *Prototype {
+	if id, err := uuid.Parse(name); err == nil {
+		if prototype, exists := server.Prototypes[id]; exists {
+			return prototype
+		}
+	}
+
+	for _, prototype := range server.Prototypes {
+		if strings.EqualFold(prototype.Name, name) {
+			return prototype
+		}
+	}
+	return nil
+}
+
+func ExecuteCreateCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is creating an item", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 || !strings.EqualFold(tokens[1], "item") {
+		character.Player.ToPlayer <- "\n\rUsage: @create item <prototype>\n\r"
+		return false
+	}
+
+	prototypeName := strings.Join(tokens[2:], " ")
+	prototype := findPrototype(character.Server, prototypeName)
+	if prototype == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no prototype called %s.\n\r", prototypeName)
+		return false
+	}
+
+	item, err := character.Server.CreateItemFromPrototype(prototype.ID)
+	if err != nil {
+		Logger.Error("Error creating item from prototype", "prototypeID", prototype.ID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe item could not be created.\n\r"
+		return false
+	}
+
+	character.Room.AddItem(item)
+
+	Logger.Info("Builder created item", "playerName", character.Player.PlayerID, "itemID", item.ID, "room_id", character.Room.RoomID)
+	character.Server.AuditPlayer(character.Context(), character.Player, AuditItemSpawn, item.ID.String(),
+		fmt.Sprintf("%s from prototype %s in room %d", item.Name, prototype.ID, character.Room.RoomID))
+	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s appears out of thin air.\n\r", item.Name))
+	return false
+}
+
+func ExecuteSpawnCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is spawning an NPC", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 3 || !strings.EqualFold(tokens[1], "npc") {
+		character.Player.ToPlayer <- "\n\rUsage: @spawn npc <npcID>\n\r"
+		return false
+	}
+
+	server := character.Server
+	template, exists := server.NPCTemplates[tokens[2]]
+	if !exists {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no NPC called %s.\n\r", tokens[2])
+		return false
+	}
+
+	room := character.Room
+	npc := NewNPC(template)
+	npc.Room = room
+	npc.HomeArea = room.Area
+
+	room.Mutex.Lock()
+	if room.NPCs == nil {
+		room.NPCs = make(map[uuid.UUID]*NPC)
+	}
+	room.NPCs[npc.ID] = npc
+	room.Mutex.Unlock()
+
+	server.Mutex.Lock()
+	if server.NPCs == nil {
+		server.NPCs = make(map[uuid.UUID]*NPC)
+	}
+	server.NPCs[npc.ID] = npc
+	server.Mutex.Unlock()
+
+	Logger.Info("Admin spawned NPC", "playerName", character.Player.PlayerID, "npcID", template.NPCID, "room_id", room.RoomID)
+	SendRoomMessage(room, fmt.Sprintf("\n\r%s appears in a flash of light.\n\r", npc.Name))
+	return false
+}
+
+// linkRooms adds an exit from one room to another unless the direction is already taken.
+func linkRooms(from *Room, direction string, to *Room) bool {
+	from.Mutex.Lock()
+	_, taken := from.Exits[direction]
+	from.Mutex.Unlock()
+
+	if taken {
+		return false
+	}
+
+	from.AddExit(&Exit{
+		ExitID:     uuid.New(),
+		Direction:  direction,
+		TargetRoom: to,
+		Visible:    true,
+		LastEdited: time.Now(),
+	})
+	return true
+}
+
+func ExecuteDigCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is digging an exit", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @dig <direction> <roomID>\n\r"
+		return false
+	}
+
+	direction, valid := parseDirection(character, tokens[1])
+	if !valid {
+		return false
+	}
+	roomID, err := strconv.ParseInt(tokens[2], 10, 64)
+	if err != nil {
+		character.Player.ToPlayer <- "\n\rThe room ID must be a number.\n\r"
+		return false
+	}
+
+	server := character.Server
+	room := character.Room
+
+	// Dig into an existing room, or create it in the current area
+	server.Mutex.Lock()
+	target, exists := server.Rooms[roomID]
+	if !exists || target == nil {
+		target = NewRoom(roomID, room.Area, UnfinishedRoomTitle, UnfinishedRoomDescription)
+		server.Rooms[roomID] = target
+	}
+	server.Mutex.Unlock()
+
+	if !linkRooms(room, direction, target) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is already an exit %s here.\n\r", direction)
+		return false
+	}
+
+	// Link back when the opposite direction is known and still free
+	if opposite, known := OppositeDirections[direction]; known && target != room {
+		linkRooms(target, opposite, room)
+	}
+
+	for _, changed := range []*Room{room, target} {
+		if err := server.Database.WriteRoom(server.Context, changed); err != nil {
+			Logger.Error("Error saving room after digging", "room_id", changed.RoomID, "error", err)
+			character.Player.ToPlayer <- "\n\rThe exit was dug but could not be saved.\n\r"
+			return false
+		}
+	}
+
+	Logger.Info("Builder dug exit", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "direction", direction, "target_room_id", roomID, "created", !exists)
+
+	if exists {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou open a way %s to room %d.\n\r", direction, roomID)
+	} else {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou dig %s into the new room %d.\n\r", direction, roomID)
+	}
+	return false
+}
+
+// roomFields maps the fields accepted by @roomedit to their length limits.
+var roomFields = map[string]int{
+	"title": MaxRoomTitleLength,
+	"desc":  MaxRoomDescriptionLength,
+	"area":  MaxRoomAreaLength,
+}
+
+// editRoom changes a text field of the character's room and saves the room.
+func editRoom(character *Character, field, text string) bool {
+	value, err := SanitizeText(text, roomFields[field])
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid %s: %v\n\r", field, err)
+		return false
+	}
+
+	room := character.Room
+	room.Mutex.Lock()
+	switch field {
+	case "title":
+		room.Title = value
+	case "desc":
+		room.Description = value
+	case "area":
+		room.Area = value
+	}
+	room.LastEdited = time.Now()
+	room.Mutex.Unlock()
+
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving edited room", "room_id", room.RoomID, "field", field, "error", err)
+		character.Player.ToPlayer <- "\n\rThe room was changed but could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Builder edited room", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "field", field)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThe room %s has been updated.\n\r", field)
+	return false
+}
+
+func ExecuteDescribeCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is describing a room", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: @describe <text>\n\r"
+		return false
+	}
+
+	return editRoom(character, "desc", strings.Join(tokens[1:], " "))
+}
+
+func ExecuteRoomEditCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is editing a room", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @roomedit <title|desc|area> <text>\n\r"
+		return false
+	}
+
+	field := strings.ToLower(tokens[1])
+	if _, valid := roomFields[field]; !valid {
+		character.Player.ToPlayer <- "\n\rYou can edit the title, desc or area of a room.\n\r"
+		return false
+	}
+
+	return editRoom(character, field, strings.Join(tokens[2:], " "))
+}
+
+func ExecuteRoomCreateCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is creating a room", "playerName", character.Player.PlayerID)
+
+	title := UnfinishedRoomTitle
+	if len(tokens) > 1 {
+		var err error
+		title, err = SanitizeText(strings.Join(tokens[1:], " "), MaxRoomTitleLength)
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid title: %v\n\r", err)
+			return false
+		}
+	}
+
+	server := character.Server
+	room := server.AddNewRoom(character.Room.Area, title, UnfinishedRoomDescription)
+
+	if err := server.Database.WriteRoom(server.Context, room); err != nil {
+		Logger.Error("Error saving new room", "room_id", room.RoomID, "error", err)
+		server.Mutex.Lock()
+		delete(server.Rooms, room.RoomID)
+		server.Mutex.Unlock()
+		character.Player.ToPlayer <- "\n\rThe room could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Builder created room", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "area", room.Area)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rCreated room %d, %s. Use @exitlink or @dig to connect it.\n\r", room.RoomID, room.Title)
+	return false
+}
+
+func ExecuteExitLinkCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is linking an exit", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @exitlink <direction> <roomID>\n\r"
+		return false
+	}
+
+	direction, valid := parseDirection(character, tokens[1])
+	if !valid {
+		return false
+	}
+	target, found := parseRoomID(character.Server, tokens[2])
+	if !found {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no room %s.\n\r", tokens[2])
+		return false
+	}
+
+	// Retarget an existing exit so its ID, and any hidden state, is kept
+	room := character.Room
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	if exists {
+		exit.TargetRoom = target
+		exit.LastEdited = time.Now()
+		room.LastEdited = time.Now()
+	}
+	room.Mutex.Unlock()
+
+	if !exists {
+		linkRooms(room, direction, target)
+	}
+
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving room after linking exit", "room_id", room.RoomID, "direction", direction, "error", err)
+		character.Player.ToPlayer <- "\n\rThe exit was linked but could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Builder linked exit", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "direction", direction, "target_room_id", target.RoomID)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThe exit %s now leads to room %d, %s.\n\r", direction, target.RoomID, target.Title)
+	return false
+}
+
+func ExecuteExitRemoveCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is removing an exit", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: @exitremove <direction>\n\r"
+		return false
+	}
+
+	direction := strings.ToLower(tokens[1])
+	room := character.Room
+
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	if exists {
+		delete(room.Exits, direction)
+		room.LastEdited = time.Now()
+	}
+	room.Mutex.Unlock()
+
+	if !exists {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no exit %s here.\n\r", direction)
+		return false
+	}
+
+	// Save the room first so it never refers to a deleted exit
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving room after removing exit", "room_id", room.RoomID, "direction", direction, "error", err)
+		character.Player.ToPlayer <- "\n\rThe exit was removed but could not be saved.\n\r"
+		return false
+	}
+
+	if err := character.Server.Database.DeleteExit(character.Context(), exit); err != nil {
+		Logger.Error("Error deleting removed exit", "room_id", room.RoomID, "exit_id", exit.ExitID, "error", err)
+	}
+
+	Logger.Info("Builder removed exit", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "direction", direction)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThe exit %s has been removed.\n\r", direction)
+	return false
+}
+
+func ExecuteRoleCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is changing a role", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @role <character> <player|builder|admin>\n\r"
+		return false
+	}
+
+	role := strings.ToLower(tokens[2])
+	if _, valid := roleLevels[role]; !valid {
+		character.Player.ToPlayer <- "\n\rThe role must be player, builder or admin.\n\r"
+		return false
+	}
+
+	target := character.Server.FindOnlineCharacter(tokens[1])
+	if target == nil || !target.IsActive() {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not online.\n\r", tokens[1])
+		return false
+	}
+
+	player := target.Player
+	player.Mutex.Lock()
+	player.Role = role
+	player.Mutex.Unlock()
+
+	if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+		Logger.Error("Error saving player role", "playerName", player.PlayerID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe role was changed but could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Admin changed player role", "playerName", character.Player.PlayerID, "target", player.PlayerID, "role", role)
+
+	player.Send(fmt.Sprintf("\n\rYou have been given the %s role.\n\r", role))
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s now has the %s role.\n\r", target.Name, role)
+	return false
+}
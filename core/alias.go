@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Limits on the aliases a player may define.
+const (
+	MaxAliases        = 50
+	MaxAliasLength    = 200
+	MaxAliasExpansion = 5 // aliases may refer to other aliases this many levels deep
+)
+
+// reservedAliasNames may not be redefined, so a player can always repair their aliases.
+var reservedAliasNames = map[string]bool{
+	"alias":   true,
+	"unalias": true,
+	"aliases": true,
+}
+
+// ExpandAlias replaces a leading alias in the input with its command, keeping any further
+// arguments. Aliases that refer to other aliases are expanded in turn, and an error is
+// returned if the expansion loops or nests too deeply.
+func (p *Player) ExpandAlias(input string) (string, error) {
+	seen := make(map[string]bool)
+
+	for depth := 0; ; depth++ {
+		tokens := strings.Fields(input)
+		if len(tokens) == 0 {
+			return input, nil
+		}
+
+		name := strings.ToLower(tokens[0])
+
+		p.Mutex.Lock()
+		command, exists := p.Aliases[name]
+		p.Mutex.Unlock()
+
+		if !exists {
+			return input, nil
+		}
+		if seen[name] {
+			return "", fmt.Errorf("\n\rThe alias %s refers back to itself.\n\r", name)
+		}
+		if depth >= MaxAliasExpansion {
+			return "", fmt.Errorf("\n\rThe alias %s nests too deeply.\n\r", name)
+		}
+		seen[name] = true
+
+		input = strings.Join(append([]string{command}, tokens[1:]...), " ")
+	}
+}
+
+// listAliases formats the player's aliases in alphabetical order.
+func (p *Player) listAliases() string {
+	p.Mutex.Lock()
+	names := make([]string, 0, len(p.Aliases))
+	for name := range p.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var output strings.Builder
+	output.WriteString("\n\rYour aliases:\n\r")
+	for _, name := range names {
+		output.WriteString(fmt.Sprintf("  %s = %s\n\r", name, p.Aliases[name]))
+	}
+	p.Mutex.Unlock()
+
+	if len(names) == 0 {
+		return "\n\rYou have no aliases.\n\rUsage: alias <name> <command>\n\r"
+	}
+	return output.String()
+}
+
+func ExecuteAliasCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is defining an alias", "playerName", character.Player.PlayerID)
+
+	player := character.Player
+
+	if len(tokens) < 3 {
+		player.ToPlayer <- player.listAliases()
+		return false
+	}
+
+	name := strings.ToLower(tokens[1])
+	if reservedAliasNames[name] {
+		player.ToPlayer <- fmt.Sprintf("\n\r%s cannot be used as an alias.\n\r", name)
+		return false
+	}
+
+	command, err := SanitizeText(strings.Join(tokens[2:], " "), MaxAliasLength)
+	if err != nil {
+		player.ToPlayer <- fmt.Sprintf("\n\rInvalid alias: %v\n\r", err)
+		return false
+	}
+
+	player.Mutex.Lock()
+	if player.Aliases == nil {
+		player.Aliases = make(map[string]string)
+	}
+	_, replacing := player.Aliases[name]
+	if !replacing && len(player.Aliases) >= MaxAliases {
+		player.Mutex.Unlock()
+		player.ToPlayer <- fmt.Sprintf("\n\rYou cannot have more than %d aliases.\n\r", MaxAliases)
+		return false
+	}
+	player.Aliases[name] = command
+	player.Mutex.Unlock()
+
+	if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+		Logger.Error("Error saving player aliases", "playerName", player.PlayerID, "error", err)
+	}
+
+	player.ToPlayer <- fmt.Sprintf("\n\r%s now runs: %s\n\r", name, command)
+	return false
+}
+
+func ExecuteUnaliasCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is removing an alias", "playerName", character.Player.PlayerID)
+
+	player := character.Player
+
+	if len(tokens) != 2 {
+		player.ToPlayer <- "\n\rUsage: unalias <name>\n\r"
+		return false
+	}
+
+	name := strings.ToLower(tokens[1])
+
+	player.Mutex.Lock()
+	_, exists := player.Aliases[name]
+	delete(player.Aliases, name)
+	player.Mutex.Unlock()
+
+	if !exists {
+		player.ToPlayer <- fmt.Sprintf("\n\rYou have no alias called %s.\n\r", name)
+		return false
+	}
+
+	if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+		Logger.Error("Error saving player aliases", "playerName", player.PlayerID, "error", err)
+	}
+
+	player.ToPlayer <- fmt.Sprintf("\n\rRemoved the alias %s.\n\r", name)
+	return false
+}
+
+func ExecuteAliasesCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is listing aliases", "playerName", character.Player.PlayerID)
+
+	character.Player.ToPlayer <- character.Player.listAliases()
+	return false
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AmbienceSweepInterval is how often rooms are checked for ambient messages that are due.
+const AmbienceSweepInterval = 5 * time.Second
+
+// Ambient messages play at a random interval between these bounds while someone is in the room.
+const (
+	AmbienceMinInterval = 45 * time.Second
+	AmbienceMaxInterval = 3 * time.Minute
+)
+
+// MaxAmbienceLength limits the length of a single ambient message.
+const MaxAmbienceLength = 200
+
+// AmbienceLoop plays ambient room messages until the server context is cancelled.
+func AmbienceLoop(s *Server) {
+	Logger.Info("Starting ambience loop")
+
+	ticker := time.NewTicker(AmbienceSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.PlayAmbience()
+		case <-s.Context.Done():
+			Logger.Info("Stopping ambience loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// nextAmbience returns a random time for a room's next ambient message.
+func nextAmbience() time.Time {
+	spread := int64(AmbienceMaxInterval - AmbienceMinInterval)
+	return time.Now().Add(AmbienceMinInterval + time.Duration(rand.Int63n(spread)))
+}
+
+// PlayAmbience sends an ambient message to each occupied room whose timer has run out.
+func (s *Server) PlayAmbience() {
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0, len(s.Rooms))
+	for _, room := range s.Rooms {
+		rooms = append(rooms, room)
+	}
+	s.Mutex.Unlock()
+
+	now := time.Now()
+	for _, room := range rooms {
+		room.Mutex.Lock()
+		message := ""
+		if room.AmbienceEnabled && len(room.Ambience) > 0 && len(room.Characters) > 0 {
+			// A room that has just become occupied waits a full interval before its first message
+			if !room.NextAmbience.IsZero() && now.After(room.NextAmbience) {
+				message = room.Ambience[rand.Intn(len(room.Ambience))]
+				room.NextAmbience = nextAmbience()
+			} else if room.NextAmbience.IsZero() {
+				room.NextAmbience = nextAmbience()
+			}
+		} else {
+			room.NextAmbience = time.Time{}
+		}
+		room.Mutex.Unlock()
+
+		if message != "" {
+			SendRoomMessage(room, fmt.Sprintf("\n\r%s\n\r", message))
+		}
+	}
+}
+
+func ExecuteAmbienceCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Builder is editing room ambience", "playerName", character.Player.PlayerID)
+
+	usage := "\n\rUsage: @ambience <list|add <text>|remove <number>|on|off>\n\r"
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- usage
+		return false
+	}
+
+	room := character.Room
+	action := strings.ToLower(tokens[1])
+
+	var result string
+	switch action {
+	case "list":
+		room.Mutex.Lock()
+		var output strings.Builder
+		state := "off"
+		if room.AmbienceEnabled {
+			state = "on"
+		}
+		output.WriteString(fmt.Sprintf("\n\rAmbience is %s in this room.\n\r", state))
+		for i, message := range room.Ambience {
+			output.WriteString(fmt.Sprintf("%d. %s\n\r", i+1, message))
+		}
+		if len(room.Ambience) == 0 {
+			output.WriteString("There are no ambient messages.\n\r")
+		}
+		room.Mutex.Unlock()
+		character.Player.ToPlayer <- output.String()
+		return false
+
+	case "add":
+		if len(tokens) < 3 {
+			character.Player.ToPlayer <- "\n\rUsage: @ambience add <text>\n\r"
+			return false
+		}
+		message, err := SanitizeText(strings.Join(tokens[2:], " "), MaxAmbienceLength)
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid message: %v\n\r", err)
+			return false
+		}
+		room.Mutex.Lock()
+		room.Ambience = append(room.Ambience, message)
+		result = fmt.Sprintf("Added ambient message %d.", len(room.Ambience))
+		room.Mutex.Unlock()
+
+	case "remove":
+		index, err := strconv.Atoi(strings.Join(tokens[2:], ""))
+		room.Mutex.Lock()
+		if err != nil || index < 1 || index > len(room.Ambience) {
+			room.Mutex.Unlock()
+			character.Player.ToPlayer <- "\n\rUsage: @ambience remove <number>, as shown by @ambience list\n\r"
+			return false
+		}
+		room.Ambience = append(room.Ambience[:index-1], room.Ambience[index:]...)
+		result = fmt.Sprintf("Removed ambient message %d.", index)
+		room.Mutex.Unlock()
+
+	case "on", "off":
+		room.Mutex.Lock()
+		room.AmbienceEnabled = action == "on"
+		room.NextAmbience = time.Time{}
+		room.Mutex.Unlock()
+		result = fmt.Sprintf("Ambience is now %s in this room.", action)
+
+	default:
+		character.Player.ToPlayer <- usage
+		return false
+	}
+
+	room.Mutex.Lock()
+	room.LastEdited = time.Now()
+	room.Mutex.Unlock()
+
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving room ambience", "room_id", room.RoomID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe ambience was changed but could not be saved.\n\r"
+		return false
+	}
+
+	Logger.Info("Builder edited room ambience", "playerName", character.Player.PlayerID, "room_id", room.RoomID, "action", action)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", result)
+	return false
+}
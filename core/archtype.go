@@ -6,30 +6,41 @@ import (
 
 // DisplayArchetypes logs the loaded archetypes for debugging purposes.
 func DisplayArchetypes(s *Server) {
-	for key, archtype := range s.ArcheTypes {
+	for key, archtype := range s.Archetypes() {
 		Logger.Debug("Archetype", "name", key, "description", archtype.Description)
 	}
 }
 
-// LoadArchetypes retrieves all archetypes from the DynamoDB table and stores them in the Server's ArcheTypes map.
-func (s *Server) LoadArchetypes() error {
+// Archetypes returns the loaded archetypes. LoadArchetypes replaces the map rather than changing
+// it, so callers may read the returned map without holding a lock, but must not modify it.
+func (s *Server) Archetypes() map[string]*Archetype {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
+	return s.ArcheTypes
+}
+
+// LoadArchetypes retrieves all archetypes from the DynamoDB table and stores them in the Server's ArcheTypes map.
+// The table is read into a new map, which replaces the old one only once it is complete.
+func (s *Server) LoadArchetypes() error {
 	var archetypes []Archetype
-	err := s.Database.Scan("archetypes", &archetypes)
+	err := s.Database.Scan(s.Context, "archetypes", &archetypes)
 	if err != nil {
 		return fmt.Errorf("error scanning archetypes table: %w", err)
 	}
 
-	s.ArcheTypes = make(map[string]*Archetype)
+	loaded := make(map[string]*Archetype, len(archetypes))
 	for _, archetype := range archetypes {
 		// Create a copy of the archetype to store in the map
 		archetypeCopy := archetype
-		s.ArcheTypes[archetype.ArchetypeName] = &archetypeCopy
+		loaded[archetype.ArchetypeName] = &archetypeCopy
 		Logger.Debug("Loaded archetype", "name", archetype.ArchetypeName, "description", archetype.Description)
 	}
 
+	s.Mutex.Lock()
+	s.ArcheTypes = loaded
+	s.Mutex.Unlock()
+
 	return nil
 }
 
@@ -39,7 +50,7 @@ func (s *Server) StoreArchetypes() error {
 	defer s.Mutex.Unlock()
 
 	for _, archetype := range s.ArcheTypes {
-		err := s.Database.Put("archetypes", *archetype)
+		err := s.Database.Put(s.Context, "archetypes", *archetype)
 		if err != nil {
 			return fmt.Errorf("error storing archetype %s: %w", archetype.ArchetypeName, err)
 		}
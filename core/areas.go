@@ -0,0 +1,124 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AreaData is a zone of rooms sharing a level range, PvP rule, respawn rules and climate,
+// as stored in DynamoDB. Rooms belong to the area whose AreaID matches their Area.
+type AreaData struct {
+	AreaID      string   `json:"AreaID" dynamodbav:"AreaID"`
+	Name        string   `json:"Name" dynamodbav:"Name"`
+	MinLevel    int      `json:"MinLevel,omitempty" dynamodbav:"MinLevel,omitempty"`
+	MaxLevel    int      `json:"MaxLevel,omitempty" dynamodbav:"MaxLevel,omitempty"`
+	PvP         bool     `json:"PvP,omitempty" dynamodbav:"PvP,omitempty"`                 // whether characters may attack each other
+	RespawnRoom int64    `json:"RespawnRoom,omitempty" dynamodbav:"RespawnRoom,omitempty"` // where characters who die here return, 0 uses RespawnRooms
+	SpawnScale  float64  `json:"SpawnScale,omitempty" dynamodbav:"SpawnScale,omitempty"`   // multiplies spawn point respawn intervals, 0 leaves them unchanged
+	Weather     []string `json:"Weather,omitempty" dynamodbav:"Weather,omitempty"`         // conditions the weather moves between, DefaultClimate when empty
+}
+
+// LoadAreas retrieves all area definitions from the DynamoDB table.
+func (s *Server) LoadAreas() error {
+	var areas []AreaData
+	err := s.Database.Scan(s.Context, "areas", &areas)
+	if err != nil {
+		return fmt.Errorf("error scanning areas table: %w", err)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Areas = make(map[string]*AreaData, len(areas))
+	for _, area := range areas {
+		areaCopy := area
+		s.Areas[area.AreaID] = &areaCopy
+		Logger.Debug("Loaded area", "areaID", area.AreaID, "name", area.Name)
+	}
+
+	return nil
+}
+
+// AreaOf returns the area a room belongs to, or nil if its Area has no definition.
+func (s *Server) AreaOf(room *Room) *AreaData {
+	if room == nil {
+		return nil
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.Areas[room.Area]
+}
+
+// AreaName returns the display name of a room's area, falling back to the room's Area text.
+func (s *Server) AreaName(room *Room) string {
+	if area := s.AreaOf(room); area != nil && area.Name != "" {
+		return area.Name
+	}
+	if room == nil || room.Area == "" {
+		return "Unknown"
+	}
+	return room.Area
+}
+
+// InArea reports whether a room belongs to the area with the given ID or name, ignoring case.
+func (s *Server) InArea(room *Room, name string) bool {
+	if room == nil {
+		return false
+	}
+	return strings.EqualFold(room.Area, name) || strings.EqualFold(s.AreaName(room), name)
+}
+
+// AllowsPvP reports whether characters may attack each other in the room. Rooms in areas
+// without a definition keep the old behaviour and allow it.
+func (s *Server) AllowsPvP(room *Room) bool {
+	area := s.AreaOf(room)
+	return area == nil || area.PvP
+}
+
+// LevelRange describes the levels the area is meant for, or an empty string if it has no range.
+func (a *AreaData) LevelRange() string {
+	switch {
+	case a.MinLevel > 0 && a.MaxLevel > 0:
+		return fmt.Sprintf("levels %d-%d", a.MinLevel, a.MaxLevel)
+	case a.MinLevel > 0:
+		return fmt.Sprintf("level %d and up", a.MinLevel)
+	case a.MaxLevel > 0:
+		return fmt.Sprintf("levels up to %d", a.MaxLevel)
+	}
+	return ""
+}
+
+// spawnInterval returns how long a spawn point waits between spawns, scaled by its room's area.
+func (s *Server) spawnInterval(room *Room, point *SpawnPoint) time.Duration {
+	interval := time.Duration(point.RespawnSeconds) * time.Second
+	if area := s.AreaOf(room); area != nil && area.SpawnScale > 0 {
+		interval = time.Duration(float64(interval) * area.SpawnScale)
+	}
+	return interval
+}
+
+// enterArea tells the character about the area they have just walked into, warning them if it is
+// above their level or allows PvP. The caller must hold c.Mutex.
+func (c *Character) enterArea(oldRoom, newRoom *Room) {
+	if oldRoom != nil && oldRoom.Area == newRoom.Area {
+		return
+	}
+
+	area := c.Server.AreaOf(newRoom)
+	if area == nil {
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("\n\rYou enter %s.\n\r", area.Name))
+	if area.MinLevel > 0 && c.Level < area.MinLevel {
+		message.WriteString(fmt.Sprintf("This area is meant for %s. Tread carefully.\n\r", area.LevelRange()))
+	}
+	if area.PvP {
+		message.WriteString("Other adventurers may attack you here.\n\r")
+	}
+	c.Player.ToPlayer <- message.String()
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditCommand         = "command"          // an admin or builder command was run
+	AuditPasswordChange  = "password_change"  // a player changed, or failed to change, their password
+	AuditCharacterDelete = "character_delete" // a player deleted one of their characters
+	AuditItemSpawn       = "item_spawn"       // an item was created out of nothing
+	AuditLoginFailure    = "login_failure"    // a password was rejected
+	AuditSSHKey          = "ssh_key"          // a player added or removed an SSH public key
+	AuditAccountCreate   = "account_create"   // a new account was registered
+)
+
+// auditLogDefault is how many entries the auditlog command shows when no count is given.
+const auditLogDefault = 20
+
+// AuditRecord is one sensitive action, as stored in the audit table.
+type AuditRecord struct {
+	AuditID  string `json:"AuditID" dynamodbav:"AuditID"`
+	Time     int64  `json:"Time" dynamodbav:"Time"` // unix nanoseconds
+	Action   string `json:"Action" dynamodbav:"Action"`
+	Actor    string `json:"Actor" dynamodbav:"Actor"` // player ID of whoever acted
+	Target   string `json:"Target,omitempty" dynamodbav:"Target,omitempty"`
+	Detail   string `json:"Detail,omitempty" dynamodbav:"Detail,omitempty"`
+	SourceIP string `json:"SourceIP,omitempty" dynamodbav:"SourceIP,omitempty"`
+}
+
+// WriteAudit stores an audit record.
+func (kp *KeyPair) WriteAudit(ctx context.Context, record *AuditRecord) error {
+	if err := kp.Put(ctx, "audit", record); err != nil {
+		return fmt.Errorf("error storing audit record: %w", err)
+	}
+	return nil
+}
+
+// LoadAudit returns every audit record, oldest first.
+func (kp *KeyPair) LoadAudit(ctx context.Context) ([]*AuditRecord, error) {
+	var records []*AuditRecord
+	if err := kp.Scan(ctx, "audit", &records); err != nil {
+		return nil, fmt.Errorf("error scanning audit table: %w", err)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Time < records[j].Time
+	})
+	return records, nil
+}
+
+// SourceIP returns the address part of a remote address such as "203.0.113.7:52144".
+func SourceIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// Audit records a sensitive action in the audit table and the log. A failure to store the record
+// is logged rather than returned, so that auditing never stops the action itself.
+func (s *Server) Audit(ctx context.Context, action, actor, target, detail, sourceIP string) {
+	record := &AuditRecord{
+		AuditID:  uuid.New().String(),
+		Time:     time.Now().UnixNano(),
+		Action:   action,
+		Actor:    actor,
+		Target:   target,
+		Detail:   detail,
+		SourceIP: sourceIP,
+	}
+
+	Logger.Info("Audit", "action", action, "actor", actor, "target", target, "detail", detail, "sourceIP", sourceIP)
+
+	if err := s.Database.WriteAudit(ctx, record); err != nil {
+		Logger.Error("Failed to store audit record", "action", action, "actor", actor, "error", err)
+	}
+}
+
+// AuditPlayer records an action taken by a player, with the address they connected from.
+func (s *Server) AuditPlayer(ctx context.Context, player *Player, action, target, detail string) {
+	s.Audit(ctx, action, player.PlayerID, target, detail, player.RemoteAddr)
+}
+
+func ExecuteAuditLogCommand(character *Character, tokens []string) bool {
+
+	usage := "\n\rUsage: auditlog [filter] [count]\n\r"
+
+	var filter string
+	count := auditLogDefault
+	for _, token := range tokens[1:] {
+		if n, err := strconv.Atoi(token); err == nil {
+			if n <= 0 {
+				character.Player.ToPlayer <- usage
+				return false
+			}
+			count = n
+			continue
+		}
+		if filter != "" {
+			character.Player.ToPlayer <- usage
+			return false
+		}
+		filter = strings.ToLower(token)
+	}
+
+	records, err := character.Server.Database.LoadAudit(character.Context())
+	if err != nil {
+		Logger.Error("Error loading audit log", "error", err)
+		character.Player.ToPlayer <- "\n\rThe audit log could not be read.\n\r"
+		return false
+	}
+
+	// Walk back from the newest record, keeping those the filter matches
+	var matched []*AuditRecord
+	for i := len(records) - 1; i >= 0 && len(matched) < count; i-- {
+		record := records[i]
+		if filter != "" &&
+			!strings.Contains(strings.ToLower(record.Action), filter) &&
+			!strings.Contains(strings.ToLower(record.Actor), filter) &&
+			!strings.Contains(strings.ToLower(record.Target), filter) &&
+			!strings.Contains(record.SourceIP, filter) {
+			continue
+		}
+		matched = append(matched, record)
+	}
+
+	if len(matched) == 0 {
+		character.Player.ToPlayer <- "\n\rNo audit entries found.\n\r"
+		return false
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\rMost recent audit entries (%d):\n\r", len(matched)))
+	for _, record := range matched {
+		output.WriteString(fmt.Sprintf("%s  %-16s %s", time.Unix(0, record.Time).Format("2006-01-02 15:04:05"), record.Action, record.Actor))
+		if record.Target != "" {
+			output.WriteString(fmt.Sprintf(" -> %s", record.Target))
+		}
+		if record.SourceIP != "" {
+			output.WriteString(fmt.Sprintf(" from %s", record.SourceIP))
+		}
+		if record.Detail != "" {
+			output.WriteString(fmt.Sprintf("\n\r    %s", record.Detail))
+		}
+		output.WriteString("\n\r")
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// Item metadata keys that make an item a bulletin board. BoardKey names the board the item shows,
+// so several items can share one board, and BoardPostRoleKey limits posting to a role such as "admin".
+const (
+	BoardKey         = "board"
+	BoardPostRoleKey = "board_post_role"
+)
+
+// Limits for bulletin board posts.
+const (
+	BoardPageSize      = 10
+	MaxPostTitleLength = 60
+	MaxPostBodyLength  = 1000
+)
+
+// PostData is a message on a bulletin board, as stored in DynamoDB.
+type PostData struct {
+	PostID  string `json:"PostID" dynamodbav:"PostID"`
+	BoardID string `json:"BoardID" dynamodbav:"BoardID"`
+	Author  string `json:"Author" dynamodbav:"Author"`
+	Title   string `json:"Title" dynamodbav:"Title"`
+	Body    string `json:"Body" dynamodbav:"Body"`
+	Posted  int64  `json:"Posted" dynamodbav:"Posted"` // unix time the post was made
+}
+
+// LoadPosts loads every bulletin board post from the database, oldest first on each board.
+func (s *Server) LoadPosts() error {
+	var posts []PostData
+	err := s.Database.Scan(s.Context, "posts", &posts)
+	if err != nil {
+		return fmt.Errorf("error scanning posts table: %w", err)
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Posted < posts[j].Posted
+	})
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Boards = make(map[string][]*PostData)
+	for i := range posts {
+		post := &posts[i]
+		s.Boards[post.BoardID] = append(s.Boards[post.BoardID], post)
+	}
+
+	Logger.Info("Loaded bulletin board posts", "posts", len(posts), "boards", len(s.Boards))
+	return nil
+}
+
+// DeletePost removes a post's record from the DynamoDB table.
+func (kp *KeyPair) DeletePost(ctx context.Context, post *PostData) error {
+	key := map[string]ddbtypes.AttributeValue{
+		"PostID": &ddbtypes.AttributeValueMemberS{Value: post.PostID},
+	}
+
+	if err := kp.Delete(ctx, "posts", key); err != nil {
+		return fmt.Errorf("error deleting post: %w", err)
+	}
+	return nil
+}
+
+// boardPosts returns a copy of the posts on a board, oldest first.
+func (s *Server) boardPosts(boardID string) []*PostData {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return append([]*PostData(nil), s.Boards[boardID]...)
+}
+
+// findBoard returns the first bulletin board lying in the room whose name contains the given text,
+// or any board when the text is empty.
+func findBoard(room *Room, name string) *Item {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	lowercaseName := strings.ToLower(name)
+	for _, item := range room.Items {
+		if item.Metadata[BoardKey] != "" && strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			return item
+		}
+	}
+	return nil
+}
+
+// canPost reports whether the character's player may post on the board.
+func canPost(character *Character, board *Item) bool {
+	role := board.Metadata[BoardPostRoleKey]
+	if role == "" {
+		return true
+	}
+	level, known := roleLevels[role]
+	return known && character.Player.PermissionLevel() >= level
+}
+
+// listBoard shows one page of a board's post titles, newest page last.
+func listBoard(character *Character, board *Item, page int) {
+	posts := character.Server.boardPosts(board.Metadata[BoardKey])
+	if len(posts) == 0 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is empty.\n\r", board.Name)
+		return
+	}
+
+	pages := (len(posts) + BoardPageSize - 1) / BoardPageSize
+	if page < 1 || page > pages {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s only has %d pages.\n\r", board.Name, pages)
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\r%s, page %d of %d:\n\r", character.Player.Colorize(ColorTitle, board.Name), page, pages))
+	for i := (page - 1) * BoardPageSize; i < len(posts) && i < page*BoardPageSize; i++ {
+		post := posts[i]
+		output.WriteString(fmt.Sprintf("%3d. %s (%s, %s)\n\r", i+1, character.Player.Filter(post.Title), post.Author, time.Unix(post.Posted, 0).Format("2006-01-02")))
+	}
+	output.WriteString("Use 'read <number>' to read a post.\n\r")
+	character.Player.ToPlayer <- output.String()
+}
+
+// postNumber returns the post a number refers to on the board, or an error explaining why there is none.
+func postNumber(character *Character, board *Item, token string) (*PostData, error) {
+	number, err := strconv.Atoi(token)
+	posts := character.Server.boardPosts(board.Metadata[BoardKey])
+	if err != nil || number < 1 || number > len(posts) {
+		return nil, fmt.Errorf("there is no post %s on %s", token, board.Name)
+	}
+	return posts[number-1], nil
+}
+
+func ExecuteReadCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is reading", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: read <board> [page] | read <number>\n\r"
+		return false
+	}
+
+	room := character.Room
+
+	// read <number> reads a post from the board in the room
+	if _, err := strconv.Atoi(tokens[1]); err == nil && len(tokens) == 2 {
+		if board := findBoard(room, ""); board != nil {
+			post, err := postNumber(character, board, tokens[1])
+			if err != nil {
+				character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+				return false
+			}
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\rBy %s on %s\n\r\n\r%s\n\r", character.Player.Colorize(ColorTitle, character.Player.Filter(post.Title)), post.Author, time.Unix(post.Posted, 0).Format("2006-01-02 15:04"), character.Player.Filter(post.Body))
+			return false
+		}
+	}
+
+	// read <board> [page] lists the posts on a board
+	name, page := strings.Join(tokens[1:], " "), 1
+	if len(tokens) > 2 {
+		if number, err := strconv.Atoi(tokens[len(tokens)-1]); err == nil {
+			name, page = strings.Join(tokens[1:len(tokens)-1], " "), number
+		}
+	}
+	if board := findBoard(room, name); board != nil {
+		listBoard(character, board, page)
+		return false
+	}
+
+	// Anything else may be an item that can be read
+	if !character.UseItemVerb(tokens) {
+		character.Player.ToPlayer <- "\n\rYou don't see that here.\n\r"
+	}
+	return false
+}
+
+func ExecutePostCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is posting to a board", "playerName", character.Player.PlayerID)
+
+	board := findBoard(character.Room, "")
+	if board == nil {
+		character.Player.ToPlayer <- "\n\rThere is no board here to post on.\n\r"
+		return false
+	}
+
+	title, body, found := strings.Cut(strings.Join(tokens[1:], " "), "|")
+	if len(tokens) < 2 || !found {
+		character.Player.ToPlayer <- "\n\rUsage: post <title> | <message>\n\r"
+		return false
+	}
+
+	if !canPost(character, board) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rOnly a %s may post on %s.\n\r", board.Metadata[BoardPostRoleKey], board.Name)
+		return false
+	}
+
+	title, err := SanitizeText(title, MaxPostTitleLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid title: %v\n\r", err)
+		return false
+	}
+	body, err = SanitizeText(body, MaxPostBodyLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid message: %v\n\r", err)
+		return false
+	}
+
+	post := &PostData{
+		PostID:  uuid.New().String(),
+		BoardID: board.Metadata[BoardKey],
+		Author:  character.Name,
+		Title:   title,
+		Body:    body,
+		Posted:  time.Now().Unix(),
+	}
+
+	if err := character.Server.Database.Put(character.Context(), "posts", post); err != nil {
+		Logger.Error("Error saving post", "boardID", post.BoardID, "error", err)
+		character.Player.ToPlayer <- "\n\rYour post could not be saved.\n\r"
+		return false
+	}
+
+	server := character.Server
+	server.Mutex.Lock()
+	if server.Boards == nil {
+		server.Boards = make(map[string][]*PostData)
+	}
+	server.Boards[post.BoardID] = append(server.Boards[post.BoardID], post)
+	number := len(server.Boards[post.BoardID])
+	server.Mutex.Unlock()
+
+	Logger.Info("Player posted to board", "playerName", character.Player.PlayerID, "boardID", post.BoardID, "postID", post.PostID)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou pin post %d to %s.\n\r", number, board.Name)
+	sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s pins a notice to %s.\n\r", character.Name, board.Name), character)
+	return false
+}
+
+// removePost takes a post off the board in the character's room. Posts may be removed by their author or an administrator.
+func removePost(character *Character, board *Item, token string) {
+	post, err := postNumber(character, board, token)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return
+	}
+
+	if !strings.EqualFold(post.Author, character.Name) && !character.Player.IsAdmin() {
+		character.Player.ToPlayer <- "\n\rYou can only remove your own posts.\n\r"
+		return
+	}
+
+	if err := character.Server.Database.DeletePost(character.Context(), post); err != nil {
+		Logger.Error("Error deleting post", "postID", post.PostID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe post could not be removed.\n\r"
+		return
+	}
+
+	server := character.Server
+	server.Mutex.Lock()
+	posts := server.Boards[post.BoardID]
+	for i, p := range posts {
+		if p == post {
+			server.Boards[post.BoardID] = append(posts[:i:i], posts[i+1:]...)
+			break
+		}
+	}
+	server.Mutex.Unlock()
+
+	Logger.Info("Player removed post", "playerName", character.Player.PlayerID, "boardID", post.BoardID, "postID", post.PostID)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou take \"%s\" down from %s.\n\r", post.Title, board.Name)
+}
@@ -0,0 +1,548 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBoltPath is the database file used by the bolt backend when none is configured.
+const DefaultBoltPath = "mud.db"
+
+// boltPageSize is the number of records handed to each ScanPages callback.
+const boltPageSize = 100
+
+// boltKeys lists the key attributes of each table, matching the DynamoDB key schemas.
+var boltKeys = map[string][]string{
+	"players":         {"PlayerID"},
+	"characters":      {"CharacterID"},
+	"rooms":           {"RoomID"},
+	"exits":           {"ExitID"},
+	"items":           {"ItemID"},
+	"prototypes":      {"PrototypeID"},
+	"archetypes":      {"ArchetypeName"},
+	"npcs":            {"NPCID"},
+	"quests":          {"QuestID"},
+	"posts":           {"PostID"},
+	"areas":           {"AreaID"},
+	"reports":         {"ReportID"},
+	"motd":            {"MotdID"},
+	"migrations":      {"MigrationID"},
+	"character_names": {"NameKey"},
+	"audit":           {"AuditID"},
+}
+
+// boltCondition matches one "Attribute = :value" clause of a key condition expression.
+var boltCondition = regexp.MustCompile(`^\s*(\w+)\s*=\s*(:\w+)\s*$`)
+
+// BoltStore is a DataStore kept in a local BoltDB file, so the server can run without DynamoDB.
+// Each table is a bucket, and each record is stored as DynamoDB-style attribute JSON.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltKeyPair opens, creating if needed, the BoltDB file at path.
+func NewBoltKeyPair(path string) (*KeyPair, error) {
+	if path == "" {
+		path = DefaultBoltPath
+	}
+
+	DatabaseLog.Info("Opening BoltDB database", "path", path)
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening bolt database %s: %w", path, err)
+	}
+
+	return &KeyPair{
+		DataStore: &BoltStore{db: db},
+		Instance:  uuid.New().String(),
+	}, nil
+}
+
+// Close closes the database file.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// Ping checks that the database file is still open.
+func (b *BoltStore) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+// Put stores an item, replacing any record with the same key.
+func (b *BoltStore) Put(ctx context.Context, tableName string, item interface{}) error {
+	return b.BatchPut(ctx, tableName, []interface{}{item})
+}
+
+// BatchPut stores many items in a single transaction.
+func (b *BoltStore) BatchPut(ctx context.Context, tableName string, items []interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(tableName))
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", tableName, err)
+		}
+
+		for _, item := range items {
+			av, err := marshalRecord(tableName, item)
+			if err != nil {
+				return err
+			}
+			if err := putRecord(bucket, tableName, av); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutVersioned stores an item whose Version attribute is one more than the version it replaces,
+// failing with ErrVersionConflict when the stored record is at any other version.
+func (b *BoltStore) PutVersioned(ctx context.Context, tableName string, item interface{}) error {
+	conflicts, err := b.BatchPutVersioned(ctx, tableName, []interface{}{item})
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w putting item into table %s", ErrVersionConflict, tableName)
+	}
+	return nil
+}
+
+// BatchPutVersioned stores many versioned items in a single transaction. Items that fail their
+// version check are skipped and their indexes returned.
+func (b *BoltStore) BatchPutVersioned(ctx context.Context, tableName string, items []interface{}) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var conflicts []int
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		conflicts = nil
+		bucket, err := tx.CreateBucketIfNotExists([]byte(tableName))
+		if err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", tableName, err)
+		}
+
+		for i, item := range items {
+			av, err := marshalRecord(tableName, item)
+			if err != nil {
+				return err
+			}
+			ok, err := versionMatches(bucket, tableName, av, av["Version"])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				conflicts = append(conflicts, i)
+				continue
+			}
+			if err := putRecord(bucket, tableName, av); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return conflicts, err
+}
+
+// Get retrieves the item with the given key.
+func (b *BoltStore) Get(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, item interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	recordKey, err := boltKey(tableName, key)
+	if err != nil {
+		return err
+	}
+
+	var av map[string]ddbtypes.AttributeValue
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tableName))
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get(recordKey)
+		if data == nil {
+			return nil
+		}
+		av, err = decodeRecord(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error getting item from table %s: %w", tableName, err)
+	}
+	if av == nil {
+		return fmt.Errorf("%w in table %s", ErrItemNotFound, tableName)
+	}
+
+	migrateRecords(tableName, []map[string]ddbtypes.AttributeValue{av})
+	if err := attributevalue.UnmarshalMap(av, item); err != nil {
+		return fmt.Errorf("error unmarshalling item: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the item with the given key. Deleting a missing item is not an error.
+func (b *BoltStore) Delete(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	recordKey, err := boltKey(tableName, key)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tableName))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete(recordKey)
+	})
+}
+
+// Update sets and removes attributes of an existing item, failing when the item does not exist.
+// When set includes a Version, the update is conditional on the stored version as in PutVersioned.
+func (b *BoltStore) Update(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, set map[string]ddbtypes.AttributeValue, remove []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	recordKey, err := boltKey(tableName, key)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tableName))
+		if bucket == nil {
+			return fmt.Errorf("item not found in table %s", tableName)
+		}
+		data := bucket.Get(recordKey)
+		if data == nil {
+			return fmt.Errorf("item not found in table %s", tableName)
+		}
+
+		av, err := decodeRecord(data)
+		if err != nil {
+			return err
+		}
+		if version, ok := set["Version"]; ok {
+			matches, err := versionMatches(bucket, tableName, av, version)
+			if err != nil {
+				return err
+			}
+			if !matches {
+				return fmt.Errorf("%w updating item in table %s", ErrVersionConflict, tableName)
+			}
+		}
+		for name, value := range set {
+			av[name] = value
+		}
+		for _, name := range remove {
+			delete(av, name)
+		}
+		return putRecord(bucket, tableName, av)
+	})
+}
+
+// Query returns the items matching every "Attribute = :value" clause of the key condition,
+// joined with AND. Any attribute can be matched, since the whole table is searched.
+func (b *BoltStore) Query(ctx context.Context, tableName string, keyConditionExpression string, expressionAttributeValues map[string]ddbtypes.AttributeValue, items interface{}) error {
+	conditions := make(map[string]ddbtypes.AttributeValue)
+	for _, clause := range strings.Split(keyConditionExpression, " AND ") {
+		match := boltCondition.FindStringSubmatch(clause)
+		if match == nil {
+			return fmt.Errorf("unsupported key condition %q", clause)
+		}
+		value, ok := expressionAttributeValues[match[2]]
+		if !ok {
+			return fmt.Errorf("missing value for %s", match[2])
+		}
+		conditions[match[1]] = value
+	}
+
+	var matched []map[string]ddbtypes.AttributeValue
+	err := b.ScanPages(ctx, tableName, func(page []map[string]ddbtypes.AttributeValue) error {
+		migrateRecords(tableName, page)
+		for _, av := range page {
+			if matchesConditions(av, conditions) {
+				matched = append(matched, av)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := attributevalue.UnmarshalListOfMaps(matched, items); err != nil {
+		return fmt.Errorf("error unmarshalling query results: %w", err)
+	}
+	return nil
+}
+
+// Scan reads every item in the table.
+func (b *BoltStore) Scan(ctx context.Context, tableName string, items interface{}) error {
+	var all []map[string]ddbtypes.AttributeValue
+	err := b.ScanPages(ctx, tableName, func(page []map[string]ddbtypes.AttributeValue) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	migrateRecords(tableName, all)
+	if err := attributevalue.UnmarshalListOfMaps(all, items); err != nil {
+		return fmt.Errorf("error unmarshalling scan results: %w", err)
+	}
+	return nil
+}
+
+// ScanPages passes the table to page boltPageSize records at a time, as stored and without the
+// migrations the other reads apply. The records are read in one transaction before the first
+// call, so page may write to the database.
+func (b *BoltStore) ScanPages(ctx context.Context, tableName string, page func([]map[string]ddbtypes.AttributeValue) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var all []map[string]ddbtypes.AttributeValue
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(tableName))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			av, err := decodeRecord(data)
+			if err != nil {
+				return err
+			}
+			all = append(all, av)
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error scanning table %s: %w", tableName, err)
+	}
+
+	for start := 0; start < len(all); start += boltPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + boltPageSize
+		if end > len(all) {
+			end = len(all)
+		}
+		if err := page(all[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParallelScan reads every item in the table. A local file gains nothing from segments, so
+// this is the same as Scan.
+func (b *BoltStore) ParallelScan(ctx context.Context, tableName string, segments int, items interface{}) error {
+	return b.Scan(ctx, tableName, items)
+}
+
+// boltKey builds the record key from the table's key attributes.
+func boltKey(tableName string, av map[string]ddbtypes.AttributeValue) ([]byte, error) {
+	names, ok := boltKeys[tableName]
+	if !ok {
+		return nil, fmt.Errorf("no key schema for table %s", tableName)
+	}
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		switch value := av[name].(type) {
+		case *ddbtypes.AttributeValueMemberS:
+			parts = append(parts, value.Value)
+		case *ddbtypes.AttributeValueMemberN:
+			parts = append(parts, value.Value)
+		default:
+			return nil, fmt.Errorf("table %s needs a string or number %s key", tableName, name)
+		}
+	}
+	return []byte(strings.Join(parts, "#")), nil
+}
+
+// versionMatches reports whether the stored copy of the record keyed by av is one version behind
+// next. A missing record, or one written before versioning, counts as version 0.
+func versionMatches(bucket *bolt.Bucket, tableName string, av map[string]ddbtypes.AttributeValue, next ddbtypes.AttributeValue) (bool, error) {
+	version, err := versionNumber(next)
+	if err != nil {
+		return false, err
+	}
+	recordKey, err := boltKey(tableName, av)
+	if err != nil {
+		return false, err
+	}
+
+	var stored int64
+	if data := bucket.Get(recordKey); data != nil {
+		record, err := decodeRecord(data)
+		if err != nil {
+			return false, err
+		}
+		if value, ok := record["Version"]; ok {
+			if stored, err = versionNumber(value); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if version <= 1 {
+		return stored == 0, nil
+	}
+	return stored == version-1, nil
+}
+
+// putRecord encodes av and stores it in bucket under its key.
+func putRecord(bucket *bolt.Bucket, tableName string, av map[string]ddbtypes.AttributeValue) error {
+	recordKey, err := boltKey(tableName, av)
+	if err != nil {
+		return err
+	}
+	data, err := encodeRecord(av)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(recordKey, data)
+}
+
+// matchesConditions reports whether av holds every attribute value in conditions.
+func matchesConditions(av map[string]ddbtypes.AttributeValue, conditions map[string]ddbtypes.AttributeValue) bool {
+	for name, want := range conditions {
+		have, ok := av[name]
+		if !ok || !reflect.DeepEqual(toBoltAttribute(have), toBoltAttribute(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// boltAttribute is the JSON form of a DynamoDB attribute value, as used by the DynamoDB API.
+type boltAttribute struct {
+	S    *string                   `json:"S,omitempty"`
+	N    *string                   `json:"N,omitempty"`
+	B    []byte                    `json:"B,omitempty"`
+	BOOL *bool                     `json:"BOOL,omitempty"`
+	NULL bool                      `json:"NULL,omitempty"`
+	L    *[]boltAttribute          `json:"L,omitempty"`
+	M    *map[string]boltAttribute `json:"M,omitempty"`
+	SS   *[]string                 `json:"SS,omitempty"`
+	NS   *[]string                 `json:"NS,omitempty"`
+	BS   *[][]byte                 `json:"BS,omitempty"`
+}
+
+func encodeRecord(av map[string]ddbtypes.AttributeValue) ([]byte, error) {
+	record := make(map[string]boltAttribute, len(av))
+	for name, value := range av {
+		record[name] = toBoltAttribute(value)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding record: %w", err)
+	}
+	return data, nil
+}
+
+func decodeRecord(data []byte) (map[string]ddbtypes.AttributeValue, error) {
+	var record map[string]boltAttribute
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("error decoding record: %w", err)
+	}
+	av := make(map[string]ddbtypes.AttributeValue, len(record))
+	for name, value := range record {
+		av[name] = fromBoltAttribute(value)
+	}
+	return av, nil
+}
+
+func toBoltAttribute(value ddbtypes.AttributeValue) boltAttribute {
+	switch v := value.(type) {
+	case *ddbtypes.AttributeValueMemberS:
+		return boltAttribute{S: &v.Value}
+	case *ddbtypes.AttributeValueMemberN:
+		return boltAttribute{N: &v.Value}
+	case *ddbtypes.AttributeValueMemberB:
+		return boltAttribute{B: v.Value}
+	case *ddbtypes.AttributeValueMemberBOOL:
+		return boltAttribute{BOOL: &v.Value}
+	case *ddbtypes.AttributeValueMemberL:
+		list := make([]boltAttribute, 0, len(v.Value))
+		for _, element := range v.Value {
+			list = append(list, toBoltAttribute(element))
+		}
+		return boltAttribute{L: &list}
+	case *ddbtypes.AttributeValueMemberM:
+		members := make(map[string]boltAttribute, len(v.Value))
+		for name, element := range v.Value {
+			members[name] = toBoltAttribute(element)
+		}
+		return boltAttribute{M: &members}
+	case *ddbtypes.AttributeValueMemberSS:
+		return boltAttribute{SS: &v.Value}
+	case *ddbtypes.AttributeValueMemberNS:
+		return boltAttribute{NS: &v.Value}
+	case *ddbtypes.AttributeValueMemberBS:
+		return boltAttribute{BS: &v.Value}
+	default:
+		return boltAttribute{NULL: true}
+	}
+}
+
+func fromBoltAttribute(value boltAttribute) ddbtypes.AttributeValue {
+	switch {
+	case value.S != nil:
+		return &ddbtypes.AttributeValueMemberS{Value: *value.S}
+	case value.N != nil:
+		return &ddbtypes.AttributeValueMemberN{Value: *value.N}
+	case value.B != nil:
+		return &ddbtypes.AttributeValueMemberB{Value: value.B}
+	case value.BOOL != nil:
+		return &ddbtypes.AttributeValueMemberBOOL{Value: *value.BOOL}
+	case value.L != nil:
+		list := make([]ddbtypes.AttributeValue, 0, len(*value.L))
+		for _, element := range *value.L {
+			list = append(list, fromBoltAttribute(element))
+		}
+		return &ddbtypes.AttributeValueMemberL{Value: list}
+	case value.M != nil:
+		members := make(map[string]ddbtypes.AttributeValue, len(*value.M))
+		for name, element := range *value.M {
+			members[name] = fromBoltAttribute(element)
+		}
+		return &ddbtypes.AttributeValueMemberM{Value: members}
+	case value.SS != nil:
+		return &ddbtypes.AttributeValueMemberSS{Value: *value.SS}
+	case value.NS != nil:
+		return &ddbtypes.AttributeValueMemberNS{Value: *value.NS}
+	case value.BS != nil:
+		return &ddbtypes.AttributeValueMemberBS{Value: *value.BS}
+	default:
+		return &ddbtypes.AttributeValueMemberNULL{Value: true}
+	}
+}
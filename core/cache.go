@@ -0,0 +1,164 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DefaultCacheTTL is how long cached records live when Storage.CacheSeconds is not set.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Cache is a read-through cache of database records with a time to live, keyed by table and
+// record ID. Records are held as attribute values, so every read unmarshals a fresh copy that
+// the caller is free to change. A nil Cache caches nothing.
+type Cache struct {
+	TTL       time.Duration
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+	stats     CacheStats
+	Mutex     sync.Mutex
+}
+
+type cacheEntry struct {
+	record  map[string]ddbtypes.AttributeValue
+	expires time.Time
+}
+
+// CacheStats counts cache activity since the server started.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"` // entries dropped because they expired
+	Size      int    `json:"size"`
+}
+
+// NewCache returns a cache whose entries expire ttl after they are stored.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		TTL:       ttl,
+		entries:   make(map[string]cacheEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func cacheKey(tableName, id string) string {
+	return tableName + "#" + id
+}
+
+// get returns the cached record, counting the lookup as a hit or a miss.
+func (c *Cache) get(tableName, id string) (map[string]ddbtypes.AttributeValue, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	key := cacheKey(tableName, id)
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		c.stats.Evictions++
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.stats.Hits++
+	return entry.record, true
+}
+
+// set stores a record, replacing any cached copy.
+func (c *Cache) set(tableName, id string, record map[string]ddbtypes.AttributeValue) {
+	if c == nil {
+		return
+	}
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	now := time.Now()
+	c.entries[cacheKey(tableName, id)] = cacheEntry{record: record, expires: now.Add(c.TTL)}
+
+	// Records that are never read again would otherwise stay until the server restarts
+	if now.Sub(c.lastSweep) > c.TTL {
+		c.sweep(now)
+	}
+}
+
+// invalidate drops the cached copy of a record.
+func (c *Cache) invalidate(tableName, id string) {
+	if c == nil {
+		return
+	}
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	delete(c.entries, cacheKey(tableName, id))
+}
+
+// sweep drops every expired entry; the caller must hold c.Mutex.
+func (c *Cache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			c.stats.Evictions++
+		}
+	}
+	c.lastSweep = now
+}
+
+// Stats returns the cache counters and current size.
+func (c *Cache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
+
+// getCached reads a record through the cache, loading it with Get and caching it on a miss.
+func (kp *KeyPair) getCached(ctx context.Context, tableName, id string, key map[string]ddbtypes.AttributeValue, item interface{}) error {
+	if record, ok := kp.Cache.get(tableName, id); ok {
+		if err := attributevalue.UnmarshalMap(record, item); err != nil {
+			return fmt.Errorf("error unmarshalling cached item: %w", err)
+		}
+		return nil
+	}
+
+	if err := kp.Get(ctx, tableName, key, item); err != nil {
+		return err
+	}
+
+	kp.cacheRecord(tableName, id, item)
+	return nil
+}
+
+// cacheRecord stores a record that was just read or written, so the cache never serves a copy
+// older than this server's own writes.
+func (kp *KeyPair) cacheRecord(tableName, id string, item interface{}) {
+	if kp.Cache == nil {
+		return
+	}
+
+	record, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		DatabaseLog.Warn("Error caching record", "tableName", tableName, "id", id, "error", err)
+		kp.Cache.invalidate(tableName, id)
+		return
+	}
+	kp.Cache.set(tableName, id, record)
+}
@@ -0,0 +1,420 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuiltInChannels are always available and new players are subscribed to them by default.
+var BuiltInChannels = []string{"ooc", "newbie"}
+
+// AreaChannel only reaches characters in the same area as the speaker. Its name is taken
+// by the area command, so it is spoken on through chat rather than registered as a command.
+const AreaChannel = "area"
+
+// DefaultChannels returns the channels players are subscribed to by default.
+func DefaultChannels() []string {
+	return append(append([]string(nil), BuiltInChannels...), AreaChannel)
+}
+
+// OfferDefaultChannels subscribes the player to each default channel they have not been given
+// before, including players whose records predate the channel, and reports whether anything
+// changed. A default channel the player has since left is not joined again.
+func (p *Player) OfferDefaultChannels() bool {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	if p.Channels == nil {
+		p.Channels = make(map[string]bool)
+	}
+	if p.OfferedChannels == nil {
+		p.OfferedChannels = make(map[string]bool)
+	}
+
+	changed := false
+	for _, name := range DefaultChannels() {
+		if p.OfferedChannels[name] {
+			continue
+		}
+		p.OfferedChannels[name] = true
+		p.Channels[name] = true
+		changed = true
+	}
+	return changed
+}
+
+// RegisterChannels registers the built-in channels and any channels from the configuration.
+func (s *Server) RegisterChannels() {
+	for _, name := range BuiltInChannels {
+		if err := s.CreateChannel(name); err != nil {
+			Logger.Error("Failed to register built-in channel", "channel", name, "error", err)
+		}
+	}
+
+	for _, name := range s.Settings().Game.Channels {
+		if err := s.CreateChannel(name); err != nil {
+			Logger.Error("Failed to register configured channel", "channel", name, "error", err)
+		}
+	}
+
+	s.Mutex.Lock()
+	if s.Channels == nil {
+		s.Channels = make(map[string]bool)
+	}
+	s.Channels[AreaChannel] = true
+	s.Mutex.Unlock()
+
+	Logger.Info("Registered channel", "channel", AreaChannel)
+}
+
+// CreateChannel adds a named channel to the server. Its name can then be used as a command,
+// which ExecuteCommand finds through ChannelExists.
+func (s *Server) CreateChannel(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid channel name %q", name)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if s.Channels == nil {
+		s.Channels = make(map[string]bool)
+	}
+
+	if s.Channels[name] {
+		return nil
+	}
+
+	if _, exists := CommandHandlers[name]; exists {
+		return fmt.Errorf("channel name %q conflicts with an existing command", name)
+	}
+
+	s.Channels[name] = true
+
+	Logger.Info("Registered channel", "channel", name)
+	return nil
+}
+
+// ChannelExists checks if a channel with the given name has been registered.
+func (s *Server) ChannelExists(name string) bool {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.Channels[strings.ToLower(name)]
+}
+
+// SendChannelMessage sends a message to every online character subscribed to the channel.
+// Messages on the area channel only reach characters in the sender's area. Characters who have
+// muted or ignored the sender, or who are fighting, do not see the message.
+func SendChannelMessage(s *Server, channel string, sender *Character, message string) {
+	Logger.Info("Sending message to channel", "channel", channel, "sender", sender.Name)
+
+	area := ""
+	if channel == AreaChannel && sender.Room != nil {
+		area = sender.Room.Area
+	}
+
+	label := fmt.Sprintf("[%s]", channel)
+	filteredText := FilterProfanity(s, message)
+
+	s.Mutex.Lock()
+	recipients := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		if character.IsActive() {
+			recipients = append(recipients, character)
+		}
+	}
+	s.Mutex.Unlock()
+
+	for _, character := range recipients {
+		if !character.Player.IsSubscribed(channel) || character.Player.IsMuted(sender.Name) {
+			continue
+		}
+		if channel == AreaChannel && (character.Room == nil || character.Room.Area != area) {
+			continue
+		}
+		// Chatter is held back from characters in combat so it does not bury the fight
+		if character != sender && character.IsInCombat() {
+			continue
+		}
+		// The label is colored for each recipient's own color settings
+		coloredLabel := character.Player.Colorize(ColorChannel, label)
+		rawMessage := fmt.Sprintf("\n\r%s %s: %s\n\r", coloredLabel, sender.Name, message)
+		filteredMessage := fmt.Sprintf("\n\r%s %s: %s\n\r", coloredLabel, sender.Name, filteredText)
+		if character.Player.SendFrom(sender, character.Player.MessageFor(rawMessage, filteredMessage)) && character != sender {
+			character.Player.Send(character.Player.RenderPrompt())
+		}
+	}
+}
+
+// IsSubscribed checks if the player has joined the named channel.
+func (p *Player) IsSubscribed(channel string) bool {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	return p.Channels[channel]
+}
+
+// JoinChannel subscribes the player to the named channel.
+func (p *Player) JoinChannel(channel string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	if p.Channels == nil {
+		p.Channels = make(map[string]bool)
+	}
+	p.Channels[channel] = true
+}
+
+// LeaveChannel unsubscribes the player from the named channel.
+func (p *Player) LeaveChannel(channel string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	delete(p.Channels, channel)
+}
+
+// IsMuted checks if the player has muted the named character on chat channels.
+func (p *Player) IsMuted(name string) bool {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	return p.Muted[strings.ToLower(name)]
+}
+
+// Mute hides the named character's channel messages from the player.
+func (p *Player) Mute(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	if p.Muted == nil {
+		p.Muted = make(map[string]bool)
+	}
+	p.Muted[strings.ToLower(name)] = true
+}
+
+// Unmute shows the named character's channel messages to the player again.
+func (p *Player) Unmute(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	delete(p.Muted, strings.ToLower(name))
+}
+
+func ExecuteChannelCommand(character *Character, tokens []string) bool {
+	channel := strings.ToLower(tokens[0])
+
+	Logger.Info("Player is speaking on a channel", "playerName", character.Player.PlayerID, "channel", channel)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rWhat do you want to say on %s?\n\r", channel)
+		return false
+	}
+
+	if !character.Player.IsSubscribed(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou have not joined the %s channel. Use 'join %s' first.\n\r", channel, channel)
+		return false
+	}
+
+	SendChannelMessage(character.Server, channel, character, strings.Join(tokens[1:], " "))
+	return false
+}
+
+func ExecuteJoinCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is joining a channel", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: join <channel>\n\r"
+		return false
+	}
+
+	channel := strings.ToLower(tokens[1])
+	if !character.Server.ChannelExists(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no channel named %s.\n\r", channel)
+		return false
+	}
+
+	if character.Player.IsSubscribed(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are already on the %s channel.\n\r", channel)
+		return false
+	}
+
+	character.Player.JoinChannel(channel)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player channels", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou join the %s channel.\n\r", channel)
+	return false
+}
+
+func ExecuteLeaveCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is leaving a channel", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: leave <channel>\n\r"
+		return false
+	}
+
+	channel := strings.ToLower(tokens[1])
+	if !character.Player.IsSubscribed(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are not on the %s channel.\n\r", channel)
+		return false
+	}
+
+	character.Player.LeaveChannel(channel)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player channels", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou leave the %s channel.\n\r", channel)
+	return false
+}
+
+func ExecuteChannelsCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is listing channels", "playerName", character.Player.PlayerID)
+
+	character.Server.Mutex.Lock()
+	names := make([]string, 0, len(character.Server.Channels))
+	for name := range character.Server.Channels {
+		names = append(names, name)
+	}
+	character.Server.Mutex.Unlock()
+
+	sort.Strings(names)
+
+	var output strings.Builder
+	output.WriteString("\n\rChannels:\n\r")
+	for _, name := range names {
+		status := ""
+		if character.Player.IsSubscribed(name) {
+			status = " (joined)"
+		}
+		output.WriteString(fmt.Sprintf("  %s%s\n\r", name, status))
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
+
+func ExecuteChatCommand(character *Character, tokens []string) bool {
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: chat <channel> <message>\n\r"
+		return false
+	}
+
+	channel := strings.ToLower(tokens[1])
+	if !character.Server.ChannelExists(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no channel named %s.\n\r", channel)
+		return false
+	}
+
+	// Speak as though the channel name had been typed as the command
+	return ExecuteChannelCommand(character, append([]string{channel}, tokens[2:]...))
+}
+
+func ExecuteMuteCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is muting a character", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.Mutex.Lock()
+		names := make([]string, 0, len(character.Player.Muted))
+		for name := range character.Player.Muted {
+			names = append(names, name)
+		}
+		character.Player.Mutex.Unlock()
+
+		if len(names) == 0 {
+			character.Player.ToPlayer <- "\n\rYou have not muted anyone.\n\rUsage: mute <character>\n\r"
+			return false
+		}
+
+		sort.Strings(names)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rMuted: %s\n\rUsage: mute <character>\n\r", strings.Join(names, ", "))
+		return false
+	}
+
+	name := tokens[1]
+	if strings.EqualFold(name, character.Name) {
+		character.Player.ToPlayer <- "\n\rYou cannot mute yourself.\n\r"
+		return false
+	}
+
+	if character.Player.IsMuted(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou have already muted %s.\n\r", name)
+		return false
+	}
+
+	character.Player.Mute(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player mutes", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou will no longer see channel messages from %s.\n\r", name)
+	return false
+}
+
+func ExecuteUnmuteCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is unmuting a character", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: unmute <character>\n\r"
+		return false
+	}
+
+	name := tokens[1]
+	if !character.Player.IsMuted(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou have not muted %s.\n\r", name)
+		return false
+	}
+
+	character.Player.Unmute(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player mutes", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou will see channel messages from %s again.\n\r", name)
+	return false
+}
+
+// ExecuteCreateChannelCommand is registered in init, as it refers to CommandHandlers through CreateChannel.
+func init() {
+	CommandHandlers["@channel"] = ExecuteCreateChannelCommand
+}
+
+func ExecuteCreateChannelCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is creating a channel", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: @channel <name>\n\r"
+		return false
+	}
+
+	channel := strings.ToLower(tokens[1])
+	if character.Server.ChannelExists(channel) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s channel already exists.\n\r", channel)
+		return false
+	}
+
+	if err := character.Server.CreateChannel(channel); err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return false
+	}
+
+	character.Player.JoinChannel(channel)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player channels", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s channel is open until the server restarts, and you have joined it. Add it to Game.Channels in the configuration to keep it.\n\r", channel)
+	return false
+}
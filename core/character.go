@@ -1,15 +1,17 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/google/uuid"
 )
@@ -34,46 +36,81 @@ var WearLocations = map[string]bool{
 	"right_wrist":  true,
 }
 
-// NewCharacter creates a new character with the specified name and archetype.
-func (s *Server) NewCharacter(name string, player *Player, room *Room, archetypeName string) (*Character, error) {
-	// Check if the character name already exists
-	if s.CharacterBloomFilter.Test([]byte(name)) {
-		return nil, fmt.Errorf("character name '%s' already exists", name)
+// WearSlot returns the inventory slot for a wear location and layer.
+// Layer 0 uses the bare location name so single-layer items keep their existing slots.
+func WearSlot(location string, layer int) string {
+	if layer == 0 {
+		return location
+	}
+	return fmt.Sprintf("%s:%d", location, layer)
+}
+
+// WornSlots returns the inventory slots the item occupies when worn.
+func (i *Item) WornSlots() []string {
+	slots := make([]string, len(i.WornOn))
+	for idx, location := range i.WornOn {
+		slots[idx] = WearSlot(location, i.Layer)
 	}
+	return slots
+}
 
+// NewCharacter creates a new character with the specified name and archetype.
+func (s *Server) NewCharacter(name string, player *Player, room *Room, archetypeName string) (*Character, error) {
+	game := s.Settings().Game
 	character := &Character{
-		ID:          uuid.New(),
-		Room:        room,
-		Name:        name,
-		Player:      player,
-		Health:      float64(s.Health),
-		Essence:     float64(s.Essence),
-		Attributes:  make(map[string]float64),
-		Abilities:   make(map[string]float64),
-		Inventory:   make(map[string]*Item),
-		Server:      s,
-		Mutex:       sync.Mutex{},
-		CombatRange: nil,
-		Facing:      nil,
-		LastSaved:   time.Now(),
-		LastEdited:  time.Now(),
+		ID:             uuid.New(),
+		Room:           room,
+		Name:           name,
+		Player:         player,
+		Health:         float64(game.StartingHealth),
+		Essence:        float64(game.StartingEssence),
+		MaxHealth:      float64(game.StartingHealth),
+		MaxEssence:     float64(game.StartingEssence),
+		Attributes:     make(map[string]float64),
+		Abilities:      make(map[string]float64),
+		Inventory:      make(map[string]*Item),
+		Server:         s,
+		Mutex:          sync.Mutex{},
+		CombatRange:    nil,
+		Facing:         nil,
+		ProtectedUntil: time.Now().Add(SpawnProtectionDuration),
+		Level:          1,
+		Explored:       make(map[int64]bool),
+		Coins:          StartingCoins,
+		LastSaved:      time.Now(),
+		LastEdited:     time.Now(),
+	}
+
+	// The name index is the authority on names in use and settles races between new characters
+	if err := s.Database.ClaimCharacterName(s.Context, name, character.ID, player.PlayerID); err != nil {
+		return nil, err
 	}
 
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// Add character name to bloom filter
-	s.CharacterBloomFilter.Add([]byte(name))
+	s.CharacterBloomFilter.AddString(strings.ToLower(name))
 
 	// Apply archetype attributes and abilities
 	if archetypeName != "" {
 		if archetype, ok := s.ArcheTypes[archetypeName]; ok {
+			character.Archetype = archetypeName
 			for attr, value := range archetype.Attributes {
 				character.Attributes[attr] = value
 			}
 			for ability, value := range archetype.Abilities {
 				character.Abilities[ability] = value
 			}
+			// Override the global starting health and essence when the archetype sets them
+			if archetype.StartHealth > 0 {
+				character.Health = archetype.StartHealth
+				character.MaxHealth = archetype.StartHealth
+			}
+			if archetype.StartEssence > 0 {
+				character.Essence = archetype.StartEssence
+				character.MaxEssence = archetype.StartEssence
+			}
 			// Set the start room if it's defined in the archetype
 			if archetype.StartRoom != 0 {
 				if startRoom, ok := s.Rooms[archetype.StartRoom]; ok {
@@ -85,6 +122,8 @@ func (s *Server) NewCharacter(name string, player *Player, room *Room, archetype
 		}
 	}
 
+	character.refreshPrompt()
+
 	// Add the character to the server's Characters map
 	s.Characters[character.ID] = character
 
@@ -99,15 +138,29 @@ func (c *Character) ToData() *CharacterData {
 	}
 
 	return &CharacterData{
-		CharacterID:   c.ID.String(),
-		PlayerID:      c.Player.PlayerID,
-		CharacterName: c.Name,
-		Attributes:    c.Attributes,
-		Abilities:     c.Abilities,
-		Essence:       c.Essence,
-		Health:        c.Health,
-		RoomID:        c.Room.RoomID,
-		Inventory:     inventoryIDs,
+		CharacterID:     c.ID.String(),
+		PlayerID:        c.Player.PlayerID,
+		CharacterName:   c.Name,
+		Attributes:      c.Attributes,
+		Abilities:       c.Abilities,
+		Essence:         c.Essence,
+		Health:          c.Health,
+		MaxEssence:      c.MaxEssence,
+		MaxHealth:       c.MaxHealth,
+		RoomID:          c.Room.RoomID,
+		Inventory:       inventoryIDs,
+		Effects:         c.effectsToData(),
+		Experience:      c.Experience,
+		Level:           c.Level,
+		TrainingPoints:  c.TrainingPoints,
+		Explored:        c.exploredToData(),
+		Coins:           c.Coins,
+		Quests:          c.Quests,
+		CompletedQuests: c.completedQuestsToData(),
+		Description:     c.Description,
+		Title:           c.Title,
+		Archetype:       c.Archetype,
+		Version:         c.Version,
 	}
 }
 
@@ -125,20 +178,20 @@ func (s *Server) CreateCharacter(player *Player) (*Character, error) {
 		return nil, fmt.Errorf("failed to receive character name input")
 	}
 
-	charName = strings.TrimSpace(charName)
-
-	// Validate character name
-	if len(charName) == 0 {
-		player.ToPlayer <- "Character name cannot be empty.\n\r"
-		return nil, fmt.Errorf("character name cannot be empty")
+	charName, err := s.ValidateCharacterName(charName)
+	if err != nil {
+		Logger.Info("Rejected character name", "playerName", player.PlayerID, "reason", err)
+		player.ToPlayer <- err.Error() + "\n\r"
+		return nil, fmt.Errorf("invalid character name: %w", err)
 	}
 
-	if len(charName) > 15 {
-		player.ToPlayer <- "Character name must be 15 characters or fewer.\n\r"
-		return nil, fmt.Errorf("character name must be 15 characters or fewer")
+	taken, err := s.CharacterNameTaken(charName)
+	if err != nil {
+		Logger.Error("Error checking character name", "characterName", charName, "error", err)
+		player.ToPlayer <- "Unable to check that name right now. Please try again later.\n\r"
+		return nil, fmt.Errorf("failed to check character name: %w", err)
 	}
-
-	if s.CharacterBloomFilter.Test([]byte(charName)) {
+	if taken {
 		player.ToPlayer <- "Character name already exists. Please choose another name.\n\r"
 		return nil, fmt.Errorf("character name already exists")
 	}
@@ -146,11 +199,11 @@ func (s *Server) CreateCharacter(player *Player) (*Character, error) {
 	var selectedArchetype string
 
 	// If archetypes are available, prompt the player to select one
-	if len(s.ArcheTypes) > 0 {
+	if archetypes := s.Archetypes(); len(archetypes) > 0 {
 		for {
 			selectionMsg := "\n\rSelect a character archetype.\n\r"
-			archetypeOptions := make([]string, 0, len(s.ArcheTypes))
-			for name, archetype := range s.ArcheTypes {
+			archetypeOptions := make([]string, 0, len(archetypes))
+			for name, archetype := range archetypes {
 				archetypeOptions = append(archetypeOptions, name+" - "+archetype.Description)
 			}
 			sort.Strings(archetypeOptions)
@@ -182,25 +235,25 @@ func (s *Server) CreateCharacter(player *Player) (*Character, error) {
 	Logger.Info("Creating character", "characterName", charName)
 
 	// Attempt to find the starting room
-	room, ok := s.Rooms[1] // This should be pulled ftom the Archtype
+	room, ok := s.Room(1) // This should be pulled ftom the Archtype
 	if !ok {
 		Logger.Warn("Starting room not found, using default room", "startingRoomID", 1)
 
 		// Attempt to find default room (room ID 0)
-		room, ok = s.Rooms[0]
+		room, ok = s.Room(0)
 		if !ok {
 			Logger.Error("No default room found", "defaultRoomID", 0)
-			Logger.Info("Available rooms", "rooms", s.Rooms)
 			player.ToPlayer <- "No starting or default room found. Please contact the administrator.\n\r"
 			return nil, fmt.Errorf("no starting or default room found")
 		}
 	}
 
-	s.Mutex.Lock()
-	defer s.Mutex.Unlock()
-
 	// Create the new character
 	character, err := s.NewCharacter(charName, player, room, selectedArchetype)
+	if errors.Is(err, ErrNameTaken) {
+		player.ToPlayer <- "Character name already exists. Please choose another name.\n\r"
+		return nil, fmt.Errorf("failed to create character: %w", err)
+	}
 	if err != nil {
 		Logger.Error("Error creating character", "characterName", charName, "error", err)
 		player.ToPlayer <- "Error creating character. Please try again later.\n\r"
@@ -217,15 +270,18 @@ func (s *Server) CreateCharacter(player *Player) (*Character, error) {
 	Logger.Info("Added character to player's character list", "characterName", charName, "characterID", character.ID, "playerName", player.PlayerID)
 
 	// Save the character to the database with error handling
-	err = s.Database.WriteCharacter(character)
+	err = s.Database.WriteCharacter(s.Context, character)
 	if err != nil {
 		Logger.Error("Error saving character to database", "characterName", charName, "error", err)
+		if releaseErr := s.Database.ReleaseCharacterName(s.Context, charName); releaseErr != nil {
+			Logger.Warn("Failed to release name of unsaved character", "characterName", charName, "error", releaseErr)
+		}
 		player.ToPlayer <- "Error saving character to database. Please try again later.\n\r"
 		return nil, fmt.Errorf("failed to save character to database: %w", err)
 	}
 
 	// Save the updated player data with error handling
-	err = s.Database.WritePlayer(player)
+	err = s.Database.WritePlayer(s.Context, player)
 	if err != nil {
 		Logger.Error("Error saving player data", "playerName", player.PlayerID, "error", err)
 		player.ToPlayer <- "Error saving player data. Please try again later.\n\r"
@@ -249,70 +305,126 @@ func (c *Character) FromData(cd *CharacterData, server *Server) error {
 	c.Abilities = cd.Abilities
 	c.Essence = cd.Essence
 	c.Health = cd.Health
+	c.MaxEssence = cd.MaxEssence
+	c.MaxHealth = cd.MaxHealth
+	c.Effects = effectsFromData(cd.Effects)
+	c.Experience = cd.Experience
+	c.Level = cd.Level
+	c.TrainingPoints = cd.TrainingPoints
+	c.Coins = cd.Coins
+	c.Description = cd.Description
+	c.Title = cd.Title
+	c.Archetype = cd.Archetype
+	c.Version = cd.Version
+	c.Quests = cd.Quests
+	if c.Quests == nil {
+		c.Quests = make(map[string][]int)
+	}
+	c.CompletedQuests = make(map[string]bool)
+	for _, questID := range cd.CompletedQuests {
+		c.CompletedQuests[questID] = true
+	}
+
+	c.Explored = make(map[int64]bool)
+	for _, roomID := range cd.Explored {
+		c.Explored[roomID] = true
+	}
+
+	// Characters saved before maximums were tracked use the larger of their current and the starting values
+	if c.MaxHealth == 0 {
+		c.MaxHealth = math.Max(c.Health, float64(server.Settings().Game.StartingHealth))
+	}
+	if c.MaxEssence == 0 {
+		c.MaxEssence = math.Max(c.Essence, float64(server.Settings().Game.StartingEssence))
+	}
 
-	// Retrieve the room; if not found, default to room ID 0
-	room, exists := server.Rooms[cd.RoomID]
+	// Retrieve the room; if it no longer exists, fall back to a default room
+	room, exists := server.Room(cd.RoomID)
 	if !exists {
-		Logger.Warn("Room not found, defaulting to room ID 0", "roomID", cd.RoomID)
-		room, exists = server.Rooms[0]
-		if !exists {
-			return fmt.Errorf("default room not found")
+		Logger.Warn("Room not found for character, using fallback room", "characterName", cd.CharacterName, "roomID", cd.RoomID)
+		room, err = server.FallbackRoom()
+		if err != nil {
+			Logger.Error("No fallback room available for character", "characterName", cd.CharacterName, "roomID", cd.RoomID, "error", err)
+			return fmt.Errorf("no room available for character %s: %w", cd.CharacterName, err)
 		}
 	}
 	c.Room = room
 	c.Server = server
 
-	// Initialize inventory
+	// Initialize inventory, loading items that fill several slots only once
 	c.Inventory = make(map[string]*Item)
+	loaded := make(map[uuid.UUID]*Item)
 	for name, itemIDStr := range cd.Inventory {
 		itemID, err := uuid.Parse(itemIDStr)
 		if err != nil {
 			Logger.Error("Error parsing item UUID", "itemID", itemIDStr, "error", err)
 			continue
 		}
-		item, err := server.Database.LoadItem(itemID.String())
-		if err != nil {
-			Logger.Error("Error loading item for character", "itemID", itemID, "characterName", c.Name, "error", err)
-			continue
+		item, exists := loaded[itemID]
+		if !exists {
+			item, err = server.Database.LoadItem(server.Context, itemID.String())
+			if err != nil {
+				Logger.Error("Error loading item for character", "itemID", itemID, "characterName", c.Name, "error", err)
+				continue
+			}
+			loaded[itemID] = item
 		}
 		c.Inventory[name] = item
 	}
 
+	c.refreshPrompt()
+
 	return nil
 }
 
-// WriteCharacter saves the character to the DynamoDB database.
-func (kp *KeyPair) WriteCharacter(character *Character) error {
+// WriteCharacter saves the full character record to the DynamoDB database. Routine saves
+// use UpdateCharacter, which only writes the dirty sections.
+func (kp *KeyPair) WriteCharacter(ctx context.Context, character *Character) error {
 
 	characterData := character.ToData()
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: characterData.CharacterID},
+	}
 
-	err := kp.Put("characters", characterData)
+	err := kp.putVersioned(ctx, "characters", key, characterData, &characterData.Version)
 	if err != nil {
 		Logger.Error("Error writing character data", "characterName", character.Name, "error", err)
 		return fmt.Errorf("error writing character data: %w", err)
 	}
 
-	Logger.Info("Successfully wrote character to database", "characterName", character.Name, "characterID", character.ID)
+	Logger.Info("Successfully wrote character to database", "characterName", character.Name, "characterID", character.ID, "version", characterData.Version)
 
+	character.Dirty = 0
+	character.Version = characterData.Version
 	character.LastSaved = time.Now()
 
 	return nil
 }
 
 // LoadCharacter retrieves a character from the DynamoDB database and reconstructs the Character object.
-func (kp *KeyPair) LoadCharacter(characterID uuid.UUID, player *Player, server *Server) (*Character, error) {
+func (kp *KeyPair) LoadCharacter(ctx context.Context, characterID uuid.UUID, player *Player, server *Server) (*Character, error) {
 
-	key := map[string]*dynamodb.AttributeValue{
-		"CharacterID": {S: aws.String(characterID.String())},
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: characterID.String()},
 	}
 
 	var cd CharacterData
-	err := kp.Get("characters", key, &cd)
+	err := kp.Get(ctx, "characters", key, &cd)
 	if err != nil {
 		Logger.Error("Error loading character data", "characterID", characterID, "error", err)
 		return nil, fmt.Errorf("error loading character data: %w", err)
 	}
 
+	// Refuse to hand a character to any player other than its owner
+	if player == nil || cd.PlayerID != player.PlayerID {
+		requester := ""
+		if player != nil {
+			requester = player.PlayerID
+		}
+		Logger.Warn("Refusing to load character owned by another player", "characterID", characterID, "owner", cd.PlayerID, "requester", requester)
+		return nil, fmt.Errorf("character %s does not belong to player %s", characterID, requester)
+	}
+
 	character := &Character{
 		Server: server,
 		Player: player,
@@ -348,92 +460,102 @@ func (kp *KeyPair) LoadCharacter(characterID uuid.UUID, player *Player, server *
 func (s *Server) DeleteCharacter(player *Player, characterName string) error {
 	Logger.Info("Attempting to delete character", "playerName", player.PlayerID, "characterName", characterName)
 
-	// Check if the character exists in the player's character list
+	// Check if the character exists in the player's character list, and remove it
+	player.Mutex.Lock()
 	characterID, exists := player.CharacterList[characterName]
+	if exists {
+		delete(player.CharacterList, characterName)
+	}
+	player.Mutex.Unlock()
 	if !exists {
 		return fmt.Errorf("character %s not found for player %s", characterName, player.PlayerID)
 	}
 
-	// Remove the character from the player's character list
-	delete(player.CharacterList, characterName)
-
 	// Update the player data in the database
-	err := s.Database.WritePlayer(player)
+	err := s.Database.WritePlayer(s.Context, player)
 	if err != nil {
 		Logger.Error("Failed to update player data after character deletion", "playerName", player.PlayerID, "error", err)
 		return fmt.Errorf("failed to update player data: %w", err)
 	}
 
 	// Delete the character from the database
-	key := map[string]*dynamodb.AttributeValue{
-		"CharacterID": {S: aws.String(characterID.String())},
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: characterID.String()},
 	}
-	err = s.Database.Delete("characters", key)
+	err = s.Database.Delete(s.Context, "characters", key)
 	if err != nil {
 		Logger.Error("Failed to delete character from database", "characterName", characterName, "characterID", characterID, "error", err)
 		return fmt.Errorf("failed to delete character from database: %w", err)
 	}
 
+	// A claim left behind here is released when the server next starts
+	if err := s.Database.ReleaseCharacterName(s.Context, characterName); err != nil {
+		Logger.Warn("Failed to release deleted character name", "characterName", characterName, "error", err)
+	}
+
 	Logger.Info("Successfully deleted character", "playerName", player.PlayerID, "characterName", characterName, "characterID", characterID)
+	s.AuditPlayer(s.Context, player, AuditCharacterDelete, characterName, characterID.String())
 	return nil
 }
 
-// LoadCharacterNames loads all character names from the database to initialize the bloom filter.
-func (kp *KeyPair) LoadCharacterNames() (map[string]bool, error) {
-	names := make(map[string]bool)
-
-	var characters []struct {
-		CharacterName string `dynamodbav:"Name"`
+// FindCharacterData looks up a stored character by name without loading it into the game.
+func (kp *KeyPair) FindCharacterData(ctx context.Context, name string) (*CharacterData, error) {
+	record, err := kp.LookupCharacterName(ctx, name)
+	if errors.Is(err, ErrItemNotFound) {
+		return nil, fmt.Errorf("character %s not found", name)
 	}
-
-	err := kp.Scan("characters", &characters)
 	if err != nil {
-		Logger.Error("Error scanning characters table", "error", err)
-		return nil, fmt.Errorf("error scanning characters: %w", err)
+		return nil, err
 	}
 
-	for _, character := range characters {
-		names[strings.ToLower(character.CharacterName)] = true
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: record.CharacterID},
 	}
 
-	if len(names) == 0 {
-		Logger.Warn("No characters found in the database")
-		return names, nil // Return empty map without error
+	var cd CharacterData
+	if err := kp.Get(ctx, "characters", key, &cd); err != nil {
+		Logger.Error("Error loading character data", "characterName", name, "characterID", record.CharacterID, "error", err)
+		return nil, fmt.Errorf("error loading character data: %w", err)
 	}
 
-	return names, nil
+	return &cd, nil
 }
 
 // InitializeBloomFilter initializes the bloom filter with existing character names,
-// as well as names from ../data/names.txt and ../data/obscenity.txt.
+// as well as names from the configured names and obscenity files.
 func (server *Server) InitializeBloomFilter() error {
-	// Load character names from the database
-	characterNames, err := server.Database.LoadCharacterNames()
+	// Load character names from the database, bringing the name index up to date
+	characterNames, err := server.Database.SyncCharacterNames(server.Context)
 	if err != nil {
 		return fmt.Errorf("failed to load character names: %w", err)
 	}
 
-	// Load additional names from names.txt
-	namesFilePath := "../data/names.txt"
-	namesFromFile, err := loadNamesFromFile(namesFilePath)
+	// Load additional names from names.txt into an exact set
+	namesFilePath := ResolveDataPath(server.Config.Data.NamesFile, DefaultNamesFile)
+	Logger.Info("Resolved names file", "path", namesFilePath)
+	server.ReservedNames, err = loadWordSet(namesFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load names from %s: %w", namesFilePath, err)
+		if server.Config.Data.Strict {
+			return fmt.Errorf("failed to load names from %s: %w", namesFilePath, err)
+		}
+		Logger.Warn("Failed to load names file, continuing without reserved names", "path", namesFilePath, "error", err)
+		server.ReservedNames = make(map[string]bool)
 	}
 
-	// Load obscenity words from obscenity.txt
-	obscenityFilePath := "../data/obscenity.txt"
-	obscenities, err := loadNamesFromFile(obscenityFilePath)
+	// Load obscenity words from obscenity.txt into an exact set
+	obscenityFilePath := ResolveDataPath(server.Config.Data.ObscenityFile, DefaultObscenityFile)
+	Logger.Info("Resolved obscenity file", "path", obscenityFilePath)
+	server.Obscenities, err = loadWordSet(obscenityFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load obscenities from %s: %w", obscenityFilePath, err)
+		if server.Config.Data.Strict {
+			return fmt.Errorf("failed to load obscenities from %s: %w", obscenityFilePath, err)
+		}
+		Logger.Warn("Failed to load obscenity file, continuing without obscenities", "path", obscenityFilePath, "error", err)
+		server.Obscenities = make(map[string]bool)
 	}
 
 	// Calculate total number of items to add to the bloom filter
-	totalItems := len(characterNames)
-	for range characterNames { // Assuming characterNames is a map; adjust if it's a slice
-		// Counting items in characterNames
-	}
-	totalItems += len(namesFromFile)
-	totalItems += len(obscenities)
+	totalItems := len(characterNames) + len(server.ReservedNames) + len(server.Obscenities)
 
 	// Ensure a minimum size
 	if totalItems < 100 {
@@ -451,12 +573,12 @@ func (server *Server) InitializeBloomFilter() error {
 	}
 
 	// Add names from names.txt to the bloom filter
-	for _, name := range namesFromFile {
+	for name := range server.ReservedNames {
 		server.CharacterBloomFilter.AddString(name)
 	}
 
 	// Add obscenities to the bloom filter
-	for _, word := range obscenities {
+	for word := range server.Obscenities {
 		server.CharacterBloomFilter.AddString(word)
 	}
 
@@ -469,6 +591,16 @@ func (server *Server) InitializeBloomFilter() error {
 	return nil
 }
 
+// IsReservedName checks if a name appears in the reserved names list using an exact lookup.
+func (server *Server) IsReservedName(name string) bool {
+	return server.ReservedNames[strings.ToLower(name)]
+}
+
+// IsObscenity checks if a word appears in the obscenity list using an exact lookup.
+func (server *Server) IsObscenity(word string) bool {
+	return server.Obscenities[strings.ToLower(word)]
+}
+
 // AddCharacterName adds a character name to the bloom filter to prevent duplicates.
 func (server *Server) AddCharacterName(name string) {
 	server.Mutex.Lock()
@@ -487,33 +619,50 @@ func (server *Server) CharacterNameExists(name string) bool {
 	return exists
 }
 
-// SaveActiveCharacters saves all active characters to the database if they have been edited since the last save.
-func (s *Server) SaveActiveCharacters() error {
+// FindOnlineCharacter returns the online character with the given name, ignoring case.
+func (s *Server) FindOnlineCharacter(name string) *Character {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	for _, character := range s.Characters {
+		if strings.EqualFold(character.Name, name) {
+			return character
+		}
+	}
+	return nil
+}
+
+// SaveActiveCharacters writes the dirty sections of every active character to the database,
+// skipping characters that have not changed since the last save.
+func (s *Server) SaveActiveCharacters(ctx context.Context) error {
 
 	Logger.Info("Saving active characters...")
 
+	s.Mutex.Lock()
+	characters := make([]*Character, 0, len(s.Characters))
 	for _, character := range s.Characters {
-		// Check if the character's LastEdited is before LastSaved
-		if !character.LastEdited.After(character.LastSaved) {
-			Logger.Info("Character not edited since last save, skipping", "characterName", character.Name)
-			continue // Skip writing this character
-		}
+		characters = append(characters, character)
+	}
+	s.Mutex.Unlock()
 
+	saved := 0
+	for _, character := range characters {
 		character.Mutex.Lock()
-		// Attempt to write the character to the database
-		err := s.Database.WriteCharacter(character)
-		if err != nil {
-			Logger.Error("Error saving character", "characterName", character.Name, "error", err)
-			continue // Continue saving other characters even if one fails
+		if character.Dirty == 0 {
+			character.Mutex.Unlock()
+			continue
 		}
 
-		// Update LastSaved after a successful write
-		character.LastSaved = time.Now()
-		Logger.Info("Character saved successfully", "characterName", character.Name)
+		// Continue saving other characters even if one fails
+		if err := s.Database.UpdateCharacter(ctx, character); err != nil {
+			Logger.Error("Error saving character", "characterName", character.Name, "error", err)
+		} else {
+			saved++
+		}
 		character.Mutex.Unlock()
 	}
 
-	Logger.Info("Active characters saved successfully.")
+	Logger.Info("Active characters saved", "saved", saved, "active", len(characters))
 	return nil
 }
 
@@ -522,13 +671,11 @@ func (c *Character) WearItem(item *Item) error {
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 
-	// Check if the item is in a hand slot
+	// Check if the item is held
 	inHand := false
-	var handSlot string
-	for slot, handItem := range c.Inventory {
-		if (slot == "left_hand" || slot == "right_hand") && handItem == item {
+	for _, held := range c.equipment().Held {
+		if held == item {
 			inHand = true
-			handSlot = slot
 			break
 		}
 	}
@@ -545,21 +692,21 @@ func (c *Character) WearItem(item *Item) error {
 		if !WearLocations[location] {
 			return fmt.Errorf("invalid wear location: %s", location)
 		}
-		if c.Inventory[location] != nil {
+		if c.Inventory[WearSlot(location, item.Layer)] != nil {
 			return fmt.Errorf("you are already wearing something on your %s", location)
 		}
 	}
 
-	for _, location := range item.WornOn {
-		c.Inventory[location] = item
+	c.releaseItem(item)
+	for _, slot := range item.WornSlots() {
+		c.Inventory[slot] = item
 	}
 
 	item.IsWorn = true
-	delete(c.Inventory, handSlot) // Remove from hand slot
 
 	Logger.Info("Item worn", "characterName", c.Name, "itemName", item.Name, "wornOn", item.WornOn)
 
-	c.LastEdited = time.Now()
+	c.markDirty(DirtyInventory)
 
 	return nil
 }
@@ -572,20 +719,25 @@ func (c *Character) ListInventory() string {
 	defer c.Mutex.Unlock()
 
 	var held, worn []string
-	wornItems := make(map[string]bool) // To avoid duplicates in worn items list
+	equipment := c.equipment()
 
-	for slot, item := range c.Inventory {
-		if item.IsWorn {
-			if !wornItems[item.Name] {
-				worn = append(worn, fmt.Sprintf("%s (worn on %s)", item.Name, strings.Join(item.WornOn, ", ")))
-				wornItems[item.Name] = true
+	for _, item := range equipment.Held {
+		slots := make([]string, 0, len(HandSlots))
+		for _, slot := range HandSlots {
+			if c.Inventory[slot] == item {
+				slots = append(slots, slot)
 			}
-		} else if slot == "left_hand" || slot == "right_hand" {
-			held = append(held, fmt.Sprintf("%s (in %s)", item.Name, slot))
-		} else {
+		}
+		held = append(held, fmt.Sprintf("%s (in %s)", item.Name, handDescription(slots)))
+	}
+	for slot, item := range c.Inventory {
+		if !item.IsWorn && !IsHandSlot(slot) {
 			held = append(held, item.Name)
 		}
 	}
+	for _, item := range equipment.Worn {
+		worn = append(worn, fmt.Sprintf("%s (worn on %s)", item.Name, strings.Join(item.WornOn, ", ")))
+	}
 
 	result := "\n\rInventory:\n\r"
 	if len(held) > 0 {
@@ -598,6 +750,13 @@ func (c *Character) ListInventory() string {
 		result += "Your inventory is empty.\n\r"
 	}
 
+	mass, capacity := c.carriedMass(), c.carryCapacity()
+	result += fmt.Sprintf("Carrying %.1f of %.1f.", mass, capacity)
+	if mass > capacity*EncumberedRatio {
+		result += " You are encumbered and move slowly."
+	}
+	result += "\n\r"
+
 	return result
 }
 
@@ -609,23 +768,16 @@ func (c *Character) AddToInventory(item *Item) {
 	defer c.Mutex.Unlock()
 
 	if item.Wearable && len(item.WornOn) > 0 {
-		for _, location := range item.WornOn {
-			c.Inventory[location] = item
+		for _, slot := range item.WornSlots() {
+			c.Inventory[slot] = item
 		}
 		item.IsWorn = true
-	} else {
-		// Place in the first available hand slot
-		if c.Inventory["right_hand"] == nil {
-			c.Inventory["right_hand"] = item
-		} else if c.Inventory["left_hand"] == nil {
-			c.Inventory["left_hand"] = item
-		} else {
-			// If both hands are full, add to general inventory
-			c.Inventory[item.Name] = item
-		}
+	} else if _, err := c.holdItem(item); err != nil {
+		// If the character's hands are full, add to general inventory
+		c.Inventory[item.Name] = item
 	}
 
-	c.LastEdited = time.Now()
+	c.markDirty(DirtyInventory)
 
 	Logger.Info("Item added to inventory", "characterName", c.Name, "itemName", item.Name)
 }
@@ -656,8 +808,8 @@ func (c *Character) RemoveFromInventory(item *Item) {
 	defer c.Mutex.Unlock()
 
 	if item.IsWorn {
-		for _, location := range item.WornOn {
-			delete(c.Inventory, location)
+		for _, slot := range item.WornSlots() {
+			delete(c.Inventory, slot)
 		}
 		item.IsWorn = false
 	} else {
@@ -670,18 +822,101 @@ func (c *Character) RemoveFromInventory(item *Item) {
 		}
 	}
 
-	c.LastEdited = time.Now()
+	c.markDirty(DirtyInventory)
 
 	Logger.Info("Item removed from inventory", "characterName", c.Name, "itemName", item.Name)
 }
 
-// CanCarryItem checks if the character can carry the specified item.
-// This is a placeholder for future weight and capacity checks.
+// CanCarryItem checks if the character can carry the specified item without exceeding their capacity.
 func (c *Character) CanCarryItem(item *Item) bool {
 	Logger.Info("Character is checking if they can carry item", "characterName", c.Name, "itemName", item.Name)
 
-	// Placeholder implementation; always returns true for now
-	return true
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.carriedMass()+item.TotalMass() <= c.carryCapacity()
+}
+
+// Carrying capacity is BaseCarryCapacity plus CarryCapacityPerStrength for each point of Strength.
+// Characters carrying more than EncumberedRatio of their capacity are encumbered and move slowly.
+const (
+	BaseCarryCapacity        = 10.0
+	CarryCapacityPerStrength = 25.0
+	EncumberedRatio          = 0.75
+	EncumberedMoveDelay      = 1 * time.Second
+)
+
+// TotalMass returns the mass of the item, including every item in a stack and any contents.
+func (i *Item) TotalMass() float64 {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+
+	mass := i.Mass
+	if i.Stackable && i.Quantity > 1 {
+		mass *= float64(i.Quantity)
+	}
+	for _, contentItem := range i.Contents {
+		mass += contentItem.TotalMass()
+	}
+	return mass
+}
+
+// CarryCapacity returns the most mass the character can carry, based on their Strength.
+func (c *Character) CarryCapacity() float64 {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.carryCapacity()
+}
+
+// carryCapacity returns the carrying capacity; the caller must hold the character's mutex.
+func (c *Character) carryCapacity() float64 {
+	return BaseCarryCapacity + c.Attributes["Strength"]*CarryCapacityPerStrength
+}
+
+// CarriedMass returns the total mass of everything the character is holding and wearing.
+func (c *Character) CarriedMass() float64 {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.carriedMass()
+}
+
+// carriedMass returns the carried mass; the caller must hold the character's mutex.
+func (c *Character) carriedMass() float64 {
+	mass := 0.0
+	counted := make(map[*Item]bool) // Worn items span several slots, so count each only once
+	for _, item := range c.Inventory {
+		if item != nil && !counted[item] {
+			mass += item.TotalMass()
+			counted[item] = true
+		}
+	}
+	return mass
+}
+
+// IsEncumbered checks if the character is carrying enough to slow them down.
+func (c *Character) IsEncumbered() bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.carriedMass() > c.carryCapacity()*EncumberedRatio
+}
+
+// StartMove records a move and returns how long until the character may move again. Moves
+// made while encumbered hold the next move back by EncumberedMoveDelay; a move made before
+// then is refused and leaves the character where they are.
+func (c *Character) StartMove() time.Duration {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if wait := time.Until(c.NextMove); wait > 0 {
+		return wait
+	}
+	if c.carriedMass() > c.carryCapacity()*EncumberedRatio {
+		c.NextMove = time.Now().Add(EncumberedMoveDelay)
+	}
+	return 0
 }
 
 // RemoveWornItem allows a character to remove a worn item.
@@ -706,46 +941,45 @@ func (c *Character) RemoveWornItem(item *Item) error {
 		return fmt.Errorf("you are not wearing that item")
 	}
 
-	// Try to place the item in the right hand first, then the left hand if right is occupied
-	var handSlot string
-	if c.Inventory["right_hand"] == nil {
-		handSlot = "right_hand"
-	} else if c.Inventory["left_hand"] == nil {
-		handSlot = "left_hand"
-	}
-
-	if handSlot == "" {
+	if c.handsFor(item.TwoHanded) == nil {
 		return fmt.Errorf("your hands are full. You need a free hand to remove an item")
 	}
 
 	// Remove item from worn locations
-	for _, location := range item.WornOn {
-		delete(c.Inventory, location)
+	for _, slot := range item.WornSlots() {
+		delete(c.Inventory, slot)
 	}
 	item.IsWorn = false
 
 	// Place item in hand slot
-	c.Inventory[handSlot] = item
-
-	c.LastEdited = time.Now()
+	hands, err := c.holdItem(item)
+	if err != nil {
+		return err
+	}
 
-	Logger.Info("Item removed from worn location and placed in hand", "characterName", c.Name, "itemName", item.Name, "handSlot", handSlot)
+	Logger.Info("Item removed from worn location and placed in hand", "characterName", c.Name, "itemName", item.Name, "hands", hands)
 	return nil
 }
 
-// getOtherCharacters returns a list of character names in the room, excluding the current character.
+// getOtherCharacters returns a list of character and NPC names in the room, excluding the current character.
 func getOtherCharacters(r *Room, currentCharacter *Character) []string {
 	if r == nil || r.Characters == nil {
 		Logger.Warn("Room or Characters map is nil in getOtherCharacters")
 		return []string{}
 	}
 
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
 	otherCharacters := make([]string, 0)
 	for _, c := range r.Characters {
 		if c != nil && c != currentCharacter {
 			otherCharacters = append(otherCharacters, c.Name)
 		}
 	}
+	for _, npc := range r.NPCs {
+		otherCharacters = append(otherCharacters, npc.Name)
+	}
 
 	Logger.Info("Found other characters in room", "count", len(otherCharacters), "room_id", r.RoomID)
 	return otherCharacters
@@ -761,7 +995,7 @@ func (c *Character) Move(direction string) {
 	if c.Room == nil {
 		c.Player.ToPlayer <- "\n\rYou are not in any room to move from.\n\r"
 		Logger.Warn("Character has no current room", "character_name", c.Name)
-		c.Player.ToPlayer <- c.Player.Prompt
+		c.Player.ToPlayer <- c.Player.RenderPrompt()
 		return
 	}
 
@@ -769,28 +1003,60 @@ func (c *Character) Move(direction string) {
 	if !exists {
 		c.Player.ToPlayer <- "\n\rYou cannot go that way.\n\r"
 		Logger.Warn("Invalid direction for movement", "character_name", c.Name, "direction", direction)
-		c.Player.ToPlayer <- c.Player.Prompt
+		c.Player.ToPlayer <- c.Player.RenderPrompt()
 		return
 	}
 
 	if selectedExit.TargetRoom == nil {
 		c.Player.ToPlayer <- "\n\rThe path leads nowhere.\n\r"
 		Logger.Warn("Target room is nil", "character_name", c.Name, "direction", direction)
-		c.Player.ToPlayer <- c.Player.Prompt
+		c.Player.ToPlayer <- c.Player.RenderPrompt()
+		return
+	}
+
+	c.Room.Mutex.Lock()
+	closed, locked := selectedExit.Closed, selectedExit.Locked
+	c.Room.Mutex.Unlock()
+
+	if !c.Server.ConditionMet(c.Room, selectedExit.Condition) {
+		c.Player.ToPlayer <- fmt.Sprintf("\n\rThe way %s cannot be taken right now.\n\r", direction)
+		c.Player.ToPlayer <- c.Player.RenderPrompt()
+		return
+	}
+
+	if closed {
+		if locked {
+			c.Player.ToPlayer <- fmt.Sprintf("\n\rThe door to the %s is locked.\n\r", direction)
+		} else {
+			c.Player.ToPlayer <- fmt.Sprintf("\n\rThe door to the %s is closed.\n\r", direction)
+		}
+		c.Player.ToPlayer <- c.Player.RenderPrompt()
 		return
 	}
 
 	newRoom := selectedExit.TargetRoom
+	CancelTrade(c, fmt.Sprintf("%s has left", c.Name))
 
 	// Safely remove the character from the old room
 	oldRoom := c.Room
 	oldRoom.Mutex.Lock()
 	delete(oldRoom.Characters, c.ID)
 	oldRoom.Mutex.Unlock()
-	SendRoomMessage(oldRoom, fmt.Sprintf("\n\r%s has left going %s.\n\r", c.Name, direction))
+	departure := fmt.Sprintf("\n\r%s has left going %s.\n\r", c.Name, direction)
+	arrival := fmt.Sprintf("\n\r%s has arrived.\n\r", c.Name)
+	if selectedExit.TravelVerb != "" {
+		verb := ThirdPerson(selectedExit.TravelVerb)
+		departure = fmt.Sprintf("\n\r%s %s %s.\n\r", c.Name, verb, direction)
+		arrival = fmt.Sprintf("\n\r%s %s in.\n\r", c.Name, verb)
+		c.Player.ToPlayer <- fmt.Sprintf("\n\rYou %s %s.\n\r", selectedExit.TravelVerb, direction)
+	}
+	QueueRoomEvent(oldRoom, RoomEventDeparture, departure, "\n\rA group of adventurers leaves.\n\r")
 
 	// Update character's room
 	c.Room = newRoom
+	c.markDirty(DirtyLocation)
+	c.explore(newRoom)
+	c.recordObjective(ObjectiveVisit, strconv.FormatInt(newRoom.RoomID, 10))
 
 	// Safely add the character to the new room
 	newRoom.Mutex.Lock()
@@ -799,12 +1065,64 @@ func (c *Character) Move(direction string) {
 	}
 	newRoom.Characters[c.ID] = c
 	newRoom.Mutex.Unlock()
-	SendRoomMessage(newRoom, fmt.Sprintf("\n\r%s has arrived.\n\r", c.Name))
+	QueueRoomEvent(newRoom, RoomEventArrival, arrival, "\n\rA group of adventurers arrives.\n\r")
+	c.enterArea(oldRoom, newRoom)
 
 	// Let the character look around the new room
 	ExecuteLookCommand(c, []string{})
 
-	c.LastEdited = time.Now()
-
 	Logger.Info("Character moved successfully", "character_name", c.Name, "new_room_id", newRoom.RoomID)
 }
+
+// Teleport moves the character directly to a room, bypassing exits.
+func (c *Character) Teleport(newRoom *Room) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	CancelTrade(c, fmt.Sprintf("%s has vanished", c.Name))
+
+	oldRoom := c.Room
+	if oldRoom != nil {
+		oldRoom.Mutex.Lock()
+		delete(oldRoom.Characters, c.ID)
+		oldRoom.Mutex.Unlock()
+		SendRoomMessage(oldRoom, fmt.Sprintf("\n\r%s vanishes in a flash of light.\n\r", c.Name))
+	}
+
+	c.Room = newRoom
+	c.markDirty(DirtyLocation)
+
+	newRoom.Mutex.Lock()
+	if newRoom.Characters == nil {
+		newRoom.Characters = make(map[uuid.UUID]*Character)
+	}
+	newRoom.Characters[c.ID] = c
+	newRoom.Mutex.Unlock()
+	sendToRoomExcept(newRoom, fmt.Sprintf("\n\r%s appears in a flash of light.\n\r", c.Name), c)
+
+	ExecuteLookCommand(c, []string{})
+
+	Logger.Info("Character teleported", "character_name", c.Name, "new_room_id", newRoom.RoomID)
+}
+
+// Score returns the character's total for an attribute or ability, including temporary modifiers.
+func (c *Character) Score(trait string) float64 {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.Attributes[trait] + c.Abilities[trait] + c.effectMod(trait) + c.itemMod(trait)
+}
+
+// itemMod returns the sum of the modifiers to a trait from the items the character holds or
+// wears. The caller must hold c.Mutex.
+func (c *Character) itemMod(trait string) float64 {
+	mod := 0.0
+	counted := make(map[*Item]bool) // Items span several slots, so count each only once
+	for _, item := range c.Inventory {
+		if item != nil && !counted[item] {
+			mod += float64(item.TraitMods[trait])
+			counted[item] = true
+		}
+	}
+	return mod
+}
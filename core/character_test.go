@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFromDataRecoversDanglingRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		rooms   []int64
+		roomID  int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "stored room exists", rooms: []int64{0, 1, 5}, roomID: 5, want: 5},
+		{name: "dangling room uses the void", rooms: []int64{0, 1}, roomID: 99, want: 0},
+		{name: "dangling room without a void uses the start room", rooms: []int64{1}, roomID: 99, want: 1},
+		{name: "no room to recover to", rooms: []int64{2}, roomID: 99, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.rooms...)
+			data := &CharacterData{
+				CharacterID:   uuid.New().String(),
+				CharacterName: "Wanderer",
+				Health:        10,
+				RoomID:        tt.roomID,
+			}
+
+			character := &Character{Server: server}
+			err := character.FromData(data, server)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FromData() placed the character in room %d, want an error", character.Room.RoomID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromData() error = %v", err)
+			}
+			if character.Room == nil || character.Room.RoomID != tt.want {
+				t.Errorf("FromData() placed the character in %v, want room %d", character.Room, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCharacter(t *testing.T) {
+	tests := []struct {
+		name          string
+		archetypes    map[string]*Archetype
+		input         []string
+		wantArchetype string
+		wantHealth    float64
+		wantEssence   float64
+		wantRoom      int64
+		wantStrength  float64
+	}{
+		{
+			name:        "default",
+			input:       []string{"Aldric"},
+			wantHealth:  10,
+			wantEssence: 3,
+			wantRoom:    1,
+		},
+		{
+			name: "archetype",
+			archetypes: map[string]*Archetype{
+				"warrior": {
+					ArchetypeName: "warrior",
+					Description:   "A seasoned fighter",
+					Attributes:    map[string]float64{"Strength": 3},
+					StartHealth:   20,
+					StartRoom:     2,
+				},
+			},
+			input:         []string{"Brenna", "1"},
+			wantArchetype: "warrior",
+			wantHealth:    20,
+			wantEssence:   3,
+			wantRoom:      2,
+			wantStrength:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, 0, 1, 2)
+			for name, archetype := range tt.archetypes {
+				server.ArcheTypes[name] = archetype
+			}
+			player := newTestPlayer(t, "creator@example.com", tt.input...)
+
+			type result struct {
+				character *Character
+				err       error
+			}
+			done := make(chan result, 1)
+			go func() {
+				character, err := server.CreateCharacter(player)
+				done <- result{character, err}
+			}()
+
+			var got result
+			select {
+			case got = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("CreateCharacter did not return, the server mutex may be held while the character is created")
+			}
+
+			if got.err != nil {
+				t.Fatalf("CreateCharacter() error = %v", got.err)
+			}
+			character := got.character
+			if character.Archetype != tt.wantArchetype {
+				t.Errorf("Archetype = %q, want %q", character.Archetype, tt.wantArchetype)
+			}
+			if character.Health != tt.wantHealth || character.MaxHealth != tt.wantHealth {
+				t.Errorf("Health = %v/%v, want %v", character.Health, character.MaxHealth, tt.wantHealth)
+			}
+			if character.Essence != tt.wantEssence || character.MaxEssence != tt.wantEssence {
+				t.Errorf("Essence = %v/%v, want %v", character.Essence, character.MaxEssence, tt.wantEssence)
+			}
+			if character.Room == nil || character.Room.RoomID != tt.wantRoom {
+				t.Errorf("Room = %v, want room %d", character.Room, tt.wantRoom)
+			}
+			if character.Attributes["Strength"] != tt.wantStrength {
+				t.Errorf("Strength = %v, want %v", character.Attributes["Strength"], tt.wantStrength)
+			}
+			if player.CharacterList[character.Name] != character.ID {
+				t.Errorf("CharacterList[%q] = %v, want %v", character.Name, player.CharacterList[character.Name], character.ID)
+			}
+			if taken, err := server.CharacterNameTaken(character.Name); err != nil || !taken {
+				t.Errorf("CharacterNameTaken(%q) = %v, %v, want true", character.Name, taken, err)
+			}
+		})
+	}
+}
+
+func TestNewCharacterUnknownArchetype(t *testing.T) {
+	server := newTestServer(t, 1)
+	player := newTestPlayer(t, "creator@example.com")
+
+	if _, err := server.NewCharacter("Dagny", player, server.Rooms[1], "necromancer"); err == nil {
+		t.Fatal("NewCharacter() with an unknown archetype succeeded, want an error")
+	}
+}
+
+func TestLoadCharacterOwnership(t *testing.T) {
+	server := newTestServer(t, 0, 1)
+	owner := newTestPlayer(t, "owner@example.com")
+
+	character, err := server.NewCharacter("Corwin", owner, server.Rooms[1], "")
+	if err != nil {
+		t.Fatalf("NewCharacter() error = %v", err)
+	}
+	if err := server.Database.WriteCharacter(context.Background(), character); err != nil {
+		t.Fatalf("WriteCharacter() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		player  *Player
+		wantErr bool
+	}{
+		{name: "owner", player: &Player{PlayerID: "owner@example.com"}},
+		{name: "another player", player: &Player{PlayerID: "thief@example.com"}, wantErr: true},
+		{name: "no player", player: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := server.Database.LoadCharacter(context.Background(), character.ID, tt.player, server)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadCharacter() handed %s to %v, want an error", loaded.Name, tt.player)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCharacter() error = %v", err)
+			}
+			if loaded.Name != character.Name {
+				t.Errorf("LoadCharacter() loaded %q, want %q", loaded.Name, character.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// ErrCharacterInPlay is returned when an operation needs a character that is logged out.
+var ErrCharacterInPlay = errors.New("character is in play")
+
+// RenameCharacter gives a stored character a new name. The name claim moves in the name index
+// and the owning player's character list follows. The caller validates the new name and makes
+// sure the character is not in play, whose next save would otherwise restore the old name.
+func (kp *KeyPair) RenameCharacter(ctx context.Context, oldName, newName string) (*CharacterData, error) {
+	cd, err := kp.FindCharacterData(ctx, oldName)
+	if err != nil {
+		return nil, err
+	}
+	oldName = cd.CharacterName
+
+	characterID, err := uuid.Parse(cd.CharacterID)
+	if err != nil {
+		return nil, fmt.Errorf("parse character ID: %w", err)
+	}
+
+	// A change of case keeps the same claim in the name index
+	sameKey := strings.EqualFold(oldName, newName)
+	if !sameKey {
+		if err := kp.ClaimCharacterName(ctx, newName, characterID, cd.PlayerID); err != nil {
+			return nil, err
+		}
+	}
+
+	set := map[string]ddbtypes.AttributeValue{
+		"Name": &ddbtypes.AttributeValueMemberS{Value: newName},
+	}
+	if err := kp.updateCharacterRecord(ctx, cd, set); err != nil {
+		if !sameKey {
+			if releaseErr := kp.ReleaseCharacterName(ctx, newName); releaseErr != nil {
+				Logger.Warn("Failed to release name of failed rename", "characterName", newName, "error", releaseErr)
+			}
+		}
+		return nil, err
+	}
+	cd.CharacterName = newName
+
+	err = kp.updateCharacterList(ctx, cd.PlayerID, func(list map[string]uuid.UUID) {
+		delete(list, oldName)
+		list[newName] = characterID
+	})
+	if err != nil {
+		return cd, fmt.Errorf("character renamed but the character list of player %s was not updated: %w", cd.PlayerID, err)
+	}
+
+	if sameKey {
+		err = kp.indexCharacterName(ctx, cd)
+	} else {
+		err = kp.ReleaseCharacterName(ctx, oldName)
+	}
+	if err != nil {
+		return cd, fmt.Errorf("character renamed but the name index was not updated: %w", err)
+	}
+
+	Logger.Info("Renamed character", "oldName", oldName, "characterName", newName, "characterID", cd.CharacterID)
+	return cd, nil
+}
+
+// TransferCharacter moves a stored character to another player's account. As with
+// RenameCharacter, the character must not be in play.
+func (kp *KeyPair) TransferCharacter(ctx context.Context, name, playerID string) (*CharacterData, error) {
+	cd, err := kp.FindCharacterData(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if cd.PlayerID == playerID {
+		return nil, fmt.Errorf("character %s already belongs to player %s", cd.CharacterName, playerID)
+	}
+
+	characterID, err := uuid.Parse(cd.CharacterID)
+	if err != nil {
+		return nil, fmt.Errorf("parse character ID: %w", err)
+	}
+
+	// Make sure the new owner exists before anything changes
+	target, err := kp.ReadPlayer(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("player %s not found", playerID)
+	}
+
+	previousOwner := cd.PlayerID
+	set := map[string]ddbtypes.AttributeValue{
+		"PlayerID": &ddbtypes.AttributeValueMemberS{Value: playerID},
+	}
+	if err := kp.updateCharacterRecord(ctx, cd, set); err != nil {
+		return nil, err
+	}
+	cd.PlayerID = playerID
+
+	target.CharacterList[cd.CharacterName] = characterID
+	if err := kp.WritePlayer(ctx, target); err != nil {
+		return cd, fmt.Errorf("character transferred but the character list of player %s was not updated: %w", playerID, err)
+	}
+
+	err = kp.updateCharacterList(ctx, previousOwner, func(list map[string]uuid.UUID) {
+		delete(list, cd.CharacterName)
+	})
+	if err != nil {
+		return cd, fmt.Errorf("character transferred but the character list of player %s was not updated: %w", previousOwner, err)
+	}
+
+	if err := kp.indexCharacterName(ctx, cd); err != nil {
+		return cd, fmt.Errorf("character transferred but the name index was not updated: %w", err)
+	}
+
+	Logger.Info("Transferred character", "characterName", cd.CharacterName, "characterID", cd.CharacterID, "from", previousOwner, "to", playerID)
+	return cd, nil
+}
+
+// updateCharacterRecord sets attributes of a stored character as its next version.
+func (kp *KeyPair) updateCharacterRecord(ctx context.Context, cd *CharacterData, set map[string]ddbtypes.AttributeValue) error {
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: cd.CharacterID},
+	}
+
+	version := cd.Version + 1
+	err := kp.writeVersioned(ctx, "characters", key, &version, func() error {
+		set["Version"] = &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)}
+		set[OwnerAttribute] = kp.ownerValue()
+		return kp.Update(ctx, "characters", key, set, nil)
+	})
+	if err != nil {
+		Logger.Error("Error updating character data", "characterName", cd.CharacterName, "error", err)
+		return fmt.Errorf("error updating character data: %w", err)
+	}
+
+	cd.Version = version
+	return nil
+}
+
+// updateCharacterList applies update to the stored character list of a player.
+func (kp *KeyPair) updateCharacterList(ctx context.Context, playerID string, update func(list map[string]uuid.UUID)) error {
+	player, err := kp.ReadPlayer(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	update(player.CharacterList)
+	return kp.WritePlayer(ctx, player)
+}
+
+// indexCharacterName rewrites the name index entry of a character whose name key is unchanged.
+func (kp *KeyPair) indexCharacterName(ctx context.Context, cd *CharacterData) error {
+	record := CharacterNameRecord{
+		NameKey:     strings.ToLower(cd.CharacterName),
+		Name:        cd.CharacterName,
+		CharacterID: cd.CharacterID,
+		PlayerID:    cd.PlayerID,
+		Version:     1,
+	}
+	if err := kp.Put(ctx, "character_names", record); err != nil {
+		return fmt.Errorf("error indexing character name %s: %w", cd.CharacterName, err)
+	}
+	return nil
+}
+
+// updateOnlineCharacterList applies update to the character list of every live player for the
+// account, including one at the character select screen, so their next save does not write back
+// the list as it was.
+func (s *Server) updateOnlineCharacterList(playerID string, update func(list map[string]uuid.UUID)) {
+	for _, player := range s.LivePlayers(playerID) {
+		player.Mutex.Lock()
+		if player.CharacterList == nil {
+			player.CharacterList = make(map[string]uuid.UUID)
+		}
+		update(player.CharacterList)
+		player.Mutex.Unlock()
+	}
+}
+
+// RenameCharacter renames a logged out character after checking the new name.
+func (s *Server) RenameCharacter(oldName, newName string) (*CharacterData, error) {
+	if s.FindOnlineCharacter(oldName) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCharacterInPlay, oldName)
+	}
+
+	newName, err := s.ValidateCharacterName(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	cd, err := s.Database.RenameCharacter(s.Context, oldName, newName)
+	if cd == nil {
+		return nil, err
+	}
+
+	s.AddCharacterName(cd.CharacterName)
+	characterID, parseErr := uuid.Parse(cd.CharacterID)
+	if parseErr == nil {
+		s.updateOnlineCharacterList(cd.PlayerID, func(list map[string]uuid.UUID) {
+			for name, id := range list {
+				if id == characterID {
+					delete(list, name)
+				}
+			}
+			list[cd.CharacterName] = characterID
+		})
+	}
+	return cd, err
+}
+
+// TransferCharacter moves a logged out character to another player's account.
+func (s *Server) TransferCharacter(name, playerID string) (*CharacterData, error) {
+	if s.FindOnlineCharacter(name) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCharacterInPlay, name)
+	}
+
+	previous, err := s.Database.FindCharacterData(s.Context, name)
+	if err != nil {
+		return nil, err
+	}
+
+	cd, err := s.Database.TransferCharacter(s.Context, name, playerID)
+	if cd == nil {
+		return nil, err
+	}
+
+	characterID, parseErr := uuid.Parse(cd.CharacterID)
+	if parseErr == nil {
+		s.updateOnlineCharacterList(previous.PlayerID, func(list map[string]uuid.UUID) {
+			delete(list, cd.CharacterName)
+		})
+		s.updateOnlineCharacterList(cd.PlayerID, func(list map[string]uuid.UUID) {
+			list[cd.CharacterName] = characterID
+		})
+	}
+	return cd, err
+}
+
+func ExecuteRenameCharacterCommand(character *Character, tokens []string) bool {
+
+	if len(tokens) != 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @renamechar <character> <new name>\n\r"
+		return false
+	}
+
+	cd, err := character.Server.RenameCharacter(tokens[1], tokens[2])
+	var nameErr *NameError
+	switch {
+	case errors.As(err, &nameErr):
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", nameErr.Reason)
+		return false
+	case errors.Is(err, ErrCharacterInPlay):
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is in play. Rename them once they have logged out.\n\r", tokens[1])
+		return false
+	case errors.Is(err, ErrNameTaken):
+		character.Player.ToPlayer <- "\n\rThat name is already taken.\n\r"
+		return false
+	case cd == nil:
+		Logger.Error("Error renaming character", "playerName", character.Player.PlayerID, "target", tokens[1], "error", err)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUnable to rename %s: %v\n\r", tokens[1], err)
+		return false
+	case err != nil:
+		Logger.Error("Character rename was incomplete", "playerName", character.Player.PlayerID, "target", tokens[1], "error", err)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s was renamed %s, but %v\n\r", tokens[1], cd.CharacterName, err)
+		return false
+	}
+
+	Logger.Info("Admin renamed character", "playerName", character.Player.PlayerID, "target", tokens[1], "characterName", cd.CharacterName)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is now named %s.\n\r", tokens[1], cd.CharacterName)
+	return false
+}
+
+func ExecuteTransferCommand(character *Character, tokens []string) bool {
+
+	if len(tokens) != 3 {
+		character.Player.ToPlayer <- "\n\rUsage: @transfer <character> <player>\n\r"
+		return false
+	}
+
+	cd, err := character.Server.TransferCharacter(tokens[1], tokens[2])
+	switch {
+	case errors.Is(err, ErrCharacterInPlay):
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is in play. Transfer them once they have logged out.\n\r", tokens[1])
+		return false
+	case cd == nil:
+		Logger.Error("Error transferring character", "playerName", character.Player.PlayerID, "target", tokens[1], "error", err)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUnable to transfer %s: %v\n\r", tokens[1], err)
+		return false
+	case err != nil:
+		Logger.Error("Character transfer was incomplete", "playerName", character.Player.PlayerID, "target", tokens[1], "error", err)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s was moved to %s, but %v\n\r", cd.CharacterName, tokens[2], err)
+		return false
+	}
+
+	Logger.Info("Admin transferred character", "playerName", character.Player.PlayerID, "characterName", cd.CharacterName, "to", cd.PlayerID)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s now belongs to %s.\n\r", cd.CharacterName, cd.PlayerID)
+	return false
+}
@@ -0,0 +1,358 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cwlogtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// Limits on a PutLogEvents call, from the CloudWatch Logs service quotas. Each event counts its
+// message in bytes plus a fixed overhead against the batch size.
+const (
+	cloudWatchMaxBatchEvents = 10000
+	cloudWatchMaxBatchBytes  = 1048576
+	cloudWatchEventOverhead  = 26
+	cloudWatchMaxEventBytes  = 262144 - cloudWatchEventOverhead
+	cloudWatchMaxBatchSpan   = 24 * time.Hour
+)
+
+// Tuning for the CloudWatch log writer.
+const (
+	cloudWatchBufferSize        = 10000            // records waiting to be sent before new ones go to stdout
+	cloudWatchFlushInterval     = 5 * time.Second  // longest a record waits before its batch is sent
+	cloudWatchRequestTimeout    = 10 * time.Second // bound on each CloudWatch call
+	cloudWatchMaxAttempts       = 5                // tries per batch before it is written to stdout
+	cloudWatchRetryDelay        = 500 * time.Millisecond
+	cloudWatchMaxRetryDelay     = 15 * time.Second
+	cloudWatchUnavailablePeriod = time.Minute // how long batches skip CloudWatch after one fails
+)
+
+// cloudWatchLogs is the writer behind the server's CloudWatch handler, kept so that shutdown can
+// flush it.
+var cloudWatchLogs *cloudWatchWriter
+
+// cloudWatchWriter ships log events to a CloudWatch Logs stream from a single goroutine. Records
+// are queued by CloudWatchHandler without waiting on the network and sent in batches, so logging
+// never blocks a game goroutine and one stream sees one writer in timestamp order. Events that
+// cannot be delivered are written to stdout instead of being lost.
+type cloudWatchWriter struct {
+	client    *cloudwatchlogs.Client
+	logGroup  string
+	logStream string
+
+	events  chan cwlogtypes.InputLogEvent
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	// Only used by the run goroutine
+	batch            []cwlogtypes.InputLogEvent
+	batchBytes       int
+	initialized      bool
+	unavailableUntil time.Time
+}
+
+func newCloudWatchWriter(client *cloudwatchlogs.Client, logGroup, logStream string) *cloudWatchWriter {
+	w := &cloudWatchWriter{
+		client:    client,
+		logGroup:  logGroup,
+		logStream: logStream,
+		events:    make(chan cwlogtypes.InputLogEvent, cloudWatchBufferSize),
+		flushes:   make(chan chan struct{}),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue queues an event for the next batch. When the queue is full or the writer has stopped
+// the event is written to stdout straight away.
+func (w *cloudWatchWriter) enqueue(event cwlogtypes.InputLogEvent) {
+	select {
+	case <-w.stop:
+		w.fallback([]cwlogtypes.InputLogEvent{event}, errors.New("log writer closed"))
+		return
+	default:
+	}
+
+	select {
+	case w.events <- event:
+	default:
+		w.fallback([]cwlogtypes.InputLogEvent{event}, errors.New("log buffer full"))
+	}
+}
+
+func (w *cloudWatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(cloudWatchFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-w.events:
+			w.add(event)
+		case <-ticker.C:
+			w.send()
+		case reply := <-w.flushes:
+			w.drain()
+			close(reply)
+		case <-w.stop:
+			w.drain()
+			return
+		}
+	}
+}
+
+// add puts an event in the current batch, sending the batch first when the event would take it
+// over the CloudWatch limits.
+func (w *cloudWatchWriter) add(event cwlogtypes.InputLogEvent) {
+	size := len(aws.ToString(event.Message)) + cloudWatchEventOverhead
+	if len(w.batch) > 0 {
+		first := time.UnixMilli(aws.ToInt64(w.batch[0].Timestamp))
+		span := time.UnixMilli(aws.ToInt64(event.Timestamp)).Sub(first)
+		if len(w.batch) >= cloudWatchMaxBatchEvents || w.batchBytes+size > cloudWatchMaxBatchBytes || span >= cloudWatchMaxBatchSpan {
+			w.send()
+		}
+	}
+	w.batch = append(w.batch, event)
+	w.batchBytes += size
+}
+
+// drain batches every queued event and sends what it has.
+func (w *cloudWatchWriter) drain() {
+	for {
+		select {
+		case event := <-w.events:
+			w.add(event)
+		default:
+			w.send()
+			return
+		}
+	}
+}
+
+// send delivers the current batch, retrying with exponential backoff. A batch that cannot be
+// delivered goes to stdout, and CloudWatch is left alone for a while so that an outage does
+// not hold up every later batch with retries.
+func (w *cloudWatchWriter) send() {
+	if len(w.batch) == 0 {
+		return
+	}
+	events := w.batch
+	w.batch = nil
+	w.batchBytes = 0
+
+	if time.Now().Before(w.unavailableUntil) {
+		w.fallback(events, errors.New("CloudWatch Logs unavailable"))
+		return
+	}
+
+	// Records from different goroutines can reach the queue slightly out of order, and
+	// CloudWatch requires a batch in timestamp order
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToInt64(events[i].Timestamp) < aws.ToInt64(events[j].Timestamp)
+	})
+
+	var err error
+	delay := cloudWatchRetryDelay
+	for attempt := 1; attempt <= cloudWatchMaxAttempts; attempt++ {
+		if err = w.put(events); err == nil {
+			w.unavailableUntil = time.Time{}
+			return
+		}
+
+		var invalid *cwlogtypes.InvalidParameterException
+		if errors.As(err, &invalid) || attempt == cloudWatchMaxAttempts {
+			break
+		}
+
+		time.Sleep(delay)
+		delay = min(delay*2, cloudWatchMaxRetryDelay)
+	}
+
+	w.unavailableUntil = time.Now().Add(cloudWatchUnavailablePeriod)
+	w.fallback(events, err)
+}
+
+// put makes one PutLogEvents call, creating the log stream first if needed.
+func (w *cloudWatchWriter) put(events []cwlogtypes.InputLogEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudWatchRequestTimeout)
+	defer cancel()
+
+	if err := w.initializeLogStream(ctx); err != nil {
+		return err
+	}
+
+	output, err := w.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(w.logGroup),
+		LogStreamName: aws.String(w.logStream),
+		LogEvents:     events,
+	})
+	if err != nil {
+		// The stream may have been deleted since it was checked
+		var notFound *cwlogtypes.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			w.initialized = false
+		}
+		return fmt.Errorf("failed to put log events: %w", err)
+	}
+
+	if rejected := output.RejectedLogEventsInfo; rejected != nil {
+		fmt.Printf("CloudWatch rejected log events: tooNewStartIndex=%d tooOldEndIndex=%d expiredEndIndex=%d\n",
+			aws.ToInt32(rejected.TooNewLogEventStartIndex), aws.ToInt32(rejected.TooOldLogEventEndIndex), aws.ToInt32(rejected.ExpiredLogEventEndIndex))
+	}
+	return nil
+}
+
+func (w *cloudWatchWriter) initializeLogStream(ctx context.Context) error {
+	if w.initialized {
+		return nil
+	}
+
+	output, err := w.client.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName:        aws.String(w.logGroup),
+		LogStreamNamePrefix: aws.String(w.logStream),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe log streams: %w", err)
+	}
+
+	// The prefix also matches longer stream names
+	for _, stream := range output.LogStreams {
+		if aws.ToString(stream.LogStreamName) == w.logStream {
+			w.initialized = true
+			return nil
+		}
+	}
+
+	_, err = w.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(w.logGroup),
+		LogStreamName: aws.String(w.logStream),
+	})
+	var exists *cwlogtypes.ResourceAlreadyExistsException
+	if err != nil && !errors.As(err, &exists) {
+		return fmt.Errorf("failed to create log stream: %w", err)
+	}
+
+	w.initialized = true
+	return nil
+}
+
+// fallback writes events that did not reach CloudWatch to stdout as JSON lines marked as
+// undelivered, so they can still be collected from the server's output.
+func (w *cloudWatchWriter) fallback(events []cwlogtypes.InputLogEvent, reason error) {
+	for _, event := range events {
+		line, err := json.Marshal(map[string]interface{}{
+			"time":       time.UnixMilli(aws.ToInt64(event.Timestamp)).Format(time.RFC3339Nano),
+			"msg":        aws.ToString(event.Message),
+			"cloudwatch": "undelivered",
+			"reason":     fmt.Sprint(reason),
+		})
+		if err != nil {
+			continue
+		}
+		fmt.Fprintln(os.Stdout, string(line))
+	}
+}
+
+// Flush sends every queued event and waits until it has been delivered or written to stdout,
+// or until ctx is done.
+func (w *cloudWatchWriter) Flush(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case w.flushes <- reply:
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close sends the queued events and stops the writer. Records logged afterwards go to stdout.
+func (w *cloudWatchWriter) Close(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseLogging delivers the log records still waiting for CloudWatch and stops shipping them.
+// It is called last during shutdown, once nothing more of interest will be logged.
+func CloseLogging(ctx context.Context) error {
+	if cloudWatchLogs == nil {
+		return nil
+	}
+	return cloudWatchLogs.Close(ctx)
+}
+
+func NewCloudWatchHandler(client *cloudwatchlogs.Client, logGroup, logStream string) *CloudWatchHandler {
+	return &CloudWatchHandler{
+		writer: newCloudWatchWriter(client, logGroup, logStream),
+	}
+}
+
+func (h *CloudWatchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle queues the record for the writer and returns without waiting for CloudWatch.
+func (h *CloudWatchHandler) Handle(ctx context.Context, r slog.Record) error {
+	message := r.Level.String() + " " + r.Message
+	for _, attr := range h.attrs {
+		message += fmt.Sprintf(" %s=%v", attr.Key, attr.Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		message += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+		return true
+	})
+
+	if len(message) > cloudWatchMaxEventBytes {
+		message = message[:cloudWatchMaxEventBytes]
+	}
+
+	timestamp := r.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	h.writer.enqueue(cwlogtypes.InputLogEvent{
+		Message:   aws.String(message),
+		Timestamp: aws.Int64(timestamp.UnixMilli()),
+	})
+	return nil
+}
+
+func (h *CloudWatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CloudWatchHandler{
+		writer: h.writer,
+		attrs:  append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h *CloudWatchHandler) WithGroup(name string) slog.Handler {
+	return h
+}
@@ -1,15 +1,17 @@
 package core
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	cognitotypes "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 )
 
 func calculateSecretHash(cognitoAppClientID, clientSecret, email string) string {
@@ -22,49 +24,106 @@ func calculateSecretHash(cognitoAppClientID, clientSecret, email string) string
 	return encodedMessage
 }
 
+// newCognitoClient creates a Cognito Identity Provider client for the configured region.
+func newCognitoClient(ctx context.Context, region string) (*cognitoidentityprovider.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("load AWS SDK config: %w", err)
+	}
+	instrumentAWS(&cfg)
+	return cognitoidentityprovider.NewFromConfig(cfg), nil
+}
+
+// ErrAccountExists is returned by SignUpUser when the email already has an account.
+var ErrAccountExists = errors.New("an account with that email already exists")
+
 func handleCognitoError(err error, email string) error {
-	if awsErr, ok := err.(awserr.Error); ok {
-		switch awsErr.Code() {
-		case cognitoidentityprovider.ErrCodeNotAuthorizedException:
-			return fmt.Errorf("incorrect username or password")
-		case cognitoidentityprovider.ErrCodeUserNotConfirmedException:
-			return fmt.Errorf("user is not confirmed")
-		case cognitoidentityprovider.ErrCodePasswordResetRequiredException:
-			return fmt.Errorf("password reset required")
-		default:
-			return fmt.Errorf("authentication failed for user %s: %w", email, awsErr)
-		}
+	if playerErr := cognitoPlayerError(err); playerErr != nil {
+		return playerErr
 	}
-	return fmt.Errorf("unexpected error during authentication for user %s: %w", email, err)
+	return fmt.Errorf("authentication failed for user %s: %w", email, err)
+}
+
+// cognitoPlayerError returns a message fit to show a player for the Cognito errors they can act on,
+// or nil for anything else.
+func cognitoPlayerError(err error) error {
+	var notAuthorized *cognitotypes.NotAuthorizedException
+	var notConfirmed *cognitotypes.UserNotConfirmedException
+	var resetRequired *cognitotypes.PasswordResetRequiredException
+	var usernameExists *cognitotypes.UsernameExistsException
+	var invalidPassword *cognitotypes.InvalidPasswordException
+	var invalidParameter *cognitotypes.InvalidParameterException
+	var codeMismatch *cognitotypes.CodeMismatchException
+	var expiredCode *cognitotypes.ExpiredCodeException
+	var userNotFound *cognitotypes.UserNotFoundException
+	var limitExceeded *cognitotypes.LimitExceededException
+	var tooManyAttempts *cognitotypes.TooManyFailedAttemptsException
+	var deliveryFailed *cognitotypes.CodeDeliveryFailureException
+
+	switch {
+	case errors.As(err, &notAuthorized), errors.As(err, &userNotFound):
+		// Unknown users get the same message so accounts cannot be discovered
+		return fmt.Errorf("incorrect username or password")
+	case errors.As(err, &notConfirmed):
+		return fmt.Errorf("user is not confirmed")
+	case errors.As(err, &resetRequired):
+		return fmt.Errorf("password reset required")
+	case errors.As(err, &usernameExists):
+		return ErrAccountExists
+	case errors.As(err, &invalidPassword):
+		return fmt.Errorf("password does not meet the requirements: %s", invalidPassword.ErrorMessage())
+	case errors.As(err, &invalidParameter):
+		return fmt.Errorf("invalid email address or password")
+	case errors.As(err, &codeMismatch):
+		return fmt.Errorf("incorrect confirmation code")
+	case errors.As(err, &expiredCode):
+		return fmt.Errorf("confirmation code has expired")
+	case errors.As(err, &limitExceeded), errors.As(err, &tooManyAttempts):
+		return fmt.Errorf("too many attempts, please wait a while and try again")
+	case errors.As(err, &deliveryFailed):
+		return fmt.Errorf("the code could not be sent, please try again later")
+	}
+	return nil
+}
+
+// codeError maps an error from confirming a code sent by email. Unknown users and accounts that
+// cannot take the code get the same message as a wrong code, so the answer never shows whether
+// an account exists.
+func codeError(err error) error {
+	var notAuthorized *cognitotypes.NotAuthorizedException
+	var userNotFound *cognitotypes.UserNotFoundException
+	if errors.As(err, &notAuthorized) || errors.As(err, &userNotFound) {
+		return fmt.Errorf("incorrect confirmation code")
+	}
+	return cognitoPlayerError(err)
 }
 
 // SignInUser attempts to sign in a user with the provided credentials
-func SignInUser(email, password string, config Configuration) (*cognitoidentityprovider.InitiateAuthOutput, error) {
-	sess, err := session.NewSession(&aws.Config{Region: aws.String(config.Aws.Region)})
+func SignInUser(ctx context.Context, email, password string, config Configuration) (*cognitoidentityprovider.InitiateAuthOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
 	if err != nil {
-		return nil, fmt.Errorf("create AWS session: %w", err)
+		return nil, err
 	}
 
-	cognitoClient := cognitoidentityprovider.New(sess)
 	secretHash := calculateSecretHash(config.Cognito.ClientID, config.Cognito.ClientSecret, email)
 
 	authInput := &cognitoidentityprovider.InitiateAuthInput{
-		AuthFlow: aws.String(cognitoidentityprovider.AuthFlowTypeUserPasswordAuth),
-		AuthParameters: map[string]*string{
-			"USERNAME":    aws.String(email),
-			"PASSWORD":    aws.String(password),
-			"SECRET_HASH": aws.String(secretHash),
+		AuthFlow: cognitotypes.AuthFlowTypeUserPasswordAuth,
+		AuthParameters: map[string]string{
+			"USERNAME":    email,
+			"PASSWORD":    password,
+			"SECRET_HASH": secretHash,
 		},
 		ClientId: aws.String(config.Cognito.ClientID),
 	}
 
-	authOutput, err := cognitoClient.InitiateAuth(authInput)
+	authOutput, err := cognitoClient.InitiateAuth(ctx, authInput)
 	if err != nil {
 		return nil, handleCognitoError(err, email)
 	}
 
 	// Check for NEW_PASSWORD_REQUIRED challenge
-	if authOutput.ChallengeName != nil && *authOutput.ChallengeName == cognitoidentityprovider.ChallengeNameTypeNewPasswordRequired {
+	if authOutput.ChallengeName == cognitotypes.ChallengeNameTypeNewPasswordRequired {
 		return authOutput, nil // Return the challenge, not an error
 	}
 
@@ -75,14 +134,13 @@ func SignInUser(email, password string, config Configuration) (*cognitoidentityp
 	return authOutput, nil
 }
 
-func SignUpUser(email, password string, config Configuration) (*cognitoidentityprovider.SignUpOutput, error) {
-	sess, err := session.NewSession(&aws.Config{Region: aws.String(config.Aws.Region)})
+func SignUpUser(ctx context.Context, email, password string, config Configuration) (*cognitoidentityprovider.SignUpOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
 	if err != nil {
-		Logger.Error("Error creating AWS session for sign-up", "error", err)
-		return nil, fmt.Errorf("an internal error occurred while creating AWS session")
+		Logger.Error("Error creating Cognito client for sign-up", "error", err)
+		return nil, fmt.Errorf("an internal error occurred while creating Cognito client")
 	}
 
-	cognitoClient := cognitoidentityprovider.New(sess)
 	secretHash := calculateSecretHash(config.Cognito.ClientID, config.Cognito.ClientSecret, email)
 
 	signUpInput := &cognitoidentityprovider.SignUpInput{
@@ -90,28 +148,30 @@ func SignUpUser(email, password string, config Configuration) (*cognitoidentityp
 		Username:   aws.String(email),
 		Password:   aws.String(password),
 		SecretHash: aws.String(secretHash),
-		UserAttributes: []*cognitoidentityprovider.AttributeType{
+		UserAttributes: []cognitotypes.AttributeType{
 			{Name: aws.String("email"), Value: aws.String(email)},
 		},
 	}
 
-	signUpOutput, err := cognitoClient.SignUp(signUpInput)
+	signUpOutput, err := cognitoClient.SignUp(ctx, signUpInput)
 	if err != nil {
 		Logger.Error("Error signing up user with Cognito", "email", email, "error", err)
+		if playerErr := cognitoPlayerError(err); playerErr != nil {
+			return nil, playerErr
+		}
 		return nil, fmt.Errorf("error signing up, please try again")
 	}
 
 	return signUpOutput, nil
 }
 
-func ConfirmUser(email, confirmationCode string, config Configuration) (*cognitoidentityprovider.ConfirmSignUpOutput, error) {
-	sess, err := session.NewSession(&aws.Config{Region: aws.String(config.Aws.Region)})
+func ConfirmUser(ctx context.Context, email, confirmationCode string, config Configuration) (*cognitoidentityprovider.ConfirmSignUpOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
 	if err != nil {
-		Logger.Error("Error creating AWS session for user confirmation", "error", err)
-		return nil, fmt.Errorf("an internal error occurred while creating AWS session")
+		Logger.Error("Error creating Cognito client for user confirmation", "error", err)
+		return nil, fmt.Errorf("an internal error occurred while creating Cognito client")
 	}
 
-	cognitoClient := cognitoidentityprovider.New(sess)
 	secretHash := calculateSecretHash(config.Cognito.ClientID, config.Cognito.ClientSecret, email)
 
 	confirmSignUpInput := &cognitoidentityprovider.ConfirmSignUpInput{
@@ -121,26 +181,114 @@ func ConfirmUser(email, confirmationCode string, config Configuration) (*cognito
 		SecretHash:       aws.String(secretHash),
 	}
 
-	confirmSignUpOutput, err := cognitoClient.ConfirmSignUp(confirmSignUpInput)
+	confirmSignUpOutput, err := cognitoClient.ConfirmSignUp(ctx, confirmSignUpInput)
 	if err != nil {
 		Logger.Error("Error confirming sign-up for user", "email", email, "error", err)
+		if playerErr := codeError(err); playerErr != nil {
+			return nil, playerErr
+		}
 		return nil, fmt.Errorf("error confirming sign up, please check your code and try again")
 	}
 
 	return confirmSignUpOutput, nil
 }
 
-func GetUserData(accessToken string, config Configuration) (*cognitoidentityprovider.GetUserOutput, error) {
-	sess, err := session.NewSession(&aws.Config{Region: aws.String(config.Aws.Region)})
+// ForgotPassword asks Cognito to email the user a code for resetting their password. An unknown
+// user is not an error, so the caller carries on exactly as though a code had been sent.
+func ForgotPassword(ctx context.Context, email string, config Configuration) (*cognitoidentityprovider.ForgotPasswordOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
 	if err != nil {
-		Logger.Error("Error creating AWS session for getting user data", "error", err)
-		return nil, fmt.Errorf("an internal error occurred while creating AWS session")
+		Logger.Error("Error creating Cognito client for password reset", "error", err)
+		return nil, fmt.Errorf("an internal error occurred while creating Cognito client")
 	}
 
-	cognitoClient := cognitoidentityprovider.New(sess)
+	secretHash := calculateSecretHash(config.Cognito.ClientID, config.Cognito.ClientSecret, email)
+
+	forgotPasswordInput := &cognitoidentityprovider.ForgotPasswordInput{
+		ClientId:   aws.String(config.Cognito.ClientID),
+		Username:   aws.String(email),
+		SecretHash: aws.String(secretHash),
+	}
+
+	forgotPasswordOutput, err := cognitoClient.ForgotPassword(ctx, forgotPasswordInput)
+	var userNotFound *cognitotypes.UserNotFoundException
+	if errors.As(err, &userNotFound) {
+		Logger.Info("Password reset requested for an unknown user", "email", email)
+		return &cognitoidentityprovider.ForgotPasswordOutput{}, nil
+	}
+	if err != nil {
+		Logger.Error("Error requesting password reset for user", "email", email, "error", err)
+		if playerErr := cognitoPlayerError(err); playerErr != nil {
+			return nil, playerErr
+		}
+		return nil, fmt.Errorf("error requesting a password reset, please try again")
+	}
+
+	return forgotPasswordOutput, nil
+}
+
+// ConfirmForgotPassword sets a new password using the code sent by ForgotPassword.
+func ConfirmForgotPassword(ctx context.Context, email, confirmationCode, newPassword string, config Configuration) (*cognitoidentityprovider.ConfirmForgotPasswordOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
+	if err != nil {
+		Logger.Error("Error creating Cognito client for password reset confirmation", "error", err)
+		return nil, fmt.Errorf("an internal error occurred while creating Cognito client")
+	}
+
+	secretHash := calculateSecretHash(config.Cognito.ClientID, config.Cognito.ClientSecret, email)
+
+	confirmInput := &cognitoidentityprovider.ConfirmForgotPasswordInput{
+		ClientId:         aws.String(config.Cognito.ClientID),
+		Username:         aws.String(email),
+		ConfirmationCode: aws.String(confirmationCode),
+		Password:         aws.String(newPassword),
+		SecretHash:       aws.String(secretHash),
+	}
+
+	confirmOutput, err := cognitoClient.ConfirmForgotPassword(ctx, confirmInput)
+	if err != nil {
+		Logger.Error("Error confirming password reset for user", "email", email, "error", err)
+		if playerErr := codeError(err); playerErr != nil {
+			return nil, playerErr
+		}
+		return nil, fmt.Errorf("error resetting the password, please check your code and try again")
+	}
+
+	return confirmOutput, nil
+}
+
+// AccountActive reports whether a Cognito user may log in without giving their password, as with
+// an SSH key: the account must be enabled and confirmed, and not waiting on a password reset.
+func AccountActive(ctx context.Context, username string, config Configuration) (bool, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
+	if err != nil {
+		return false, err
+	}
+
+	output, err := cognitoClient.AdminGetUser(ctx, &cognitoidentityprovider.AdminGetUserInput{
+		UserPoolId: aws.String(config.Cognito.UserPoolID),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		var userNotFound *cognitotypes.UserNotFoundException
+		if errors.As(err, &userNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading account status for %s: %w", username, err)
+	}
+
+	return output.Enabled && output.UserStatus == cognitotypes.UserStatusTypeConfirmed, nil
+}
+
+func GetUserData(ctx context.Context, accessToken string, config Configuration) (*cognitoidentityprovider.GetUserOutput, error) {
+	cognitoClient, err := newCognitoClient(ctx, config.Aws.Region)
+	if err != nil {
+		Logger.Error("Error creating Cognito client for getting user data", "error", err)
+		return nil, fmt.Errorf("an internal error occurred while creating Cognito client")
+	}
 
 	getUserInput := &cognitoidentityprovider.GetUserInput{AccessToken: aws.String(accessToken)}
-	userOutput, err := cognitoClient.GetUser(getUserInput)
+	userOutput, err := cognitoClient.GetUser(ctx, getUserInput)
 	if err != nil {
 		Logger.Error("Error getting user data with access token", "error", err)
 		return nil, fmt.Errorf("error retrieving user data, please try again")
@@ -154,7 +302,7 @@ func ChangePassword(server *Server, username, oldPassword, newPassword string) e
 
 	// Step 1: Authenticate the user
 	Logger.Info("Step 1: Authenticating user", "username", username)
-	signInOutput, err := SignInUser(username, oldPassword, server.Config)
+	signInOutput, err := SignInUser(server.Context, username, oldPassword, server.Config)
 	if err != nil {
 		Logger.Error("Authentication failed for user", "username", username, "error", err)
 		return fmt.Errorf("authentication failed: %v", err)
@@ -163,38 +311,33 @@ func ChangePassword(server *Server, username, oldPassword, newPassword string) e
 	Logger.Info("SignInOutput for user", "username", username, "signInOutput", signInOutput)
 
 	// Step 2: Handle NEW_PASSWORD_REQUIRED challenge if present
-	if signInOutput.ChallengeName != nil && *signInOutput.ChallengeName == cognitoidentityprovider.ChallengeNameTypeNewPasswordRequired {
+	if signInOutput.ChallengeName == cognitotypes.ChallengeNameTypeNewPasswordRequired {
 		Logger.Info("NEW_PASSWORD_REQUIRED challenge detected for user", "username", username)
 
-		// Create a new AWS session
-		sess, err := session.NewSession(&aws.Config{
-			Region: aws.String(server.Config.Aws.Region),
-		})
+		// Create Cognito Identity Provider client
+		cognitoClient, err := newCognitoClient(server.Context, server.Config.Aws.Region)
 		if err != nil {
-			Logger.Error("Failed to create AWS session for user", "username", username, "error", err)
-			return fmt.Errorf("failed to create AWS session: %v", err)
+			Logger.Error("Failed to create Cognito client for user", "username", username, "error", err)
+			return fmt.Errorf("failed to create Cognito client: %v", err)
 		}
 
-		// Create Cognito Identity Provider client
-		cognitoClient := cognitoidentityprovider.New(sess)
-
 		// Calculate SECRET_HASH
 		secretHash := calculateSecretHash(server.Config.Cognito.ClientID, server.Config.Cognito.ClientSecret, username)
 
 		// Respond to the NEW_PASSWORD_REQUIRED challenge
 		challengeResponseInput := &cognitoidentityprovider.RespondToAuthChallengeInput{
-			ChallengeName: aws.String(cognitoidentityprovider.ChallengeNameTypeNewPasswordRequired),
+			ChallengeName: cognitotypes.ChallengeNameTypeNewPasswordRequired,
 			ClientId:      aws.String(server.Config.Cognito.ClientID),
-			ChallengeResponses: map[string]*string{
-				"USERNAME":     aws.String(username),
-				"NEW_PASSWORD": aws.String(newPassword),
-				"SECRET_HASH":  aws.String(secretHash),
+			ChallengeResponses: map[string]string{
+				"USERNAME":     username,
+				"NEW_PASSWORD": newPassword,
+				"SECRET_HASH":  secretHash,
 			},
 			Session: signInOutput.Session,
 		}
 
 		Logger.Info("Sending challenge response for user", "username", username)
-		challengeResponse, err := cognitoClient.RespondToAuthChallenge(challengeResponseInput)
+		challengeResponse, err := cognitoClient.RespondToAuthChallenge(server.Context, challengeResponseInput)
 		if err != nil {
 			Logger.Error("Failed to respond to NEW_PASSWORD_REQUIRED challenge for user", "username", username, "error", err)
 			return fmt.Errorf("failed to set new password: %v", err)
@@ -215,18 +358,13 @@ func ChangePassword(server *Server, username, oldPassword, newPassword string) e
 		return fmt.Errorf("no valid access token available")
 	}
 
-	// Create a new AWS session
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(server.Config.Aws.Region),
-	})
+	// Create Cognito Identity Provider client
+	cognitoClient, err := newCognitoClient(server.Context, server.Config.Aws.Region)
 	if err != nil {
-		Logger.Error("Failed to create AWS session for user", "username", username, "error", err)
-		return fmt.Errorf("failed to create AWS session: %v", err)
+		Logger.Error("Failed to create Cognito client for user", "username", username, "error", err)
+		return fmt.Errorf("failed to create Cognito client: %v", err)
 	}
 
-	// Create Cognito Identity Provider client
-	cognitoClient := cognitoidentityprovider.New(sess)
-
 	// Perform the change password operation
 	input := &cognitoidentityprovider.ChangePasswordInput{
 		PreviousPassword: aws.String(oldPassword),
@@ -234,7 +372,7 @@ func ChangePassword(server *Server, username, oldPassword, newPassword string) e
 		AccessToken:      signInOutput.AuthenticationResult.AccessToken,
 	}
 
-	_, err = cognitoClient.ChangePassword(input)
+	_, err = cognitoClient.ChangePassword(server.Context, input)
 	if err != nil {
 		Logger.Error("Failed to change password for user", "username", username, "error", err)
 		return fmt.Errorf("failed to change password: %v", err)
@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // ColorMap maps color names to ANSI color codes.
@@ -34,3 +36,139 @@ func ApplyColor(colorName, text string) string {
 	// Return the original text if colorName is not found
 	return text
 }
+
+// Kinds of output that are colored according to the player's theme.
+const (
+	ColorTitle      = "title"
+	ColorExits      = "exits"
+	ColorItems      = "items"
+	ColorCharacters = "characters"
+	ColorSay        = "say"
+	ColorTell       = "tell"
+	ColorCombat     = "combat"
+	ColorChannel    = "channel"
+	ColorSnoop      = "snoop"
+	ColorUnvisited  = "unvisited"
+)
+
+// DefaultColorTheme is used by players who have not chosen a theme.
+const DefaultColorTheme = "default"
+
+// ColorThemes maps each theme to the color used for each kind of output.
+// Kinds missing from a theme are left uncolored.
+var ColorThemes = map[string]map[string]string{
+	"default": {
+		ColorTitle:      "bright_white",
+		ColorExits:      "green",
+		ColorItems:      "yellow",
+		ColorCharacters: "cyan",
+		ColorSay:        "bright_white",
+		ColorTell:       "magenta",
+		ColorCombat:     "red",
+		ColorChannel:    "bright_cyan",
+		ColorSnoop:      "bright_magenta",
+		ColorUnvisited:  "bright_black",
+	},
+	"vivid": {
+		ColorTitle:      "bright_yellow",
+		ColorExits:      "bright_green",
+		ColorItems:      "bright_yellow",
+		ColorCharacters: "bright_cyan",
+		ColorSay:        "bright_white",
+		ColorTell:       "bright_magenta",
+		ColorCombat:     "bright_red",
+		ColorChannel:    "bright_blue",
+		ColorSnoop:      "bright_magenta",
+		ColorUnvisited:  "bright_black",
+	},
+	"minimal": {
+		ColorTitle:     "bright_white",
+		ColorCombat:    "red",
+		ColorChannel:   "bright_white",
+		ColorSnoop:     "bright_white",
+		ColorUnvisited: "bright_black",
+	},
+}
+
+// ColorThemeNames returns the names of the color themes in alphabetical order.
+func ColorThemeNames() []string {
+	names := make([]string, 0, len(ColorThemes))
+	for name := range ColorThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Colorize colors text for the given kind of output using the player's theme.
+// Players with color turned off receive the text unchanged.
+func (p *Player) Colorize(kind, text string) string {
+	if p == nil || !p.Color.Load() {
+		return text
+	}
+
+	p.Mutex.Lock()
+	theme, exists := ColorThemes[p.ColorTheme]
+	p.Mutex.Unlock()
+
+	if !exists {
+		theme = ColorThemes[DefaultColorTheme]
+	}
+
+	return ApplyColor(theme[kind], text)
+}
+
+// colorizeList colors each entry of a list for the given kind of output.
+func (p *Player) colorizeList(kind string, entries []string) []string {
+	colored := make([]string, len(entries))
+	for i, entry := range entries {
+		colored[i] = p.Colorize(kind, entry)
+	}
+	return colored
+}
+
+func ExecuteColorCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing color settings", "playerName", character.Player.PlayerID)
+
+	player := character.Player
+
+	switch {
+	case len(tokens) == 2 && (strings.EqualFold(tokens[1], "on") || strings.EqualFold(tokens[1], "off")):
+		player.Color.Store(strings.EqualFold(tokens[1], "on"))
+
+	case len(tokens) == 3 && strings.EqualFold(tokens[1], "theme"):
+		theme := strings.ToLower(tokens[2])
+		if _, exists := ColorThemes[theme]; !exists {
+			player.ToPlayer <- fmt.Sprintf("\n\rUnknown theme. Available themes: %s\n\r", strings.Join(ColorThemeNames(), ", "))
+			return false
+		}
+		player.Mutex.Lock()
+		player.ColorTheme = theme
+		player.Mutex.Unlock()
+		player.Color.Store(true)
+
+	default:
+		status := "off"
+		if player.Color.Load() {
+			status = "on"
+		}
+
+		player.Mutex.Lock()
+		theme := player.ColorTheme
+		player.Mutex.Unlock()
+		if theme == "" {
+			theme = DefaultColorTheme
+		}
+
+		player.ToPlayer <- fmt.Sprintf("\n\rColor is %s, using the %s theme.\n\rAvailable themes: %s\n\rUsage: color <on|off> or color theme <name>\n\r", status, theme, strings.Join(ColorThemeNames(), ", "))
+		return false
+	}
+
+	if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+		Logger.Error("Error saving color settings", "playerName", player.PlayerID, "error", err)
+	}
+
+	player.ToPlayer <- player.Colorize(ColorTitle, "\n\rColor settings updated.\n\r")
+	return false
+}
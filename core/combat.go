@@ -1,9 +1,101 @@
 package core
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// SpawnProtectionDuration is how long a newly created or respawned character cannot be attacked.
+const SpawnProtectionDuration = 2 * time.Minute
+
+// CombatRoundDuration is the time between automatic attacks for characters engaged in a fight.
+const CombatRoundDuration = 3 * time.Second
+
+// MaxHitMultiplier caps how much a strong Challenge outcome can multiply a weapon's damage roll.
+const MaxHitMultiplier = 2.0
+
+// UnarmedWeapon is used for damage when a character attacks with nothing in their hands.
+var UnarmedWeapon = &Item{Name: "fists", MinDamage: 1, MaxDamage: 2, DamageType: "bludgeoning"}
+
+// combatRand is the random source behind every combat roll, guarded by combatRandMutex.
+var (
+	combatRand      = rand.New(rand.NewSource(time.Now().UnixNano()))
+	combatRandMutex sync.Mutex
+)
+
+// SeedCombat reseeds the combat random source so that rolls can be reproduced.
+func SeedCombat(seed int64) {
+	combatRandMutex.Lock()
+	defer combatRandMutex.Unlock()
+	combatRand = rand.New(rand.NewSource(seed))
+}
+
+// CombatFloat64 returns a random number in [0.0, 1.0) from the combat random source.
+func CombatFloat64() float64 {
+	combatRandMutex.Lock()
+	defer combatRandMutex.Unlock()
+	return combatRand.Float64()
+}
+
+// IsWeapon reports whether the item has a damage range and can be used to attack.
+func (i *Item) IsWeapon() bool {
+	return i.MaxDamage > 0
+}
+
+// Weapon returns the weapon held in the character's right hand, then left hand,
+// falling back to UnarmedWeapon when neither hand holds one.
+func (c *Character) Weapon() *Item {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	for _, slot := range HandSlots {
+		if item := c.Inventory[slot]; item != nil && item.IsWeapon() {
+			return item
+		}
+	}
+	return UnarmedWeapon
+}
+
+// Absorb returns the total damage absorbed by the armor the character is wearing.
+func (c *Character) Absorb() float64 {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	absorb := 0.0
+	counted := make(map[*Item]bool) // Items span several slots, so count each only once
+	for _, item := range c.Inventory {
+		if item.IsWorn && !counted[item] {
+			absorb += item.Absorb
+			counted[item] = true
+		}
+	}
+	return absorb
+}
+
+// CalculateDamage computes the damage dealt by a weapon for a Challenge outcome.
+// Outcomes below 1 miss. A hit rolls within the weapon's damage range, is scaled by
+// the outcome up to MaxHitMultiplier, and is reduced by the defender's armor absorb.
+func CalculateDamage(weapon *Item, outcome, absorb float64) float64 {
+	if weapon == nil || outcome < 1 {
+		return 0
+	}
+
+	minDamage := math.Min(weapon.MinDamage, weapon.MaxDamage)
+	roll := minDamage + CombatFloat64()*(weapon.MaxDamage-minDamage)
+
+	damage := roll*math.Min(outcome, MaxHitMultiplier) - absorb
+	if damage < 0 {
+		return 0
+	}
+	return damage
+}
+
 // EnterCombat initializes the CombatRange map when a character enters combat
 func (c *Character) EnterCombat() {
 	c.Mutex.Lock()
@@ -18,6 +110,7 @@ func (c *Character) ExitCombat() {
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 	c.CombatRange = nil
+	c.Attacking = false
 }
 
 // SetCombatRange sets the range to a target character, initializing the map if necessary
@@ -41,6 +134,20 @@ func (c *Character) GetCombatRange(target *Character) int {
 	return 0 // RangeFar
 }
 
+// Disengage removes a single opponent from combat, leaving combat entirely if no opponents remain
+func (c *Character) Disengage(target *Character) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	delete(c.CombatRange, target.ID)
+	if len(c.CombatRange) == 0 {
+		c.CombatRange = nil
+	}
+	if c.Facing == target {
+		c.Facing = nil
+		c.Attacking = false
+	}
+}
+
 // IsInCombat checks if the character is currently in combat
 func (c *Character) IsInCombat() bool {
 	return c.CombatRange != nil && len(c.CombatRange) > 0
@@ -79,9 +186,279 @@ func (c *Character) GetFacing() *Character {
 	return c.Facing
 }
 
-// ClearFacing clears the character's facing
+// ClearFacing clears the character's facing, which also stops any automatic attacks
 func (c *Character) ClearFacing() {
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 	c.Facing = nil
+	c.Attacking = false
+}
+
+// GrantProtection makes the character immune to combat for the given duration
+func (c *Character) GrantProtection(duration time.Duration) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.ProtectedUntil = time.Now().Add(duration)
+}
+
+// IsProtected checks if the character is still under spawn protection
+func (c *Character) IsProtected() bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	return time.Now().Before(c.ProtectedUntil)
+}
+
+// ClearProtection removes any remaining spawn protection from the character
+func (c *Character) ClearProtection() {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.ProtectedUntil = time.Time{}
+}
+
+// Respawn returns a dead character to the respawn room for the area they died in,
+// restores their health, and grants them spawn protection.
+func (c *Character) Respawn() error {
+	oldRoom := c.Room
+
+	area := ""
+	if oldRoom != nil {
+		area = oldRoom.Area
+	}
+
+	newRoom, err := c.Server.RespawnRoom(area)
+	if err != nil {
+		CombatLog.Error("No respawn room available", "characterName", c.Name, "area", area, "error", err)
+		return fmt.Errorf("no respawn room available: %w", err)
+	}
+
+	c.ExitCombat()
+	c.ClearFacing()
+	c.ClearEffects()
+	CancelTrade(c, fmt.Sprintf("%s has died", c.Name))
+
+	if oldRoom != nil {
+		oldRoom.Mutex.Lock()
+		delete(oldRoom.Characters, c.ID)
+		oldRoom.Mutex.Unlock()
+	}
+
+	c.Mutex.Lock()
+	c.Room = newRoom
+	c.Health = c.MaxHealth
+	c.Position = PositionStanding
+	c.markDirty(DirtyStats | DirtyLocation)
+	c.Mutex.Unlock()
+
+	SendRoomMessage(newRoom, fmt.Sprintf("\n\r%s appears in a shimmer of light.\n\r", c.Name))
+
+	newRoom.Mutex.Lock()
+	newRoom.Characters[c.ID] = c
+	newRoom.Mutex.Unlock()
+
+	c.GrantProtection(SpawnProtectionDuration)
+
+	CombatLog.Info("Character respawned", "characterName", c.Name, "area", area, "roomID", newRoom.RoomID)
+	return nil
+}
+
+// IsAttacking checks if the character attacks the character they are facing every combat round
+func (c *Character) IsAttacking() bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	return c.Attacking
+}
+
+// SetAttacking starts or stops the character's automatic attacks
+func (c *Character) SetAttacking(attacking bool) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.Attacking = attacking
+}
+
+// Engage starts a fight between the attacker and the target, keeping any range they are already
+// fighting at. The target turns to fight back unless they are already facing someone else.
+func Engage(attacker, target *Character) {
+	Provoke(attacker, target)
+
+	attacker.SetFacing(target)
+	attacker.SetAttacking(true)
+}
+
+// Provoke puts the aggressor and the target in combat without the aggressor attacking every round,
+// as when a harmful spell is cast. The target turns to fight back unless they are already facing someone else.
+func Provoke(aggressor, target *Character) {
+	// Taking an aggressive action ends the aggressor's own protection
+	aggressor.ClearProtection()
+
+	// Nobody fights sitting down
+	aggressor.StandUp()
+	target.StandUp()
+
+	aggressor.SetCombatRange(target, aggressor.GetCombatRange(target))
+
+	target.SetCombatRange(aggressor, target.GetCombatRange(aggressor))
+	if facing := target.GetFacing(); facing == nil || facing == aggressor {
+		target.SetFacing(aggressor)
+		target.SetAttacking(true)
+	}
+}
+
+// sendToRoomExcept sends a message to every active character in the room other than those listed.
+func sendToRoomExcept(r *Room, message string, except ...*Character) {
+	sendColoredToRoomExcept(r, "", message, except...)
+}
+
+// sendColoredToRoomExcept is sendToRoomExcept with the message colored for the given kind
+// of output according to each recipient's color settings.
+func sendColoredToRoomExcept(r *Room, kind, message string, except ...*Character) {
+	r.Mutex.Lock()
+	recipients := make([]*Character, 0, len(r.Characters))
+	for _, character := range r.Characters {
+		if character.IsActive() {
+			recipients = append(recipients, character)
+		}
+	}
+	r.Mutex.Unlock()
+
+	for _, character := range recipients {
+		skip := false
+		for _, excluded := range except {
+			if character == excluded {
+				skip = true
+				break
+			}
+		}
+		if !skip && character.Player.Send(character.Player.Colorize(kind, message)) {
+			character.Player.Send(character.Player.RenderPrompt())
+		}
+	}
+}
+
+// ResolveAttack makes a single attack from the attacker against the target. The hit is decided
+// by a Challenge of the attacker's Agility and Melee against the target's Agility and Dodge,
+// and a slain target is respawned. It returns true if the target was slain.
+func ResolveAttack(attacker, target *Character) bool {
+	weapon := attacker.Weapon()
+	room := attacker.Room
+
+	attackerScore := attacker.Score("Agility") + attacker.Score("Melee")
+	defenderScore := target.Score("Agility") + target.Score("Dodge")
+
+	outcome := Challenge(attackerScore, defenderScore, attacker.Server.Settings().Game.Balance)
+	damage := CalculateDamage(weapon, outcome, target.Absorb())
+
+	CombatLog.Info("Attack resolved", "attacker", attacker.Name, "defender", target.Name, "weapon", weapon.Name, "damageType", weapon.DamageType, "outcome", outcome, "damage", damage)
+
+	if outcome < 1 {
+		attacker.Player.Send(attacker.Player.Colorize(ColorCombat, fmt.Sprintf("\n\rYou swing your %s at %s and miss.\n\r", weapon.Name, target.Name)))
+		if target.Player.Send(target.Player.Colorize(ColorCombat, fmt.Sprintf("\n\r%s swings at you and misses.\n\r", attacker.Name))) {
+			target.Player.Send(target.Player.RenderPrompt())
+		}
+		sendColoredToRoomExcept(room, ColorCombat, fmt.Sprintf("\n\r%s swings at %s and misses.\n\r", attacker.Name, target.Name), attacker, target)
+		return false
+	}
+
+	if damage == 0 {
+		attacker.Player.Send(attacker.Player.Colorize(ColorCombat, fmt.Sprintf("\n\rYour %s glances harmlessly off %s's armor.\n\r", weapon.Name, target.Name)))
+		if target.Player.Send(target.Player.Colorize(ColorCombat, fmt.Sprintf("\n\r%s's blow glances harmlessly off your armor.\n\r", attacker.Name))) {
+			target.Player.Send(target.Player.RenderPrompt())
+		}
+		sendColoredToRoomExcept(room, ColorCombat, fmt.Sprintf("\n\r%s's blow glances off %s's armor.\n\r", attacker.Name, target.Name), attacker, target)
+		return false
+	}
+
+	target.Mutex.Lock()
+	target.Health -= damage
+	health := target.Health
+	target.markDirty(DirtyStats)
+	target.Mutex.Unlock()
+
+	damageText := strings.TrimSpace(fmt.Sprintf("%.1f %s", damage, weapon.DamageType))
+	attacker.Player.Send(attacker.Player.Colorize(ColorCombat, fmt.Sprintf("\n\rYou hit %s with your %s for %s damage.\n\r", target.Name, weapon.Name, damageText)))
+	target.Player.Send(target.Player.Colorize(ColorCombat, fmt.Sprintf("\n\r%s hits you with their %s for %s damage.\n\r", attacker.Name, weapon.Name, damageText)))
+	sendColoredToRoomExcept(room, ColorCombat, fmt.Sprintf("\n\r%s hits %s with their %s.\n\r", attacker.Name, target.Name, weapon.Name), attacker, target)
+
+	if health > 0 {
+		target.Player.Send(target.Player.RenderPrompt())
+		return false
+	}
+
+	Slay(attacker, target)
+	return true
+}
+
+// Slay announces that the attacker has killed the target, ends their fight and respawns the target.
+func Slay(attacker, target *Character) {
+	room := target.Room
+
+	CombatLog.Info("Character was slain", "attacker", attacker.Name, "defender", target.Name)
+
+	attacker.Player.Send(attacker.Player.Colorize(ColorCombat, fmt.Sprintf("\n\rYou have slain %s!\n\r", target.Name)))
+	target.Player.Send(target.Player.Colorize(ColorCombat, "\n\rYou have been slain!\n\r"))
+	sendColoredToRoomExcept(room, ColorCombat, fmt.Sprintf("\n\r%s has been slain by %s!\n\r", target.Name, attacker.Name), attacker, target)
+
+	attacker.Disengage(target)
+	attacker.GainExperience(KillExperience*target.GetLevel(), "slaying "+target.Name)
+	attacker.RecordObjective(ObjectiveKill, target.Name)
+
+	if err := target.Respawn(); err != nil {
+		CombatLog.Error("Error respawning slain character", "characterName", target.Name, "error", err)
+	}
+	target.Player.Send(target.Player.RenderPrompt())
+}
+
+// CombatLoop resolves a round of attacks every CombatRoundDuration until the server context is cancelled.
+func CombatLoop(s *Server) {
+	CombatLog.Info("Starting combat loop", "roundDuration", CombatRoundDuration)
+
+	ticker := time.NewTicker(CombatRoundDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.CombatRound()
+		case <-s.Context.Done():
+			CombatLog.Info("Stopping combat loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// CombatRound makes every attacking character strike the character they are facing once.
+// Characters whose opponent has left, logged out, or become protected stop attacking.
+func (s *Server) CombatRound() {
+	s.Mutex.Lock()
+	fighters := make([]*Character, 0)
+	for _, character := range s.Characters {
+		if character.IsAttacking() {
+			fighters = append(fighters, character)
+		}
+	}
+	s.Mutex.Unlock()
+
+	for _, character := range fighters {
+		target := character.GetFacing()
+
+		if !character.IsActive() || target == nil || !character.IsInCombat() {
+			character.SetAttacking(false)
+			continue
+		}
+
+		if target.Room != character.Room || !target.IsActive() {
+			character.Disengage(target)
+			if character.Player.Send(fmt.Sprintf("\n\r%s is no longer here.\n\r", target.Name)) {
+				character.Player.Send(character.Player.RenderPrompt())
+			}
+			continue
+		}
+
+		if target.IsProtected() || !character.Server.AllowsPvP(character.Room) {
+			character.SetAttacking(false)
+			continue
+		}
+
+		ResolveAttack(character, target)
+		character.Player.Send(character.Player.RenderPrompt())
+	}
 }
@@ -0,0 +1,40 @@
+package core
+
+import "testing"
+
+func TestSeedCombatRepeatsRolls(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+	}{
+		{name: "zero", seed: 0},
+		{name: "positive", seed: 42},
+		{name: "negative", seed: -7},
+	}
+
+	roll := func() []float64 {
+		rolls := make([]float64, 5)
+		for i := range rolls {
+			rolls[i] = CombatFloat64()
+		}
+		return rolls
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SeedCombat(tt.seed)
+			first := roll()
+			SeedCombat(tt.seed)
+			second := roll()
+
+			for i := range first {
+				if first[i] != second[i] {
+					t.Fatalf("roll %d = %v after reseeding, want %v", i, second[i], first[i])
+				}
+				if first[i] < 0 || first[i] >= 1 {
+					t.Errorf("roll %d = %v, want a value in [0, 1)", i, first[i])
+				}
+			}
+		})
+	}
+}
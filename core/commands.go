@@ -6,38 +6,134 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type CommandHandler func(character *Character, tokens []string) bool
 
 var CommandHandlers = map[string]CommandHandler{
-	"quit":      ExecuteQuitCommand,
-	"show":      ExecuteShowCommand,
-	"look":      ExecuteLookCommand,
-	"say":       ExecuteSayCommand,
-	"go":        ExecuteGoCommand,
-	"help":      ExecuteHelpCommand,
-	"who":       ExecuteWhoCommand,
-	"password":  ExecutePasswordCommand,
-	"challenge": ExecuteChallengeCommand,
-	"take":      ExecuteTakeCommand,
-	"get":       ExecuteTakeCommand, // Alias for take command
-	"drop":      ExecuteDropCommand,
-	"inventory": ExecuteInventoryCommand,
-	"wear":      ExecuteWearCommand,
-	"remove":    ExecuteRemoveCommand,
-	"examine":   ExecuteExamineCommand,
-	"assess":    ExecuteAssessCommand,
-	"i":         ExecuteInventoryCommand, // Alias for inventory command
-	"inv":       ExecuteInventoryCommand, // Alias for inventory command
-	"\"":        ExecuteSayCommand,       // Allow for double quotes to be used as a shortcut for the say command
-	"'":         ExecuteSayCommand,       // Allow for single quotes to be used as a shortcut for the say command
-	"q!":        ExecuteQuitCommand,      // Allow for q! to be used as a shortcut for the quit command
-}
-
-func ValidateCommand(command string) (string, []string, error) {
-
-	Logger.Debug("Received command", "command", command)
+	"quit":         ExecuteQuitCommand,
+	"show":         ExecuteShowCommand,
+	"look":         ExecuteLookCommand,
+	"say":          ExecuteSayCommand,
+	"tell":         ExecuteTellCommand,
+	"whisper":      ExecuteWhisperCommand,
+	"emote":        ExecuteEmoteCommand,
+	"go":           ExecuteGoCommand,
+	"help":         ExecuteHelpCommand,
+	"who":          ExecuteWhoCommand,
+	"whois":        ExecuteWhoisCommand,
+	"time":         ExecuteTimeCommand,
+	"weather":      ExecuteWeatherCommand,
+	"light":        ExecuteLightCommand,
+	"extinguish":   ExecuteExtinguishCommand,
+	"password":     ExecutePasswordCommand,
+	"challenge":    ExecuteChallengeCommand,
+	"take":         ExecuteTakeCommand,
+	"get":          ExecuteTakeCommand, // Alias for take command
+	"drop":         ExecuteDropCommand,
+	"put":          ExecutePutCommand,
+	"inventory":    ExecuteInventoryCommand,
+	"wear":         ExecuteWearCommand,
+	"remove":       ExecuteRemoveCommand,
+	"read":         ExecuteReadCommand,
+	"post":         ExecutePostCommand,
+	"examine":      ExecuteExamineCommand,
+	"description":  ExecuteDescriptionCommand,
+	"title":        ExecuteTitleCommand,
+	"stand":        ExecuteStandCommand,
+	"wake":         ExecuteStandCommand, // Alias for stand command
+	"sit":          ExecuteSitCommand,
+	"rest":         ExecuteRestCommand,
+	"sleep":        ExecuteSleepCommand,
+	"assess":       ExecuteAssessCommand,
+	"face":         ExecuteFaceCommand,
+	"attack":       ExecuteAttackCommand,
+	"cast":         ExecuteCastCommand,
+	"train":        ExecuteTrainCommand,
+	"advance":      ExecuteTrainCommand,
+	"list":         ExecuteListCommand,
+	"buy":          ExecuteBuyCommand,
+	"sell":         ExecuteSellCommand,
+	"quest":        ExecuteQuestCommand,
+	"give":         ExecuteGiveCommand,
+	"trade":        ExecuteTradeCommand,
+	"open":         ExecuteOpenCommand,
+	"close":        ExecuteCloseCommand,
+	"lock":         ExecuteLockCommand,
+	"unlock":       ExecuteUnlockCommand,
+	"filter":       ExecuteFilterCommand,
+	"color":        ExecuteColorCommand,
+	"alias":        ExecuteAliasCommand,
+	"unalias":      ExecuteUnaliasCommand,
+	"sshkey":       ExecuteSSHKeyCommand,
+	"aliases":      ExecuteAliasesCommand,
+	"prompt":       ExecutePromptCommand,
+	"echo":         ExecuteEchoCommand,
+	"join":         ExecuteJoinCommand,
+	"leave":        ExecuteLeaveCommand,
+	"channels":     ExecuteChannelsCommand,
+	"chat":         ExecuteChatCommand,
+	"mute":         ExecuteMuteCommand,
+	"unmute":       ExecuteUnmuteCommand,
+	"ignore":       ExecuteIgnoreCommand,
+	"unignore":     ExecuteUnignoreCommand,
+	"friend":       ExecuteFriendCommand,
+	"unfriend":     ExecuteUnfriendCommand,
+	"bug":          ExecuteReportCommand,
+	"typo":         ExecuteReportCommand,
+	"idea":         ExecuteReportCommand,
+	"@reports":     ExecuteReportsCommand,
+	"@ignored":     ExecuteIgnoredCommand,
+	"motd":         ExecuteMOTDCommand,
+	"hide":         ExecuteHideCommand,
+	"reveal":       ExecuteRevealCommand,
+	"area":         ExecuteAreaCommand,
+	"map":          ExecuteMapCommand,
+	"purge":        ExecutePurgeCommand,
+	"rename":       ExecuteRenameCommand,
+	"shortdesc":    ExecuteShortDescCommand,
+	"connectivity": ExecuteConnectivityCommand,
+	"snoop":        ExecuteSnoopCommand,
+	"@teleport":    ExecuteTeleportCommand,
+	"@create":      ExecuteCreateCommand,
+	"@spawn":       ExecuteSpawnCommand,
+	"@dig":         ExecuteDigCommand,
+	"@describe":    ExecuteDescribeCommand,
+	"@roomcreate":  ExecuteRoomCreateCommand,
+	"@roomedit":    ExecuteRoomEditCommand,
+	"@exitlink":    ExecuteExitLinkCommand,
+	"@exitremove":  ExecuteExitRemoveCommand,
+	"@ambience":    ExecuteAmbienceCommand,
+	"@role":        ExecuteRoleCommand,
+	"@renamechar":  ExecuteRenameCharacterCommand,
+	"@transfer":    ExecuteTransferCommand,
+	"loglevel":     ExecuteLogLevelCommand,
+	"auditlog":     ExecuteAuditLogCommand,
+	"i":            ExecuteInventoryCommand, // Alias for inventory command
+	"inv":          ExecuteInventoryCommand, // Alias for inventory command
+	"\"":           ExecuteSayCommand,       // Allow for double quotes to be used as a shortcut for the say command
+	"'":            ExecuteSayCommand,       // Allow for single quotes to be used as a shortcut for the say command
+	"q!":           ExecuteForceQuitCommand, // Allow for q! to be used as a shortcut for quitting immediately
+	"quit!":        ExecuteForceQuitCommand, // Quit immediately, even while in combat
+}
+
+// SensitiveCommands lists the commands whose arguments must never be written to the logs. They
+// change how the account logs in, so they may not be forced either.
+var SensitiveCommands = map[string]bool{
+	"password": true,
+	"sshkey":   true,
+}
+
+// RedactCommand returns the command for logging, masking the arguments of sensitive commands.
+func RedactCommand(verb string, tokens []string) string {
+	if !SensitiveCommands[verb] || len(tokens) < 2 {
+		return strings.Join(tokens, " ")
+	}
+	return tokens[0] + " ***"
+}
+
+func ValidateCommand(s *Server, command string) (string, []string, error) {
 
 	trimmedCommand := strings.TrimSpace(command)
 	tokens := strings.Fields(trimmedCommand)
@@ -46,31 +142,134 @@ func ValidateCommand(command string) (string, []string, error) {
 		return "", nil, errors.New("\n\rNo command entered.\n\r")
 	}
 
+	// Only the verb is logged here, arguments may contain passwords
 	verb := strings.ToLower(tokens[0])
-	if _, exists := CommandHandlers[verb]; !exists {
-		return "", tokens, fmt.Errorf(" command not understood")
+	Logger.Debug("Received command", "verb", verb)
+
+	// A bare direction shortcut such as n moves the character
+	if direction, exists := DirectionShortcuts[verb]; exists && len(tokens) == 1 {
+		return "go", []string{"go", direction}, nil
+	}
+
+	if _, exists := s.CommandHandler(verb); !exists {
+		resolved, err := ResolveAbbreviation(verb)
+		if err != nil {
+			return "", tokens, err
+		}
+		verb = resolved
+		tokens[0] = verb
 	}
 
 	return verb, tokens, nil
 }
 
+// DirectionShortcuts maps the abbreviations accepted for movement to their directions.
+var DirectionShortcuts = map[string]string{
+	"n":  "north",
+	"s":  "south",
+	"e":  "east",
+	"w":  "west",
+	"u":  "up",
+	"d":  "down",
+	"ne": "northeast",
+	"nw": "northwest",
+	"se": "southeast",
+	"sw": "southwest",
+}
+
+// CommandAbbreviations holds preferred abbreviations for commands that share a prefix with others.
+var CommandAbbreviations = map[string]string{
+	"l": "look",
+	"x": "examine",
+}
+
+// ResolveAbbreviation expands an unambiguous prefix of a command name to the command.
+// Privileged commands must always be typed in full.
+func ResolveAbbreviation(prefix string) (string, error) {
+	if verb, exists := CommandAbbreviations[prefix]; exists {
+		return verb, nil
+	}
+
+	candidates := make([]string, 0)
+	for verb := range CommandHandlers {
+		if AdminCommands[verb] || BuilderCommands[verb] {
+			continue
+		}
+		if strings.HasPrefix(verb, prefix) {
+			candidates = append(candidates, verb)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf(" command not understood")
+	}
+
+	// Aliases such as inv and inventory share a prefix, so the shortest wins when it prefixes the rest
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i]) != len(candidates[j]) {
+			return len(candidates[i]) < len(candidates[j])
+		}
+		return candidates[i] < candidates[j]
+	})
+	for _, candidate := range candidates[1:] {
+		if !strings.HasPrefix(candidate, candidates[0]) {
+			sort.Strings(candidates)
+			return "", fmt.Errorf("\n\r%s is ambiguous, did you mean: %s?\n\r", prefix, strings.Join(candidates, ", "))
+		}
+	}
+
+	return candidates[0], nil
+}
+
+// CommandHandler returns the handler for a verb. Channel names are commands too, but are looked up
+// in the server's channel registry so that channels created at runtime never write to CommandHandlers.
+func (s *Server) CommandHandler(verb string) (CommandHandler, bool) {
+	if handler, ok := CommandHandlers[verb]; ok {
+		return handler, true
+	}
+	if s.ChannelExists(verb) {
+		return ExecuteChannelCommand, true
+	}
+	return nil, false
+}
+
 func ExecuteCommand(character *Character, verb string, tokens []string) bool {
 
 	Logger.Debug("Executing command", "verb", verb)
 
-	handler, ok := CommandHandlers[verb]
-	if !ok {
+	handler, ok := character.Server.CommandHandler(verb)
+	if !ok || !character.Player.CanUseCommand(verb) {
 		character.Player.ToPlayer <- "\n\rCommand not yet implemented or recognized.\n\r"
 		return false
 	}
-	return handler(character, tokens)
+
+	if AdminCommands[verb] || BuilderCommands[verb] {
+		character.Server.AuditPlayer(character.Context(), character.Player, AuditCommand, character.Name, RedactCommand(verb, tokens))
+	}
+
+	start := time.Now()
+	defer func() { RecordCommand(time.Since(start)) }()
+	return traceCommand(character, verb, handler, tokens)
 }
 
 func ExecuteQuitCommand(character *Character, tokens []string) bool {
+	// Quitting does not pause a fight, so require an explicit confirmation while in combat
+	if character.IsInCombat() {
+		Logger.Info("Player attempted to quit while in combat", "playerName", character.Player.PlayerID)
+		character.Player.ToPlayer <- "\n\rYou are in combat! Quitting will not pause the fight.\n\rUse 'quit!' or 'q!' if you really want to leave.\n\r"
+		return false
+	}
+
+	return ExecuteForceQuitCommand(character, tokens)
+}
+
+func ExecuteForceQuitCommand(character *Character, tokens []string) bool {
 	Logger.Info("Player is quitting", "playerName", character.Player.PlayerID)
 
 	// Send goodbye message
-	character.Player.ToPlayer <- "\n\rGoodbye!"
+	character.Player.Send("\n\rGoodbye!")
+
+	CancelTrade(character, fmt.Sprintf("%s has left", character.Name))
 
 	// Remove character from the room
 	character.Room.Mutex.Lock()
@@ -87,7 +286,7 @@ func ExecuteQuitCommand(character *Character, tokens []string) bool {
 
 	// Save character state to database
 	character.Mutex.Lock()
-	err := character.Server.Database.WriteCharacter(character)
+	err := character.Server.Database.UpdateCharacter(character.Context(), character)
 	if err != nil {
 		Logger.Error("Error saving character state on quit", "characterName", character.Name, "error", err)
 	}
@@ -109,17 +308,110 @@ func ExecuteSayCommand(character *Character, tokens []string) bool {
 
 	message := strings.Join(tokens[1:], " ")
 	broadcastMessage := fmt.Sprintf("\n\r%s says %s\n\r", character.Name, message)
+	filteredMessage := fmt.Sprintf("\n\r%s says %s\n\r", character.Name, FilterProfanity(character.Server, message))
 
 	for _, c := range character.Room.Characters {
-		if c != character {
+		if c != character && c.IsActive() {
 			// Send message to other characters in the room
-			c.Player.ToPlayer <- broadcastMessage
-			c.Player.ToPlayer <- c.Player.Prompt
+			if c.Player.SendFrom(character, c.Player.Colorize(ColorSay, c.Player.MessageFor(broadcastMessage, filteredMessage))) {
+				c.Player.Send(c.Player.RenderPrompt())
+			}
 		}
 	}
 
 	// Send only the broadcast message to the player who issued the command
-	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou say %s\n\r", message)
+	character.Player.ToPlayer <- character.Player.Colorize(ColorSay, fmt.Sprintf("\n\rYou say %s\n\r", message))
+
+	return false
+}
+
+func ExecuteTellCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is sending a tell", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: tell <character> <message>\n\r"
+		return false
+	}
+
+	targetName := tokens[1]
+	target := character.Server.FindOnlineCharacter(targetName)
+	if target == nil || !target.IsActive() {
+		if character.Server.CharacterNameExists(targetName) {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not online.\n\r", targetName)
+		} else {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no one called %s.\n\r", targetName)
+		}
+		return false
+	}
+
+	if target == character {
+		character.Player.ToPlayer <- "\n\rYou mutter to yourself.\n\r"
+		return false
+	}
+
+	message := strings.Join(tokens[2:], " ")
+	rawMessage := fmt.Sprintf("\n\r%s tells you, \"%s\"\n\r", character.Name, message)
+	filteredMessage := fmt.Sprintf("\n\r%s tells you, \"%s\"\n\r", character.Name, FilterProfanity(character.Server, message))
+
+	if target.Player.SendFrom(character, target.Player.Colorize(ColorTell, target.Player.MessageFor(rawMessage, filteredMessage))) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+
+	character.Player.ToPlayer <- character.Player.Colorize(ColorTell, fmt.Sprintf("\n\rYou tell %s, \"%s\"\n\r", target.Name, message))
+
+	return false
+}
+
+func ExecuteWhisperCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is whispering", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: whisper <character> <message>\n\r"
+		return false
+	}
+
+	targetName := tokens[1]
+	var target *Character
+
+	room := character.Room
+	room.Mutex.Lock()
+	for _, c := range room.Characters {
+		if c != character && c.IsActive() && strings.EqualFold(c.Name, targetName) {
+			target = c
+			break
+		}
+	}
+	others := make([]*Character, 0, len(room.Characters))
+	for _, c := range room.Characters {
+		if c != character && c != target && c.IsActive() {
+			others = append(others, c)
+		}
+	}
+	room.Mutex.Unlock()
+
+	if target == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", targetName)
+		return false
+	}
+
+	message := strings.Join(tokens[2:], " ")
+	rawMessage := fmt.Sprintf("\n\r%s whispers to you, \"%s\"\n\r", character.Name, message)
+	filteredMessage := fmt.Sprintf("\n\r%s whispers to you, \"%s\"\n\r", character.Name, FilterProfanity(character.Server, message))
+
+	if target.Player.SendFrom(character, target.Player.Colorize(ColorTell, target.Player.MessageFor(rawMessage, filteredMessage))) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+
+	// Everyone else in the room notices the whisper but not what was said
+	for _, c := range others {
+		if c.Player.Send(fmt.Sprintf("\n\r%s whispers something to %s.\n\r", character.Name, target.Name)) {
+			c.Player.Send(c.Player.RenderPrompt())
+		}
+	}
+
+	character.Player.ToPlayer <- character.Player.Colorize(ColorTell, fmt.Sprintf("\n\rYou whisper to %s, \"%s\"\n\r", target.Name, message))
 
 	return false
 }
@@ -129,10 +421,158 @@ func ExecuteLookCommand(character *Character, tokens []string) bool {
 	Logger.Info("Player is looking around", "playerName", character.Player.PlayerID)
 
 	room := character.Room
+
+	if len(tokens) > 1 {
+		character.Player.ToPlayer <- lookAt(character, room, strings.Join(tokens[1:], " "))
+		return false
+	}
+
 	character.Player.ToPlayer <- RoomInfo(room, character)
 	return false
 }
 
+// lookAt describes a direction, NPC, character or item the character can see, checked in that order.
+func lookAt(character *Character, room *Room, target string) string {
+	notHere := fmt.Sprintf("\n\rYou don't see %s here.\n\r", target)
+	if !character.CanSee() {
+		return notHere
+	}
+
+	direction := strings.ToLower(target)
+	if shortcut, exists := DirectionShortcuts[direction]; exists {
+		direction = shortcut
+	}
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	var visible, closed bool
+	var beyond *Room
+	if exists {
+		visible, closed, beyond = exit.Visible, exit.Closed, exit.TargetRoom
+	}
+	room.Mutex.Unlock()
+
+	if visible {
+		switch {
+		case closed:
+			return fmt.Sprintf("\n\rThe door to the %s is closed.\n\r", direction)
+		case beyond == nil:
+			return fmt.Sprintf("\n\rThe way %s leads nowhere.\n\r", direction)
+		default:
+			return fmt.Sprintf("\n\rLooking %s you can make out %s.\n\r", direction, character.Player.Colorize(ColorTitle, beyond.Title))
+		}
+	}
+
+	if npc := room.FindNPC(target); npc != nil {
+		return fmt.Sprintf("\n\r%s\n\r%s\n\r", character.Player.Colorize(ColorCharacters, npc.Name), npc.Description)
+	}
+
+	if other := findCharacterInRoom(room, target); other != nil {
+		return describeCharacter(character, other)
+	}
+
+	if item := character.findItemInReach(target); item != nil {
+		return describeItem(item)
+	}
+
+	return notHere
+}
+
+// describeCharacter shows a character's description and what they are holding and wearing.
+func describeCharacter(viewer, other *Character) string {
+	other.Mutex.Lock()
+	description := other.Description
+	equipment := other.equipment()
+	other.Mutex.Unlock()
+
+	if description == "" {
+		description = fmt.Sprintf("You see nothing special about %s.", other.Name)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\r%s\n\r%s\n\r", viewer.Player.Colorize(ColorCharacters, other.Name), description))
+	for _, group := range []struct {
+		label string
+		items []*Item
+	}{{"Holding", equipment.Held}, {"Wearing", equipment.Worn}} {
+		if len(group.items) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(group.items))
+		for _, item := range group.items {
+			names = append(names, item.Name)
+		}
+		sort.Strings(names)
+		output.WriteString(fmt.Sprintf("%s: %s\n\r", group.label, strings.Join(viewer.Player.colorizeList(ColorItems, names), ", ")))
+	}
+	return output.String()
+}
+
+// MaxCharacterDescriptionLength limits the description others see when they look at a character.
+const MaxCharacterDescriptionLength = 500
+
+func ExecuteDescriptionCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing their description", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Mutex.Lock()
+		description := character.Description
+		character.Mutex.Unlock()
+		if description == "" {
+			description = "You have no description."
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\rUsage: description <text>\n\r", description)
+		return false
+	}
+
+	description, err := SanitizeText(strings.Join(tokens[1:], " "), MaxCharacterDescriptionLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid description: %v\n\r", err)
+		return false
+	}
+
+	character.Mutex.Lock()
+	character.Description = description
+	character.markDirty(DirtyStats)
+	character.Mutex.Unlock()
+
+	character.Player.ToPlayer <- "\n\rYour description has been updated.\n\r"
+	return false
+}
+
+// MaxCharacterTitleLength limits the title shown after a character's name.
+const MaxCharacterTitleLength = 40
+
+func ExecuteTitleCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing their title", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Mutex.Lock()
+		title := character.Title
+		character.Mutex.Unlock()
+		if title == "" {
+			title = "You have no title."
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\rUsage: title <text>\n\r", title)
+		return false
+	}
+
+	title, err := SanitizeText(strings.Join(tokens[1:], " "), MaxCharacterTitleLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid title: %v\n\r", err)
+		return false
+	}
+
+	character.Mutex.Lock()
+	character.Title = title
+	character.markDirty(DirtyStats)
+	character.Mutex.Unlock()
+
+	character.Player.ToPlayer <- "\n\rYour title has been updated.\n\r"
+	return false
+}
+
 func ExecuteGoCommand(character *Character, tokens []string) bool {
 
 	Logger.Info("Player is attempting to move", "playerName", character.Player.PlayerID)
@@ -142,6 +582,16 @@ func ExecuteGoCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
+	switch character.GetPosition() {
+	case PositionStanding:
+	case PositionSleeping:
+		character.Player.ToPlayer <- "\n\rYou can't go anywhere in your sleep. Stand up first.\n\r"
+		return false
+	default:
+		character.Player.ToPlayer <- "\n\rYou need to stand up first.\n\r"
+		return false
+	}
+
 	// Ensure the correct number of arguments are provided
 
 	if len(tokens) < 2 {
@@ -149,6 +599,15 @@ func ExecuteGoCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
+	// A heavy load slows the character down, so they must catch their breath between moves
+	if wait := character.StartMove(); wait > 0 {
+		character.Player.ToPlayer <- "\n\rYou are still catching your breath under the weight of your load.\n\r"
+		return false
+	}
+	if character.IsEncumbered() {
+		character.Player.ToPlayer <- "\n\rYou trudge along under the weight of your load.\n\r"
+	}
+
 	direction := tokens[1]
 	character.Move(direction)
 
@@ -181,7 +640,7 @@ func ExecuteChallengeCommand(character *Character, tokens []string) bool {
 	}
 
 	// Calculate the outcome using the Challenge function
-	outcome := Challenge(attackerScore, defenderScore, character.Server.Balance)
+	outcome := Challenge(attackerScore, defenderScore, character.Server.Settings().Game.Balance)
 
 	// Provide feedback to the player based on the challenge outcome
 	feedbackMessage := fmt.Sprintf("\n\rChallenge outcome: %f\n\r", outcome)
@@ -190,42 +649,120 @@ func ExecuteChallengeCommand(character *Character, tokens []string) bool {
 	return false
 }
 
+// formatIdle renders an idle duration for the who list, empty when the player is active.
+func formatIdle(idle time.Duration) string {
+	switch {
+	case idle < time.Minute:
+		return ""
+	case idle < time.Hour:
+		return fmt.Sprintf("idle %dm", int(idle.Minutes()))
+	default:
+		return fmt.Sprintf("idle %dh%02dm", int(idle.Hours()), int(idle.Minutes())%60)
+	}
+}
+
+// whoEntry describes one online character for the who list.
+type whoEntry struct {
+	name  string
+	label string
+}
+
 func ExecuteWhoCommand(character *Character, tokens []string) bool {
 	Logger.Info("Player is listing all characters online", "playerName", character.Player.PlayerID)
 
 	// Retrieve the server instance from the character
 	server := character.Server
+	isAdmin := character.Player.IsAdmin()
 
-	characterNames := make([]string, 0, len(server.Characters))
+	// An optional filter narrows the list to friends or to a single area
+	filter := strings.TrimSpace(strings.Join(tokens[1:], " "))
+	friendsOnly := strings.EqualFold(filter, "friends")
+
+	server.Mutex.Lock()
+	online := make([]*Character, 0, len(server.Characters))
 	for _, char := range server.Characters {
-		characterNames = append(characterNames, char.Name)
+		online = append(online, char)
+	}
+	server.Mutex.Unlock()
+
+	entries := make([]whoEntry, 0, len(online))
+	for _, char := range online {
+		char.Mutex.Lock()
+		room := char.Room
+		level := char.Level
+		archetype := char.Archetype
+		char.Mutex.Unlock()
+
+		if friendsOnly && !character.Player.IsFriend(char.Name) {
+			continue
+		}
+		if filter != "" && !friendsOnly && !server.InArea(room, filter) {
+			continue
+		}
+
+		details := []string{fmt.Sprintf("L%d", level)}
+		if archetype != "" {
+			details[0] += " " + archetype
+		}
+		if char.Player != nil {
+			if idle := formatIdle(char.Player.IdleTime()); idle != "" {
+				details = append(details, idle)
+			}
+		}
+		if isAdmin && room != nil {
+			details = append(details, fmt.Sprintf("#%d", room.RoomID))
+		}
+
+		entries = append(entries, whoEntry{
+			name:  char.Name,
+			label: fmt.Sprintf("%s (%s)", char.Name, strings.Join(details, ", ")),
+		})
 	}
 
 	// Sort character names for consistent display
-	sort.Strings(characterNames)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	var messageBuilder strings.Builder
+	switch {
+	case friendsOnly:
+		messageBuilder.WriteString("\n\rOnline Friends:\n\r")
+	case filter != "":
+		messageBuilder.WriteString(fmt.Sprintf("\n\rOnline Characters in %s:\n\r", filter))
+	default:
+		messageBuilder.WriteString("\n\rOnline Characters:\n\r")
+	}
+
+	if len(entries) == 0 {
+		messageBuilder.WriteString("No one.\n\r")
+		character.Player.ToPlayer <- messageBuilder.String()
+		return false
+	}
 
 	// Calculate the number of columns and rows based on console dimensions
-	maxNameLength := 15
-	columnWidth := maxNameLength + 2 // Adding 2 for spacing between names
+	maxLabelLength := 15
+	for _, entry := range entries {
+		if len(entry.label) > maxLabelLength {
+			maxLabelLength = len(entry.label)
+		}
+	}
+	columnWidth := maxLabelLength + 2 // Adding 2 for spacing between entries
 	columns := character.Player.ConsoleWidth / columnWidth
 	if columns == 0 {
 		columns = 1 // Ensure at least one column if console width is too small
 	}
-	rows := len(characterNames) / columns
-	if len(characterNames)%columns != 0 {
+	rows := len(entries) / columns
+	if len(entries)%columns != 0 {
 		rows++ // Add an extra row for any remainder
 	}
 
-	// Prepare message builder to construct the output
-	var messageBuilder strings.Builder
-	messageBuilder.WriteString("\n\rOnline Characters:\n\r")
-
 	// Loop through rows and columns to construct the output
 	for row := 0; row < rows; row++ {
 		for col := 0; col < columns; col++ {
 			index := row + col*rows
-			if index < len(characterNames) {
-				messageBuilder.WriteString(fmt.Sprintf("%-15s  ", characterNames[index]))
+			if index < len(entries) {
+				messageBuilder.WriteString(fmt.Sprintf("%-*s  ", maxLabelLength, entries[index].label))
 			}
 		}
 		messageBuilder.WriteString("\n\r") // New line at the end of each row
@@ -237,29 +774,172 @@ func ExecuteWhoCommand(character *Character, tokens []string) bool {
 	return false
 }
 
+func ExecuteWhoisCommand(character *Character, tokens []string) bool {
+	Logger.Info("Player is looking up a character", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: whois <character>\n\r"
+		return false
+	}
+
+	server := character.Server
+	name := tokens[1]
+	isAdmin := character.Player.IsAdmin()
+
+	// Prefer the live character so online status and location are current
+	target := server.FindOnlineCharacter(name)
+
+	var data *CharacterData
+	if target != nil {
+		// Copy only what whois shows, since the target's own goroutines change the rest
+		target.Mutex.Lock()
+		data = &CharacterData{
+			CharacterName: target.Name,
+			Title:         target.Title,
+			Archetype:     target.Archetype,
+			Description:   target.Description,
+			Health:        target.Health,
+			MaxHealth:     target.MaxHealth,
+			Essence:       target.Essence,
+			MaxEssence:    target.MaxEssence,
+		}
+		if target.Room != nil {
+			data.RoomID = target.Room.RoomID
+		}
+		if target.Player != nil {
+			data.PlayerID = target.Player.PlayerID
+		}
+		target.Mutex.Unlock()
+	} else {
+		if !server.CharacterNameExists(name) {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no character named %s.\n\r", name)
+			return false
+		}
+
+		found, err := server.Database.FindCharacterData(server.Context, name)
+		if err != nil {
+			Logger.Error("Error looking up character", "characterName", name, "error", err)
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no character named %s.\n\r", name)
+			return false
+		}
+		data = found
+	}
+
+	status := "Offline"
+	if target != nil {
+		status = "Online"
+	}
+
+	room, _ := server.Room(data.RoomID)
+	area := server.AreaName(room)
+
+	heading := data.CharacterName
+	if data.Title != "" {
+		heading += " " + data.Title
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\r%s\n\r", character.Player.Colorize(ColorTitle, heading)))
+	if data.Archetype != "" {
+		output.WriteString(fmt.Sprintf("  Archetype: %s\n\r", data.Archetype))
+	}
+	output.WriteString(fmt.Sprintf("  Status:    %s\n\r", status))
+	output.WriteString(fmt.Sprintf("  Area:      %s\n\r", area))
+
+	if isAdmin {
+		output.WriteString(fmt.Sprintf("  Room:      %d\n\r", data.RoomID))
+		output.WriteString(fmt.Sprintf("  Health:    %.0f/%.0f\n\r", data.Health, data.MaxHealth))
+		output.WriteString(fmt.Sprintf("  Essence:   %.0f/%.0f\n\r", data.Essence, data.MaxEssence))
+		output.WriteString(fmt.Sprintf("  Player:    %s\n\r", data.PlayerID))
+	}
+
+	if data.Description != "" {
+		output.WriteString(fmt.Sprintf("\n\r%s\n\r", data.Description))
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
+
 func ExecutePasswordCommand(character *Character, tokens []string) bool {
 
 	Logger.Info("Player is attempting to change their password", "playerName", character.Player.PlayerID)
 
-	if len(tokens) != 3 {
-		character.Player.ToPlayer <- "\n\rUsage: password <oldPassword> <newPassword>\n\r"
+	var oldPassword, newPassword string
+
+	switch len(tokens) {
+	case 1:
+		// Prompt for the passwords so they are never echoed back
+		var ok bool
+		if oldPassword, ok = character.Player.ReadHidden("\n\rCurrent password: "); !ok {
+			return true
+		}
+		if newPassword, ok = character.Player.ReadHidden("New password: "); !ok {
+			return true
+		}
+		confirmPassword, ok := character.Player.ReadHidden("Confirm new password: ")
+		if !ok {
+			return true
+		}
+		if newPassword != confirmPassword {
+			character.Player.ToPlayer <- "\n\rPasswords do not match.\n\r"
+			return false
+		}
+	case 3:
+		oldPassword = tokens[1]
+		newPassword = tokens[2]
+	default:
+		character.Player.ToPlayer <- "\n\rUsage: password\n\r"
 		return false
 	}
 
-	oldPassword := tokens[1]
-	newPassword := tokens[2]
-
 	err := ChangePassword(character.Server, character.Player.PlayerID, oldPassword, newPassword)
 	if err != nil {
 		Logger.Error("Failed to change password for user", "playerName", character.Player.PlayerID, "error", err)
+		character.Server.AuditPlayer(character.Context(), character.Player, AuditPasswordChange, character.Player.PlayerID, "failed")
 		character.Player.ToPlayer <- "\n\rFailed to change password. Please try again.\n\r"
 		return false
 	}
 
+	character.Server.AuditPlayer(character.Context(), character.Player, AuditPasswordChange, character.Player.PlayerID, "changed")
+
+	// Keys registered before the change could belong to whoever knew the old password
+	revoked, err := character.Server.RevokeSSHKeys(character.Context(), character.Player.PlayerID)
+	if err != nil {
+		Logger.Error("Failed to revoke SSH keys after a password change", "playerName", character.Player.PlayerID, "error", err)
+	}
+	if revoked > 0 {
+		character.Server.AuditPlayer(character.Context(), character.Player, AuditSSHKey, character.Player.PlayerID, fmt.Sprintf("revoked %d keys after a password change", revoked))
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rPassword changed successfully. Your registered SSH keys have been removed, use sshkey add to register them again.\n\r")
+		return false
+	}
+
 	character.Player.ToPlayer <- "\n\rPassword changed successfully.\n\r"
 	return false // Keep the command loop running
 }
 
+func ExecuteEchoCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing their echo setting", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 || (tokens[1] != "on" && tokens[1] != "off") {
+		state := "off"
+		if character.Player.Echo.Load() {
+			state = "on"
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rEcho is %s. Usage: echo <on|off>\n\r", state)
+		return false
+	}
+
+	character.Player.Echo.Store(tokens[1] == "on")
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player echo setting", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rEcho turned %s.\n\r", tokens[1])
+	return false
+}
+
 func ExecuteShowCommand(character *Character, tokens []string) bool {
 
 	Logger.Info("Player is displaying character information", "playerName", character.Player.PlayerID)
@@ -273,6 +953,11 @@ func ExecuteShowCommand(character *Character, tokens []string) bool {
 	// Health and Essence (integer component only)
 	output.WriteString(fmt.Sprintf("Health: %d, Essence: %d\r\n", int(character.Health), int(character.Essence)))
 
+	// Level, experience toward the next level, and unspent training points
+	output.WriteString(character.ProgressSummary() + "\r\n")
+	output.WriteString(fmt.Sprintf("Coins: %d\r\n", character.GetCoins()))
+	output.WriteString(character.ExploredSummary() + "\r\n")
+
 	// Attributes
 	output.WriteString("Attributes:\r\n")
 	for attr, value := range character.Attributes {
@@ -287,6 +972,14 @@ func ExecuteShowCommand(character *Character, tokens []string) bool {
 		}
 	}
 
+	// Effects, with the time remaining on each
+	if effects := character.EffectSummary(); len(effects) > 0 {
+		output.WriteString("Effects:\r\n")
+		for _, line := range effects {
+			output.WriteString(line + "\r\n")
+		}
+	}
+
 	// Send the composed information to the player
 	player.ToPlayer <- output.String()
 
@@ -295,10 +988,14 @@ func ExecuteShowCommand(character *Character, tokens []string) bool {
 
 func ExecuteTakeCommand(character *Character, tokens []string) bool {
 	if len(tokens) < 2 {
-		character.Player.ToPlayer <- "\n\rUsage: take <item name>\n\r"
+		character.Player.ToPlayer <- "\n\rUsage: take <item name> [from <container>]\n\r"
 		return false
 	}
 
+	if itemName, containerName, ok := splitTokens(tokens[1:], "from"); ok {
+		return takeFromContainer(character, itemName, containerName)
+	}
+
 	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
 	var itemToTake *Item
 
@@ -315,30 +1012,157 @@ func ExecuteTakeCommand(character *Character, tokens []string) bool {
 	}
 
 	if !character.CanCarryItem(itemToTake) {
-		character.Player.ToPlayer <- "\n\rYou can't carry any more items.\n\r"
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is too heavy. You are already carrying %.1f of %.1f.\n\r", itemToTake.Name, character.CarriedMass(), character.CarryCapacity())
+		return false
+	}
+
+	hands, err := character.HoldItem(itemToTake)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
 		return false
 	}
+	character.Room.RemoveItem(itemToTake)
 
-	// Try to place the item in the right hand first, then the left hand if right is occupied
-	var handSlot string
-	if character.Inventory["right_hand"] == nil {
-		handSlot = "right_hand"
-	} else if character.Inventory["left_hand"] == nil {
-		handSlot = "left_hand"
+	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s picks up %s.\n\r", character.Name, itemToTake.Name))
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou take %s and hold it in your %s.\n\r", itemToTake.Name, hands)
+	character.RecordObjective(ObjectiveCollect, itemToTake.Name)
+	if character.IsEncumbered() {
+		character.Player.ToPlayer <- "\n\rYou are weighed down by your load and will move slowly.\n\r"
 	}
+	return false
+}
 
-	if handSlot == "" {
-		character.Player.ToPlayer <- "\n\rYour hands are full. You need a free hand to pick up an item.\n\r"
-		return false
+// splitTokens splits the tokens around the first occurrence of the separator word,
+// returning the text before and after it. Both halves must be non-empty.
+func splitTokens(tokens []string, separator string) (string, string, bool) {
+	for i, token := range tokens {
+		if strings.EqualFold(token, separator) && i > 0 && i < len(tokens)-1 {
+			return strings.Join(tokens[:i], " "), strings.Join(tokens[i+1:], " "), true
+		}
 	}
+	return "", "", false
+}
+
+// findContainer looks for a container the character is carrying, then one lying in the room.
+// It reports whether the container was found in the character's inventory.
+func findContainer(character *Character, containerName string) (*Item, bool) {
+	lowercaseName := strings.ToLower(containerName)
 
-	character.Room.RemoveItem(itemToTake)
 	character.Mutex.Lock()
-	character.Inventory[handSlot] = itemToTake
+	for _, item := range character.Inventory {
+		if item != nil && item.Container && strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			character.Mutex.Unlock()
+			return item, true
+		}
+	}
 	character.Mutex.Unlock()
 
-	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s picks up %s.\n\r", character.Name, itemToTake.Name))
-	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou take %s and hold it in your %s.\n\r", itemToTake.Name, strings.Replace(handSlot, "_", " ", -1))
+	character.Room.Mutex.Lock()
+	defer character.Room.Mutex.Unlock()
+	for _, item := range character.Room.Items {
+		if item != nil && item.Container && strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			return item, false
+		}
+	}
+
+	return nil, false
+}
+
+func takeFromContainer(character *Character, itemName, containerName string) bool {
+
+	Logger.Info("Player is taking an item from a container", "playerName", character.Player.PlayerID)
+
+	container, carried := findContainer(character, containerName)
+	if container == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see a container called %s.\n\r", containerName)
+		return false
+	}
+
+	if !character.CanHold(false) {
+		character.Player.ToPlayer <- "\n\rYour hands are full. You need a free hand to take an item.\n\r"
+		return false
+	}
+
+	itemToTake := container.TakeItem(itemName)
+	if itemToTake == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no %s in %s.\n\r", itemName, container.Name)
+		return false
+	}
+
+	// Two-handed items need the other hand free as well
+	if itemToTake.TwoHanded && !character.CanHold(true) {
+		if err := container.PutItem(itemToTake); err != nil {
+			Logger.Error("Error returning item to container", "itemID", itemToTake.ID, "containerID", container.ID, "error", err)
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou need both hands free to hold %s.\n\r", itemToTake.Name)
+		return false
+	}
+
+	// Items already in a carried container count towards the load, so only check items from the room
+	if !carried && !character.CanCarryItem(itemToTake) {
+		if err := container.PutItem(itemToTake); err != nil {
+			Logger.Error("Error returning item to container", "itemID", itemToTake.ID, "containerID", container.ID, "error", err)
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is too heavy. You are already carrying %.1f of %.1f.\n\r", itemToTake.Name, character.CarriedMass(), character.CarryCapacity())
+		return false
+	}
+
+	hands, err := character.HoldItem(itemToTake)
+	if err != nil {
+		Logger.Error("Error holding item taken from container", "itemID", itemToTake.ID, "error", err)
+		if err := container.PutItem(itemToTake); err != nil {
+			Logger.Error("Error returning item to container", "itemID", itemToTake.ID, "containerID", container.ID, "error", err)
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return false
+	}
+
+	if err := character.Server.Database.WriteItem(character.Context(), container); err != nil {
+		Logger.Error("Error saving container contents", "containerID", container.ID, "error", err)
+	}
+
+	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s takes %s from %s.\n\r", character.Name, itemToTake.Name, container.Name))
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou take %s from %s and hold it in your %s.\n\r", itemToTake.Name, container.Name, hands)
+	character.RecordObjective(ObjectiveCollect, itemToTake.Name)
+	return false
+}
+
+func ExecutePutCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is putting an item in a container", "playerName", character.Player.PlayerID)
+
+	itemName, containerName, ok := splitTokens(tokens[1:], "in")
+	if !ok {
+		character.Player.ToPlayer <- "\n\rUsage: put <item name> in <container>\n\r"
+		return false
+	}
+
+	itemToPut := character.HeldItem(itemName)
+	if itemToPut == nil {
+		character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
+		return false
+	}
+
+	container, _ := findContainer(character, containerName)
+	if container == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see a container called %s.\n\r", containerName)
+		return false
+	}
+
+	if err := container.PutItem(itemToPut); err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return false
+	}
+
+	character.ReleaseItem(itemToPut)
+
+	// Writing the container also writes the contents it now holds
+	if err := character.Server.Database.WriteItem(character.Context(), container); err != nil {
+		Logger.Error("Error saving container contents", "containerID", container.ID, "error", err)
+	}
+
+	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s puts %s in %s.\n\r", character.Name, itemToPut.Name, container.Name))
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou put %s in %s.\n\r", itemToPut.Name, container.Name)
 	return false
 }
 
@@ -357,35 +1181,82 @@ func ExecuteDropCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
-	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
-	var itemToDrop *Item
-	var handSlot string
-
-	// Check if the item is in a hand slot
-	for slot, item := range character.Inventory {
-		if (slot == "left_hand" || slot == "right_hand") && strings.Contains(strings.ToLower(item.Name), itemName) {
-			itemToDrop = item
-			handSlot = slot
-			break
-		}
-	}
-
+	itemToDrop := character.HeldItem(strings.Join(tokens[1:], " "))
 	if itemToDrop == nil {
 		character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
 		return false
 	}
-	character.Mutex.Lock()
-	delete(character.Inventory, handSlot)
-	character.Mutex.Unlock()
-	character.Room.Mutex.Lock()
-	character.Room.AddItem(itemToDrop)
-	character.Room.Mutex.Unlock()
+	character.ReleaseItem(itemToDrop)
+	character.Room.DropItem(itemToDrop)
 
 	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou drop %s.\n\r", itemToDrop.Name)
 	SendRoomMessage(character.Room, fmt.Sprintf("\n\r%s drops %s.\n\r", character.Name, itemToDrop.Name))
 	return false
 }
 
+func ExecuteGiveCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is giving an item", "playerName", character.Player.PlayerID)
+
+	itemName, targetName, ok := splitTokens(tokens[1:], "to")
+	if !ok {
+		character.Player.ToPlayer <- "\n\rUsage: give <item name> to <character>\n\r"
+		return false
+	}
+
+	item := character.HeldItem(itemName)
+	if item == nil {
+		character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
+		return false
+	}
+
+	target := findCharacterInRoom(character.Room, targetName)
+	if target == nil || target == character {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", targetName)
+		return false
+	}
+
+	if !target.CanHold(item.TwoHanded) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s has no free hand to take %s.\n\r", target.Name, item.Name)
+		return false
+	}
+
+	if !target.CanCarryItem(item) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s cannot carry the weight of %s.\n\r", target.Name, item.Name)
+		return false
+	}
+
+	if !character.ReleaseItem(item) {
+		character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
+		return false
+	}
+
+	hands, err := target.HoldItem(item)
+	if err != nil {
+		// The target's hands filled up in the meantime, so the item goes back to the giver
+		if _, holdErr := character.HoldItem(item); holdErr != nil {
+			character.Room.DropItem(item)
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s has no free hand to take %s.\n\r", target.Name, item.Name)
+		return false
+	}
+
+	for _, c := range []*Character{character, target} {
+		if err := c.Server.SaveCharacter(c); err != nil {
+			Logger.Error("Error saving character after giving item", "characterName", c.Name, "itemID", item.ID, "error", err)
+		}
+	}
+
+	Logger.Info("Character gave item", "giver", character.Name, "receiver", target.Name, "itemID", item.ID)
+
+	sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s gives %s to %s.\n\r", character.Name, item.Name, target.Name), character, target)
+	if target.Player.Send(fmt.Sprintf("\n\r%s gives you %s. You hold it in your %s.\n\r", character.Name, item.Name, hands)) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou give %s to %s.\n\r", item.Name, target.Name)
+	return false
+}
+
 func ExecuteWearCommand(character *Character, tokens []string) bool {
 
 	Logger.Info("Player is attempting to wear an item", "playerName", character.Player.PlayerID)
@@ -429,6 +1300,14 @@ func ExecuteRemoveCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
+	// remove <number> takes a post down from the board in the room
+	if _, err := strconv.Atoi(tokens[1]); err == nil && len(tokens) == 2 {
+		if board := findBoard(character.Room, ""); board != nil {
+			removePost(character, board, tokens[1])
+			return false
+		}
+	}
+
 	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
 	var itemToRemove *Item
 
@@ -464,26 +1343,18 @@ func ExecuteExamineCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
-	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
-
-	// Check inventory first
-	item := character.FindInInventory(itemName)
-
-	// If not in inventory, check room
-	if item == nil {
-		for _, roomItem := range character.Room.Items {
-			if strings.Contains(strings.ToLower(roomItem.Name), itemName) {
-				item = roomItem
-				break
-			}
-		}
-	}
-
+	item := character.findItemInReach(strings.Join(tokens[1:], " "))
 	if item == nil {
 		character.Player.ToPlayer <- "\n\rYou don't see that item here.\n\r"
 		return false
 	}
 
+	character.Player.ToPlayer <- describeItem(item)
+	return false
+}
+
+// describeItem lists everything known about an item for examine and look.
+func describeItem(item *Item) string {
 	description := fmt.Sprintf("\n\rItem: %s (ID: %s)\n\r", item.Name, item.ID)
 	description += fmt.Sprintf("Description: %s\n\r", item.Description)
 	description += fmt.Sprintf("Mass: %.2f\n\r", item.Mass)
@@ -493,15 +1364,22 @@ func ExecuteExamineCommand(character *Character, tokens []string) bool {
 		description += fmt.Sprintf("Quantity: %d/%d\n\r", item.Quantity, item.MaxStack)
 	}
 
+	if item.TwoHanded {
+		description += "It takes both hands to hold.\n\r"
+	}
+
 	if item.Wearable {
 		description += fmt.Sprintf("Wearable on: %s\n\r", strings.Join(item.WornOn, ", "))
+		if item.Layer != 0 {
+			description += fmt.Sprintf("Layer: %d\n\r", item.Layer)
+		}
 		if item.IsWorn {
 			description += "This item is currently being worn.\n\r"
 		}
 	}
 
 	if item.Container {
-		description += "This is a container.\n\r"
+		description += fmt.Sprintf("This is a container holding %.1f of %.1f.\n\r", item.ContentsMass(), item.ContainerCapacity())
 		if len(item.Contents) > 0 {
 			description += "It contains:\n\r"
 			for _, contentItem := range item.Contents {
@@ -512,11 +1390,8 @@ func ExecuteExamineCommand(character *Character, tokens []string) bool {
 		}
 	}
 
-	if len(item.Verbs) > 0 {
-		description += "Special actions:\n\r"
-		for verb, action := range item.Verbs {
-			description += fmt.Sprintf("  %s: %s\n\r", verb, action)
-		}
+	if verbs := item.VerbNames(); len(verbs) > 0 {
+		description += fmt.Sprintf("Special actions: %s\n\r", strings.Join(verbs, ", "))
 	}
 
 	if len(item.TraitMods) > 0 {
@@ -533,8 +1408,7 @@ func ExecuteExamineCommand(character *Character, tokens []string) bool {
 		}
 	}
 
-	character.Player.ToPlayer <- description
-	return false
+	return description
 }
 
 func ExecuteAssessCommand(character *Character, tokens []string) bool {
@@ -599,7 +1473,7 @@ func ExecuteFaceCommand(character *Character, tokens []string) bool {
 
 	// Find the target character in the same room
 	for _, c := range character.Room.Characters {
-		if strings.EqualFold(c.Name, targetName) {
+		if c.IsActive() && strings.EqualFold(c.Name, targetName) {
 			targetCharacter = c
 			break
 		}
@@ -610,6 +1484,19 @@ func ExecuteFaceCommand(character *Character, tokens []string) bool {
 		return false
 	}
 
+	if targetCharacter.IsProtected() {
+		character.Player.ToPlayer <- "\n\rThey are under the protection of the gods.\n\r"
+		return false
+	}
+
+	if !character.Server.AllowsPvP(character.Room) {
+		character.Player.ToPlayer <- "\n\rFighting other adventurers is not allowed here.\n\r"
+		return false
+	}
+
+	// Taking an aggressive action ends the attacker's own protection
+	character.ClearProtection()
+
 	// Set facing for the character executing the command
 	character.SetFacing(targetCharacter)
 
@@ -623,33 +1510,250 @@ func ExecuteFaceCommand(character *Character, tokens []string) bool {
 	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are now facing %s at far range.\n\r", targetCharacter.Name)
 
 	// Notify the target character
-	targetCharacter.Player.ToPlayer <- fmt.Sprintf("\n\r%s is now facing you at far range.\n\r", character.Name)
-	targetCharacter.Player.ToPlayer <- targetCharacter.Player.Prompt
+	if targetCharacter.IsActive() {
+		targetCharacter.Player.Send(fmt.Sprintf("\n\r%s is now facing you at far range.\n\r", character.Name))
+		targetCharacter.Player.Send(targetCharacter.Player.RenderPrompt())
+	}
 
 	return false
 }
 
+func ExecuteAttackCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is attacking", "playerName", character.Player.PlayerID)
+
+	var targetCharacter *Character
+
+	if len(tokens) < 2 {
+		targetCharacter = character.GetFacing()
+		if targetCharacter == nil {
+			character.Player.ToPlayer <- "\n\rUsage: attack <character>, or face a character first\n\r"
+			return false
+		}
+	} else {
+		targetName := strings.Join(tokens[1:], " ")
+		for _, c := range character.Room.Characters {
+			if c != character && c.IsActive() && strings.EqualFold(c.Name, targetName) {
+				targetCharacter = c
+				break
+			}
+		}
+		if targetCharacter == nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", targetName)
+			return false
+		}
+	}
+
+	if targetCharacter.Room != character.Room || !targetCharacter.IsActive() {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is no longer here.\n\r", targetCharacter.Name)
+		character.ClearFacing()
+		return false
+	}
+
+	if targetCharacter.IsProtected() {
+		character.Player.ToPlayer <- "\n\rThey are under the protection of the gods.\n\r"
+		return false
+	}
+
+	if !character.Server.AllowsPvP(character.Room) {
+		character.Player.ToPlayer <- "\n\rFighting other adventurers is not allowed here.\n\r"
+		return false
+	}
+
+	// Further blows land each combat round, so attacking again would only strike twice
+	if character.IsAttacking() && character.GetFacing() == targetCharacter {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are already attacking %s.\n\r", targetCharacter.Name)
+		return false
+	}
+
+	Engage(character, targetCharacter)
+	ResolveAttack(character, targetCharacter)
+
+	return false
+}
+
+func ExecuteFilterCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing their profanity filter", "playerName", character.Player.PlayerID)
+
+	if !character.Server.Settings().Game.ProfanityFilter {
+		character.Player.ToPlayer <- "\n\rThe profanity filter is not enabled on this server.\n\r"
+		return false
+	}
+
+	if len(tokens) != 2 || (strings.ToLower(tokens[1]) != "on" && strings.ToLower(tokens[1]) != "off") {
+		status := "on"
+		if character.Player.ShowProfanity {
+			status = "off"
+		}
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYour profanity filter is %s.\n\rUsage: filter <on|off>\n\r", status)
+		return false
+	}
+
+	character.Player.Mutex.Lock()
+	character.Player.ShowProfanity = strings.ToLower(tokens[1]) == "off"
+	character.Player.Mutex.Unlock()
+
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving profanity filter setting", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rProfanity filter turned %s.\n\r", strings.ToLower(tokens[1]))
+	return false
+}
+
+// AreaPageSize is the number of rooms shown per page by the area command.
+const AreaPageSize = 20
+
+func ExecuteAreaCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is listing the rooms in their area", "playerName", character.Player.PlayerID)
+
+	area := character.Room.Area
+	name := character.Server.AreaName(character.Room)
+
+	// Players only see the area's details; builders get the full room listing
+	if !character.Player.IsBuilder() {
+		var output strings.Builder
+		output.WriteString(fmt.Sprintf("\n\rYou are in %s.\n\r", name))
+		if definition := character.Server.AreaOf(character.Room); definition != nil {
+			if levels := definition.LevelRange(); levels != "" {
+				output.WriteString(fmt.Sprintf("It is meant for %s.\n\r", levels))
+			}
+			if definition.PvP {
+				output.WriteString("Adventurers may fight each other here.\n\r")
+			}
+		}
+		character.Player.ToPlayer <- output.String()
+		return false
+	}
+
+	rooms := character.Server.AreaRooms(area)
+
+	pages := (len(rooms) + AreaPageSize - 1) / AreaPageSize
+	if pages == 0 {
+		pages = 1
+	}
+
+	page := 1
+	if len(tokens) > 1 {
+		var err error
+		page, err = strconv.Atoi(tokens[1])
+		if err != nil || page < 1 || page > pages {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: area [page], where page is between 1 and %d\n\r", pages)
+			return false
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\rRooms in %s (%d total, page %d of %d):\n\r", name, len(rooms), page, pages))
+
+	start := (page - 1) * AreaPageSize
+	end := start + AreaPageSize
+	if end > len(rooms) {
+		end = len(rooms)
+	}
+
+	for _, room := range rooms[start:end] {
+		marker := " "
+		if room == character.Room {
+			marker = "*"
+		}
+		output.WriteString(fmt.Sprintf("%s%6d  %s\n\r", marker, room.RoomID, room.Title))
+	}
+
+	if page < pages {
+		output.WriteString(fmt.Sprintf("Type 'area %d' for the next page.\n\r", page+1))
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
+
 func ExecuteHelpCommand(character *Character, tokens []string) bool {
 
 	Logger.Info("Player is requesting help", "playerName", character.Player.PlayerID)
 
 	helpMessage := "\n\rAvailable Commands:" +
 		"\n\rhelp - Display available commands" +
+		"\n\rCommands may be shortened to any unambiguous prefix, and n, s, e, w, u and d move you." +
 		"\n\rshow - Display character information" +
 		"\n\rsay <message> - Say something to all players" +
-		"\n\rlook - Look around the room" +
+		"\n\rtell <character> <message> - Send a private message to a character anywhere in the game" +
+		"\n\rwhisper <character> <message> - Whisper privately to a character in the room" +
+		"\n\remote <text> - Describe an action to everyone in the room" +
+		"\n\r" + strings.Join(SocialNames(), ", ") + " [character] - Perform a social, optionally at someone" +
+		"\n\rlook [target] - Look around the room, or at a direction, someone or an item in it" +
+		"\n\rdescription <text> - Set the description others see when they look at you" +
 		"\n\rgo <direction> - Move in a direction" +
+		"\n\rsit/rest/sleep - Settle down to recover health and essence faster" +
+		"\n\rstand/wake - Get back on your feet so you can move" +
+		"\n\ropen/close <direction> - Open or close a door" +
+		"\n\rlock/unlock <direction> - Lock or unlock a door with its key" +
 		"\n\rtake <item> - Take an item from the room" +
 		"\n\rdrop <item> - Drop a held item" +
+		"\n\rgive <item> to <character> - Hand a held item to another character in the room" +
+		"\n\rtrade <character> - Trade items and coins; see 'trade' for offer, coins, confirm and cancel" +
+		"\n\rput <item> in <container> - Put a held item into a container" +
+		"\n\rget <item> from <container> - Take an item out of a container" +
 		"\n\rwear <item> - Wear an item from your inventory" +
 		"\n\rremove <item> - Remove a worn item" +
+		"\n\rread <board> [page] - List the posts on a bulletin board" +
+		"\n\rread <number> - Read a post on the bulletin board here" +
+		"\n\rpost <title> | <message> - Pin a post to the bulletin board here" +
+		"\n\rremove <number> - Take your post down from the bulletin board here" +
 		"\n\rexamine <item> - Get detailed information about an item" +
 		"\n\rinventory (or i) - Check your inventory" +
 		"\n\rassess - Assess your current combat situation" +
 		"\n\rface <character> - Face a character in the room" +
-		"\n\rwho - List all characters online" +
-		"\n\rpassword <oldPassword> <newPassword> - Change your password" +
-		"\n\rquit - Quit the game\n\r"
+		"\n\rattack [character] - Attack the character you are facing, or the one named, every round until the fight ends" +
+		"\n\rcast <spell> [target] - Cast a spell, on yourself unless a target is named" +
+		"\n\rtrain (or advance) [attribute or ability] - Show your progress, or spend training points to raise a score" +
+		"\n\rlist - List the goods of a merchant in the room" +
+		"\n\rbuy <item> - Buy an item from a merchant in the room" +
+		"\n\rsell <item> - Sell an item you are holding to a merchant in the room" +
+		"\n\rquest [list | accept <quest> | abandon <quest>] - Show your quest log, or take on or give up a quest" +
+		"\n\rarea [page] - Show the area you are in" +
+		"\n\rmap [radius] - Draw a map of the rooms around you" +
+		"\n\rtime - Show the time of day in the game world" +
+		"\n\rweather - Show the weather where you are" +
+		"\n\rlight <item> - Light a torch or lamp you are carrying so you can see in the dark" +
+		"\n\rextinguish <item> - Put out a light you are carrying" +
+		"\n\rwho [area|friends] - List characters online, optionally only friends or those in an area" +
+		"\n\rwhois <character> - Show a character's public profile" +
+		"\n\rchannels - List the chat channels" +
+		"\n\rjoin <channel> - Join a chat channel" +
+		"\n\rleave <channel> - Leave a chat channel" +
+		"\n\r<channel> <message> - Speak on a chat channel you have joined" +
+		"\n\rchat <channel> <message> - Speak on a chat channel, including the area channel" +
+		"\n\rmute [character] - Hide a character's channel messages, or list who you have muted" +
+		"\n\runmute <character> - Show a character's channel messages again" +
+		"\n\rignore [character] - Hide everything a character says to you, or list who you ignore" +
+		"\n\runignore <character> - Hear an ignored character again" +
+		"\n\rfriend [character] - Add a character to your friends list, or list your friends" +
+		"\n\runfriend <character> - Remove a character from your friends list" +
+		"\n\rbug <text> - Report a bug to the administrators" +
+		"\n\rtypo <text> - Report a typo to the administrators" +
+		"\n\ridea <text> - Suggest an idea to the administrators" +
+		"\n\rfilter <on|off> - Toggle the profanity filter on what others say" +
+		"\n\ralias <name> <command> - Define a shortcut for a command, extra words are appended" +
+		"\n\runalias <name> - Remove one of your aliases" +
+		"\n\raliases - List your aliases" +
+		"\n\rprompt <format|default> - Set your prompt, use prompt alone to list the tokens" +
+		"\n\rcolor <on|off> - Toggle colored output, or color theme <name> to pick a theme" +
+		"\n\recho <on|off> - Toggle whether your typing is echoed back to you" +
+		"\n\rpassword - Change your password" +
+		"\n\rsshkey [list|add <key>|remove <number>] - Manage the SSH keys you can log in with" +
+		"\n\rquit - Quit the game" +
+		"\n\rquit! (or q!) - Quit the game immediately, even while in combat\n\r"
+
+	if character.Player.IsBuilder() {
+		helpMessage += BuilderHelp + "\n\r"
+	}
+
+	if character.Player.IsAdmin() {
+		helpMessage += AdminHelp + "\n\r"
+	}
 
 	character.Player.ToPlayer <- helpMessage
 	return false
@@ -1,59 +1,173 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/google/uuid"
 )
 
+// ErrItemNotFound is returned by Get when no item has the requested key.
+var ErrItemNotFound = errors.New("item not found")
+
+// ErrVersionConflict is returned by versioned writes when the stored record is not at the
+// version the writer expected, because another server or an earlier run wrote it first.
+var ErrVersionConflict = errors.New("version conflict")
+
+// DataStore is a storage backend for KeyPair. Keys, queries and partial updates are expressed
+// with DynamoDB attribute values whichever backend is in use.
+type DataStore interface {
+	Ping(ctx context.Context) error
+	Put(ctx context.Context, tableName string, item interface{}) error
+	Get(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, item interface{}) error
+	Delete(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue) error
+	BatchPut(ctx context.Context, tableName string, items []interface{}) error
+	PutVersioned(ctx context.Context, tableName string, item interface{}) error
+	BatchPutVersioned(ctx context.Context, tableName string, items []interface{}) ([]int, error)
+	Update(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, set map[string]ddbtypes.AttributeValue, remove []string) error
+	Query(ctx context.Context, tableName string, keyConditionExpression string, expressionAttributeValues map[string]ddbtypes.AttributeValue, items interface{}) error
+	Scan(ctx context.Context, tableName string, items interface{}) error
+	ScanPages(ctx context.Context, tableName string, page func([]map[string]ddbtypes.AttributeValue) error) error
+	ParallelScan(ctx context.Context, tableName string, segments int, items interface{}) error
+	Close() error
+}
+
+// DynamoStore is the DataStore backed by Amazon DynamoDB.
+type DynamoStore struct {
+	db *dynamodb.Client
+}
+
+// OpenKeyPair opens the storage backend selected by the Storage section of the configuration,
+// with a cache in front of it unless CacheSeconds is negative.
+func OpenKeyPair(ctx context.Context, config Configuration) (*KeyPair, error) {
+	var kp *KeyPair
+	var err error
+	switch strings.ToLower(config.Storage.Backend) {
+	case "", "dynamodb":
+		kp, err = NewKeyPair(ctx, config.Aws.Region)
+	case "bolt":
+		kp, err = NewBoltKeyPair(config.Storage.Path)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.Storage.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.Storage.CacheSeconds == 0:
+		kp.Cache = NewCache(DefaultCacheTTL)
+	case config.Storage.CacheSeconds > 0:
+		kp.Cache = NewCache(time.Duration(config.Storage.CacheSeconds) * time.Second)
+	default:
+		DatabaseLog.Info("Record cache disabled")
+	}
+
+	return kp, nil
+}
+
 // NewKeyPair initializes a new DynamoDB client.
-func NewKeyPair(region string) (*KeyPair, error) {
-	Logger.Info("Initializing DynamoDB client", "region", region)
+func NewKeyPair(ctx context.Context, region string) (*KeyPair, error) {
+	DatabaseLog.Info("Initializing DynamoDB client", "region", region)
 
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region),
-	})
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
-		return nil, fmt.Errorf("error creating AWS session: %w", err)
+		return nil, fmt.Errorf("error loading AWS SDK config: %w", err)
 	}
 
-	svc := dynamodb.New(sess)
+	instrumentAWS(&cfg)
+	svc := dynamodb.NewFromConfig(cfg)
 
 	return &KeyPair{
-		db: svc,
+		DataStore: &DynamoStore{db: svc},
+		Instance:  uuid.New().String(),
 	}, nil
 }
 
-func (k *KeyPair) Put(tableName string, item interface{}) error {
-	av, err := dynamodbattribute.MarshalMap(item)
+// Close releases the backend. DynamoDB clients hold no resources that need closing.
+func (k *DynamoStore) Close() error {
+	return nil
+}
+
+// Ping checks that DynamoDB can be reached with the configured credentials.
+func (k *DynamoStore) Ping(ctx context.Context) error {
+	_, err := k.db.ListTables(ctx, &dynamodb.ListTablesInput{
+		Limit: aws.Int32(1),
+	})
 	if err != nil {
-		return fmt.Errorf("error marshalling item: %w", err)
+		return fmt.Errorf("error reaching DynamoDB: %w", err)
 	}
+	return nil
+}
 
-	input := &dynamodb.PutItemInput{
+func (k *DynamoStore) Put(ctx context.Context, tableName string, item interface{}) error {
+	av, err := marshalRecord(tableName, item)
+	if err != nil {
+		return err
+	}
+
+	return k.putItem(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(tableName),
+	})
+}
+
+// PutVersioned stores an item whose Version attribute is one more than the version it replaces.
+// The write fails with ErrVersionConflict when the stored record is at any other version.
+func (k *DynamoStore) PutVersioned(ctx context.Context, tableName string, item interface{}) error {
+	put, err := versionedPut(tableName, item)
+	if err != nil {
+		return err
 	}
 
+	return k.putItem(ctx, &dynamodb.PutItemInput{
+		Item:                      put.Item,
+		TableName:                 put.TableName,
+		ConditionExpression:       put.ConditionExpression,
+		ExpressionAttributeNames:  put.ExpressionAttributeNames,
+		ExpressionAttributeValues: put.ExpressionAttributeValues,
+	})
+}
+
+// putItem sends a PutItem request, retrying throttled requests with backoff.
+func (k *DynamoStore) putItem(ctx context.Context, input *dynamodb.PutItemInput) error {
+	tableName := aws.ToString(input.TableName)
+
 	// Implement retries with exponential backoff
 	const maxRetries = 3
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		_, err = k.db.PutItem(input)
+		start := time.Now()
+		_, err := k.db.PutItem(ctx, input)
+		RecordDatabaseCall("PutItem", time.Since(start), err)
 		if err != nil {
+			var conditionErr *ddbtypes.ConditionalCheckFailedException
+			if errors.As(err, &conditionErr) {
+				return fmt.Errorf("%w putting item into table %s", ErrVersionConflict, tableName)
+			}
 			if isRetryableError(err) && attempt < maxRetries-1 {
 				backoffDuration := time.Duration(attempt+1) * time.Second
-				Logger.Warn("Retryable error in PutItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
-				time.Sleep(backoffDuration)
+				RecordDatabaseRetry("PutItem")
+				DatabaseLog.Warn("Retryable error in PutItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("error putting item into table %s: %w", tableName, err)
 		}
-		Logger.Info("Successfully put item into table", "tableName", tableName)
+		DatabaseLog.Info("Successfully put item into table", "tableName", tableName)
 		return nil
 	}
 
@@ -61,7 +175,7 @@ func (k *KeyPair) Put(tableName string, item interface{}) error {
 }
 
 // Get retrieves an item from the DynamoDB table.
-func (k *KeyPair) Get(tableName string, key map[string]*dynamodb.AttributeValue, item interface{}) error {
+func (k *DynamoStore) Get(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, item interface{}) error {
 	input := &dynamodb.GetItemInput{
 		Key:       key,
 		TableName: aws.String(tableName),
@@ -71,12 +185,17 @@ func (k *KeyPair) Get(tableName string, key map[string]*dynamodb.AttributeValue,
 	var result *dynamodb.GetItemOutput
 	var err error
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		result, err = k.db.GetItem(input)
+		start := time.Now()
+		result, err = k.db.GetItem(ctx, input)
+		RecordDatabaseCall("GetItem", time.Since(start), err)
 		if err != nil {
 			if isRetryableError(err) && attempt < maxRetries-1 {
 				backoffDuration := time.Duration(attempt+1) * time.Second
-				Logger.Warn("Retryable error in GetItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
-				time.Sleep(backoffDuration)
+				RecordDatabaseRetry("GetItem")
+				DatabaseLog.Warn("Retryable error in GetItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("error getting item from table %s: %w", tableName, err)
@@ -84,11 +203,12 @@ func (k *KeyPair) Get(tableName string, key map[string]*dynamodb.AttributeValue,
 		break
 	}
 
-	if result.Item == nil {
-		return fmt.Errorf("item not found in table %s", tableName)
+	if len(result.Item) == 0 {
+		return fmt.Errorf("%w in table %s", ErrItemNotFound, tableName)
 	}
 
-	err = dynamodbattribute.UnmarshalMap(result.Item, item)
+	migrateRecords(tableName, []map[string]ddbtypes.AttributeValue{result.Item})
+	err = attributevalue.UnmarshalMap(result.Item, item)
 	if err != nil {
 		return fmt.Errorf("error unmarshalling item: %w", err)
 	}
@@ -97,7 +217,7 @@ func (k *KeyPair) Get(tableName string, key map[string]*dynamodb.AttributeValue,
 }
 
 // Delete removes an item from the DynamoDB table.
-func (k *KeyPair) Delete(tableName string, key map[string]*dynamodb.AttributeValue) error {
+func (k *DynamoStore) Delete(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue) error {
 	input := &dynamodb.DeleteItemInput{
 		Key:       key,
 		TableName: aws.String(tableName),
@@ -105,25 +225,312 @@ func (k *KeyPair) Delete(tableName string, key map[string]*dynamodb.AttributeVal
 
 	const maxRetries = 3
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		_, err := k.db.DeleteItem(input)
+		start := time.Now()
+		_, err := k.db.DeleteItem(ctx, input)
+		RecordDatabaseCall("DeleteItem", time.Since(start), err)
 		if err != nil {
 			if isRetryableError(err) && attempt < maxRetries-1 {
 				backoffDuration := time.Duration(attempt+1) * time.Second
-				Logger.Warn("Retryable error in DeleteItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
-				time.Sleep(backoffDuration)
+				RecordDatabaseRetry("DeleteItem")
+				DatabaseLog.Warn("Retryable error in DeleteItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("error deleting item from table %s: %w", tableName, err)
 		}
-		Logger.Info("Successfully deleted item from table", "tableName", tableName)
+		DatabaseLog.Info("Successfully deleted item from table", "tableName", tableName)
 		return nil
 	}
 
 	return fmt.Errorf("failed to delete item from table %s after %d attempts", tableName, maxRetries)
 }
 
+// BatchWriteLimit is the most items DynamoDB accepts in a single BatchWriteItem call.
+const BatchWriteLimit = 25
+
+// BatchPut stores many items into the DynamoDB table, BatchWriteLimit at a time. Items DynamoDB
+// leaves unprocessed are retried with backoff.
+func (k *DynamoStore) BatchPut(ctx context.Context, tableName string, items []interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	requests := make([]ddbtypes.WriteRequest, 0, len(items))
+	for _, item := range items {
+		av, err := marshalRecord(tableName, item)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, ddbtypes.WriteRequest{PutRequest: &ddbtypes.PutRequest{Item: av}})
+	}
+
+	for start := 0; start < len(requests); start += BatchWriteLimit {
+		end := start + BatchWriteLimit
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if err := k.batchWrite(ctx, tableName, requests[start:end]); err != nil {
+			return err
+		}
+	}
+
+	DatabaseLog.Info("Successfully batch wrote items to table", "tableName", tableName, "count", len(requests))
+	return nil
+}
+
+// batchWrite sends one chunk of write requests, retrying any that DynamoDB leaves unprocessed.
+func (k *DynamoStore) batchWrite(ctx context.Context, tableName string, requests []ddbtypes.WriteRequest) error {
+	const maxRetries = 5
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		start := time.Now()
+		output, err := k.db.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]ddbtypes.WriteRequest{tableName: requests},
+		})
+		RecordDatabaseCall("BatchWriteItem", time.Since(start), err)
+		if err != nil {
+			if isRetryableError(err) && attempt < maxRetries-1 {
+				backoffDuration := time.Duration(attempt+1) * time.Second
+				RecordDatabaseRetry("BatchWriteItem")
+				DatabaseLog.Warn("Retryable error in BatchWriteItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("error batch writing items to table %s: %w", tableName, err)
+		}
+
+		requests = output.UnprocessedItems[tableName]
+		if len(requests) == 0 {
+			return nil
+		}
+
+		backoffDuration := time.Duration(attempt+1) * time.Second
+		RecordDatabaseRetry("BatchWriteItem")
+		DatabaseLog.Warn("Unprocessed items in BatchWriteItem, will retry", "attempt", attempt+1, "unprocessed", len(requests), "backoff", backoffDuration)
+		if err := sleepContext(ctx, backoffDuration); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to write %d items to table %s after %d attempts", len(requests), tableName, maxRetries)
+}
+
+// VersionedPutConcurrency is how many conditional puts BatchPutVersioned keeps in flight at once.
+const VersionedPutConcurrency = 10
+
+// BatchPutVersioned stores many versioned items with the same version check as PutVersioned.
+// BatchWriteItem cannot take conditions, so each item is a conditional PutItem, several at a
+// time. A conditional put uses the same write capacity as an item in a batch write, where
+// TransactWriteItems would use twice as much. Items that fail their version check are left
+// unwritten and their indexes returned so the caller can resolve the conflicts.
+func (k *DynamoStore) BatchPutVersioned(ctx context.Context, tableName string, items []interface{}) ([]int, error) {
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		conflicts []int
+		errs      []error
+	)
+	slots := make(chan struct{}, VersionedPutConcurrency)
+	for i, item := range items {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, item interface{}) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			err := k.PutVersioned(ctx, tableName, item)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch {
+			case errors.Is(err, ErrVersionConflict):
+				conflicts = append(conflicts, i)
+			case err != nil:
+				errs = append(errs, err)
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	sort.Ints(conflicts)
+	if err := errors.Join(errs...); err != nil {
+		return conflicts, fmt.Errorf("error writing %d versioned items to table %s: %w", len(errs), tableName, err)
+	}
+
+	if len(items) > 0 {
+		DatabaseLog.Info("Successfully wrote versioned items to table", "tableName", tableName, "count", len(items)-len(conflicts), "conflicts", len(conflicts))
+	}
+	return conflicts, nil
+}
+
+// versionedPut builds the conditional put of a versioned item for PutVersioned.
+func versionedPut(tableName string, item interface{}) (*ddbtypes.Put, error) {
+	av, err := marshalRecord(tableName, item)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	values := make(map[string]ddbtypes.AttributeValue)
+	condition, err := versionCondition(av["Version"], names, values)
+	if err != nil {
+		return nil, err
+	}
+
+	put := &ddbtypes.Put{
+		Item:                     av,
+		TableName:                aws.String(tableName),
+		ConditionExpression:      aws.String(condition),
+		ExpressionAttributeNames: names,
+	}
+	if len(values) > 0 {
+		put.ExpressionAttributeValues = values
+	}
+	return put, nil
+}
+
+// marshalRecord converts an item to the record written for it, tagged with the table's schema
+// version. Items that are already records, such as those rewritten by migrations, are written as is.
+func marshalRecord(tableName string, item interface{}) (map[string]ddbtypes.AttributeValue, error) {
+	av, ok := item.(map[string]ddbtypes.AttributeValue)
+	if !ok {
+		var err error
+		if av, err = attributevalue.MarshalMap(item); err != nil {
+			return nil, fmt.Errorf("error marshalling item: %w", err)
+		}
+	}
+	stampSchemaVersion(tableName, av)
+	return av, nil
+}
+
+// versionCondition returns the condition that the stored record is one version behind next, the
+// Version being written, adding its placeholders to names and values. Records written before
+// versioning was added have no Version and count as version 0.
+func versionCondition(next ddbtypes.AttributeValue, names map[string]string, values map[string]ddbtypes.AttributeValue) (string, error) {
+	version, err := versionNumber(next)
+	if err != nil {
+		return "", err
+	}
+
+	names["#version"] = "Version"
+	if version <= 1 {
+		return "attribute_not_exists(#version)", nil
+	}
+	values[":version"] = &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(version-1, 10)}
+	return "#version = :version", nil
+}
+
+// versionNumber reads a Version attribute value.
+func versionNumber(value ddbtypes.AttributeValue) (int64, error) {
+	number, ok := value.(*ddbtypes.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("versioned item has no numeric Version")
+	}
+	version, err := strconv.ParseInt(number.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Version %q: %w", number.Value, err)
+	}
+	return version, nil
+}
+
+// Update sets and removes individual attributes of an existing item in the DynamoDB table.
+// The update fails rather than creating a partial item when the key does not exist. When set
+// includes a Version, the update is conditional on the stored version as in PutVersioned.
+func (k *DynamoStore) Update(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, set map[string]ddbtypes.AttributeValue, remove []string) error {
+	names := make(map[string]string)
+	values := make(map[string]ddbtypes.AttributeValue)
+
+	setNames := make([]string, 0, len(set))
+	for name := range set {
+		setNames = append(setNames, name)
+	}
+	sort.Strings(setNames)
+
+	var setClauses, removeClauses, conditions []string
+	for i, name := range setNames {
+		placeholder := fmt.Sprintf("#s%d", i)
+		names[placeholder] = name
+		values[fmt.Sprintf(":s%d", i)] = set[name]
+		setClauses = append(setClauses, fmt.Sprintf("%s = :s%d", placeholder, i))
+	}
+	for i, name := range remove {
+		placeholder := fmt.Sprintf("#r%d", i)
+		names[placeholder] = name
+		removeClauses = append(removeClauses, placeholder)
+	}
+	i := 0
+	for name := range key {
+		placeholder := fmt.Sprintf("#k%d", i)
+		names[placeholder] = name
+		conditions = append(conditions, fmt.Sprintf("attribute_exists(%s)", placeholder))
+		i++
+	}
+
+	if len(setClauses) == 0 && len(removeClauses) == 0 {
+		return nil
+	}
+
+	version, versioned := set["Version"]
+	if versioned {
+		condition, err := versionCondition(version, names, values)
+		if err != nil {
+			return err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	var expression []string
+	if len(setClauses) > 0 {
+		expression = append(expression, "SET "+strings.Join(setClauses, ", "))
+	}
+	if len(removeClauses) > 0 {
+		expression = append(expression, "REMOVE "+strings.Join(removeClauses, ", "))
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                aws.String(tableName),
+		Key:                      key,
+		UpdateExpression:         aws.String(strings.Join(expression, " ")),
+		ConditionExpression:      aws.String(strings.Join(conditions, " AND ")),
+		ExpressionAttributeNames: names,
+	}
+	if len(values) > 0 {
+		input.ExpressionAttributeValues = values
+	}
+
+	const maxRetries = 3
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		start := time.Now()
+		_, err := k.db.UpdateItem(ctx, input)
+		RecordDatabaseCall("UpdateItem", time.Since(start), err)
+		if err != nil {
+			var conditionErr *ddbtypes.ConditionalCheckFailedException
+			if versioned && errors.As(err, &conditionErr) {
+				return fmt.Errorf("%w updating item in table %s", ErrVersionConflict, tableName)
+			}
+			if isRetryableError(err) && attempt < maxRetries-1 {
+				backoffDuration := time.Duration(attempt+1) * time.Second
+				RecordDatabaseRetry("UpdateItem")
+				DatabaseLog.Warn("Retryable error in UpdateItem, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("error updating item in table %s: %w", tableName, err)
+		}
+		DatabaseLog.Info("Successfully updated item in table", "tableName", tableName, "attributes", len(setNames)+len(remove))
+		return nil
+	}
+
+	return fmt.Errorf("failed to update item in table %s after %d attempts", tableName, maxRetries)
+}
+
 // Query performs a query operation on the DynamoDB table.
-func (k *KeyPair) Query(tableName string, keyConditionExpression string, expressionAttributeValues map[string]*dynamodb.AttributeValue, items interface{}) error {
+func (k *DynamoStore) Query(ctx context.Context, tableName string, keyConditionExpression string, expressionAttributeValues map[string]ddbtypes.AttributeValue, items interface{}) error {
 	input := &dynamodb.QueryInput{
 		TableName:                 aws.String(tableName),
 		KeyConditionExpression:    aws.String(keyConditionExpression),
@@ -135,12 +542,17 @@ func (k *KeyPair) Query(tableName string, keyConditionExpression string, express
 	var result *dynamodb.QueryOutput
 	var err error
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		result, err = k.db.Query(input)
+		start := time.Now()
+		result, err = k.db.Query(ctx, input)
+		RecordDatabaseCall("Query", time.Since(start), err)
 		if err != nil {
 			if isRetryableError(err) && attempt < maxRetries-1 {
 				backoffDuration := time.Duration(attempt+1) * time.Second
-				Logger.Warn("Retryable error in Query, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
-				time.Sleep(backoffDuration)
+				RecordDatabaseRetry("Query")
+				DatabaseLog.Warn("Retryable error in Query, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+				if err := sleepContext(ctx, backoffDuration); err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("error querying table %s: %w", tableName, err)
@@ -148,7 +560,8 @@ func (k *KeyPair) Query(tableName string, keyConditionExpression string, express
 		break
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, items)
+	migrateRecords(tableName, result.Items)
+	err = attributevalue.UnmarshalListOfMaps(result.Items, items)
 	if err != nil {
 		return fmt.Errorf("error unmarshalling query results: %w", err)
 	}
@@ -156,44 +569,129 @@ func (k *KeyPair) Query(tableName string, keyConditionExpression string, express
 	return nil
 }
 
-// Scan performs a scan operation on the DynamoDB table.
-func (k *KeyPair) Scan(tableName string, items interface{}) error {
-	input := &dynamodb.ScanInput{
-		TableName: aws.String(tableName),
+// DefaultScanSegments is the number of parallel segments used when loading large tables at startup.
+const DefaultScanSegments = 4
+
+// Scan reads every item in the DynamoDB table, following LastEvaluatedKey across pages.
+func (k *DynamoStore) Scan(ctx context.Context, tableName string, items interface{}) error {
+	var all []map[string]ddbtypes.AttributeValue
+	err := k.ScanPages(ctx, tableName, func(page []map[string]ddbtypes.AttributeValue) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Implement retries with exponential backoff
-	const maxRetries = 3
-	var result *dynamodb.ScanOutput
-	var err error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		result, err = k.db.Scan(input)
-		if err != nil {
-			if isRetryableError(err) && attempt < maxRetries-1 {
-				backoffDuration := time.Duration(attempt+1) * time.Second
-				Logger.Warn("Retryable error in Scan, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
-				time.Sleep(backoffDuration)
-				continue
+	migrateRecords(tableName, all)
+	err = attributevalue.UnmarshalListOfMaps(all, items)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling scan results: %w", err)
+	}
+
+	return nil
+}
+
+// ScanPages streams the DynamoDB table to page one page at a time, so callers can process large
+// tables without holding them in memory. Scanning stops at the first error page returns. Records
+// are passed as stored, without the migrations the other reads apply.
+func (k *DynamoStore) ScanPages(ctx context.Context, tableName string, page func([]map[string]ddbtypes.AttributeValue) error) error {
+	return k.scanAll(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName)}, page)
+}
+
+// ParallelScan reads every item in the DynamoDB table using the given number of segments scanned
+// concurrently, which shortens loading large tables such as rooms and items.
+func (k *DynamoStore) ParallelScan(ctx context.Context, tableName string, segments int, items interface{}) error {
+	if segments < 2 {
+		return k.Scan(ctx, tableName, items)
+	}
+
+	results := make([][]map[string]ddbtypes.AttributeValue, segments)
+	errs := make([]error, segments)
+
+	var wg sync.WaitGroup
+	for segment := 0; segment < segments; segment++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			input := &dynamodb.ScanInput{
+				TableName:     aws.String(tableName),
+				Segment:       aws.Int32(int32(segment)),
+				TotalSegments: aws.Int32(int32(segments)),
 			}
-			return fmt.Errorf("error scanning table %s: %w", tableName, err)
+			errs[segment] = k.scanAll(ctx, input, func(page []map[string]ddbtypes.AttributeValue) error {
+				results[segment] = append(results[segment], page...)
+				return nil
+			})
+		}(segment)
+	}
+	wg.Wait()
+
+	var all []map[string]ddbtypes.AttributeValue
+	for segment, result := range results {
+		if errs[segment] != nil {
+			return fmt.Errorf("error scanning segment %d of table %s: %w", segment, tableName, errs[segment])
 		}
-		break
+		all = append(all, result...)
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, items)
+	migrateRecords(tableName, all)
+	err := attributevalue.UnmarshalListOfMaps(all, items)
 	if err != nil {
 		return fmt.Errorf("error unmarshalling scan results: %w", err)
 	}
 
+	DatabaseLog.Info("Parallel scan complete", "tableName", tableName, "segments", segments, "count", len(all))
 	return nil
 }
 
+// scanAll runs the scan described by input, passing each page of results to page until
+// DynamoDB stops returning a LastEvaluatedKey.
+func (k *DynamoStore) scanAll(ctx context.Context, input *dynamodb.ScanInput, page func([]map[string]ddbtypes.AttributeValue) error) error {
+	tableName := aws.ToString(input.TableName)
+
+	for {
+		// Implement retries with exponential backoff
+		const maxRetries = 3
+		var result *dynamodb.ScanOutput
+		var err error
+		for attempt := 0; attempt < maxRetries; attempt++ {
+			start := time.Now()
+			result, err = k.db.Scan(ctx, input)
+			RecordDatabaseCall("Scan", time.Since(start), err)
+			if err != nil {
+				if isRetryableError(err) && attempt < maxRetries-1 {
+					backoffDuration := time.Duration(attempt+1) * time.Second
+					RecordDatabaseRetry("Scan")
+					DatabaseLog.Warn("Retryable error in Scan, will retry", "attempt", attempt+1, "backoff", backoffDuration, "error", err)
+					if err := sleepContext(ctx, backoffDuration); err != nil {
+						return err
+					}
+					continue
+				}
+				return fmt.Errorf("error scanning table %s: %w", tableName, err)
+			}
+			break
+		}
+
+		if err := page(result.Items); err != nil {
+			return err
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
 // isRetryableError checks if the error is retryable based on AWS error codes.
 func isRetryableError(err error) bool {
-	if awsErr, ok := err.(awserr.Error); ok {
-		switch awsErr.Code() {
-		case dynamodb.ErrCodeProvisionedThroughputExceededException,
-			dynamodb.ErrCodeInternalServerError,
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ProvisionedThroughputExceededException",
+			"InternalServerError",
 			"ThrottlingException",
 			"RequestLimitExceeded":
 			return true
@@ -201,3 +699,17 @@ func isRetryableError(err error) bool {
 	}
 	return false
 }
+
+// sleepContext waits for the backoff duration, returning early with the context's error if it
+// is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
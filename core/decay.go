@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DecaySweepInterval is how often rooms are checked for decayed items.
+const DecaySweepInterval = 30 * time.Second
+
+// DecayWarning is how long before an item decays that the room is warned.
+const DecayWarning = time.Minute
+
+// DecayLoop removes items that have lain on the ground too long, every DecaySweepInterval,
+// until the server context is cancelled. Decay is disabled when ItemDecayMinutes is 0.
+func DecayLoop(s *Server) {
+	if s.Settings().Game.ItemDecayMinutes == 0 {
+		Logger.Info("Item decay is disabled")
+		return
+	}
+
+	Logger.Info("Starting item decay loop", "decayMinutes", s.Settings().Game.ItemDecayMinutes)
+
+	ticker := time.NewTicker(DecaySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.SweepDecayedItems()
+		case <-s.Context.Done():
+			Logger.Info("Stopping item decay loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// SweepDecayedItems warns rooms about items that are about to decay and destroys those that have.
+func (s *Server) SweepDecayedItems() {
+	decayAfter := time.Duration(s.Settings().Game.ItemDecayMinutes) * time.Minute
+	now := time.Now()
+
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0, len(s.Rooms))
+	for _, room := range s.Rooms {
+		if room != nil {
+			rooms = append(rooms, room)
+		}
+	}
+	s.Mutex.Unlock()
+
+	for _, room := range rooms {
+		var warned, decayed []*Item
+
+		room.Mutex.Lock()
+		for _, item := range room.Items {
+			if item.NoDecay || item.DroppedAt.IsZero() {
+				continue
+			}
+			decaysAt := item.DroppedAt.Add(decayAfter)
+			switch {
+			case !now.Before(decaysAt):
+				decayed = append(decayed, item)
+			case !item.DecayWarned && !now.Before(decaysAt.Add(-DecayWarning)):
+				item.DecayWarned = true
+				warned = append(warned, item)
+			}
+		}
+		room.Mutex.Unlock()
+
+		for _, item := range warned {
+			SendRoomMessage(room, fmt.Sprintf("\n\r%s is starting to fall apart.\n\r", item.Name))
+		}
+
+		for _, item := range decayed {
+			if err := s.DestroyItem(item); err != nil {
+				Logger.Error("Error destroying decayed item", "itemID", item.ID, "roomID", room.RoomID, "error", err)
+				continue
+			}
+
+			Logger.Info("Item decayed", "itemName", item.Name, "itemID", item.ID, "roomID", room.RoomID)
+			SendRoomMessage(room, fmt.Sprintf("\n\r%s crumbles to dust.\n\r", item.Name))
+		}
+	}
+}
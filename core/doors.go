@@ -0,0 +1,201 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// farSide returns the exit leading back through the same door, or nil if the far side has no door.
+func farSide(room *Room, exit *Exit) *Exit {
+	target := exit.TargetRoom
+	if target == nil || target == room {
+		return nil
+	}
+
+	target.Mutex.Lock()
+	defer target.Mutex.Unlock()
+
+	if back, exists := target.Exits[OppositeDirections[exit.Direction]]; exists && back.Door && back.TargetRoom == room {
+		return back
+	}
+	for _, back := range target.Exits {
+		if back.Door && back.TargetRoom == room {
+			return back
+		}
+	}
+	return nil
+}
+
+// hasKey reports whether the character is holding or wearing an item made from the given prototype.
+func (c *Character) hasKey(keyID string) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	for _, item := range c.Inventory {
+		if item.PrototypeID.String() == keyID {
+			return true
+		}
+	}
+	return false
+}
+
+// changeDoor applies a change to the door in the given direction from the character's room and to the
+// matching door on the far side, then saves both rooms. The change returns the message for the character,
+// or an error if the door cannot be changed.
+func changeDoor(character *Character, tokens []string, change func(exit *Exit) (string, error)) bool {
+	verb := strings.ToLower(tokens[0])
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: %s <direction>\n\r", verb)
+		return false
+	}
+
+	direction := strings.ToLower(tokens[1])
+	room := character.Room
+
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	room.Mutex.Unlock()
+
+	if !exists || !exit.Visible {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no exit %s here.\n\r", direction)
+		return false
+	}
+
+	back := farSide(room, exit)
+
+	room.Mutex.Lock()
+	if !exit.Door {
+		room.Mutex.Unlock()
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no door to the %s.\n\r", direction)
+		return false
+	}
+	message, err := change(exit)
+	if err == nil {
+		exit.LastEdited = time.Now()
+		room.LastEdited = time.Now()
+	}
+	closed, locked := exit.Closed, exit.Locked
+	room.Mutex.Unlock()
+
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return false
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", message)
+	sendToRoomExcept(room, fmt.Sprintf("\n\r%s %ss the door to the %s.\n\r", character.Name, verb, direction), character)
+
+	if err := character.Server.Database.WriteRoom(character.Context(), room); err != nil {
+		Logger.Error("Error saving room after changing door", "room_id", room.RoomID, "direction", direction, "error", err)
+	}
+
+	if back != nil {
+		target := exit.TargetRoom
+		target.Mutex.Lock()
+		back.Closed, back.Locked = closed, locked
+		back.LastEdited = time.Now()
+		target.LastEdited = time.Now()
+		target.Mutex.Unlock()
+
+		SendRoomMessage(target, fmt.Sprintf("\n\rThe door to the %s %s from the other side.\n\r", back.Direction, doorChange(verb)))
+		if err := character.Server.Database.WriteRoom(character.Context(), target); err != nil {
+			Logger.Error("Error saving far side of door", "room_id", target.RoomID, "direction", back.Direction, "error", err)
+		}
+	}
+
+	Logger.Info("Door changed", "characterName", character.Name, "room_id", room.RoomID, "direction", direction, "action", verb)
+	return false
+}
+
+// doorChange describes what a door did when seen from the far side.
+func doorChange(verb string) string {
+	switch verb {
+	case "open":
+		return "swings open"
+	case "close":
+		return "swings shut"
+	case "lock":
+		return "clicks as it is locked"
+	default:
+		return "clicks as it is unlocked"
+	}
+}
+
+func ExecuteOpenCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is opening a door", "playerName", character.Player.PlayerID)
+
+	return changeDoor(character, tokens, func(exit *Exit) (string, error) {
+		switch {
+		case !exit.Closed:
+			return "", fmt.Errorf("the door is already open")
+		case exit.Locked:
+			return "", fmt.Errorf("the door is locked")
+		}
+		exit.Closed = false
+		return fmt.Sprintf("You open the door to the %s.", exit.Direction), nil
+	})
+}
+
+func ExecuteCloseCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is closing a door", "playerName", character.Player.PlayerID)
+
+	return changeDoor(character, tokens, func(exit *Exit) (string, error) {
+		if exit.Closed {
+			return "", fmt.Errorf("the door is already closed")
+		}
+		exit.Closed = true
+		return fmt.Sprintf("You close the door to the %s.", exit.Direction), nil
+	})
+}
+
+func ExecuteLockCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is locking a door", "playerName", character.Player.PlayerID)
+
+	return lockDoor(character, tokens, true)
+}
+
+func ExecuteUnlockCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is unlocking a door", "playerName", character.Player.PlayerID)
+
+	return lockDoor(character, tokens, false)
+}
+
+// lockDoor locks or unlocks a closed door, provided the character has its key.
+func lockDoor(character *Character, tokens []string, lock bool) bool {
+	// Check for the key up front so the room lock is never held while taking the character's
+	room := character.Room
+	keyID := ""
+	if len(tokens) == 2 {
+		room.Mutex.Lock()
+		if exit, exists := room.Exits[strings.ToLower(tokens[1])]; exists {
+			keyID = exit.KeyID
+		}
+		room.Mutex.Unlock()
+	}
+	hasKey := keyID != "" && character.hasKey(keyID)
+
+	return changeDoor(character, tokens, func(exit *Exit) (string, error) {
+		switch {
+		case exit.KeyID == "":
+			return "", fmt.Errorf("the door has no lock")
+		case !exit.Closed:
+			return "", fmt.Errorf("you need to close the door first")
+		case exit.Locked == lock && lock:
+			return "", fmt.Errorf("the door is already locked")
+		case exit.Locked == lock:
+			return "", fmt.Errorf("the door is not locked")
+		case !hasKey || exit.KeyID != keyID:
+			return "", fmt.Errorf("you don't have the key")
+		}
+		exit.Locked = lock
+		if lock {
+			return fmt.Sprintf("You lock the door to the %s.", exit.Direction), nil
+		}
+		return fmt.Sprintf("You unlock the door to the %s.", exit.Direction), nil
+	})
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EffectTickDuration is how often timed effects apply their periodic changes.
+const EffectTickDuration = 3 * time.Second
+
+// Stacking rules decide what happens when an effect is applied to a character who already has it.
+const (
+	StackRefresh   = iota // the remaining duration is reset
+	StackIntensity        // another stack is added, up to MaxStacks, and the duration is reset
+	StackExtend           // the duration is added to the time remaining
+)
+
+// EffectDefinition describes a timed effect that can be applied to a character.
+// Modifiers and periodic changes are multiplied by the number of stacks.
+type EffectDefinition struct {
+	Name      string
+	Message   string             // shown to the character when the effect takes hold
+	Expiry    string             // shown to the character when the effect wears off
+	Duration  time.Duration      // default duration when applied
+	TraitMods map[string]float64 // modifiers to attributes and abilities while active
+	Health    float64            // health gained (or lost, when negative) every tick
+	Essence   float64            // essence gained (or lost, when negative) every tick
+	Stacking  int
+	MaxStacks int
+}
+
+// ActiveEffect is an effect currently affecting a character.
+type ActiveEffect struct {
+	Definition *EffectDefinition
+	Stacks     int
+	Expires    time.Time
+}
+
+// EffectData is the stored form of an active effect. Remaining time is kept rather than
+// the expiry, so effects are paused while the character is offline.
+type EffectData struct {
+	Name      string  `json:"Name" dynamodbav:"Name"`
+	Stacks    int     `json:"Stacks" dynamodbav:"Stacks"`
+	Remaining float64 `json:"Remaining" dynamodbav:"Remaining"` // seconds
+}
+
+// Effects holds every effect that can be applied, keyed by name.
+var Effects = map[string]*EffectDefinition{
+	"poisoned": {
+		Name:      "poisoned",
+		Message:   "Poison burns through your veins.",
+		Expiry:    "The poison has run its course.",
+		Duration:  15 * time.Second,
+		Health:    -1,
+		Stacking:  StackIntensity,
+		MaxStacks: 3,
+	},
+	"regenerating": {
+		Name:     "regenerating",
+		Message:  "Your body begins to knit itself back together.",
+		Expiry:   "Your regeneration slows and stops.",
+		Duration: 30 * time.Second,
+		Health:   1,
+		Stacking: StackRefresh,
+	},
+	"warded": {
+		Name:      "warded",
+		Message:   "A shimmering ward surrounds you.",
+		Expiry:    "The shimmering ward around you fades.",
+		Duration:  60 * time.Second,
+		TraitMods: map[string]float64{"Dodge": 1},
+		Stacking:  StackExtend,
+	},
+	"weakened": {
+		Name:      "weakened",
+		Message:   "Your limbs grow heavy.",
+		Expiry:    "Your strength returns.",
+		Duration:  30 * time.Second,
+		TraitMods: map[string]float64{"Strength": -1, "Melee": -1},
+		Stacking:  StackRefresh,
+	},
+}
+
+// ApplyEffect applies the named effect to the character following its stacking rule.
+// A zero duration uses the effect's default.
+func (c *Character) ApplyEffect(name string, duration time.Duration) error {
+	definition, exists := Effects[name]
+	if !exists {
+		return fmt.Errorf("unknown effect %s", name)
+	}
+	if duration <= 0 {
+		duration = definition.Duration
+	}
+
+	c.Mutex.Lock()
+	if c.Effects == nil {
+		c.Effects = make(map[string]*ActiveEffect)
+	}
+
+	now := time.Now()
+	effect, active := c.Effects[name]
+	switch {
+	case !active:
+		c.Effects[name] = &ActiveEffect{Definition: definition, Stacks: 1, Expires: now.Add(duration)}
+	case definition.Stacking == StackIntensity:
+		if effect.Stacks < definition.MaxStacks {
+			effect.Stacks++
+		}
+		effect.Expires = now.Add(duration)
+	case definition.Stacking == StackExtend:
+		effect.Expires = effect.Expires.Add(duration)
+	default:
+		effect.Expires = now.Add(duration)
+	}
+	c.markDirty(DirtyStats)
+	c.Mutex.Unlock()
+
+	if c.Player.Send(c.Player.Colorize(ColorCombat, "\n\r"+definition.Message+"\n\r")) {
+		c.Player.Send(c.Player.RenderPrompt())
+	}
+	return nil
+}
+
+// RemoveEffect ends the named effect, returning true if the character had it.
+func (c *Character) RemoveEffect(name string) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if _, active := c.Effects[name]; !active {
+		return false
+	}
+	delete(c.Effects, name)
+	c.markDirty(DirtyStats)
+	return true
+}
+
+// ClearEffects removes every effect from the character.
+func (c *Character) ClearEffects() {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if len(c.Effects) > 0 {
+		c.Effects = nil
+		c.markDirty(DirtyStats)
+	}
+}
+
+// effectMod returns the sum of the active effects' modifiers to a trait. The caller must hold c.Mutex.
+func (c *Character) effectMod(trait string) float64 {
+	mod := 0.0
+	for _, effect := range c.Effects {
+		mod += effect.Definition.TraitMods[trait] * float64(effect.Stacks)
+	}
+	return mod
+}
+
+// TickEffects applies the periodic changes of the character's effects and removes those that have expired.
+// It returns true if the effects killed the character.
+func (c *Character) TickEffects() bool {
+	now := time.Now()
+	var expired []*EffectDefinition
+
+	c.Mutex.Lock()
+	if len(c.Effects) == 0 {
+		c.Mutex.Unlock()
+		return false
+	}
+
+	for name, effect := range c.Effects {
+		stacks := float64(effect.Stacks)
+		c.Health = math.Min(c.Health+effect.Definition.Health*stacks, c.MaxHealth)
+		c.Essence = math.Max(math.Min(c.Essence+effect.Definition.Essence*stacks, c.MaxEssence), 0)
+
+		if !now.Before(effect.Expires) {
+			expired = append(expired, effect.Definition)
+			delete(c.Effects, name)
+		}
+	}
+	health := c.Health
+	c.markDirty(DirtyStats)
+	c.Mutex.Unlock()
+
+	for _, definition := range expired {
+		c.Player.Send(c.Player.Colorize(ColorCombat, "\n\r"+definition.Expiry+"\n\r"))
+	}
+	if health <= 0 {
+		return true
+	}
+
+	// A message is followed by the prompt, otherwise it is only sent when the tick changed it
+	if len(expired) > 0 {
+		c.Player.Send(c.Player.RenderPrompt())
+	} else {
+		c.Player.SendPromptIfChanged()
+	}
+	return false
+}
+
+// EffectLoop ticks every character's effects every EffectTickDuration until the server context is cancelled.
+func EffectLoop(s *Server) {
+	Logger.Info("Starting effect loop", "tickDuration", EffectTickDuration)
+
+	ticker := time.NewTicker(EffectTickDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.TickEffects()
+		case <-s.Context.Done():
+			Logger.Info("Stopping effect loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// TickEffects ticks the effects of every active character, respawning any the effects have killed.
+func (s *Server) TickEffects() {
+	s.Mutex.Lock()
+	characters := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		characters = append(characters, character)
+	}
+	s.Mutex.Unlock()
+
+	for _, character := range characters {
+		if !character.IsActive() {
+			continue
+		}
+
+		if character.TickEffects() {
+			Succumb(character)
+		}
+	}
+}
+
+// Succumb handles a character killed by their effects rather than by another character.
+func Succumb(character *Character) {
+	room := character.Room
+
+	Logger.Info("Character succumbed to their effects", "characterName", character.Name)
+
+	character.ClearEffects()
+	character.Player.Send(character.Player.Colorize(ColorCombat, "\n\rYou succumb to your afflictions!\n\r"))
+	sendColoredToRoomExcept(room, ColorCombat, fmt.Sprintf("\n\r%s collapses and dies!\n\r", character.Name), character)
+
+	if err := character.Respawn(); err != nil {
+		Logger.Error("Error respawning character", "characterName", character.Name, "error", err)
+	}
+	character.Player.Send(character.Player.RenderPrompt())
+}
+
+// EffectSummary describes the character's active effects for display, one per line.
+func (c *Character) EffectSummary() []string {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	names := make([]string, 0, len(c.Effects))
+	for name := range c.Effects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		effect := c.Effects[name]
+		line := fmt.Sprintf("%-15s: %3ds", name, int(math.Ceil(time.Until(effect.Expires).Seconds())))
+		if effect.Stacks > 1 {
+			line += fmt.Sprintf(" (x%d)", effect.Stacks)
+		}
+
+		mods := make([]string, 0, len(effect.Definition.TraitMods))
+		for trait, mod := range effect.Definition.TraitMods {
+			mods = append(mods, fmt.Sprintf("%s %+d", trait, int(mod*float64(effect.Stacks))))
+		}
+		sort.Strings(mods)
+		if len(mods) > 0 {
+			line += " " + strings.Join(mods, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// effectsToData converts the active effects for storage. The caller must hold c.Mutex.
+func (c *Character) effectsToData() []EffectData {
+	if len(c.Effects) == 0 {
+		return nil
+	}
+
+	data := make([]EffectData, 0, len(c.Effects))
+	for name, effect := range c.Effects {
+		remaining := time.Until(effect.Expires).Seconds()
+		if remaining <= 0 {
+			continue
+		}
+		data = append(data, EffectData{Name: name, Stacks: effect.Stacks, Remaining: remaining})
+	}
+	return data
+}
+
+// effectsFromData restores stored effects, skipping any that are no longer defined.
+func effectsFromData(data []EffectData) map[string]*ActiveEffect {
+	effects := make(map[string]*ActiveEffect)
+	now := time.Now()
+	for _, ed := range data {
+		definition, exists := Effects[ed.Name]
+		if !exists {
+			Logger.Warn("Skipping unknown stored effect", "effect", ed.Name)
+			continue
+		}
+		stacks := ed.Stacks
+		if stacks < 1 {
+			stacks = 1
+		}
+		effects[ed.Name] = &ActiveEffect{
+			Definition: definition,
+			Stacks:     stacks,
+			Expires:    now.Add(time.Duration(ed.Remaining * float64(time.Second))),
+		}
+	}
+	return effects
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hand slots in a character's inventory. A two-handed item fills both.
+const (
+	RightHand = "right_hand"
+	LeftHand  = "left_hand"
+)
+
+// HandSlots lists the hand slots in the order items are placed in them.
+var HandSlots = []string{RightHand, LeftHand}
+
+// IsHandSlot reports whether an inventory slot is one of the character's hands.
+func IsHandSlot(slot string) bool {
+	return slot == RightHand || slot == LeftHand
+}
+
+// Equipment separates the items a character is holding from those they are wearing.
+// Items that fill several slots appear only once.
+type Equipment struct {
+	Held []*Item
+	Worn []*Item
+}
+
+// Equipment returns the items the character is holding and wearing.
+func (c *Character) Equipment() Equipment {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.equipment()
+}
+
+// equipment returns the items the character is holding and wearing. The caller must hold c.Mutex.
+func (c *Character) equipment() Equipment {
+	var equipment Equipment
+
+	for _, slot := range HandSlots {
+		if item := c.Inventory[slot]; item != nil && (len(equipment.Held) == 0 || equipment.Held[0] != item) {
+			equipment.Held = append(equipment.Held, item)
+		}
+	}
+
+	counted := make(map[*Item]bool)
+	for _, item := range c.Inventory {
+		if item.IsWorn && !counted[item] {
+			equipment.Worn = append(equipment.Worn, item)
+			counted[item] = true
+		}
+	}
+
+	return equipment
+}
+
+// handsFor returns the hand slots an item would be held in, or nil if the character has no room to hold it.
+// The caller must hold c.Mutex.
+func (c *Character) handsFor(twoHanded bool) []string {
+	if twoHanded {
+		if c.Inventory[RightHand] == nil && c.Inventory[LeftHand] == nil {
+			return HandSlots
+		}
+		return nil
+	}
+
+	for _, slot := range HandSlots {
+		if c.Inventory[slot] == nil {
+			return []string{slot}
+		}
+	}
+	return nil
+}
+
+// CanHold reports whether the character has enough free hands to hold an item.
+func (c *Character) CanHold(twoHanded bool) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.handsFor(twoHanded) != nil
+}
+
+// HoldItem puts an item in the character's free hand, or both hands if it is two-handed,
+// returning a description of where it is held.
+func (c *Character) HoldItem(item *Item) (string, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.holdItem(item)
+}
+
+// holdItem puts an item in the character's hands. The caller must hold c.Mutex.
+func (c *Character) holdItem(item *Item) (string, error) {
+	slots := c.handsFor(item.TwoHanded)
+	if slots == nil {
+		if item.TwoHanded {
+			return "", fmt.Errorf("you need both hands free to hold %s", item.Name)
+		}
+		return "", fmt.Errorf("your hands are full")
+	}
+
+	for _, slot := range slots {
+		c.Inventory[slot] = item
+	}
+	c.markDirty(DirtyInventory)
+
+	return handDescription(slots), nil
+}
+
+// handDescription describes hand slots for messages, such as "right hand" or "both hands".
+func handDescription(slots []string) string {
+	if len(slots) > 1 {
+		return "both hands"
+	}
+	return strings.Replace(slots[0], "_", " ", -1)
+}
+
+// ReleaseItem takes an item out of the character's hands, returning false if they were not holding it.
+func (c *Character) ReleaseItem(item *Item) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.releaseItem(item)
+}
+
+// releaseItem takes an item out of the character's hands. The caller must hold c.Mutex.
+func (c *Character) releaseItem(item *Item) bool {
+	released := false
+	for _, slot := range HandSlots {
+		if c.Inventory[slot] == item {
+			delete(c.Inventory, slot)
+			released = true
+		}
+	}
+	if released {
+		c.markDirty(DirtyInventory)
+	}
+	return released
+}
+
+// HeldItem returns the item in the character's hands whose name contains the given text, ignoring case.
+func (c *Character) HeldItem(name string) *Item {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	lowercaseName := strings.ToLower(name)
+	for _, item := range c.equipment().Held {
+		if strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			return item
+		}
+	}
+	return nil
+}
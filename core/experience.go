@@ -0,0 +1,178 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	ExperiencePerLevel     = 100 // experience needed for level 2; each level after needs this much more than the last
+	TrainingPointsPerLevel = 3   // training points awarded on reaching each new level
+	ExploreExperience      = 10  // experience for entering a room for the first time
+	KillExperience         = 25  // experience per level of the character slain
+	AttributeTrainCost     = 2   // training points to raise an attribute by one
+	AbilityTrainCost       = 1   // training points to raise an ability by one
+	MaxTrainedScore        = 10  // attributes and abilities cannot be trained above this
+)
+
+// GetLevel returns the character's level.
+func (c *Character) GetLevel() int {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.Level
+}
+
+// ExperienceForLevel returns the total experience needed to reach a level.
+func ExperienceForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	return ExperiencePerLevel * level * (level - 1) / 2
+}
+
+// GainExperience awards experience to the character, levelling them up as thresholds are passed.
+func (c *Character) GainExperience(amount int, reason string) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	c.addExperience(amount, reason)
+}
+
+// addExperience awards experience and reports it to the player. The caller must hold c.Mutex.
+func (c *Character) addExperience(amount int, reason string) {
+	if amount <= 0 {
+		return
+	}
+
+	if c.Level < 1 {
+		c.Level = 1
+	}
+	c.Experience += amount
+	c.markDirty(DirtyStats)
+
+	c.Player.Send(fmt.Sprintf("\n\rYou gain %d experience for %s.\n\r", amount, reason))
+
+	for c.Experience >= ExperienceForLevel(c.Level+1) {
+		c.Level++
+		c.TrainingPoints += TrainingPointsPerLevel
+		Logger.Info("Character gained a level", "characterName", c.Name, "level", c.Level)
+		c.Player.Send(c.Player.Colorize(ColorTitle, fmt.Sprintf("\n\rYou have reached level %d! You have %d training points to spend.\n\r", c.Level, c.TrainingPoints)))
+	}
+}
+
+// explore records the character's first visit to a room and awards experience for it.
+// The caller must hold c.Mutex.
+func (c *Character) explore(room *Room) {
+	if c.Explored == nil {
+		c.Explored = make(map[int64]bool)
+	}
+	if c.Explored[room.RoomID] {
+		return
+	}
+	c.Explored[room.RoomID] = true
+	c.addExperience(ExploreExperience, "exploring "+room.Title)
+}
+
+// exploredToData lists the rooms the character has visited for storage. The caller must hold c.Mutex.
+func (c *Character) exploredToData() []int64 {
+	rooms := make([]int64, 0, len(c.Explored))
+	for roomID := range c.Explored {
+		rooms = append(rooms, roomID)
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i] < rooms[j] })
+	return rooms
+}
+
+// ExploredSummary reports how many of the world's rooms the character has visited.
+func (c *Character) ExploredSummary() string {
+	c.Mutex.Lock()
+	explored := len(c.Explored)
+	c.Mutex.Unlock()
+
+	c.Server.Mutex.Lock()
+	total := len(c.Server.Rooms)
+	c.Server.Mutex.Unlock()
+
+	if total == 0 {
+		return fmt.Sprintf("Explored: %d rooms", explored)
+	}
+	return fmt.Sprintf("Explored: %d of %d rooms (%d%%)", explored, total, explored*100/total)
+}
+
+// findTrait matches a name against the character's attributes and abilities, ignoring case.
+// The caller must hold c.Mutex.
+func (c *Character) findTrait(name string) (trait string, isAttribute bool, found bool) {
+	for attr := range c.Attributes {
+		if strings.EqualFold(attr, name) {
+			return attr, true, true
+		}
+	}
+	for ability := range c.Abilities {
+		if strings.EqualFold(ability, name) {
+			return ability, false, true
+		}
+	}
+	return "", false, false
+}
+
+// Train spends training points to raise an attribute or ability by one, returning the new score.
+func (c *Character) Train(name string) (string, float64, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	trait, isAttribute, found := c.findTrait(name)
+	if !found {
+		return "", 0, fmt.Errorf("you have no attribute or ability called %s", name)
+	}
+
+	scores, cost := c.Abilities, AbilityTrainCost
+	if isAttribute {
+		scores, cost = c.Attributes, AttributeTrainCost
+	}
+
+	if scores[trait] >= MaxTrainedScore {
+		return "", 0, fmt.Errorf("your %s cannot be trained any further", trait)
+	}
+	if c.TrainingPoints < cost {
+		return "", 0, fmt.Errorf("training %s costs %d points and you have %d", trait, cost, c.TrainingPoints)
+	}
+
+	c.TrainingPoints -= cost
+	scores[trait]++
+	c.markDirty(DirtyStats)
+
+	return trait, scores[trait], nil
+}
+
+// ProgressSummary describes the character's level and experience for display.
+func (c *Character) ProgressSummary() string {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return fmt.Sprintf("Level: %d, Experience: %d/%d, Training points: %d",
+		c.Level, c.Experience, ExperienceForLevel(c.Level+1), c.TrainingPoints)
+}
+
+func ExecuteTrainCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is training", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\rAttributes cost %d points and abilities %d to raise by one, up to %d.\n\rUsage: train <attribute or ability>\n\r",
+			character.ProgressSummary(), AttributeTrainCost, AbilityTrainCost, MaxTrainedScore)
+		return false
+	}
+
+	trait, score, err := character.Train(strings.Join(tokens[1:], " "))
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUnable to train: %v.\n\r", err)
+		return false
+	}
+
+	Logger.Info("Character trained", "characterName", character.Name, "trait", trait, "score", score)
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYour %s rises to %d.\n\r", trait, int(score))
+	return false
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FilterProfanity masks any word in the message that appears in the server's obscenity set.
+// Matching is exact and case-insensitive, ignoring surrounding punctuation.
+func FilterProfanity(s *Server, message string) string {
+	if s == nil || len(s.Obscenities) == 0 {
+		return message
+	}
+
+	words := strings.Fields(message)
+	for i, word := range words {
+		trimmed := strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if trimmed == "" || !s.IsObscenity(trimmed) {
+			continue
+		}
+		words[i] = strings.Replace(word, trimmed, strings.Repeat("*", len([]rune(trimmed))), 1)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// MessageFor returns the version of a public message the player should see,
+// applying the profanity filter unless it is disabled or the player has opted out.
+func (p *Player) MessageFor(raw, filtered string) string {
+	if p.ShowProfanity || !p.Server.Settings().Game.ProfanityFilter {
+		return raw
+	}
+	return filtered
+}
+
+// Filter returns the message as the player should see it, for text such as board posts that is
+// stored as written and filtered when it is shown.
+func (p *Player) Filter(message string) string {
+	return p.MessageFor(message, FilterProfanity(p.Server, message))
+}
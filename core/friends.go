@@ -0,0 +1,105 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IsFriend checks if the player has listed the named character as a friend.
+func (p *Player) IsFriend(name string) bool {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	return p.Friends[strings.ToLower(name)]
+}
+
+// Befriend adds the named character to the player's friends list.
+func (p *Player) Befriend(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	if p.Friends == nil {
+		p.Friends = make(map[string]bool)
+	}
+	p.Friends[strings.ToLower(name)] = true
+}
+
+// Unfriend removes the named character from the player's friends list.
+func (p *Player) Unfriend(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	delete(p.Friends, strings.ToLower(name))
+}
+
+func ExecuteFriendCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is adding a friend", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.Mutex.Lock()
+		names := make([]string, 0, len(character.Player.Friends))
+		for name := range character.Player.Friends {
+			names = append(names, name)
+		}
+		character.Player.Mutex.Unlock()
+
+		if len(names) == 0 {
+			character.Player.ToPlayer <- "\n\rYou have not listed any friends.\n\rUsage: friend <character>\n\r"
+			return false
+		}
+
+		sort.Strings(names)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rFriends: %s\n\rUsage: friend <character>\n\r", strings.Join(names, ", "))
+		return false
+	}
+
+	name := tokens[1]
+	if strings.EqualFold(name, character.Name) {
+		character.Player.ToPlayer <- "\n\rYou cannot list yourself as a friend.\n\r"
+		return false
+	}
+
+	if !character.Server.CharacterNameExists(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no one called %s.\n\r", name)
+		return false
+	}
+
+	if character.Player.IsFriend(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is already on your friends list.\n\r", name)
+		return false
+	}
+
+	character.Player.Befriend(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player friends list", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s has been added to your friends list.\n\r", name)
+	return false
+}
+
+func ExecuteUnfriendCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is removing a friend", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: unfriend <character>\n\r"
+		return false
+	}
+
+	name := tokens[1]
+	if !character.Player.IsFriend(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not on your friends list.\n\r", name)
+		return false
+	}
+
+	character.Player.Unfriend(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player friends list", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s has been removed from your friends list.\n\r", name)
+	return false
+}
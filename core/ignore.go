@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HeavilyIgnoredThreshold is how many players must ignore a character before @ignored reports them.
+const HeavilyIgnoredThreshold = 3
+
+// IsIgnoring checks if the player has ignored the named character.
+func (p *Player) IsIgnoring(name string) bool {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	return p.Ignored[strings.ToLower(name)]
+}
+
+// Ignore hides everything the named character says to or near the player.
+func (p *Player) Ignore(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	if p.Ignored == nil {
+		p.Ignored = make(map[string]bool)
+	}
+	p.Ignored[strings.ToLower(name)] = true
+}
+
+// Unignore shows the named character's messages to the player again.
+func (p *Player) Unignore(name string) {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	delete(p.Ignored, strings.ToLower(name))
+}
+
+// SendFrom delivers a message written by another character, dropping it if the player is ignoring them.
+// It returns false if the message was not delivered.
+func (p *Player) SendFrom(sender *Character, message string) bool {
+	if sender != nil && p.IsIgnoring(sender.Name) {
+		return false
+	}
+	return p.Send(message)
+}
+
+func ExecuteIgnoreCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is ignoring a character", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.Mutex.Lock()
+		names := make([]string, 0, len(character.Player.Ignored))
+		for name := range character.Player.Ignored {
+			names = append(names, name)
+		}
+		character.Player.Mutex.Unlock()
+
+		if len(names) == 0 {
+			character.Player.ToPlayer <- "\n\rYou are not ignoring anyone.\n\rUsage: ignore <character>\n\r"
+			return false
+		}
+
+		sort.Strings(names)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rIgnoring: %s\n\rUsage: ignore <character>\n\r", strings.Join(names, ", "))
+		return false
+	}
+
+	name := tokens[1]
+	if strings.EqualFold(name, character.Name) {
+		character.Player.ToPlayer <- "\n\rYou cannot ignore yourself.\n\r"
+		return false
+	}
+
+	if !character.Server.CharacterNameExists(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no one called %s.\n\r", name)
+		return false
+	}
+
+	if character.Player.IsIgnoring(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are already ignoring %s.\n\r", name)
+		return false
+	}
+
+	character.Player.Ignore(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player ignore list", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	Logger.Info("Player ignored character", "playerName", character.Player.PlayerID, "ignored", strings.ToLower(name))
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou will no longer hear anything %s says, tells or emotes.\n\r", name)
+	return false
+}
+
+func ExecuteUnignoreCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is unignoring a character", "playerName", character.Player.PlayerID)
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: unignore <character>\n\r"
+		return false
+	}
+
+	name := tokens[1]
+	if !character.Player.IsIgnoring(name) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are not ignoring %s.\n\r", name)
+		return false
+	}
+
+	character.Player.Unignore(name)
+	if err := character.Server.Database.WritePlayer(character.Context(), character.Player); err != nil {
+		Logger.Error("Error saving player ignore list", "playerName", character.Player.PlayerID, "error", err)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou will hear %s again.\n\r", name)
+	return false
+}
+
+// CountIgnored scans every player's ignore list and returns how many players ignore each character name.
+func (kp *KeyPair) CountIgnored(ctx context.Context) (map[string]int, error) {
+	var players []PlayerData
+	if err := kp.Scan(ctx, "players", &players); err != nil {
+		return nil, fmt.Errorf("error scanning players: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, pd := range players {
+		for _, name := range pd.Ignored {
+			counts[name]++
+		}
+	}
+	return counts, nil
+}
+
+func ExecuteIgnoredCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is reviewing ignored characters", "playerName", character.Player.PlayerID)
+
+	counts, err := character.Server.Database.CountIgnored(character.Context())
+	if err != nil {
+		Logger.Error("Error counting ignored characters", "error", err)
+		character.Player.ToPlayer <- "\n\rThe ignore lists could not be read.\n\r"
+		return false
+	}
+
+	names := make([]string, 0, len(counts))
+	for name, count := range counts {
+		if count >= HeavilyIgnoredThreshold {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rNo one is ignored by %d or more players.\n\r", HeavilyIgnoredThreshold)
+		return false
+	}
+
+	// Most ignored first
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\rCharacters ignored by %d or more players:\n\r", HeavilyIgnoredThreshold))
+	for _, name := range names {
+		output.WriteString(fmt.Sprintf("  %-20s %d\n\r", name, counts[name]))
+	}
+	character.Player.ToPlayer <- output.String()
+	return false
+}
@@ -1,12 +1,13 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 )
 
@@ -18,7 +19,7 @@ func DisplayPrototypes(prototypes map[uuid.UUID]*Prototype) {
 }
 
 // StorePrototypes stores item prototypes into the DynamoDB table.
-func (kp *KeyPair) StorePrototypes(prototypes map[uuid.UUID]*Prototype) error {
+func (kp *KeyPair) StorePrototypes(ctx context.Context, prototypes map[uuid.UUID]*Prototype) error {
 	for _, prototype := range prototypes {
 		prototypeData := PrototypeData{
 			PrototypeID: prototype.ID.String(),
@@ -31,6 +32,15 @@ func (kp *KeyPair) StorePrototypes(prototypes map[uuid.UUID]*Prototype) error {
 			Quantity:    prototype.Quantity,
 			Wearable:    prototype.Wearable,
 			WornOn:      prototype.WornOn,
+			Layer:       prototype.Layer,
+			MinDamage:   prototype.MinDamage,
+			MaxDamage:   prototype.MaxDamage,
+			DamageType:  prototype.DamageType,
+			Absorb:      prototype.Absorb,
+			Capacity:    prototype.Capacity,
+			NoDecay:     prototype.NoDecay,
+			TwoHanded:   prototype.TwoHanded,
+			LightSource: prototype.LightSource,
 			Verbs:       prototype.Verbs,
 			Overrides:   prototype.Overrides,
 			TraitMods:   prototype.TraitMods,
@@ -39,11 +49,12 @@ func (kp *KeyPair) StorePrototypes(prototypes map[uuid.UUID]*Prototype) error {
 			Metadata:    prototype.Metadata,
 		}
 
-		err := kp.Put("prototypes", prototypeData)
+		err := kp.Put(ctx, "prototypes", prototypeData)
 		if err != nil {
 			Logger.Error("Error storing prototype", "name", prototype.Name, "error", err)
 			return fmt.Errorf("error storing prototype %s: %w", prototype.Name, err)
 		}
+		kp.cacheRecord("prototypes", prototypeData.PrototypeID, prototypeData)
 
 		prototype.LastSaved = time.Now()
 
@@ -54,9 +65,9 @@ func (kp *KeyPair) StorePrototypes(prototypes map[uuid.UUID]*Prototype) error {
 }
 
 // LoadPrototypes retrieves all item prototypes from the DynamoDB table.
-func (kp *KeyPair) LoadPrototypes() (map[uuid.UUID]*Prototype, error) {
+func (kp *KeyPair) LoadPrototypes(ctx context.Context) (map[uuid.UUID]*Prototype, error) {
 	var prototypeDataList []PrototypeData
-	err := kp.Scan("prototypes", &prototypeDataList)
+	err := kp.Scan(ctx, "prototypes", &prototypeDataList)
 	if err != nil {
 		Logger.Error("Error scanning prototypes table", "error", err)
 		return nil, fmt.Errorf("error scanning prototypes: %w", err)
@@ -69,27 +80,7 @@ func (kp *KeyPair) LoadPrototypes() (map[uuid.UUID]*Prototype, error) {
 			Logger.Error("Error parsing prototype UUID", "id", prototypeData.PrototypeID, "error", err)
 			continue
 		}
-		prototype := &Prototype{
-			ID:          id,
-			Name:        prototypeData.Name,
-			Description: prototypeData.Description,
-			Mass:        prototypeData.Mass,
-			Value:       prototypeData.Value,
-			Stackable:   prototypeData.Stackable,
-			MaxStack:    prototypeData.MaxStack,
-			Quantity:    prototypeData.Quantity,
-			Wearable:    prototypeData.Wearable,
-			WornOn:      prototypeData.WornOn,
-			Verbs:       prototypeData.Verbs,
-			Overrides:   prototypeData.Overrides,
-			TraitMods:   prototypeData.TraitMods,
-			Container:   prototypeData.Container,
-			CanPickUp:   prototypeData.CanPickUp,
-			Metadata:    prototypeData.Metadata,
-			Mutex:       sync.Mutex{},
-			LastEdited:  time.Now(),
-			LastSaved:   time.Now(),
-		}
+		prototype := prototypeFromData(id, &prototypeData)
 		prototypes[id] = prototype
 		Logger.Debug("Loaded prototype from database", "id", id, "name", prototype.Name)
 	}
@@ -97,51 +88,119 @@ func (kp *KeyPair) LoadPrototypes() (map[uuid.UUID]*Prototype, error) {
 	return prototypes, nil
 }
 
+// LoadPrototype retrieves a single prototype, through the cache, from the DynamoDB table.
+func (kp *KeyPair) LoadPrototype(ctx context.Context, id uuid.UUID) (*Prototype, error) {
+	key := map[string]ddbtypes.AttributeValue{
+		"PrototypeID": &ddbtypes.AttributeValueMemberS{Value: id.String()},
+	}
+
+	var prototypeData PrototypeData
+	if err := kp.getCached(ctx, "prototypes", id.String(), key, &prototypeData); err != nil {
+		Logger.Error("Error loading prototype data", "prototypeID", id, "error", err)
+		return nil, fmt.Errorf("error loading prototype data: %w", err)
+	}
+
+	return prototypeFromData(id, &prototypeData), nil
+}
+
+// prototypeFromData creates a Prototype from PrototypeData.
+func prototypeFromData(id uuid.UUID, data *PrototypeData) *Prototype {
+	return &Prototype{
+		ID:          id,
+		Name:        data.Name,
+		Description: data.Description,
+		Mass:        data.Mass,
+		Value:       data.Value,
+		Stackable:   data.Stackable,
+		MaxStack:    data.MaxStack,
+		Quantity:    data.Quantity,
+		Wearable:    data.Wearable,
+		WornOn:      data.WornOn,
+		Layer:       data.Layer,
+		MinDamage:   data.MinDamage,
+		MaxDamage:   data.MaxDamage,
+		DamageType:  data.DamageType,
+		Absorb:      data.Absorb,
+		Capacity:    data.Capacity,
+		NoDecay:     data.NoDecay,
+		TwoHanded:   data.TwoHanded,
+		LightSource: data.LightSource,
+		Verbs:       data.Verbs,
+		Overrides:   data.Overrides,
+		TraitMods:   data.TraitMods,
+		Container:   data.Container,
+		CanPickUp:   data.CanPickUp,
+		Metadata:    data.Metadata,
+		Mutex:       sync.Mutex{},
+		LastEdited:  time.Now(),
+		LastSaved:   time.Now(),
+	}
+}
+
 // LoadItem retrieves an item from the DynamoDB table.
-func (k *KeyPair) LoadItem(id string) (*Item, error) {
+func (k *KeyPair) LoadItem(ctx context.Context, id string) (*Item, error) {
 	if id == "" {
 		return nil, fmt.Errorf("empty item ID provided")
 	}
 
-	key := map[string]*dynamodb.AttributeValue{
-		"ItemID": {
-			S: aws.String(id),
-		},
+	key := map[string]ddbtypes.AttributeValue{
+		"ItemID": &ddbtypes.AttributeValueMemberS{Value: id},
 	}
 
 	var itemData ItemData
-	err := k.Get("items", key, &itemData)
+	err := k.getCached(ctx, "items", id, key, &itemData)
 	if err != nil {
 		Logger.Error("Error loading item data", "itemID", id, "error", err)
 		return nil, fmt.Errorf("error loading item data: %w", err)
 	}
 
-	return k.itemFromData(&itemData)
+	return k.itemFromData(ctx, &itemData)
 }
 
 // WriteItem stores an item into the DynamoDB table, handling nested contents if it's a container.
-func (k *KeyPair) WriteItem(obj *Item) error {
+func (k *KeyPair) WriteItem(ctx context.Context, obj *Item) error {
 	// Recursively write contained items if the item is a container
 	if obj.Container {
 		for _, contentItem := range obj.Contents {
-			if err := k.WriteItem(contentItem); err != nil {
+			if err := k.WriteItem(ctx, contentItem); err != nil {
 				Logger.Error("Error writing content item", "contentItemID", contentItem.ID, "parentItemID", obj.ID, "error", err)
 				return fmt.Errorf("error writing content item %s: %w", contentItem.ID, err)
 			}
 		}
 	}
 
+	// Write the item data to the DynamoDB table
+	itemData := obj.toData()
+	key := map[string]ddbtypes.AttributeValue{
+		"ItemID": &ddbtypes.AttributeValueMemberS{Value: itemData.ItemID},
+	}
+	err := k.putVersioned(ctx, "items", key, itemData, &itemData.Version)
+	if err != nil {
+		Logger.Error("Error writing item data", "itemName", obj.Name, "itemID", obj.ID, "error", err)
+		return fmt.Errorf("error writing item data: %w", err)
+	}
+
+	obj.Version = itemData.Version
+	obj.LastSaved = time.Now()
+	k.cacheRecord("items", itemData.ItemID, itemData)
+
+	Logger.Info("Successfully wrote item", "itemName", obj.Name, "itemID", obj.ID)
+	return nil
+}
+
+// toData converts an Item into an ItemData struct for database storage.
+func (obj *Item) toData() *ItemData {
 	// Prepare the list of content IDs
 	contentIDs := make([]string, 0, len(obj.Contents))
 	for _, contentItem := range obj.Contents {
 		contentIDs = append(contentIDs, contentItem.ID.String())
 	}
 
-	// Create the ItemData struct to store in DynamoDB
-	itemData := ItemData{
+	itemData := &ItemData{
 		ItemID:      obj.ID.String(),
 		PrototypeID: obj.PrototypeID.String(),
 		Name:        obj.Name,
+		ShortDesc:   obj.ShortDesc,
 		Description: obj.Description,
 		Mass:        obj.Mass,
 		Value:       obj.Value,
@@ -150,6 +209,16 @@ func (k *KeyPair) WriteItem(obj *Item) error {
 		Quantity:    obj.Quantity,
 		Wearable:    obj.Wearable,
 		WornOn:      obj.WornOn,
+		Layer:       obj.Layer,
+		MinDamage:   obj.MinDamage,
+		MaxDamage:   obj.MaxDamage,
+		DamageType:  obj.DamageType,
+		Absorb:      obj.Absorb,
+		Capacity:    obj.Capacity,
+		NoDecay:     obj.NoDecay,
+		TwoHanded:   obj.TwoHanded,
+		LightSource: obj.LightSource,
+		Lit:         obj.Lit,
 		Verbs:       obj.Verbs,
 		Overrides:   obj.Overrides,
 		TraitMods:   obj.TraitMods,
@@ -158,23 +227,158 @@ func (k *KeyPair) WriteItem(obj *Item) error {
 		IsWorn:      obj.IsWorn,
 		CanPickUp:   obj.CanPickUp,
 		Metadata:    obj.Metadata,
+		Version:     obj.Version,
+	}
+	if !obj.DroppedAt.IsZero() {
+		itemData.DroppedAt = obj.DroppedAt.Unix()
 	}
 
-	// Write the item data to the DynamoDB table
-	err := k.Put("items", itemData)
+	return itemData
+}
+
+// DeleteItem removes an item's record from the DynamoDB table.
+func (k *KeyPair) DeleteItem(ctx context.Context, item *Item) error {
+	key := map[string]ddbtypes.AttributeValue{
+		"ItemID": &ddbtypes.AttributeValueMemberS{Value: item.ID.String()},
+	}
+
+	err := k.Delete(ctx, "items", key)
+	k.Cache.invalidate("items", item.ID.String())
 	if err != nil {
-		Logger.Error("Error writing item data", "itemName", obj.Name, "itemID", obj.ID, "error", err)
-		return fmt.Errorf("error writing item data: %w", err)
+		Logger.Error("Error deleting item data", "itemName", item.Name, "itemID", item.ID, "error", err)
+		return fmt.Errorf("error deleting item data: %w", err)
 	}
 
-	obj.LastSaved = time.Now()
+	Logger.Info("Successfully deleted item", "itemName", item.Name, "itemID", item.ID)
+	return nil
+}
+
+// ModifyItemQuantity changes an item's quantity by delta and persists the change immediately,
+// so consumed charges are not restored if the server crashes before the next auto-save.
+// When the quantity reaches zero the item is destroyed.
+func (s *Server) ModifyItemQuantity(item *Item, delta int) error {
+	if item == nil {
+		return fmt.Errorf("cannot modify quantity of nil item")
+	}
+
+	item.Mutex.Lock()
+	quantity := int64(item.Quantity) + int64(delta)
+	if quantity < 0 {
+		item.Mutex.Unlock()
+		return fmt.Errorf("not enough %s remaining", item.Name)
+	}
+	if item.MaxStack > 0 && quantity > int64(item.MaxStack) {
+		item.Mutex.Unlock()
+		return fmt.Errorf("%s cannot hold more than %d", item.Name, item.MaxStack)
+	}
+	item.Quantity = uint32(quantity)
+	item.LastEdited = time.Now()
+	item.Mutex.Unlock()
+
+	Logger.Info("Modified item quantity", "itemName", item.Name, "itemID", item.ID, "delta", delta, "quantity", quantity)
+
+	if quantity == 0 {
+		return s.DestroyItem(item)
+	}
+
+	if err := s.Database.WriteItem(s.Context, item); err != nil {
+		return fmt.Errorf("error saving item quantity: %w", err)
+	}
 
-	Logger.Info("Successfully wrote item", "itemName", obj.Name, "itemID", obj.ID)
 	return nil
 }
 
+// DestroyItem permanently removes an item from the game. It is taken out of any room,
+// inventory, or container holding it, its contents are destroyed, and its database record is deleted.
+func (s *Server) DestroyItem(item *Item) error {
+	if item == nil {
+		return fmt.Errorf("cannot destroy nil item")
+	}
+
+	// Destroy the contents first so they do not become orphaned records
+	item.Mutex.Lock()
+	contents := item.Contents
+	item.Contents = nil
+	item.Mutex.Unlock()
+
+	for _, contentItem := range contents {
+		if err := s.DestroyItem(contentItem); err != nil {
+			Logger.Error("Error destroying content item", "contentItemID", contentItem.ID, "parentItemID", item.ID, "error", err)
+		}
+	}
+
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0, len(s.Rooms))
+	for _, room := range s.Rooms {
+		if room != nil {
+			rooms = append(rooms, room)
+		}
+	}
+	characters := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		if character != nil {
+			characters = append(characters, character)
+		}
+	}
+	s.Mutex.Unlock()
+
+	// Remove the item from any room it is lying in, or from a container in the room
+	for _, room := range rooms {
+		room.Mutex.Lock()
+		if _, exists := room.Items[item.ID]; exists {
+			delete(room.Items, item.ID)
+			room.LastEdited = time.Now()
+			Logger.Info("Removed destroyed item from room", "itemID", item.ID, "roomID", room.RoomID)
+		}
+		for _, roomItem := range room.Items {
+			removeFromContainer(roomItem, item)
+		}
+		room.Mutex.Unlock()
+	}
+
+	// Remove the item from any inventory slot holding it, or from a container being carried
+	for _, character := range characters {
+		character.Mutex.Lock()
+		for slot, invItem := range character.Inventory {
+			if invItem == item {
+				delete(character.Inventory, slot)
+				character.markDirty(DirtyInventory)
+				Logger.Info("Removed destroyed item from inventory", "itemID", item.ID, "characterName", character.Name, "slot", slot)
+			} else {
+				removeFromContainer(invItem, item)
+			}
+		}
+		character.Mutex.Unlock()
+	}
+
+	return s.Database.DeleteItem(s.Context, item)
+}
+
+// removeFromContainer removes the target item from the container or any container nested within it.
+func removeFromContainer(container *Item, target *Item) bool {
+	if container == nil || container == target || !container.Container {
+		return false
+	}
+
+	container.Mutex.Lock()
+	defer container.Mutex.Unlock()
+
+	for i, contentItem := range container.Contents {
+		if contentItem == target {
+			container.Contents = append(container.Contents[:i], container.Contents[i+1:]...)
+			container.LastEdited = time.Now()
+			return true
+		}
+		if removeFromContainer(contentItem, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // SaveActiveItems saves all active items from rooms and characters to the database.
-func (s *Server) SaveActiveItems() error {
+func (s *Server) SaveActiveItems(ctx context.Context) error {
 	if s == nil {
 		return fmt.Errorf("server is nil")
 	}
@@ -184,46 +388,50 @@ func (s *Server) SaveActiveItems() error {
 	// Collect all items from rooms and characters
 	itemsToSave := make(map[uuid.UUID]*Item)
 
+	// Snapshot the world under the server mutex, since builders add rooms while the game runs
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0, len(s.Rooms))
+	for _, room := range s.Rooms {
+		rooms = append(rooms, room)
+	}
+	characters := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		characters = append(characters, character)
+	}
+	s.Mutex.Unlock()
+
 	// Items in rooms
-	if s.Rooms != nil {
-		for roomID, room := range s.Rooms {
-			if room == nil {
-				Logger.Warn("Nil room found", "roomID", roomID)
+	for _, room := range rooms {
+		if room == nil {
+			Logger.Warn("Nil room found")
+			continue
+		}
+		room.Mutex.Lock()
+		for _, item := range room.Items {
+			if item == nil {
+				Logger.Warn("Nil item found in room", "roomID", room.RoomID)
 				continue
 			}
-			room.Mutex.Lock()
-			for _, item := range room.Items {
-				if item == nil {
-					Logger.Warn("Nil item found in room", "roomID", roomID)
-					continue
-				}
-				itemsToSave[item.ID] = item
-			}
-			room.Mutex.Unlock()
+			itemsToSave[item.ID] = item
 		}
-	} else {
-		Logger.Warn("Server Rooms map is nil")
+		room.Mutex.Unlock()
 	}
 
 	// Items in character inventories
-	if s.Characters != nil {
-		for charID, character := range s.Characters {
-			if character == nil {
-				Logger.Warn("Nil character found", "characterID", charID)
+	for _, character := range characters {
+		if character == nil {
+			Logger.Warn("Nil character found")
+			continue
+		}
+		character.Mutex.Lock()
+		for _, item := range character.Inventory {
+			if item == nil {
+				Logger.Warn("Nil item found in inventory", "characterID", character.ID)
 				continue
 			}
-			character.Mutex.Lock()
-			for _, item := range character.Inventory {
-				if item == nil {
-					Logger.Warn("Nil item found in inventory", "characterID", charID)
-					continue
-				}
-				itemsToSave[item.ID] = item
-			}
-			character.Mutex.Unlock()
+			itemsToSave[item.ID] = item
 		}
-	} else {
-		Logger.Warn("Server Characters map is nil")
+		character.Mutex.Unlock()
 	}
 
 	// Save all collected items
@@ -231,38 +439,66 @@ func (s *Server) SaveActiveItems() error {
 		return fmt.Errorf("server database is nil")
 	}
 
-	for _, item := range itemsToSave {
+	// Gather the edited items, including those nested in containers, into one batch
+	var edited []*Item
+	var writes []versionedWrite
+	var collect func(item *Item)
+	collect = func(item *Item) {
 		if item == nil {
 			Logger.Warn("Attempting to save a nil item, skipping")
-			continue
+			return
 		}
-
-		// Check if LastEdited is after LastSaved, skip if it is not
-		if !item.LastEdited.After(item.LastSaved) {
-			Logger.Info("Item not edited since last save, skipping", "itemName", item.Name, "itemID", item.ID)
-			continue
+		for _, contentItem := range item.Contents {
+			collect(contentItem)
+		}
+		if item.LastEdited.After(item.LastSaved) {
+			data := item.toData()
+			edited = append(edited, item)
+			writes = append(writes, versionedWrite{
+				Key: map[string]ddbtypes.AttributeValue{
+					"ItemID": &ddbtypes.AttributeValueMemberS{Value: data.ItemID},
+				},
+				Data:    data,
+				Version: &data.Version,
+			})
 		}
+	}
+	for _, item := range itemsToSave {
+		collect(item)
+	}
+
+	err := s.Database.batchPutVersioned(ctx, "items", writes)
 
-		// Attempt to write the item to the database
-		if err := s.Database.WriteItem(item); err != nil {
-			Logger.Error("Error saving item", "itemName", item.Name, "itemID", item.ID, "error", err)
-			// Continue saving other items even if one fails
-		} else {
-			// Update LastSaved after successful save
-			item.LastSaved = time.Now()
-			Logger.Info("Successfully saved item", "itemName", item.Name, "itemID", item.ID)
+	now := time.Now()
+	saved := 0
+	for i, item := range edited {
+		if writes[i].Written {
+			item.Version = *writes[i].Version
+			item.LastSaved = now
+			s.Database.cacheRecord("items", item.ID.String(), writes[i].Data)
+			saved++
 		}
 	}
 
-	Logger.Info("Finished saving active items")
+	if err != nil {
+		Logger.Error("Error saving items", "count", len(writes), "saved", saved, "error", err)
+		return fmt.Errorf("error saving items: %w", err)
+	}
+
+	Logger.Info("Finished saving active items", "saved", saved, "active", len(itemsToSave))
 	return nil
 }
 
 func (s *Server) CreateItemFromPrototype(prototypeID uuid.UUID) (*Item, error) {
 	prototype, exists := s.Prototypes[prototypeID]
 	if !exists {
-		Logger.Error("Prototype not found", "prototypeID", prototypeID)
-		return nil, fmt.Errorf("prototype with ID %s not found", prototypeID)
+		// Prototypes added to the database since startup are read through the cache
+		var err error
+		prototype, err = s.Database.LoadPrototype(s.Context, prototypeID)
+		if err != nil {
+			Logger.Error("Prototype not found", "prototypeID", prototypeID)
+			return nil, fmt.Errorf("prototype with ID %s not found", prototypeID)
+		}
 	}
 
 	newItem := &Item{
@@ -277,6 +513,15 @@ func (s *Server) CreateItemFromPrototype(prototypeID uuid.UUID) (*Item, error) {
 		Quantity:    prototype.Quantity,
 		Wearable:    prototype.Wearable,
 		WornOn:      prototype.WornOn,
+		Layer:       prototype.Layer,
+		MinDamage:   prototype.MinDamage,
+		MaxDamage:   prototype.MaxDamage,
+		DamageType:  prototype.DamageType,
+		Absorb:      prototype.Absorb,
+		Capacity:    prototype.Capacity,
+		NoDecay:     prototype.NoDecay,
+		TwoHanded:   prototype.TwoHanded,
+		LightSource: prototype.LightSource,
 		Verbs:       prototype.Verbs,
 		Overrides:   prototype.Overrides,
 		TraitMods:   make(map[string]int8),
@@ -311,7 +556,7 @@ func (s *Server) CreateItemFromPrototype(prototypeID uuid.UUID) (*Item, error) {
 	}
 
 	// Save the new item to the database
-	if err := s.Database.WriteItem(newItem); err != nil {
+	if err := s.Database.WriteItem(s.Context, newItem); err != nil {
 		Logger.Error("Failed to write new item to database", "itemName", newItem.Name, "itemID", newItem.ID, "error", err)
 		return nil, fmt.Errorf("failed to write new item to database: %w", err)
 	}
@@ -323,7 +568,7 @@ func (s *Server) CreateItemFromPrototype(prototypeID uuid.UUID) (*Item, error) {
 }
 
 // itemFromData creates an Item from ItemData
-func (kp *KeyPair) itemFromData(itemData *ItemData) (*Item, error) {
+func (kp *KeyPair) itemFromData(ctx context.Context, itemData *ItemData) (*Item, error) {
 	if itemData == nil {
 		return nil, fmt.Errorf("itemData is nil")
 	}
@@ -342,6 +587,7 @@ func (kp *KeyPair) itemFromData(itemData *ItemData) (*Item, error) {
 		ID:          itemID,
 		PrototypeID: prototypeID,
 		Name:        itemData.Name,
+		ShortDesc:   itemData.ShortDesc,
 		Description: itemData.Description,
 		Mass:        itemData.Mass,
 		Value:       itemData.Value,
@@ -350,6 +596,16 @@ func (kp *KeyPair) itemFromData(itemData *ItemData) (*Item, error) {
 		Quantity:    itemData.Quantity,
 		Wearable:    itemData.Wearable,
 		WornOn:      itemData.WornOn,
+		Layer:       itemData.Layer,
+		MinDamage:   itemData.MinDamage,
+		MaxDamage:   itemData.MaxDamage,
+		DamageType:  itemData.DamageType,
+		Absorb:      itemData.Absorb,
+		Capacity:    itemData.Capacity,
+		NoDecay:     itemData.NoDecay,
+		TwoHanded:   itemData.TwoHanded,
+		LightSource: itemData.LightSource,
+		Lit:         itemData.Lit,
 		Verbs:       itemData.Verbs,
 		Overrides:   itemData.Overrides,
 		TraitMods:   itemData.TraitMods,
@@ -357,16 +613,20 @@ func (kp *KeyPair) itemFromData(itemData *ItemData) (*Item, error) {
 		IsWorn:      itemData.IsWorn,
 		CanPickUp:   itemData.CanPickUp,
 		Metadata:    itemData.Metadata,
+		Version:     itemData.Version,
 		Mutex:       sync.Mutex{},
 		LastEdited:  time.Now(),
 		LastSaved:   time.Now(),
 	}
+	if itemData.DroppedAt != 0 {
+		item.DroppedAt = time.Unix(itemData.DroppedAt, 0)
+	}
 
 	// Handle Contents if the item is a container
 	if item.Container {
 		item.Contents = make([]*Item, 0, len(itemData.Contents))
 		for _, contentID := range itemData.Contents {
-			contentItem, err := kp.LoadItem(contentID)
+			contentItem, err := kp.LoadItem(ctx, contentID)
 			if err != nil {
 				Logger.Error("Error loading content item", "contentID", contentID, "parentItemID", item.ID, "error", err)
 				continue // Skip this content item but continue with others
@@ -435,7 +695,7 @@ func (r *Room) getVisibleItems() []string {
 			allItems = append(allItems, itemInfo)
 
 			if item.CanPickUp {
-				visibleItems = append(visibleItems, item.Name)
+				visibleItems = append(visibleItems, item.DisplayName())
 				Logger.Info("Found visible item", "item_name", item.Name, "item_id", itemID, "room_id", r.RoomID)
 			} else {
 				Logger.Debug("Item not visible (can't be picked up)", "item_name", item.Name, "item_id", itemID, "room_id", r.RoomID)
@@ -455,9 +715,9 @@ func (r *Room) getVisibleItems() []string {
 }
 
 // LoadAllItems loads all items for all rooms.
-func (kp *KeyPair) LoadAllItems() (map[string]*Item, error) {
+func (kp *KeyPair) LoadAllItems(ctx context.Context) (map[string]*Item, error) {
 	var itemsData []ItemData
-	err := kp.Scan("items", &itemsData)
+	err := kp.ParallelScan(ctx, "items", DefaultScanSegments, &itemsData)
 	if err != nil {
 		Logger.Error("Error scanning items", "error", err)
 		return nil, fmt.Errorf("error scanning items: %w", err)
@@ -469,7 +729,7 @@ func (kp *KeyPair) LoadAllItems() (map[string]*Item, error) {
 			Logger.Warn("Skipping item with empty ID")
 			continue
 		}
-		item, err := kp.itemFromData(&itemData)
+		item, err := kp.itemFromData(ctx, &itemData)
 		if err != nil {
 			Logger.Error("Error creating item from data", "item_id", itemData.ItemID, "error", err)
 			continue
@@ -501,6 +761,16 @@ func (r *Room) AddItem(item *Item) {
 	Logger.Info("Added item to room", "itemName", item.Name, "itemID", item.ID, "roomID", r.RoomID)
 }
 
+// DropItem leaves an item on the ground of the room, where it decays after ItemDecayMinutes.
+func (r *Room) DropItem(item *Item) {
+	if item == nil {
+		return
+	}
+	item.DroppedAt = time.Now()
+	item.DecayWarned = false
+	r.AddItem(item)
+}
+
 // RemoveItem removes an item from the room's item list.
 func (r *Room) RemoveItem(item *Item) {
 	r.Mutex.Lock()
@@ -512,8 +782,100 @@ func (r *Room) RemoveItem(item *Item) {
 	}
 
 	item.LastEdited = time.Now()
+	item.DroppedAt = time.Time{}
+	item.DecayWarned = false
 
 	delete(r.Items, item.ID)
 
 	Logger.Info("Removed item from room", "itemName", item.Name, "itemID", item.ID, "roomID", r.RoomID)
 }
+
+// DefaultContainerCapacity is the mass a container can hold when its capacity is not set.
+const DefaultContainerCapacity = 20.0
+
+// ContainerCapacity returns the most mass the container can hold.
+func (i *Item) ContainerCapacity() float64 {
+	if i.Capacity > 0 {
+		return i.Capacity
+	}
+	return DefaultContainerCapacity
+}
+
+// ContentsMass returns the total mass of the items inside the container.
+func (i *Item) ContentsMass() float64 {
+	i.Mutex.Lock()
+	contents := append([]*Item(nil), i.Contents...)
+	i.Mutex.Unlock()
+
+	mass := 0.0
+	for _, contentItem := range contents {
+		mass += contentItem.TotalMass()
+	}
+	return mass
+}
+
+// contains reports whether the target is this item or is nested anywhere inside it.
+func (i *Item) contains(target *Item) bool {
+	if i == target {
+		return true
+	}
+
+	i.Mutex.Lock()
+	contents := append([]*Item(nil), i.Contents...)
+	i.Mutex.Unlock()
+
+	for _, contentItem := range contents {
+		if contentItem.contains(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// PutItem places an item inside the container, refusing items that would exceed its capacity.
+func (i *Item) PutItem(item *Item) error {
+	if !i.Container {
+		return fmt.Errorf("%s is not a container", i.Name)
+	}
+
+	if item.contains(i) {
+		return fmt.Errorf("you cannot put %s inside itself", item.Name)
+	}
+
+	if i.ContentsMass()+item.TotalMass() > i.ContainerCapacity() {
+		return fmt.Errorf("%s will not fit in %s", item.Name, i.Name)
+	}
+
+	i.Mutex.Lock()
+	i.Contents = append(i.Contents, item)
+	i.LastEdited = time.Now()
+	i.Mutex.Unlock()
+
+	Logger.Info("Put item in container", "itemName", item.Name, "itemID", item.ID, "containerID", i.ID)
+	return nil
+}
+
+// TakeItem removes the first item in the container whose name contains itemName, ignoring case.
+func (i *Item) TakeItem(itemName string) *Item {
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+
+	lowercaseName := strings.ToLower(itemName)
+	for idx, contentItem := range i.Contents {
+		if strings.Contains(strings.ToLower(contentItem.Name), lowercaseName) {
+			i.Contents = append(i.Contents[:idx], i.Contents[idx+1:]...)
+			i.LastEdited = time.Now()
+			Logger.Info("Took item from container", "itemName", contentItem.Name, "itemID", contentItem.ID, "containerID", i.ID)
+			return contentItem
+		}
+	}
+	return nil
+}
+
+// DisplayName returns the short description used in room listings, or the item name if none is set.
+func (i *Item) DisplayName() string {
+	if i.ShortDesc != "" {
+		return i.ShortDesc
+	}
+	return i.Name
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Light levels. Characters can only see in rooms brighter than LightDark.
+const (
+	LightDark        = 0
+	LightIndoors     = 2 // enclosed rooms are lit unless flagged dark
+	LightDaylight    = 3
+	LightSourceLevel = 2 // brightness added by a burning light source
+)
+
+// GivesLight reports whether the item is a burning light source, or always glows because
+// its metadata has "light" set to "true".
+func (item *Item) GivesLight() bool {
+	item.Mutex.Lock()
+	defer item.Mutex.Unlock()
+
+	return item.Lit || item.Metadata["light"] == "true"
+}
+
+// BaseLight returns the room's light level from its flags, the time of day and its own Light,
+// before any light sources are counted.
+func (s *Server) BaseLight(r *Room) int {
+	level := LightIndoors
+	switch {
+	case r.HasFlag(RoomFlagDark):
+		level = LightDark
+	case r.HasFlag(RoomFlagOutdoor) && s.IsNight():
+		level = LightDark
+	case r.HasFlag(RoomFlagOutdoor):
+		level = LightDaylight
+	}
+	return level + r.Light
+}
+
+// LightLevel returns how bright the character's room is to them, counting the light sources
+// they carry and any burning on the ground.
+func (c *Character) LightLevel() int {
+	if c.Room == nil {
+		return LightIndoors
+	}
+
+	level := c.Server.BaseLight(c.Room)
+	if c.HasLight() {
+		level += LightSourceLevel
+	}
+
+	c.Room.Mutex.Lock()
+	for _, item := range c.Room.Items {
+		if item.GivesLight() {
+			level += LightSourceLevel
+			break
+		}
+	}
+	c.Room.Mutex.Unlock()
+
+	return level
+}
+
+// changeLight lights or extinguishes a light source the character is carrying.
+func changeLight(character *Character, tokens []string, lit bool) bool {
+	verb := "light"
+	if !lit {
+		verb = "extinguish"
+	}
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: %s <item>\n\r", verb)
+		return false
+	}
+
+	name := strings.Join(tokens[1:], " ")
+	item := character.FindInInventory(name)
+	if item == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are not carrying %s.\n\r", name)
+		return false
+	}
+
+	item.Mutex.Lock()
+	if !item.LightSource {
+		item.Mutex.Unlock()
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou cannot %s the %s.\n\r", verb, item.Name)
+		return false
+	}
+	if item.Lit == lit {
+		item.Mutex.Unlock()
+		if lit {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s is already lit.\n\r", item.Name)
+		} else {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s is not lit.\n\r", item.Name)
+		}
+		return false
+	}
+	item.Lit = lit
+	item.LastEdited = time.Now()
+	item.Mutex.Unlock()
+
+	character.Mutex.Lock()
+	character.markDirty(DirtyInventory)
+	character.Mutex.Unlock()
+
+	Logger.Info("Player changed a light source", "playerName", character.Player.PlayerID, "itemName", item.Name, "lit", lit)
+
+	// Items may describe lighting themselves with a "light" verb
+	if action, exists := item.ItemVerb(verb); exists {
+		character.runAction(item, action)
+	} else {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou %s the %s.\n\r", verb, item.Name)
+	}
+
+	if lit {
+		sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s lights a %s.\n\r", character.Name, item.Name), character)
+	} else {
+		sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s extinguishes a %s.\n\r", character.Name, item.Name), character)
+	}
+	return false
+}
+
+func ExecuteLightCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is lighting an item", "playerName", character.Player.PlayerID)
+
+	return changeLight(character, tokens, true)
+}
+
+func ExecuteExtinguishCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is extinguishing an item", "playerName", character.Player.PlayerID)
+
+	return changeLight(character, tokens, false)
+}
@@ -2,12 +2,10 @@ package core
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"runtime"
-	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,30 +13,38 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
-	cwlogtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-xray-sdk-go/strategy/ctxmissing"
 	"github.com/aws/aws-xray-sdk-go/xray"
 )
 
 // Global variables
 var (
 	Logger *slog.Logger
+
+	// LogLevel is the minimum level written to stdout. It can be changed while the server runs.
+	LogLevel = new(slog.LevelVar)
 )
 
-func InitializeLogging(cfg *Configuration) error {
-	// Determine the log level
-	var level slog.Level
-	switch cfg.Logging.LogLevel {
+// ConfigLogLevel converts a Logging.LogLevel setting (10 debug, 20 info, 30 warn, 40 error)
+// to a slog level, defaulting to info.
+func ConfigLogLevel(setting int) slog.Level {
+	switch setting {
 	case 10:
-		level = slog.LevelDebug
+		return slog.LevelDebug
 	case 20:
-		level = slog.LevelInfo
+		return slog.LevelInfo
 	case 30:
-		level = slog.LevelWarn
+		return slog.LevelWarn
 	case 40:
-		level = slog.LevelError
+		return slog.LevelError
 	default:
-		level = slog.LevelInfo
+		return slog.LevelInfo
 	}
+}
+
+func InitializeLogging(cfg *Configuration) error {
+	// Determine the log level
+	LogLevel.Set(ConfigLogLevel(cfg.Logging.LogLevel))
 
 	// Initialize AWS SDK configuration
 	awsCfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(cfg.Aws.Region))
@@ -51,17 +57,21 @@ func InitializeLogging(cfg *Configuration) error {
 
 	// Create CloudWatch handler
 	cwHandler := NewCloudWatchHandler(client, cfg.Logging.LogGroup, cfg.Logging.LogStream)
+	cloudWatchLogs = cwHandler.writer
 
-	// Create a multi-writer handler that writes to both CloudWatch and stdout
-	multiHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}).WithAttrs([]slog.Attr{
+	// Create a multi-writer handler that writes to both CloudWatch and stdout. Levels are checked
+	// by levelHandler and the subsystem loggers, so stdout takes every level passed to it.
+	multiHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}).WithAttrs([]slog.Attr{
 		slog.String("application", cfg.Logging.ApplicationName),
 		slog.String("region", cfg.Aws.Region),
 	})
 
 	// Initialize the Logger with both handlers
-	Logger = slog.New(NewMultiHandler(multiHandler, cwHandler))
+	Logger = slog.New(&levelHandler{handler: NewMultiHandler(multiHandler, cwHandler), level: LogLevel})
 	slog.SetDefault(Logger)
 
+	ApplySubsystemLevels(cfg.Logging.SubsystemLevels)
+
 	return nil
 }
 
@@ -91,8 +101,11 @@ func EnableXRay(cfg *Configuration) error {
 
 	Logger.Info("Configuring AWS X-Ray", "logLevel", xrayLogLevel)
 
+	// Work done outside a traced command or login, such as auto-saves, has no segment to
+	// record its AWS calls in and is simply not traced
 	err := xray.Configure(xray.Config{
-		LogLevel: xrayLogLevel,
+		LogLevel:               xrayLogLevel,
+		ContextMissingStrategy: ctxmissing.NewDefaultIgnoreErrorStrategy(),
 	})
 
 	if err != nil {
@@ -100,81 +113,14 @@ func EnableXRay(cfg *Configuration) error {
 		return fmt.Errorf("failed to configure AWS X-Ray: %w", err)
 	}
 
-	Logger.Info("AWS X-Ray successfully configured")
-
-	return nil
-}
-
-func NewCloudWatchHandler(client *cloudwatchlogs.Client, logGroup, logStream string) *CloudWatchHandler {
-	return &CloudWatchHandler{
-		client:      client,
-		logGroup:    logGroup,
-		logStream:   logStream,
-		mutex:       sync.Mutex{},
-		initialized: false,
+	if cfg.Logging.ApplicationName != "" {
+		traceName = cfg.Logging.ApplicationName
 	}
-}
-
-func (h *CloudWatchHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return true
-}
+	tracingEnabled.Store(true)
 
-func (h *CloudWatchHandler) Handle(ctx context.Context, r slog.Record) error {
-	if err := h.initializeLogStream(ctx); err != nil {
-		// Log the error to stdout as a fallback
-		fmt.Printf("Failed to initialize CloudWatch log stream: %v\n", err)
-		return err
-	}
-
-	message := r.Message
-	for _, attr := range h.attrs {
-		message += fmt.Sprintf(" %s=%v", attr.Key, attr.Value)
-	}
-	r.Attrs(func(a slog.Attr) bool {
-		message += fmt.Sprintf(" %s=%v", a.Key, a.Value)
-		return true
-	})
-
-	input := &cloudwatchlogs.PutLogEventsInput{
-		LogGroupName:  aws.String(h.logGroup),
-		LogStreamName: aws.String(h.logStream),
-		LogEvents: []cwlogtypes.InputLogEvent{
-			{
-				Message:   aws.String(message),
-				Timestamp: aws.Int64(time.Now().UnixNano() / int64(time.Millisecond)),
-			},
-		},
-	}
-
-	// Implement retry logic
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		_, err := h.client.PutLogEvents(ctx, input)
-		if err == nil {
-			return nil
-		}
-		if i == maxRetries-1 {
-			// Log the error to stdout as a fallback
-			fmt.Printf("Failed to write log to CloudWatch after %d retries: %v\n", maxRetries, err)
-			return err
-		}
-		// Wait before retrying (you might want to implement exponential backoff here)
-		time.Sleep(time.Second * time.Duration(i+1))
-	}
-	return fmt.Errorf("failed to write log to CloudWatch after %d retries", maxRetries)
-}
-
-func (h *CloudWatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &CloudWatchHandler{
-		client:    h.client,
-		logGroup:  h.logGroup,
-		logStream: h.logStream,
-		attrs:     append(h.attrs, attrs...),
-	}
-}
+	Logger.Info("AWS X-Ray successfully configured")
 
-func (h *CloudWatchHandler) WithGroup(name string) slog.Handler {
-	return h
+	return nil
 }
 
 func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
@@ -192,6 +138,9 @@ func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
 	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
 		if err := handler.Handle(ctx, r); err != nil {
 			return err
 		}
@@ -215,13 +164,20 @@ func (h *MultiHandler) WithGroup(name string) slog.Handler {
 	return NewMultiHandler(newHandlers...)
 }
 
-func SendMetrics(s *Server, interval time.Duration) error {
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(s.Config.Aws.Region))
+// NewCloudWatchClient creates the CloudWatch client shared by SendMetrics and the report metric.
+func NewCloudWatchClient(ctx context.Context, region string) (*cloudwatch.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
-		return fmt.Errorf("failed to load AWS SDK config: %w", err)
+		return nil, fmt.Errorf("failed to load AWS SDK config: %w", err)
 	}
+	return cloudwatch.NewFromConfig(cfg), nil
+}
 
-	client := cloudwatch.NewFromConfig(cfg)
+func SendMetrics(s *Server, interval time.Duration) error {
+	client := s.CloudWatch
+	if client == nil {
+		return fmt.Errorf("no CloudWatch client")
+	}
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -234,27 +190,46 @@ func SendMetrics(s *Server, interval time.Duration) error {
 
 			playerCount := float64(len(s.Characters))
 			memoryUsageMB := float64(m.Alloc) / 1024 / 1024
+			cache := s.Database.Cache.Stats()
 
-			_, err := client.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
-				Namespace: aws.String(s.Config.Logging.MetricNamespace),
-				MetricData: []types.MetricDatum{
-					{
-						MetricName: aws.String("PlayerCount"),
-						Unit:       types.StandardUnitCount,
-						Value:      aws.Float64(playerCount),
-					},
-					{
-						MetricName: aws.String("MemoryUsage"),
-						Unit:       types.StandardUnitMegabytes,
-						Value:      aws.Float64(memoryUsageMB),
-					},
+			metricData := []types.MetricDatum{
+				{
+					MetricName: aws.String("PlayerCount"),
+					Unit:       types.StandardUnitCount,
+					Value:      aws.Float64(playerCount),
 				},
+				{
+					MetricName: aws.String("MemoryUsage"),
+					Unit:       types.StandardUnitMegabytes,
+					Value:      aws.Float64(memoryUsageMB),
+				},
+				{
+					MetricName: aws.String("CacheHits"),
+					Unit:       types.StandardUnitCount,
+					Value:      aws.Float64(float64(cache.Hits)),
+				},
+				{
+					MetricName: aws.String("CacheMisses"),
+					Unit:       types.StandardUnitCount,
+					Value:      aws.Float64(float64(cache.Misses)),
+				},
+				{
+					MetricName: aws.String("CacheSize"),
+					Unit:       types.StandardUnitCount,
+					Value:      aws.Float64(float64(cache.Size)),
+				},
+			}
+			metricData = append(metricData, collectMetrics()...)
+
+			_, err := client.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
+				Namespace:  aws.String(s.Config.Logging.MetricNamespace),
+				MetricData: metricData,
 			})
 
 			if err != nil {
 				Logger.Error("Failed to send metrics to CloudWatch", "error", err)
 			} else {
-				Logger.Info("Sent metrics to CloudWatch", "playerCount", playerCount, "memoryUsageMB", memoryUsageMB)
+				Logger.Info("Sent metrics to CloudWatch", "playerCount", playerCount, "memoryUsageMB", memoryUsageMB, "cacheHits", cache.Hits, "cacheMisses", cache.Misses, "cacheSize", cache.Size)
 			}
 
 		case <-s.Context.Done():
@@ -263,43 +238,3 @@ func SendMetrics(s *Server, interval time.Duration) error {
 		}
 	}
 }
-
-func (h *CloudWatchHandler) initializeLogStream(ctx context.Context) error {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
-
-	if h.initialized {
-		return nil
-	}
-
-	// Check if the log stream exists
-	describeLogStreamsInput := &cloudwatchlogs.DescribeLogStreamsInput{
-		LogGroupName:        aws.String(h.logGroup),
-		LogStreamNamePrefix: aws.String(h.logStream),
-	}
-
-	output, err := h.client.DescribeLogStreams(ctx, describeLogStreamsInput)
-	if err != nil {
-		// If the error is not because the stream doesn't exist, return the error
-		var notFoundErr *types.ResourceNotFoundException
-		if !errors.As(err, &notFoundErr) {
-			return fmt.Errorf("failed to describe log streams: %w", err)
-		}
-	}
-
-	// If the log stream doesn't exist, create it
-	if output == nil || len(output.LogStreams) == 0 {
-		createLogStreamInput := &cloudwatchlogs.CreateLogStreamInput{
-			LogGroupName:  aws.String(h.logGroup),
-			LogStreamName: aws.String(h.logStream),
-		}
-
-		_, err = h.client.CreateLogStream(ctx, createLogStreamInput)
-		if err != nil {
-			return fmt.Errorf("failed to create log stream: %w", err)
-		}
-	}
-
-	h.initialized = true
-	return nil
-}
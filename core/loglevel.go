@@ -0,0 +1,217 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Subsystems whose log level can be set apart from LogLevel. Their records carry a subsystem
+// attribute naming them.
+var (
+	DatabaseLog = slog.New(&subsystemHandler{name: "database"})
+	CombatLog   = slog.New(&subsystemHandler{name: "combat"})
+	NetworkLog  = slog.New(&subsystemHandler{name: "network"})
+)
+
+// Subsystems lists the names accepted by SetSubsystemLevel.
+var Subsystems = []string{"combat", "database", "network"}
+
+// subsystemLevels holds the levels set for subsystems; a subsystem without one follows LogLevel.
+var subsystemLevels = struct {
+	levels map[string]slog.Level
+	Mutex  sync.RWMutex
+}{levels: make(map[string]slog.Level)}
+
+// ParseLogLevel reads a level name: debug, info, warn or error.
+func ParseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+}
+
+func isSubsystem(name string) bool {
+	for _, subsystem := range Subsystems {
+		if subsystem == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSubsystemLevel sets the level of one subsystem, leaving the rest of the server at LogLevel.
+func SetSubsystemLevel(name string, level slog.Level) error {
+	if !isSubsystem(name) {
+		return fmt.Errorf("unknown subsystem %q, use one of %s", name, strings.Join(Subsystems, ", "))
+	}
+
+	subsystemLevels.Mutex.Lock()
+	subsystemLevels.levels[name] = level
+	subsystemLevels.Mutex.Unlock()
+	return nil
+}
+
+// ClearSubsystemLevel returns a subsystem to following LogLevel.
+func ClearSubsystemLevel(name string) {
+	subsystemLevels.Mutex.Lock()
+	delete(subsystemLevels.levels, name)
+	subsystemLevels.Mutex.Unlock()
+}
+
+// SubsystemLevel returns the level a subsystem logs at and whether it was set apart from LogLevel.
+func SubsystemLevel(name string) (slog.Level, bool) {
+	subsystemLevels.Mutex.RLock()
+	defer subsystemLevels.Mutex.RUnlock()
+
+	if level, ok := subsystemLevels.levels[name]; ok {
+		return level, true
+	}
+	return LogLevel.Level(), false
+}
+
+// ApplySubsystemLevels replaces the subsystem levels with those in Logging.SubsystemLevels,
+// which use the same numbers as Logging.LogLevel.
+func ApplySubsystemLevels(settings map[string]int) {
+	subsystemLevels.Mutex.Lock()
+	subsystemLevels.levels = make(map[string]slog.Level, len(settings))
+	subsystemLevels.Mutex.Unlock()
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := SetSubsystemLevel(name, ConfigLogLevel(settings[name])); err != nil {
+			Logger.Warn("Ignoring subsystem log level", "error", err)
+		}
+	}
+}
+
+// levelHandler drops records below its level before they reach the wrapped handler, which
+// is left to pass every level so that subsystems can log below LogLevel.
+type levelHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{handler: h.handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{handler: h.handler.WithGroup(name), level: h.level}
+}
+
+// subsystemHandler writes through whatever handler Logger has at the time, so the subsystem
+// loggers can be created before logging is initialized and follow any later change to Logger.
+type subsystemHandler struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// base returns the handler beneath Logger's level check.
+func (h *subsystemHandler) base() slog.Handler {
+	logger := Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	handler := logger.Handler()
+	if leveled, ok := handler.(*levelHandler); ok {
+		return leveled.handler
+	}
+	return handler
+}
+
+func (h *subsystemHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	minimum, _ := SubsystemLevel(h.name)
+	return level >= minimum && h.base().Enabled(ctx, level)
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(h.attrs...)
+	r.AddAttrs(slog.String("subsystem", h.name))
+	return h.base().Handle(ctx, r)
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &subsystemHandler{name: h.name, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *subsystemHandler) WithGroup(name string) slog.Handler {
+	return h
+}
+
+func ExecuteLogLevelCommand(character *Character, tokens []string) bool {
+
+	switch len(tokens) {
+	case 1:
+		var output strings.Builder
+		output.WriteString(fmt.Sprintf("\n\rServer log level: %s\n\r", LogLevel.Level()))
+		for _, name := range Subsystems {
+			level, set := SubsystemLevel(name)
+			if set {
+				output.WriteString(fmt.Sprintf("  %-10s %s\n\r", name, level))
+			} else {
+				output.WriteString(fmt.Sprintf("  %-10s %s (server level)\n\r", name, level))
+			}
+		}
+		character.Player.ToPlayer <- output.String()
+		return false
+
+	case 2:
+		level, err := ParseLogLevel(tokens[1])
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%v\n\r", err)
+			return false
+		}
+		LogLevel.Set(level)
+		Logger.Warn("Admin changed the log level", "playerName", character.Player.PlayerID, "level", level)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rServer log level set to %s.\n\r", level)
+		return false
+
+	case 3:
+		name := strings.ToLower(tokens[1])
+		if strings.EqualFold(tokens[2], "default") {
+			ClearSubsystemLevel(name)
+			Logger.Warn("Admin cleared a subsystem log level", "playerName", character.Player.PlayerID, "subsystem", name)
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s log level now follows the server level.\n\r", name)
+			return false
+		}
+		level, err := ParseLogLevel(tokens[2])
+		if err == nil {
+			err = SetSubsystemLevel(name, level)
+		}
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%v\n\r", err)
+			return false
+		}
+		Logger.Warn("Admin changed a subsystem log level", "playerName", character.Player.PlayerID, "subsystem", name, "level", level)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThe %s log level is set to %s.\n\r", name, level)
+		return false
+	}
+
+	character.Player.ToPlayer <- "\n\rUsage: loglevel [subsystem] [debug|info|warn|error|default]\n\r"
+	return false
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits on how many rooms away the map command reaches.
+const (
+	DefaultMapRadius = 3
+	MaxMapRadius     = 6
+)
+
+// mapOffsets is the grid step taken by each direction that can be drawn on a flat map.
+var mapOffsets = map[string][2]int{
+	"north":     {0, -1},
+	"south":     {0, 1},
+	"east":      {1, 0},
+	"west":      {-1, 0},
+	"northeast": {1, -1},
+	"northwest": {-1, -1},
+	"southeast": {1, 1},
+	"southwest": {-1, 1},
+}
+
+// mapConnectors is the symbol drawn between two rooms joined in each direction.
+var mapConnectors = map[string]byte{
+	"north":     '|',
+	"south":     '|',
+	"east":      '-',
+	"west":      '-',
+	"northeast": '/',
+	"southwest": '/',
+	"northwest": '\\',
+	"southeast": '\\',
+}
+
+// mapCell is one character of a rendered map and the kind of output it is colored as.
+type mapCell struct {
+	symbol byte
+	kind   string
+}
+
+// mapRoom is a room placed on the map grid, relative to the character's room at 0,0.
+type mapRoom struct {
+	room    *Room
+	x, y    int
+	exits   map[string]*Room
+	up      bool
+	down    bool
+	other   bool // another character is in the room
+	visited bool // the viewer has explored the room
+}
+
+// layoutMap walks visible exits breadth-first from the room, placing each room reached on a grid
+// up to radius steps away. Rooms whose position is already taken, as in twisting passages, are skipped.
+func layoutMap(viewer *Character, start *Room, radius int) []*mapRoom {
+	placed := make(map[[2]int]*mapRoom)
+	seen := make(map[int64]bool)
+
+	origin := &mapRoom{room: start}
+	placed[[2]int{0, 0}] = origin
+	seen[start.RoomID] = true
+
+	queue := []*mapRoom{origin}
+	order := []*mapRoom{origin}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		current.room.Mutex.Lock()
+		current.exits = make(map[string]*Room)
+		for direction, exit := range current.room.Exits {
+			if !exit.Visible || exit.TargetRoom == nil {
+				continue
+			}
+			switch direction {
+			case "up":
+				current.up = true
+			case "down":
+				current.down = true
+			default:
+				current.exits[direction] = exit.TargetRoom
+			}
+		}
+		for _, other := range current.room.Characters {
+			if other != viewer && other.IsActive() {
+				current.other = true
+			}
+		}
+		current.room.Mutex.Unlock()
+
+		if max(abs(current.x), abs(current.y)) >= radius {
+			continue
+		}
+
+		for direction, target := range current.exits {
+			offset, drawable := mapOffsets[direction]
+			if !drawable || seen[target.RoomID] {
+				continue
+			}
+			position := [2]int{current.x + offset[0], current.y + offset[1]}
+			if _, taken := placed[position]; taken {
+				continue
+			}
+
+			next := &mapRoom{room: target, x: position[0], y: position[1]}
+			placed[position] = next
+			seen[target.RoomID] = true
+			queue = append(queue, next)
+			order = append(order, next)
+		}
+	}
+
+	return order
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// renderMap draws the placed rooms as text. Each room is three characters wide, "[@]" for the
+// character's own room, with connectors between rooms and ? marking exits that lead off the map.
+// Rooms the character has not visited are drawn greyed out in parentheses.
+func renderMap(player *Player, rooms []*mapRoom, radius int) string {
+	// Leave a margin so exits leading off the edge can still be drawn
+	width := radius*8 + 11
+	height := radius*4 + 5
+
+	canvas := make([][]mapCell, height)
+	for row := range canvas {
+		canvas[row] = make([]mapCell, width)
+		for col := range canvas[row] {
+			canvas[row][col] = mapCell{symbol: ' '}
+		}
+	}
+
+	set := func(col, row int, symbol byte, kind string) {
+		if row < 0 || row >= height || col < 0 || col >= width {
+			return
+		}
+		if existing := canvas[row][col].symbol; existing != ' ' && existing != symbol {
+			// Diagonal connectors crossing between four rooms
+			if (existing == '/' && symbol == '\\') || (existing == '\\' && symbol == '/') {
+				symbol = 'X'
+			} else {
+				return
+			}
+		}
+		canvas[row][col] = mapCell{symbol: symbol, kind: kind}
+	}
+
+	onMap := make(map[int64][2]int, len(rooms))
+	for _, placed := range rooms {
+		onMap[placed.room.RoomID] = [2]int{placed.x, placed.y}
+	}
+
+	for _, placed := range rooms {
+		col := (placed.x+radius)*4 + 4
+		row := (placed.y+radius)*2 + 2
+
+		mark, kind := byte(' '), ""
+		switch {
+		case placed.x == 0 && placed.y == 0:
+			mark, kind = '@', ColorTitle
+		case placed.other:
+			mark, kind = '*', ColorCharacters
+		case placed.up && placed.down:
+			mark = '%'
+		case placed.up:
+			mark = '^'
+		case placed.down:
+			mark = 'v'
+		}
+		opening, closing, frame := byte('['), byte(']'), ""
+		if !placed.visited {
+			opening, closing, frame = '(', ')', ColorUnvisited
+			if kind == "" {
+				kind = ColorUnvisited
+			}
+		}
+		set(col, row, opening, frame)
+		set(col+1, row, mark, kind)
+		set(col+2, row, closing, frame)
+
+		for direction, target := range placed.exits {
+			offset, drawable := mapOffsets[direction]
+			if !drawable {
+				continue
+			}
+			set(col+1+offset[0]*2, row+offset[1], mapConnectors[direction], ColorExits)
+
+			// Exits to rooms that are not drawn next to this one lead somewhere unexplored
+			position, drawn := onMap[target.RoomID]
+			if !drawn || position != [2]int{placed.x + offset[0], placed.y + offset[1]} {
+				set(col+1+offset[0]*4, row+offset[1]*2, '?', ColorExits)
+			}
+		}
+	}
+
+	// Trim the blank rows and columns around the drawn rooms
+	top, bottom, left, right := height, -1, width, -1
+	for row := range canvas {
+		for col, cell := range canvas[row] {
+			if cell.symbol != ' ' {
+				top, bottom = min(top, row), max(bottom, row)
+				left, right = min(left, col), max(right, col)
+			}
+		}
+	}
+
+	var output strings.Builder
+	for row := top; row <= bottom; row++ {
+		line := canvas[row][left : right+1]
+		end := len(line)
+		for end > 0 && line[end-1].symbol == ' ' {
+			end--
+		}
+		for _, cell := range line[:end] {
+			if cell.kind != "" {
+				output.WriteString(player.Colorize(cell.kind, string(cell.symbol)))
+			} else {
+				output.WriteByte(cell.symbol)
+			}
+		}
+		output.WriteString("\n\r")
+	}
+	return output.String()
+}
+
+// mapRadiusFor shrinks the radius until the map fits the player's console.
+func mapRadiusFor(player *Player, radius int) int {
+	for radius > 1 && (radius*8+3 > player.ConsoleWidth || radius*4+1 > player.ConsoleHeight-3) {
+		radius--
+	}
+	return radius
+}
+
+func ExecuteMapCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is viewing the map", "playerName", character.Player.PlayerID)
+
+	radius := DefaultMapRadius
+	if len(tokens) > 1 {
+		var err error
+		radius, err = strconv.Atoi(tokens[1])
+		if err != nil || radius < 1 || radius > MaxMapRadius {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: map [radius], where radius is between 1 and %d\n\r", MaxMapRadius)
+			return false
+		}
+	}
+	radius = mapRadiusFor(character.Player, radius)
+
+	character.Mutex.Lock()
+	room := character.Room
+	character.Mutex.Unlock()
+
+	if room == nil {
+		character.Player.ToPlayer <- "\n\rYou are nowhere at all.\n\r"
+		return false
+	}
+
+	rooms := layoutMap(character, room, radius)
+
+	character.Mutex.Lock()
+	for _, placed := range rooms {
+		placed.visited = placed.room == room || character.Explored[placed.room.RoomID]
+	}
+	character.Mutex.Unlock()
+
+	var output strings.Builder
+	output.WriteString(character.Player.Colorize(ColorTitle, fmt.Sprintf("\n\r[%s]\n\r", character.Server.AreaName(room))))
+	output.WriteString(renderMap(character.Player, rooms, radius))
+	output.WriteString("@ you  * others  ^ up  v down  % up and down  ( ) unvisited  ? unexplored\n\r")
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
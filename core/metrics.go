@@ -0,0 +1,326 @@
+package core
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxLatencySamples bounds the command latencies kept between publications. Past it, samples
+// are replaced at random so the percentiles still describe the whole interval.
+const maxLatencySamples = 10000
+
+// commandLatencyBuckets are the upper bounds, in seconds, of the Prometheus command latency
+// histogram.
+var commandLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// metrics collects the counters and timings published by SendMetrics and the Prometheus
+// endpoint. SendMetrics resets the interval values each time it publishes them, so every
+// CloudWatch value covers one metrics interval; the totals count from startup, as Prometheus
+// expects.
+var metrics = newMetricsCollector()
+
+type metricsCollector struct {
+	commands          int
+	commandLatencies  []float64 // milliseconds
+	latencySeen       int
+	database          map[string]*databaseStats
+	connections       int
+	connectionErrors  int
+	handshakeFailures int
+	authFailures      int
+	started           time.Time
+	totals            metricTotals
+	Mutex             sync.Mutex
+}
+
+// metricTotals are the counters kept since startup.
+type metricTotals struct {
+	commands          int64
+	commandSeconds    float64
+	commandBuckets    []int64 // commands at or under each of commandLatencyBuckets
+	database          map[string]*databaseTotals
+	connections       int64
+	connectionErrors  int64
+	handshakeFailures int64
+	authFailures      int64
+}
+
+// databaseTotals are the counters of one kind of DynamoDB call since startup.
+type databaseTotals struct {
+	calls   int64
+	errors  int64
+	retries int64
+	seconds float64
+}
+
+// databaseStats are the timings of one kind of DynamoDB call.
+type databaseStats struct {
+	calls   int
+	errors  int
+	retries int
+	total   float64 // milliseconds
+	minimum float64
+	maximum float64
+}
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{
+		database: make(map[string]*databaseStats),
+		started:  time.Now(),
+		totals: metricTotals{
+			commandBuckets: make([]int64, len(commandLatencyBuckets)),
+			database:       make(map[string]*databaseTotals),
+		},
+	}
+}
+
+// RecordCommand counts a command run by a player and how long its handler took.
+func RecordCommand(duration time.Duration) {
+	m := metrics
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	m.commands++
+	m.latencySeen++
+	ms := float64(duration) / float64(time.Millisecond)
+	if len(m.commandLatencies) < maxLatencySamples {
+		m.commandLatencies = append(m.commandLatencies, ms)
+	} else if i := rand.Intn(m.latencySeen); i < maxLatencySamples {
+		m.commandLatencies[i] = ms
+	}
+
+	m.totals.commands++
+	m.totals.commandSeconds += duration.Seconds()
+	for i, bound := range commandLatencyBuckets {
+		if duration.Seconds() <= bound {
+			m.totals.commandBuckets[i]++
+		}
+	}
+}
+
+// RecordDatabaseCall times one DynamoDB request. A failed condition check is the expected
+// answer to a versioned write, so it is not counted as an error.
+func RecordDatabaseCall(operation string, duration time.Duration, err error) {
+	m := metrics
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	stats := m.databaseStats(operation)
+	ms := float64(duration) / float64(time.Millisecond)
+	if stats.calls == 0 || ms < stats.minimum {
+		stats.minimum = ms
+	}
+	if ms > stats.maximum {
+		stats.maximum = ms
+	}
+	stats.calls++
+	stats.total += ms
+
+	totals := m.databaseTotals(operation)
+	totals.calls++
+	totals.seconds += duration.Seconds()
+
+	var conditionErr *ddbtypes.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &conditionErr) {
+		stats.errors++
+		totals.errors++
+	}
+}
+
+// RecordDatabaseRetry counts a DynamoDB request that is sent again after being throttled or
+// partly processed.
+func RecordDatabaseRetry(operation string) {
+	m := metrics
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	m.databaseStats(operation).retries++
+	m.databaseTotals(operation).retries++
+}
+
+// databaseStats returns the stats of an operation, the caller holding the mutex.
+func (m *metricsCollector) databaseStats(operation string) *databaseStats {
+	stats, ok := m.database[operation]
+	if !ok {
+		stats = &databaseStats{}
+		m.database[operation] = stats
+	}
+	return stats
+}
+
+// databaseTotals returns the totals of an operation, the caller holding the mutex.
+func (m *metricsCollector) databaseTotals(operation string) *databaseTotals {
+	totals, ok := m.totals.database[operation]
+	if !ok {
+		totals = &databaseTotals{}
+		m.totals.database[operation] = totals
+	}
+	return totals
+}
+
+// RecordConnection counts a connection accepted by the SSH listener.
+func RecordConnection() {
+	metrics.Mutex.Lock()
+	metrics.connections++
+	metrics.totals.connections++
+	metrics.Mutex.Unlock()
+}
+
+// RecordConnectionError counts a failure to accept a connection.
+func RecordConnectionError() {
+	metrics.Mutex.Lock()
+	metrics.connectionErrors++
+	metrics.totals.connectionErrors++
+	metrics.Mutex.Unlock()
+}
+
+// RecordHandshakeFailure counts a connection dropped before its SSH handshake completed.
+func RecordHandshakeFailure() {
+	metrics.Mutex.Lock()
+	metrics.handshakeFailures++
+	metrics.totals.handshakeFailures++
+	metrics.Mutex.Unlock()
+}
+
+// RecordAuthFailure counts a rejected login.
+func RecordAuthFailure() {
+	metrics.Mutex.Lock()
+	metrics.authFailures++
+	metrics.totals.authFailures++
+	metrics.Mutex.Unlock()
+}
+
+// snapshotTotals returns a copy of the totals that can be read without the mutex.
+func snapshotTotals() metricTotals {
+	m := metrics
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	totals := m.totals
+	totals.commandBuckets = append([]int64(nil), m.totals.commandBuckets...)
+	totals.database = make(map[string]*databaseTotals, len(m.totals.database))
+	for operation, stats := range m.totals.database {
+		copied := *stats
+		totals.database[operation] = &copied
+	}
+	return totals
+}
+
+// percentile returns the value below which the fraction p of the sorted values fall.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// collectMetrics returns the metrics gathered since the last call and starts a new interval.
+func collectMetrics() []types.MetricDatum {
+	m := metrics
+	m.Mutex.Lock()
+	commands := m.commands
+	latencies := m.commandLatencies
+	database := m.database
+	connections, connectionErrors := m.connections, m.connectionErrors
+	handshakeFailures, authFailures := m.handshakeFailures, m.authFailures
+	elapsed := time.Since(m.started)
+
+	m.commands, m.latencySeen, m.commandLatencies = 0, 0, nil
+	m.database = make(map[string]*databaseStats)
+	m.connections, m.connectionErrors, m.handshakeFailures, m.authFailures = 0, 0, 0, 0
+	m.started = time.Now()
+	m.Mutex.Unlock()
+
+	count := func(name string, value int) types.MetricDatum {
+		return types.MetricDatum{
+			MetricName: aws.String(name),
+			Unit:       types.StandardUnitCount,
+			Value:      aws.Float64(float64(value)),
+		}
+	}
+
+	perMinute := 0.0
+	if elapsed > 0 {
+		perMinute = float64(commands) / elapsed.Minutes()
+	}
+
+	data := []types.MetricDatum{
+		count("CommandsProcessed", commands),
+		{
+			// CloudWatch has no per-minute unit, so the rate is published without one
+			MetricName: aws.String("CommandsPerMinute"),
+			Unit:       types.StandardUnitNone,
+			Value:      aws.Float64(perMinute),
+		},
+		count("ConnectionsAccepted", connections),
+		count("ConnectionErrors", connectionErrors),
+		count("HandshakeFailures", handshakeFailures),
+		count("AuthFailures", authFailures),
+		count("Goroutines", runtime.NumGoroutine()),
+	}
+
+	if len(latencies) > 0 {
+		sort.Float64s(latencies)
+		for _, p := range []struct {
+			name     string
+			fraction float64
+		}{
+			{"CommandLatencyP50", 0.50},
+			{"CommandLatencyP90", 0.90},
+			{"CommandLatencyP99", 0.99},
+			{"CommandLatencyMax", 1},
+		} {
+			data = append(data, types.MetricDatum{
+				MetricName: aws.String(p.name),
+				Unit:       types.StandardUnitMilliseconds,
+				Value:      aws.Float64(percentile(latencies, p.fraction)),
+			})
+		}
+	}
+
+	for operation, stats := range database {
+		dimensions := []types.Dimension{{Name: aws.String("Operation"), Value: aws.String(operation)}}
+		data = append(data,
+			types.MetricDatum{
+				MetricName: aws.String("DatabaseLatency"),
+				Unit:       types.StandardUnitMilliseconds,
+				Dimensions: dimensions,
+				StatisticValues: &types.StatisticSet{
+					SampleCount: aws.Float64(float64(stats.calls)),
+					Sum:         aws.Float64(stats.total),
+					Minimum:     aws.Float64(stats.minimum),
+					Maximum:     aws.Float64(stats.maximum),
+				},
+			},
+			types.MetricDatum{
+				MetricName: aws.String("DatabaseErrors"),
+				Unit:       types.StandardUnitCount,
+				Dimensions: dimensions,
+				Value:      aws.Float64(float64(stats.errors)),
+			},
+			types.MetricDatum{
+				MetricName: aws.String("DatabaseRetries"),
+				Unit:       types.StandardUnitCount,
+				Dimensions: dimensions,
+				Value:      aws.Float64(float64(stats.retries)),
+			},
+		)
+	}
+
+	return data
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SchemaVersionAttribute is the attribute recording which migrations a stored record has had.
+const SchemaVersionAttribute = "SchemaVersion"
+
+// Migration upgrades records of one table from the previous schema version to Version. Apply
+// works on the stored attributes so it can read fields the current data structs no longer have.
+// Records partly saved by UpdateCharacter can keep an old SchemaVersion, so Apply must leave a
+// record that is already in the new format unchanged.
+type Migration struct {
+	Table       string
+	Version     int // schema version after Apply; each table counts up from 1
+	Description string
+	Apply       func(record map[string]ddbtypes.AttributeValue) error
+}
+
+// Migrations lists every migration, in version order within each table. Records are migrated
+// when they are read, and for good by the migrate command of world_tool.
+var Migrations = []Migration{
+	{
+		Table:       "characters",
+		Version:     1,
+		Description: "Start characters saved before levels were tracked at level 1",
+		Apply:       migrateCharacterLevel,
+	},
+}
+
+// MigrationRecord is the entry in the migrations table for a migration run over a whole table.
+type MigrationRecord struct {
+	MigrationID string `json:"MigrationID" dynamodbav:"MigrationID"` // table and version, such as characters-1
+	Table       string `json:"Table" dynamodbav:"Table"`
+	Version     int    `json:"Version" dynamodbav:"Version"`
+	Description string `json:"Description" dynamodbav:"Description"`
+	AppliedAt   string `json:"AppliedAt" dynamodbav:"AppliedAt"` // RFC 3339
+	Records     int    `json:"Records" dynamodbav:"Records"`     // records rewritten by the run
+}
+
+// SchemaVersion returns the current schema version of the table, 0 when it has no migrations.
+func SchemaVersion(tableName string) int {
+	version := 0
+	for _, migration := range Migrations {
+		if migration.Table == tableName && migration.Version > version {
+			version = migration.Version
+		}
+	}
+	return version
+}
+
+// recordSchemaVersion returns the schema version a record was written at, 0 when it predates
+// schema versions.
+func recordSchemaVersion(record map[string]ddbtypes.AttributeValue) int {
+	number, ok := record[SchemaVersionAttribute].(*ddbtypes.AttributeValueMemberN)
+	if !ok {
+		return 0
+	}
+	version, err := strconv.Atoi(number.Value)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// MigrateRecord applies the table's pending migrations to record in place and reports whether
+// any were applied.
+func MigrateRecord(tableName string, record map[string]ddbtypes.AttributeValue) (bool, error) {
+	current := recordSchemaVersion(record)
+	if current >= SchemaVersion(tableName) {
+		return false, nil
+	}
+
+	for _, migration := range Migrations {
+		if migration.Table != tableName || migration.Version <= current {
+			continue
+		}
+		if err := migration.Apply(record); err != nil {
+			return false, fmt.Errorf("error applying migration %s-%d: %w", tableName, migration.Version, err)
+		}
+		current = migration.Version
+		record[SchemaVersionAttribute] = &ddbtypes.AttributeValueMemberN{Value: strconv.Itoa(current)}
+	}
+
+	return true, nil
+}
+
+// migrateRecords brings records read from the database up to date before they are unmarshalled.
+// A record that fails to migrate is logged and returned as it was read.
+func migrateRecords(tableName string, records []map[string]ddbtypes.AttributeValue) {
+	if SchemaVersion(tableName) == 0 {
+		return
+	}
+	for _, record := range records {
+		migrated, err := MigrateRecord(tableName, record)
+		if err != nil {
+			DatabaseLog.Error("Error migrating record", "tableName", tableName, "error", err)
+		} else if migrated {
+			DatabaseLog.Debug("Migrated record on read", "tableName", tableName, "schemaVersion", SchemaVersion(tableName))
+		}
+	}
+}
+
+// stampSchemaVersion tags a record about to be written with the table's current schema version,
+// since every record the server writes is in the current format.
+func stampSchemaVersion(tableName string, record map[string]ddbtypes.AttributeValue) {
+	if version := SchemaVersion(tableName); version > 0 {
+		record[SchemaVersionAttribute] = &ddbtypes.AttributeValueMemberN{Value: strconv.Itoa(version)}
+	}
+}
+
+// MigrateTable rewrites every record of the table that is behind its schema version and records
+// the table's migrations in the migrations table. With dryRun set, the stale records are only
+// counted. Run it while the server is stopped, as the rewrite does not check record versions.
+func (kp *KeyPair) MigrateTable(ctx context.Context, tableName string, dryRun bool) (int, error) {
+	if SchemaVersion(tableName) == 0 {
+		return 0, nil
+	}
+
+	// ScanPages passes records as stored, before the migrations applied on read
+	var stale []interface{}
+	err := kp.ScanPages(ctx, tableName, func(page []map[string]ddbtypes.AttributeValue) error {
+		for _, record := range page {
+			migrated, err := MigrateRecord(tableName, record)
+			if err != nil {
+				return err
+			}
+			if migrated {
+				stale = append(stale, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error migrating table %s: %w", tableName, err)
+	}
+
+	if dryRun {
+		return len(stale), nil
+	}
+
+	if err := kp.BatchPut(ctx, tableName, stale); err != nil {
+		return 0, fmt.Errorf("error writing migrated records to table %s: %w", tableName, err)
+	}
+
+	appliedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, migration := range Migrations {
+		if migration.Table != tableName {
+			continue
+		}
+		record := MigrationRecord{
+			MigrationID: fmt.Sprintf("%s-%d", tableName, migration.Version),
+			Table:       tableName,
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   appliedAt,
+			Records:     len(stale),
+		}
+		if err := kp.Put(ctx, "migrations", record); err != nil {
+			return len(stale), fmt.Errorf("error recording migration %s: %w", record.MigrationID, err)
+		}
+	}
+
+	DatabaseLog.Info("Migrated table", "tableName", tableName, "schemaVersion", SchemaVersion(tableName), "records", len(stale))
+	return len(stale), nil
+}
+
+// MigrateAll runs MigrateTable over every table that has migrations, returning the number of
+// records migrated in each.
+func (kp *KeyPair) MigrateAll(ctx context.Context, dryRun bool) (map[string]int, error) {
+	tables := make(map[string]bool)
+	for _, migration := range Migrations {
+		tables[migration.Table] = true
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	counts := make(map[string]int, len(names))
+	for _, name := range names {
+		count, err := kp.MigrateTable(ctx, name, dryRun)
+		if err != nil {
+			return counts, err
+		}
+		counts[name] = count
+	}
+	return counts, nil
+}
+
+// AppliedMigrations lists the migrations recorded in the migrations table.
+func (kp *KeyPair) AppliedMigrations(ctx context.Context) ([]MigrationRecord, error) {
+	var records []MigrationRecord
+	if err := kp.Scan(ctx, "migrations", &records); err != nil {
+		return nil, fmt.Errorf("error scanning migrations: %w", err)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].MigrationID < records[j].MigrationID })
+	return records, nil
+}
+
+// migrateCharacterLevel sets the level of characters saved before levels were tracked to 1.
+func migrateCharacterLevel(record map[string]ddbtypes.AttributeValue) error {
+	if number, ok := record["Level"].(*ddbtypes.AttributeValueMemberN); ok {
+		if level, err := strconv.Atoi(number.Value); err == nil && level >= 1 {
+			return nil
+		}
+	}
+	record["Level"] = &ddbtypes.AttributeValueMemberN{Value: "1"}
+	return nil
+}
@@ -1,52 +1,110 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/google/uuid"
 )
 
-func (k *KeyPair) GetAllMOTDs() ([]*MOTD, error) {
-	input := &dynamodb.ScanInput{
-		TableName:        aws.String("motd"),
-		FilterExpression: aws.String("active = :active"),
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":active": {
-				BOOL: aws.Bool(true),
-			},
-		},
-	}
-
-	result, err := k.db.Scan(input)
+func (k *KeyPair) GetAllMOTDs(ctx context.Context) ([]*MOTD, error) {
+	var motdsData []MOTDData
+	err := k.Scan(ctx, "motd", &motdsData)
 	if err != nil {
 		return nil, fmt.Errorf("error scanning MOTDs: %w", err)
 	}
 
-	var motds []*MOTD
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &motds)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling MOTDs: %w", err)
+	motds := make([]*MOTD, 0, len(motdsData))
+	for _, data := range motdsData {
+		if !data.Active {
+			continue
+		}
+		motdID, err := uuid.Parse(data.MotdID)
+		if err != nil {
+			Logger.Error("Invalid MOTD UUID", "motdID", data.MotdID, "error", err)
+			continue
+		}
+		// Messages added by the database scripts carry no time zone
+		createdAt, err := time.Parse(time.RFC3339, data.CreatedAt)
+		if err != nil {
+			createdAt, _ = time.Parse("2006-01-02T15:04:05.999999", data.CreatedAt)
+		}
+		motds = append(motds, &MOTD{
+			MotdID:    motdID,
+			Active:    data.Active,
+			Message:   data.Message,
+			CreatedAt: createdAt,
+		})
 	}
 
+	// Show the oldest messages first
+	sort.Slice(motds, func(i, j int) bool {
+		return motds[i].CreatedAt.Before(motds[j].CreatedAt)
+	})
+
 	return motds, nil
 }
 
+// WriteMOTD stores a message of the day in the DynamoDB database.
+func (k *KeyPair) WriteMOTD(ctx context.Context, motd *MOTD) error {
+	data := MOTDData{
+		MotdID:    motd.MotdID.String(),
+		Active:    motd.Active,
+		Message:   motd.Message,
+		CreatedAt: motd.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if err := k.Put(ctx, "motd", data); err != nil {
+		return fmt.Errorf("error writing MOTD: %w", err)
+	}
+	return nil
+}
+
+// LoadBanner reads the configured banner file shown to players when they connect.
+// A missing banner is not an error; the server simply shows no banner.
+func (s *Server) LoadBanner() error {
+	bannerFilePath := ResolveDataPath(s.Config.Data.BannerFile, DefaultBannerFile)
+	Logger.Info("Resolved banner file", "path", bannerFilePath)
+
+	data, err := os.ReadFile(bannerFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			Logger.Info("No banner file found", "path", bannerFilePath)
+			return nil
+		}
+		return fmt.Errorf("failed to read banner from %s: %w", bannerFilePath, err)
+	}
+
+	s.Banner = string(data)
+	return nil
+}
+
 func DisplayUnseenMOTDs(server *Server, player *Player) {
 	if server == nil || player == nil {
 		Logger.Error("Invalid server or player object")
 		return
 	}
 
+	if server.Banner != "" {
+		player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", server.Banner)
+	}
+
 	Logger.Info("Displaying MOTDs for player", "playerName", player.PlayerID)
 
 	defaultMOTDID, _ := uuid.Parse("00000000-0000-0000-0000-000000000000")
 	welcomeDisplayed := false
 
+	// Admins can change the messages while players log in
+	server.Mutex.Lock()
+	activeMotDs := append([]*MOTD(nil), server.ActiveMotDs...)
+	server.Mutex.Unlock()
+
 	// First, look for and display the welcome message
-	for _, motd := range server.ActiveMotDs {
+	for _, motd := range activeMotDs {
 		if motd != nil && motd.MotdID == defaultMOTDID {
 			player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", motd.Message)
 			welcomeDisplayed = true
@@ -60,7 +118,7 @@ func DisplayUnseenMOTDs(server *Server, player *Player) {
 	}
 
 	// Then display other unseen MOTDs
-	for _, motd := range server.ActiveMotDs {
+	for _, motd := range activeMotDs {
 		if motd == nil || motd.MotdID == defaultMOTDID {
 			continue
 		}
@@ -84,8 +142,121 @@ func DisplayUnseenMOTDs(server *Server, player *Player) {
 	}
 
 	// Save the updated player data
-	err := server.Database.WritePlayer(player)
+	err := server.Database.WritePlayer(server.Context, player)
 	if err != nil {
 		Logger.Error("Error saving player data after displaying MOTDs", "playerName", player.PlayerID, "error", err)
 	}
 }
+
+// MaxMOTDLength limits the length of a message of the day added in game.
+const MaxMOTDLength = 1000
+
+// findMOTD returns the active message whose ID starts with the given text, or an error if none or several match.
+func (s *Server) findMOTD(prefix string) (*MOTD, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var found *MOTD
+	for _, motd := range s.ActiveMotDs {
+		if strings.HasPrefix(motd.MotdID.String(), strings.ToLower(prefix)) {
+			if found != nil {
+				return nil, fmt.Errorf("%s matches more than one message", prefix)
+			}
+			found = motd
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("there is no active message %s", prefix)
+	}
+	return found, nil
+}
+
+func ExecuteMOTDCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is managing MOTDs", "playerName", character.Player.PlayerID)
+
+	usage := "\n\rUsage: motd list | motd add <message> | motd deactivate <id>\n\r"
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- usage
+		return false
+	}
+
+	server := character.Server
+
+	switch strings.ToLower(tokens[1]) {
+	case "list":
+		server.Mutex.Lock()
+		var output strings.Builder
+		output.WriteString("\n\rActive messages of the day:\n\r")
+		for _, motd := range server.ActiveMotDs {
+			output.WriteString(fmt.Sprintf("%s  %s  %s\n\r", motd.MotdID.String()[:8], motd.CreatedAt.Format("2006-01-02"), motd.Message))
+		}
+		if len(server.ActiveMotDs) == 0 {
+			output.WriteString("There are none.\n\r")
+		}
+		server.Mutex.Unlock()
+		character.Player.ToPlayer <- output.String()
+
+	case "add":
+		message, err := SanitizeText(strings.Join(tokens[2:], " "), MaxMOTDLength)
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid message: %v\n\r", err)
+			return false
+		}
+
+		motd := &MOTD{
+			MotdID:    uuid.New(),
+			Active:    true,
+			Message:   message,
+			CreatedAt: time.Now(),
+		}
+		if err := server.Database.WriteMOTD(server.Context, motd); err != nil {
+			Logger.Error("Error saving MOTD", "motdID", motd.MotdID, "error", err)
+			character.Player.ToPlayer <- "\n\rThe message could not be saved.\n\r"
+			return false
+		}
+
+		server.Mutex.Lock()
+		server.ActiveMotDs = append(server.ActiveMotDs, motd)
+		server.Mutex.Unlock()
+
+		Logger.Info("Admin added MOTD", "playerName", character.Player.PlayerID, "motdID", motd.MotdID)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rAdded message %s. Players will see it when they next log in.\n\r", motd.MotdID.String()[:8])
+
+	case "deactivate":
+		if len(tokens) != 3 {
+			character.Player.ToPlayer <- usage
+			return false
+		}
+		motd, err := server.findMOTD(tokens[2])
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+			return false
+		}
+
+		inactive := *motd
+		inactive.Active = false
+		if err := server.Database.WriteMOTD(server.Context, &inactive); err != nil {
+			Logger.Error("Error deactivating MOTD", "motdID", motd.MotdID, "error", err)
+			character.Player.ToPlayer <- "\n\rThe message could not be deactivated.\n\r"
+			return false
+		}
+
+		server.Mutex.Lock()
+		for i, active := range server.ActiveMotDs {
+			if active == motd {
+				server.ActiveMotDs = append(server.ActiveMotDs[:i:i], server.ActiveMotDs[i+1:]...)
+				break
+			}
+		}
+		server.Mutex.Unlock()
+
+		Logger.Info("Admin deactivated MOTD", "playerName", character.Player.PlayerID, "motdID", motd.MotdID)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rMessage %s is no longer shown.\n\r", motd.MotdID.String()[:8])
+
+	default:
+		character.Player.ToPlayer <- usage
+	}
+
+	return false
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// Character names are counted in letters, not bytes, so names in any alphabet get the same room.
+const (
+	MinNameLength = 3
+	MaxNameLength = 15
+)
+
+// minObscenitySubstring is the shortest obscenity that is also rejected inside longer names.
+// Shorter words only match exactly, as they turn up inside too many innocent names.
+const minObscenitySubstring = 4
+
+// reservedWords cannot be used as character names, since players would read them as staff or
+// as the game itself.
+var reservedWords = map[string]bool{
+	"admin": true, "administrator": true, "moderator": true, "staff": true, "system": true,
+	"server": true, "god": true, "wizard": true, "immortal": true, "everyone": true,
+	"someone": true, "somebody": true, "nobody": true, "anyone": true, "self": true,
+	"all": true, "you": true, "new": true,
+}
+
+// nameScripts are the alphabets a name may be written in. A name must keep to one of them, so
+// look-alike letters from another alphabet cannot be used to copy an existing name.
+var nameScripts = []*unicode.RangeTable{
+	unicode.Latin, unicode.Greek, unicode.Cyrillic, unicode.Armenian, unicode.Georgian,
+	unicode.Hebrew, unicode.Arabic, unicode.Hangul, unicode.Han, unicode.Hiragana, unicode.Katakana,
+}
+
+// NameError is returned when a character name is rejected. Its message is shown to the player.
+type NameError struct {
+	Reason string
+}
+
+func (e *NameError) Error() string {
+	return e.Reason
+}
+
+// NormalizeName trims a character name and capitalizes it, with the first letter of the name and
+// of each hyphenated part in upper case and the rest in lower case.
+func NormalizeName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == '-' {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+	}
+	return string(runes)
+}
+
+// ValidateCharacterName checks a proposed character name and returns it normalized. A rejected
+// name returns a *NameError explaining what to change. Whether the name is already taken is
+// checked separately.
+func (s *Server) ValidateCharacterName(name string) (string, error) {
+	name = NormalizeName(name)
+	if name == "" {
+		return "", &NameError{"Character name cannot be empty."}
+	}
+
+	letters := 0
+	var script *unicode.RangeTable
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '\'':
+			// A hyphen or apostrophe may only join two letters
+			if i == 0 || i == len(runes)-1 || !unicode.IsLetter(runes[i-1]) || !unicode.IsLetter(runes[i+1]) {
+				return "", &NameError{"A hyphen or apostrophe must sit between two letters."}
+			}
+		case unicode.IsLetter(r):
+			letters++
+			letterScript := scriptOf(r)
+			if letterScript == nil {
+				return "", &NameError{fmt.Sprintf("The letter %q cannot be used in character names.", r)}
+			}
+			if script != nil && letterScript != script {
+				return "", &NameError{"Character names must be written in a single alphabet."}
+			}
+			script = letterScript
+		case unicode.IsSpace(r):
+			return "", &NameError{"Character names must be a single word without spaces."}
+		case unicode.IsDigit(r):
+			return "", &NameError{"Character names cannot contain numbers."}
+		default:
+			return "", &NameError{fmt.Sprintf("Character names may only contain letters, hyphens and apostrophes, not %q.", r)}
+		}
+	}
+
+	if letters < MinNameLength || letters > MaxNameLength {
+		return "", &NameError{fmt.Sprintf("Character names must be between %d and %d letters long.", MinNameLength, MaxNameLength)}
+	}
+	if strings.Count(name, "-")+strings.Count(name, "'") > 1 {
+		return "", &NameError{"Character names may contain only one hyphen or apostrophe."}
+	}
+
+	lower := strings.ToLower(name)
+	if reservedWords[lower] || s.IsReservedName(lower) || s.isNPCName(lower) {
+		return "", &NameError{"That name is reserved. Please choose another name."}
+	}
+	if s.containsObscenity(lower) {
+		return "", &NameError{"That name contains a word that is not allowed. Please choose another name."}
+	}
+
+	return name, nil
+}
+
+// scriptOf returns the alphabet of one of the nameScripts the letter belongs to, or nil.
+func scriptOf(r rune) *unicode.RangeTable {
+	for _, script := range nameScripts {
+		if unicode.Is(script, r) {
+			return script
+		}
+	}
+	return nil
+}
+
+// isNPCName reports whether the lower case name is the name of an NPC template.
+func (s *Server) isNPCName(lower string) bool {
+	for _, template := range s.NPCTemplates {
+		if strings.ToLower(template.Name) == lower {
+			return true
+		}
+	}
+	return false
+}
+
+// containsObscenity checks the lower case name against the obscenity list, ignoring hyphens and
+// apostrophes. Longer obscenities are also found inside the name.
+func (s *Server) containsObscenity(lower string) bool {
+	joined := strings.NewReplacer("-", "", "'", "").Replace(lower)
+	if s.IsObscenity(joined) {
+		return true
+	}
+	for word := range s.Obscenities {
+		if len([]rune(word)) >= minObscenitySubstring && strings.Contains(joined, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNameTaken is returned when a character name is claimed by another character.
+var ErrNameTaken = errors.New("character name taken")
+
+// CharacterNameRecord is the entry in the character_names table that claims a name for one
+// character. The table is the authority on which names are in use; the bloom filter only saves
+// looking most names up.
+type CharacterNameRecord struct {
+	NameKey     string `json:"NameKey" dynamodbav:"NameKey"` // lower case name
+	Name        string `json:"Name" dynamodbav:"Name"`
+	CharacterID string `json:"CharacterID" dynamodbav:"CharacterID"`
+	PlayerID    string `json:"PlayerID" dynamodbav:"PlayerID"`
+	Version     int64  `json:"Version" dynamodbav:"Version"`
+}
+
+func characterNameKey(name string) map[string]ddbtypes.AttributeValue {
+	return map[string]ddbtypes.AttributeValue{
+		"NameKey": &ddbtypes.AttributeValueMemberS{Value: strings.ToLower(name)},
+	}
+}
+
+// LookupCharacterName returns the claim on a character name, ignoring case. An unclaimed name
+// returns an error wrapping ErrItemNotFound.
+func (kp *KeyPair) LookupCharacterName(ctx context.Context, name string) (*CharacterNameRecord, error) {
+	var record CharacterNameRecord
+	if err := kp.Get(ctx, "character_names", characterNameKey(name), &record); err != nil {
+		return nil, fmt.Errorf("error looking up character name %s: %w", name, err)
+	}
+	return &record, nil
+}
+
+// ClaimCharacterName records the name as belonging to the character. The write only succeeds
+// when no other character holds the name, so two players choosing the same name at once cannot
+// both have it; the loser gets ErrNameTaken.
+func (kp *KeyPair) ClaimCharacterName(ctx context.Context, name string, characterID uuid.UUID, playerID string) error {
+	record := CharacterNameRecord{
+		NameKey:     strings.ToLower(name),
+		Name:        name,
+		CharacterID: characterID.String(),
+		PlayerID:    playerID,
+		Version:     1,
+	}
+
+	err := kp.PutVersioned(ctx, "character_names", record)
+	if errors.Is(err, ErrVersionConflict) {
+		return fmt.Errorf("%w: %s", ErrNameTaken, name)
+	}
+	if err != nil {
+		return fmt.Errorf("error claiming character name %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReleaseCharacterName frees a name so that a new character can take it.
+func (kp *KeyPair) ReleaseCharacterName(ctx context.Context, name string) error {
+	if err := kp.Delete(ctx, "character_names", characterNameKey(name)); err != nil {
+		return fmt.Errorf("error releasing character name %s: %w", name, err)
+	}
+	return nil
+}
+
+// SyncCharacterNames brings the name index in line with the characters table and returns the
+// lower case names of every stored character. Characters saved before the index existed are
+// added to it, and claims left by characters that were never saved or were since deleted are
+// released.
+func (kp *KeyPair) SyncCharacterNames(ctx context.Context) (map[string]bool, error) {
+	var characters []struct {
+		CharacterID   string `dynamodbav:"CharacterID"`
+		PlayerID      string `dynamodbav:"PlayerID"`
+		CharacterName string `dynamodbav:"Name"`
+	}
+	if err := kp.Scan(ctx, "characters", &characters); err != nil {
+		Logger.Error("Error scanning characters table", "error", err)
+		return nil, fmt.Errorf("error scanning characters: %w", err)
+	}
+
+	var records []CharacterNameRecord
+	if err := kp.Scan(ctx, "character_names", &records); err != nil {
+		return nil, fmt.Errorf("error scanning character names: %w", err)
+	}
+	claims := make(map[string]CharacterNameRecord, len(records))
+	for _, record := range records {
+		claims[record.NameKey] = record
+	}
+
+	names := make(map[string]bool, len(characters))
+	characterIDs := make(map[string]bool, len(characters))
+	var missing []interface{}
+	for _, character := range characters {
+		name := strings.ToLower(character.CharacterName)
+		names[name] = true
+		characterIDs[character.CharacterID] = true
+
+		claim, ok := claims[name]
+		if !ok {
+			record := CharacterNameRecord{
+				NameKey:     name,
+				Name:        character.CharacterName,
+				CharacterID: character.CharacterID,
+				PlayerID:    character.PlayerID,
+				Version:     1,
+			}
+			claims[name] = record
+			missing = append(missing, record)
+		} else if claim.CharacterID != character.CharacterID {
+			Logger.Warn("Character name is shared by more than one character", "characterName", character.CharacterName, "characterID", character.CharacterID, "claimedBy", claim.CharacterID)
+		}
+	}
+
+	if len(missing) > 0 {
+		if err := kp.BatchPut(ctx, "character_names", missing); err != nil {
+			return nil, fmt.Errorf("error adding character names to index: %w", err)
+		}
+		Logger.Info("Added character names to index", "count", len(missing))
+	}
+
+	for _, record := range records {
+		if characterIDs[record.CharacterID] {
+			continue
+		}
+		if err := kp.ReleaseCharacterName(ctx, record.Name); err != nil {
+			return nil, err
+		}
+		Logger.Info("Released character name with no character", "characterName", record.Name, "characterID", record.CharacterID)
+	}
+
+	return names, nil
+}
+
+// CharacterNameTaken reports whether a stored character already has the name, ignoring case.
+// The bloom filter rules out most names without a database read; a hit is confirmed against
+// the name index, so a false positive never blocks a name.
+func (s *Server) CharacterNameTaken(name string) (bool, error) {
+	if !s.CharacterNameExists(name) {
+		return false, nil
+	}
+
+	_, err := s.Database.LookupCharacterName(s.Context, name)
+	if errors.Is(err, ErrItemNotFound) {
+		Logger.Info("Bloom filter false positive for character name", "characterName", name)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultNPCTickSeconds is the time between NPC actions in real seconds when not configured.
+const DefaultNPCTickSeconds = 10
+
+// Chances, per tick, that an NPC emotes or wanders to a neighbouring room.
+const (
+	NPCEmoteChance  = 0.2
+	NPCWanderChance = 0.1
+)
+
+// LoadNPCTemplates retrieves all NPC definitions from the DynamoDB table.
+func (s *Server) LoadNPCTemplates() error {
+	var templates []NPCData
+	err := s.Database.Scan(s.Context, "npcs", &templates)
+	if err != nil {
+		return fmt.Errorf("error scanning npcs table: %w", err)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.NPCTemplates = make(map[string]*NPCData, len(templates))
+	for _, template := range templates {
+		// Create a copy of the template to store in the map
+		templateCopy := template
+		s.NPCTemplates[template.NPCID] = &templateCopy
+		Logger.Debug("Loaded NPC template", "npcID", template.NPCID, "name", template.Name)
+	}
+
+	return nil
+}
+
+// NewNPC creates an NPC instance from its definition.
+func NewNPC(template *NPCData) *NPC {
+	return &NPC{
+		ID:          uuid.New(),
+		TemplateID:  template.NPCID,
+		Name:        template.Name,
+		Description: template.Description,
+		Emotes:      template.Emotes,
+		Wanders:     template.Wanders,
+		Sells:       template.Sells,
+	}
+}
+
+// SpawnNPCs populates every room with the NPCs listed in its spawn definitions.
+func (s *Server) SpawnNPCs() {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if s.NPCs == nil {
+		s.NPCs = make(map[uuid.UUID]*NPC)
+	}
+
+	for _, room := range s.Rooms {
+		for _, templateID := range room.Spawns {
+			template, exists := s.NPCTemplates[templateID]
+			if !exists {
+				Logger.Warn("NPC template not found for room spawn", "room_id", room.RoomID, "npcID", templateID)
+				continue
+			}
+
+			npc := placeNPC(room, template)
+			s.NPCs[npc.ID] = npc
+			Logger.Info("Spawned NPC", "name", npc.Name, "npcID", templateID, "room_id", room.RoomID)
+		}
+	}
+}
+
+// NPCLoop runs the NPC tick until the server context is cancelled.
+func NPCLoop(s *Server) {
+	tickSeconds := time.Duration(s.Settings().Game.NPCTickSeconds)
+	if tickSeconds == 0 {
+		tickSeconds = DefaultNPCTickSeconds
+	}
+
+	Logger.Info("Starting NPC loop", "tickSeconds", int(tickSeconds))
+
+	ticker := time.NewTicker(tickSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.TickNPCs()
+		case <-s.Context.Done():
+			Logger.Info("Stopping NPC loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// TickNPCs gives every NPC a chance to emote or wander.
+func (s *Server) TickNPCs() {
+	s.Mutex.Lock()
+	npcs := make([]*NPC, 0, len(s.NPCs))
+	for _, npc := range s.NPCs {
+		npcs = append(npcs, npc)
+	}
+	s.Mutex.Unlock()
+
+	for _, npc := range npcs {
+		if npc.Wanders && rand.Float64() < NPCWanderChance {
+			npc.Wander(s)
+			continue
+		}
+		if len(npc.Emotes) > 0 && rand.Float64() < NPCEmoteChance {
+			npc.Emote()
+		}
+	}
+}
+
+// Emote sends one of the NPC's emotes, chosen at random, to its room.
+func (n *NPC) Emote() {
+	n.Mutex.Lock()
+	room := n.Room
+	n.Mutex.Unlock()
+
+	if room == nil || len(n.Emotes) == 0 {
+		return
+	}
+
+	emote := n.Emotes[rand.Intn(len(n.Emotes))]
+	SendRoomMessage(room, fmt.Sprintf("\n\r%s %s\n\r", n.Name, emote))
+}
+
+// Wander moves the NPC through a random visible exit that stays within its home area
+// and whose day phase or weather condition currently holds.
+func (n *NPC) Wander(s *Server) {
+	n.Mutex.Lock()
+	defer n.Mutex.Unlock()
+
+	oldRoom := n.Room
+	if oldRoom == nil {
+		return
+	}
+
+	oldRoom.Mutex.Lock()
+	exits := make([]*Exit, 0, len(oldRoom.Exits))
+	for _, exit := range oldRoom.Exits {
+		if exit.Visible && !exit.Closed && exit.TargetRoom != nil && exit.TargetRoom.Area == n.HomeArea && s.ConditionMet(oldRoom, exit.Condition) {
+			exits = append(exits, exit)
+		}
+	}
+	oldRoom.Mutex.Unlock()
+
+	if len(exits) == 0 {
+		return
+	}
+
+	exit := exits[rand.Intn(len(exits))]
+	direction := exit.Direction
+	newRoom := exit.TargetRoom
+
+	oldRoom.Mutex.Lock()
+	delete(oldRoom.NPCs, n.ID)
+	oldRoom.Mutex.Unlock()
+	SendRoomMessage(oldRoom, fmt.Sprintf("\n\r%s has left going %s.\n\r", n.Name, direction))
+
+	n.Room = newRoom
+
+	newRoom.Mutex.Lock()
+	if newRoom.NPCs == nil {
+		newRoom.NPCs = make(map[uuid.UUID]*NPC)
+	}
+	newRoom.NPCs[n.ID] = n
+	newRoom.Mutex.Unlock()
+	SendRoomMessage(newRoom, fmt.Sprintf("\n\r%s has arrived.\n\r", n.Name))
+
+	Logger.Debug("NPC wandered", "name", n.Name, "direction", direction, "room_id", newRoom.RoomID)
+}
+
+// FindNPC returns the NPC in the room with the given name, ignoring case.
+func (r *Room) FindNPC(name string) *NPC {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	for _, npc := range r.NPCs {
+		if strings.EqualFold(npc.Name, name) {
+			return npc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DirtySection flags the parts of a character that changed since it was last saved.
+type DirtySection uint8
+
+const (
+	DirtyStats     DirtySection = 1 << iota // health, essence, traits, experience, effects, quests, description and title
+	DirtyInventory                          // carried items and coins
+	DirtyLocation                           // current room and explored rooms
+)
+
+// DirtyAll marks every section of a character for saving.
+const DirtyAll = DirtyStats | DirtyInventory | DirtyLocation
+
+// characterSections lists the CharacterData attributes written for each dirty section.
+var characterSections = []struct {
+	Section    DirtySection
+	Attributes []string
+}{
+	{DirtyStats, []string{"Attributes", "Abilities", "Essence", "Health", "MaxEssence", "MaxHealth", "Effects",
+		"Experience", "Level", "TrainingPoints", "Quests", "CompletedQuests", "Description", "Title"}},
+	{DirtyInventory, []string{"Inventory", "Coins"}},
+	{DirtyLocation, []string{"RoomID", "Explored"}},
+}
+
+// markDirty records that the given sections of the character changed. The caller must hold c.Mutex.
+func (c *Character) markDirty(sections DirtySection) {
+	c.Dirty |= sections
+	c.LastEdited = time.Now()
+	if sections&(DirtyStats|DirtyLocation) != 0 {
+		c.refreshPrompt()
+	}
+}
+
+// UpdateCharacter writes only the dirty sections of the character to the database, leaving the
+// rest of the stored record untouched. The caller must hold character.Mutex.
+func (kp *KeyPair) UpdateCharacter(ctx context.Context, character *Character) error {
+	if character.Dirty == 0 {
+		return nil
+	}
+
+	av, err := attributevalue.MarshalMap(character.ToData())
+	if err != nil {
+		return fmt.Errorf("error marshalling character data: %w", err)
+	}
+
+	set := make(map[string]ddbtypes.AttributeValue)
+	var remove []string
+	for _, section := range characterSections {
+		if character.Dirty&section.Section == 0 {
+			continue
+		}
+		for _, attribute := range section.Attributes {
+			// Empty fields tagged omitempty are not marshalled, so they are removed from the record
+			if value, ok := av[attribute]; ok {
+				set[attribute] = value
+			} else {
+				remove = append(remove, attribute)
+			}
+		}
+	}
+
+	key := map[string]ddbtypes.AttributeValue{
+		"CharacterID": &ddbtypes.AttributeValueMemberS{Value: character.ID.String()},
+	}
+
+	version := character.Version + 1
+	err = kp.writeVersioned(ctx, "characters", key, &version, func() error {
+		set["Version"] = &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)}
+		set[OwnerAttribute] = kp.ownerValue()
+		return kp.Update(ctx, "characters", key, set, remove)
+	})
+	if err != nil {
+		Logger.Error("Error updating character data", "characterName", character.Name, "error", err)
+		return fmt.Errorf("error updating character data: %w", err)
+	}
+
+	Logger.Info("Successfully updated character in database", "characterName", character.Name, "sections", character.Dirty, "version", version)
+
+	character.Dirty = 0
+	character.Version = version
+	character.LastSaved = time.Now()
+
+	return nil
+}
+
+// SaveCharacter locks the character and writes its dirty sections to the database.
+func (s *Server) SaveCharacter(character *Character) error {
+	character.Mutex.Lock()
+	defer character.Mutex.Unlock()
+
+	return s.Database.UpdateCharacter(s.Context, character)
+}
+
+// maxVersionRetries is how many times a write that keeps hitting version conflicts is retried.
+const maxVersionRetries = 3
+
+// OwnerAttribute names the attribute of a versioned record holding the Instance of the KeyPair
+// that last wrote it.
+const OwnerAttribute = "Owner"
+
+// ErrForeignWrite is returned, wrapping ErrVersionConflict, when a record changed since it was
+// read by a writer other than this server, such as another server instance or an admin tool.
+var ErrForeignWrite = fmt.Errorf("%w: record was changed by another writer", ErrVersionConflict)
+
+// versionedWrite is one record written by batchPutVersioned.
+type versionedWrite struct {
+	Key     map[string]ddbtypes.AttributeValue
+	Data    interface{}
+	Version *int64 // the Version field of Data, holding the version last read or written
+	Written bool   // set once the record is stored
+}
+
+// storedOwnership is the part of a stored record read to resolve a version conflict.
+type storedOwnership struct {
+	Version int64  `dynamodbav:"Version"`
+	Owner   string `dynamodbav:"Owner"`
+}
+
+// ownerValue returns the Owner attribute written with every versioned record.
+func (kp *KeyPair) ownerValue() ddbtypes.AttributeValue {
+	return &ddbtypes.AttributeValueMemberS{Value: kp.Instance}
+}
+
+// resolveConflict re-reads a record whose versioned write failed. When this server wrote the
+// stored version itself, for instance a single save that overtook a batch already in flight, the
+// copy in memory is the newer one and *version is moved past the stored version so it can be
+// written again. A record changed by anyone else is left alone and ErrForeignWrite returned, so
+// their change is never lost; the conflict is logged for an operator to resolve.
+func (kp *KeyPair) resolveConflict(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, version *int64) error {
+	var stored storedOwnership
+	err := kp.Get(ctx, tableName, key, &stored)
+	if errors.Is(err, ErrItemNotFound) {
+		// Deleted since it was read, so there is nothing of anyone else's to overwrite
+		stored = storedOwnership{Owner: kp.Instance}
+	} else if err != nil {
+		return fmt.Errorf("error reading conflicting record: %w", err)
+	}
+
+	if stored.Owner != kp.Instance {
+		DatabaseLog.Error("Version conflict with a record changed by another writer, not overwriting it",
+			"tableName", tableName, "key", keyString(key), "expected", *version-1, "stored", stored.Version, "owner", stored.Owner, "instance", kp.Instance)
+		return fmt.Errorf("%w in table %s", ErrForeignWrite, tableName)
+	}
+
+	DatabaseLog.Warn("Version conflict with this server's own write, rewriting over it",
+		"tableName", tableName, "key", keyString(key), "expected", *version-1, "stored", stored.Version)
+	*version = stored.Version + 1
+	return nil
+}
+
+// keyString formats a record key for the log.
+func keyString(key map[string]ddbtypes.AttributeValue) string {
+	parts := make([]string, 0, len(key))
+	for name, value := range key {
+		switch v := value.(type) {
+		case *ddbtypes.AttributeValueMemberS:
+			parts = append(parts, name+"="+v.Value)
+		case *ddbtypes.AttributeValueMemberN:
+			parts = append(parts, name+"="+v.Value)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// writeVersioned runs write, a conditional write of version *version of a record. A stale write
+// is retried only when resolveConflict finds the stored version was written by this server.
+func (kp *KeyPair) writeVersioned(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, version *int64, write func() error) error {
+	err := write()
+	for attempt := 0; errors.Is(err, ErrVersionConflict) && attempt < maxVersionRetries; attempt++ {
+		if err = kp.resolveConflict(ctx, tableName, key, version); err != nil {
+			return err
+		}
+		err = write()
+	}
+	return err
+}
+
+// ownedRecord marshals data, a versioned record, and stamps it with this server as its owner.
+func (kp *KeyPair) ownedRecord(data interface{}) (map[string]ddbtypes.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling item: %w", err)
+	}
+	av[OwnerAttribute] = kp.ownerValue()
+	return av, nil
+}
+
+// putVersioned stores data as the next version of its record. version points at the Version
+// field of data and holds the stored version once the write succeeds.
+func (kp *KeyPair) putVersioned(ctx context.Context, tableName string, key map[string]ddbtypes.AttributeValue, data interface{}, version *int64) error {
+	*version++
+	return kp.writeVersioned(ctx, tableName, key, version, func() error {
+		record, err := kp.ownedRecord(data)
+		if err != nil {
+			return err
+		}
+		return kp.PutVersioned(ctx, tableName, record)
+	})
+}
+
+// batchPutVersioned stores each record as its next version, then resolves any version conflicts
+// one record at a time. Records that could not be stored, including those another writer changed,
+// are left with Written unset so they stay edited in memory.
+func (kp *KeyPair) batchPutVersioned(ctx context.Context, tableName string, writes []versionedWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+
+	items := make([]interface{}, len(writes))
+	for i := range writes {
+		*writes[i].Version++
+		record, err := kp.ownedRecord(writes[i].Data)
+		if err != nil {
+			return err
+		}
+		items[i] = record
+	}
+
+	conflicts, err := kp.BatchPutVersioned(ctx, tableName, items)
+	if err != nil {
+		return err
+	}
+
+	conflicted := make(map[int]bool, len(conflicts))
+	for _, i := range conflicts {
+		conflicted[i] = true
+	}
+	for i := range writes {
+		writes[i].Written = !conflicted[i]
+	}
+
+	var refused, failed int
+	for _, i := range conflicts {
+		write := &writes[i]
+		err := kp.resolveConflict(ctx, tableName, write.Key, write.Version)
+		if err == nil {
+			err = kp.writeVersioned(ctx, tableName, write.Key, write.Version, func() error {
+				record, err := kp.ownedRecord(write.Data)
+				if err != nil {
+					return err
+				}
+				return kp.PutVersioned(ctx, tableName, record)
+			})
+		}
+		if errors.Is(err, ErrForeignWrite) {
+			refused++
+			continue
+		}
+		if err != nil {
+			Logger.Error("Error resolving version conflict", "tableName", tableName, "error", err)
+			failed++
+			continue
+		}
+		write.Written = true
+	}
+
+	if refused > 0 || failed > 0 {
+		return fmt.Errorf("%d records in table %s were changed by another writer and %d could not be written", refused, tableName, failed)
+	}
+	return nil
+}
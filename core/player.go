@@ -2,24 +2,46 @@ package core
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 )
 
-// WritePlayer stores the player data into the DynamoDB database.
-func (k *KeyPair) WritePlayer(player *Player) error {
+// WritePlayer stores the player data into the DynamoDB database. The fields are copied under
+// player.Mutex, so the caller must not hold it.
+func (k *KeyPair) WritePlayer(ctx context.Context, player *Player) error {
+	player.Mutex.Lock()
 	pd := PlayerData{
-		PlayerID:      player.PlayerID,
-		CharacterList: make(map[string]string),
-		SeenMotDs:     make([]string, len(player.SeenMotD)),
+		PlayerID:        player.PlayerID,
+		CharacterList:   make(map[string]string),
+		SeenMotDs:       make([]string, len(player.SeenMotD)),
+		Channels:        make([]string, 0, len(player.Channels)),
+		OfferedChannels: make([]string, 0, len(player.OfferedChannels)),
+		Muted:           make([]string, 0, len(player.Muted)),
+		Ignored:         make([]string, 0, len(player.Ignored)),
+		Friends:         make([]string, 0, len(player.Friends)),
+		EchoOff:         !player.Echo.Load(),
+		Role:            player.Role,
+		Aliases:         make(map[string]string, len(player.Aliases)),
+		ColorOff:        !player.Color.Load(),
+		ColorTheme:      player.ColorTheme,
+		ShowProfanity:   player.ShowProfanity,
+		SSHKeys:         append([]string(nil), player.SSHKeys...),
+	}
+
+	// Only a customised prompt is stored
+	if player.Prompt != DefaultPrompt {
+		pd.Prompt = player.Prompt
 	}
 
 	// Convert UUIDs to strings for CharacterList
@@ -32,30 +54,64 @@ func (k *KeyPair) WritePlayer(player *Player) error {
 		pd.SeenMotDs[i] = motdID.String()
 	}
 
+	// Convert the channel set to a sorted list
+	for channel := range player.Channels {
+		pd.Channels = append(pd.Channels, channel)
+	}
+	sort.Strings(pd.Channels)
+	for channel := range player.OfferedChannels {
+		pd.OfferedChannels = append(pd.OfferedChannels, channel)
+	}
+	sort.Strings(pd.OfferedChannels)
+
+	// Convert the muted character set to a sorted list
+	for name := range player.Muted {
+		pd.Muted = append(pd.Muted, name)
+	}
+	sort.Strings(pd.Muted)
+
+	// Convert the ignored character set to a sorted list
+	for name := range player.Ignored {
+		pd.Ignored = append(pd.Ignored, name)
+	}
+	sort.Strings(pd.Ignored)
+
+	// Convert the friend set to a sorted list
+	for name := range player.Friends {
+		pd.Friends = append(pd.Friends, name)
+	}
+	sort.Strings(pd.Friends)
+
+	for name, command := range player.Aliases {
+		pd.Aliases[name] = command
+	}
+	player.Mutex.Unlock()
+
 	// Write the player data to the DynamoDB table with proper error handling
-	err := k.Put("players", pd)
+	err := k.Put(ctx, "players", pd)
 	if err != nil {
 		Logger.Error("Error storing player data", "playerName", player.PlayerID, "error", err)
 		return fmt.Errorf("error storing player data: %w", err)
 	}
 
-	Logger.Info("Successfully wrote player data", "playerName", player.PlayerID, "characterCount", len(player.CharacterList), "seenMotDCount", len(player.SeenMotD))
+	Logger.Info("Successfully wrote player data", "playerName", player.PlayerID, "characterCount", len(pd.CharacterList), "seenMotDCount", len(pd.SeenMotDs))
 	return nil
 }
 
 // ReadPlayer retrieves the player data from the DynamoDB database.
-func (k *KeyPair) ReadPlayer(playerName string) (string, map[string]uuid.UUID, []uuid.UUID, error) {
-	key := map[string]*dynamodb.AttributeValue{
-		"PlayerID": {S: aws.String(playerName)},
+// The returned Player only has its persisted fields populated.
+func (k *KeyPair) ReadPlayer(ctx context.Context, playerName string) (*Player, error) {
+	key := map[string]ddbtypes.AttributeValue{
+		"PlayerID": &ddbtypes.AttributeValueMemberS{Value: playerName},
 	}
 
 	var pd PlayerData
 
 	// Read the player data from the DynamoDB table with proper error handling
-	err := k.Get("players", key, &pd)
+	err := k.Get(ctx, "players", key, &pd)
 	if err != nil {
 		Logger.Error("Error reading player data", "playerName", playerName, "error", err)
-		return "", nil, nil, fmt.Errorf("player not found")
+		return nil, fmt.Errorf("player not found")
 	}
 
 	// Convert character IDs from strings to UUIDs
@@ -80,89 +136,329 @@ func (k *KeyPair) ReadPlayer(playerName string) (string, map[string]uuid.UUID, [
 		seenMotDs = append(seenMotDs, id)
 	}
 
+	// Convert subscribed channels to a set
+	channels := make(map[string]bool, len(pd.Channels))
+	for _, channel := range pd.Channels {
+		channels[channel] = true
+	}
+
+	offered := make(map[string]bool, len(pd.OfferedChannels))
+	for _, channel := range pd.OfferedChannels {
+		offered[channel] = true
+	}
+
+	// Convert muted character names to a set
+	muted := make(map[string]bool, len(pd.Muted))
+	for _, name := range pd.Muted {
+		muted[name] = true
+	}
+
+	// Convert ignored character names to a set
+	ignored := make(map[string]bool, len(pd.Ignored))
+	for _, name := range pd.Ignored {
+		ignored[name] = true
+	}
+
+	// Convert friend names to a set
+	friends := make(map[string]bool, len(pd.Friends))
+	for _, name := range pd.Friends {
+		friends[name] = true
+	}
+
 	Logger.Info("Successfully read player data", "playerName", pd.PlayerID, "characterCount", len(characterList), "seenMotDCount", len(seenMotDs))
-	return pd.PlayerID, characterList, seenMotDs, nil
+	player := &Player{
+		PlayerID:        pd.PlayerID,
+		CharacterList:   characterList,
+		SeenMotD:        seenMotDs,
+		Channels:        channels,
+		OfferedChannels: offered,
+		Muted:           muted,
+		Ignored:         ignored,
+		Friends:         friends,
+		Role:            pd.Role,
+		Aliases:         pd.Aliases,
+		Prompt:          pd.Prompt,
+		ColorTheme:      pd.ColorTheme,
+		ShowProfanity:   pd.ShowProfanity,
+		SSHKeys:         pd.SSHKeys,
+	}
+	player.Echo.Store(!pd.EchoOff)
+	player.Color.Store(!pd.ColorOff)
+
+	if player.Prompt == "" {
+		player.Prompt = DefaultPrompt
+	}
+
+	return player, nil
+}
+
+// IsActive reports whether the character has a connected player that can still receive messages.
+func (c *Character) IsActive() bool {
+	return c != nil && c.Player != nil && c.Player.ToPlayer != nil && !c.Player.Closed.Load()
+}
+
+// Send delivers a message to the player, skipping players whose session is tearing down.
+// It returns false if the message could not be delivered.
+func (p *Player) Send(message string) (sent bool) {
+	if p == nil || p.ToPlayer == nil || p.Closed.Load() {
+		return false
+	}
+
+	// The output channel may be closed between the check above and the send
+	defer func() {
+		if r := recover(); r != nil {
+			NetworkLog.Warn("Dropped message to disconnected player", "playerName", p.PlayerID, "error", r)
+			sent = false
+		}
+	}()
+
+	p.ToPlayer <- message
+	return true
+}
+
+// IdleTime returns how long it has been since the player last sent a line of input.
+func (p *Player) IdleTime() time.Duration {
+	last := p.LastInput.Load()
+	if last == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, last))
+}
+
+// CloseOutput marks the player as disconnected and closes their output channel.
+func (p *Player) CloseOutput() {
+	if p.Closed.Swap(true) {
+		return
+	}
+	close(p.ToPlayer)
+}
+
+// reportError passes an input error to anyone watching PlayerError without blocking
+// the input goroutine when nothing is.
+func (p *Player) reportError(err error) {
+	select {
+	case p.PlayerError <- err:
+	default:
+	}
 }
 
 // PlayerInput handles the player's input in a separate goroutine.
 // It reads input from the player's SSH connection and sends it to the FromPlayer channel.
 func PlayerInput(p *Player) {
-	Logger.Info("Player input goroutine started", "playerName", p.PlayerID)
+	NetworkLog.Info("Player input goroutine started", "playerName", p.PlayerID)
 
 	var inputBuffer []rune
+	var previous rune
 	reader := bufio.NewReader(p.Connection)
 
 	defer func() {
 		close(p.FromPlayer)
-		Logger.Info("Player input goroutine ended", "playerName", p.PlayerID)
+		NetworkLog.Info("Player input goroutine ended", "playerName", p.PlayerID)
 	}()
 
+	// Lines dropped in a row by the rate limiter
+	dropped := 0
+
 	for {
 		r, _, err := reader.ReadRune()
 		if err != nil {
 			if err == io.EOF {
-				Logger.Info("Player disconnected", "playerName", p.PlayerID)
-				p.PlayerError <- err
+				NetworkLog.Info("Player disconnected", "playerName", p.PlayerID)
+				p.reportError(err)
 				return
 			} else {
-				Logger.Error("Error reading from player", "playerName", p.PlayerID, "error", err)
-				p.PlayerError <- err
+				NetworkLog.Error("Error reading from player", "playerName", p.PlayerID, "error", err)
+				p.reportError(err)
 				continue
 			}
 		}
 
 		switch r {
 		case '\n', '\r':
-			if len(inputBuffer) > 0 {
+			if len(inputBuffer) > 0 && !p.Limiter.Allow() {
+				inputBuffer = inputBuffer[:0]
+				dropped++
+				if dropped == 1 {
+					NetworkLog.Warn("Rate limiting player input", "playerName", p.PlayerID)
+					p.Send("\n\rYou are sending commands too quickly. Slow down.\n\r")
+				}
+				if dropped >= p.Server.floodLimit() {
+					NetworkLog.Warn("Disconnecting player for flooding", "playerName", p.PlayerID, "dropped", dropped)
+					p.Send("\n\rYou have been disconnected for flooding.\n\r")
+					p.Connection.Close()
+					return
+				}
+			} else if len(inputBuffer) > 0 {
+				dropped = 0
 				p.FromPlayer <- string(inputBuffer)
 				inputBuffer = inputBuffer[:0]
+			} else if p.Paging.Load() && !(r == '\n' && previous == '\r') {
+				// An empty line moves on to the next page of long output
+				p.FromPlayer <- ""
 			}
-			if p.Echo {
+			if p.Echo.Load() {
 				p.Connection.Write([]byte("\r\n"))
 			}
 		case '\b', 127: // Backspace and Delete
 			if len(inputBuffer) > 0 {
 				inputBuffer = inputBuffer[:len(inputBuffer)-1]
-				if p.Echo {
+				if p.Echo.Load() {
 					p.Connection.Write([]byte("\b \b"))
 				}
 			}
 		case '\x03': // Ctrl+C
-			Logger.Info("Player sent interrupt signal", "playerName", p.PlayerID)
-			p.PlayerError <- errors.New("player interrupt")
+			NetworkLog.Info("Player sent interrupt signal", "playerName", p.PlayerID)
+			p.reportError(errors.New("player interrupt"))
 			p.Connection.Close()
 			return
 		default:
 			if len(inputBuffer) < 1024 { // Max input size
 				inputBuffer = append(inputBuffer, r)
-				if p.Echo {
+				if p.Echo.Load() {
 					p.Connection.Write([]byte(string(r)))
 				}
 			}
 		}
+		previous = r
 	}
 }
 
+// ReadHidden prompts the player and reads one line of input with echo disabled.
+// It must only be called from the goroutine that consumes the player's input.
+func (p *Player) ReadHidden(prompt string) (string, bool) {
+	echo := p.Echo.Swap(false)
+	defer p.Echo.Store(echo)
+
+	p.ToPlayer <- prompt
+	input, ok := <-p.FromPlayer
+	p.ToPlayer <- "\n\r"
+
+	return strings.TrimSpace(input), ok
+}
+
 // PlayerOutput handles sending messages to the player in a separate goroutine.
 // It reads messages from the ToPlayer channel and writes them to the player's SSH connection.
 func PlayerOutput(p *Player) {
-	Logger.Info("Player output goroutine started", "playerName", p.PlayerID)
+	NetworkLog.Info("Player output goroutine started", "playerName", p.PlayerID)
 
 	defer func() {
 		close(p.FromPlayer)
-		Logger.Info("Player output goroutine ended", "playerName", p.PlayerID)
+		NetworkLog.Info("Player output goroutine ended", "playerName", p.PlayerID)
 	}()
 
-	for message := range p.ToPlayer {
-		wrappedMessage := wrapText(message, p.ConsoleWidth)
-		_, err := p.Connection.Write([]byte(wrappedMessage))
+	// Lines of the long output being paged through, and of the messages that arrived while
+	// the player was reading it
+	var paged, queued []string
+
+	for {
+		var err error
+
+		select {
+		case message, ok := <-p.ToPlayer:
+			if !ok {
+				NetworkLog.Info("Message channel closed for player", "playerName", p.PlayerID)
+				return
+			}
+
+			lines := make([]string, 0, 1)
+			for _, line := range strings.SplitAfter(wrapText(message, p.ConsoleWidth), "\r\n") {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+
+			// While the player is reading a page, new output waits behind it
+			if p.Paging.Load() {
+				queued = append(queued, lines...)
+			} else {
+				paged = lines
+				err = p.writePage(&paged)
+			}
+			p.mirrorToSnoopers(message)
+
+		case more := <-p.PageControl:
+			if more {
+				err = p.writePage(&paged)
+			} else {
+				// Stopping only drops the rest of the paged output, not what arrived meanwhile
+				paged = nil
+				p.Paging.Store(false)
+				if len(queued) == 0 {
+					_, err = p.Connection.Write([]byte(p.RenderPrompt()))
+				}
+			}
+		}
+
+		// Once the paged output is finished, the messages held back behind it are shown
+		if err == nil && !p.Paging.Load() && len(queued) > 0 {
+			paged, queued = queued, nil
+			err = p.writePage(&paged)
+		}
 		if err != nil {
-			Logger.Error("Failed to send message to player", "playerName", p.PlayerID, "error", err)
+			NetworkLog.Error("Failed to send message to player", "playerName", p.PlayerID, "error", err)
 			return
 		}
 	}
+}
+
+// MorePrompt is shown after each page of output too long to fit on the player's screen.
+const MorePrompt = "--More-- (Enter to continue, q to stop)"
+
+// writePage writes as many pending lines as fit on the player's screen. When lines remain,
+// it shows the --More-- prompt and leaves the player paging.
+func (p *Player) writePage(pending *[]string) error {
+	lines := *pending
+	pageSize := p.ConsoleHeight - 1
+
+	// Count the rows the lines take up on screen, up to the first line that does not fit
+	fit, rows := 0, 0
+	for ; fit < len(lines); fit++ {
+		rows += renderedRows(lines[fit], p.ConsoleWidth)
+		if rows > pageSize {
+			break
+		}
+	}
+
+	if pageSize < 1 || fit == len(lines) {
+		*pending = nil
+		p.Paging.Store(false)
+		_, err := p.Connection.Write([]byte(strings.Join(lines, "")))
+		return err
+	}
+
+	// A single line taller than the screen is shown on a page of its own
+	if fit == 0 {
+		fit = 1
+	}
+
+	*pending = lines[fit:]
+	p.Paging.Store(true)
+	_, err := p.Connection.Write([]byte(strings.Join(lines[:fit], "") + MorePrompt))
+	return err
+}
+
+// ansiCode matches the color codes written by ApplyColor, which take no space on screen.
+var ansiCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// renderedRows returns how many rows of a screen width columns wide the line fills.
+func renderedRows(line string, width int) int {
+	visible := utf8.RuneCountInString(ansiCode.ReplaceAllString(strings.TrimRight(line, "\r\n"), ""))
+	if width < 1 || visible <= width {
+		return 1
+	}
+	return (visible + width - 1) / width
+}
+
+// ContinuePaging handles a line of input while the player is paging through long output.
+// Entering q discards the rest of the output, anything else shows the next page.
+func (p *Player) ContinuePaging(input string) {
+	more := !strings.EqualFold(strings.TrimSpace(input), "q")
 
-	Logger.Info("Message channel closed for player", "playerName", p.PlayerID)
+	select {
+	case p.PageControl <- more:
+	default:
+		// A page request is already waiting to be handled
+	}
 }
 
 // InputLoop is the main loop that handles player commands.
@@ -170,11 +466,15 @@ func PlayerOutput(p *Player) {
 func InputLoop(c *Character) {
 	Logger.Info("Starting input loop for character", "characterName", c.Name)
 
+	c.Player.Mutex.Lock()
+	c.Player.Character = c
+	c.Player.Mutex.Unlock()
+
 	// Initially execute the look command with no additional tokens
 	ExecuteLookCommand(c, []string{})
 
 	// Send initial prompt to player
-	c.Player.ToPlayer <- c.Player.Prompt
+	c.Player.ToPlayer <- c.Player.RenderPrompt()
 
 	// Create a ticker that ticks once per second
 	commandTicker := time.NewTicker(time.Second)
@@ -182,37 +482,101 @@ func InputLoop(c *Character) {
 
 	var lastCommand string
 	shouldQuit := false
+	linkDead := false
+
+	idleWarn := time.Duration(c.Server.Settings().Game.IdleWarnMinutes) * time.Minute
+	idleTimeout := time.Duration(c.Server.Settings().Game.IdleTimeout) * time.Minute
+	c.Player.LastInput.Store(time.Now().UnixNano())
+	warned := false
 
 	for !shouldQuit {
 		select {
 		case <-commandTicker.C:
+			idle := c.Player.IdleTime()
+			if idleTimeout > 0 && idle >= idleTimeout {
+				Logger.Info("Disconnecting idle player", "playerName", c.Player.PlayerID, "idle", idle)
+				c.Player.Send("\n\rYou have been idle too long and are being disconnected.\n\r")
+				shouldQuit = ExecuteForceQuitCommand(c, []string{"quit!"})
+				break
+			}
+			if idleWarn > 0 && !warned && idle >= idleWarn {
+				warned = true
+				c.Player.ToPlayer <- "\n\rYou have been idle for a while. Enter a command to stay connected.\n\r"
+				c.Player.ToPlayer <- c.Player.RenderPrompt()
+			}
+
 			if lastCommand != "" {
-				verb, tokens, err := ValidateCommand(strings.TrimSpace(lastCommand))
+				// Aliases are expanded before the command is validated
+				var verb string
+				var tokens []string
+				expanded, err := c.Player.ExpandAlias(strings.TrimSpace(lastCommand))
+				if err == nil {
+					verb, tokens, err = ValidateCommand(c.Server, expanded)
+				}
 				if err != nil {
-					c.Player.ToPlayer <- err.Error() + "\n\r"
+					// Input that is not a command may be a verb understood by an item in reach
+					if !c.UseItemVerb(strings.Fields(expanded)) {
+						c.Player.ToPlayer <- err.Error() + "\n\r"
+					}
 				} else {
 					// Execute the command
 					shouldQuit = ExecuteCommand(c, verb, tokens)
-					Logger.Info("Player issued command", "playerName", c.Player.PlayerID, "command", strings.Join(tokens, " "))
+					Logger.Info("Player issued command", "playerName", c.Player.PlayerID, "command", RedactCommand(verb, tokens))
 				}
 				lastCommand = ""
 				if !shouldQuit {
-					c.Player.ToPlayer <- c.Player.Prompt
+					c.Player.ToPlayer <- c.Player.RenderPrompt()
 				}
 			}
 
+		case forced := <-c.Player.Forced:
+			Logger.Info("Running forced command", "playerName", c.Player.PlayerID, "by", forced.By, "command", RedactCommand(forced.Verb, forced.Tokens))
+			shouldQuit = ExecuteCommand(c, forced.Verb, forced.Tokens)
+			if !shouldQuit {
+				c.Player.ToPlayer <- c.Player.RenderPrompt()
+			}
+
 		case inputLine, more := <-c.Player.FromPlayer:
 			if !more {
 				Logger.Info("Input channel closed for player", "playerName", c.Player.PlayerID)
 				shouldQuit = true
+				linkDead = c.Server.Settings().Game.LinkDeadSeconds > 0 && !c.Server.ShuttingDown.Load()
+				break
+			}
+			c.Player.LastInput.Store(time.Now().UnixNano())
+			warned = false
+			if c.Player.Paging.Load() {
+				c.Player.ContinuePaging(inputLine)
 				break
 			}
 			lastCommand = strings.Replace(inputLine, "\n", "\n\r", -1)
 		}
 	}
 
-	// Cleanup code
-	close(c.Player.FromPlayer)
+	// Cleanup code. FromPlayer belongs to PlayerInput, which closes it when the connection ends.
+	c.Player.Mutex.Lock()
+	c.Player.Character = nil
+	c.Player.Mutex.Unlock()
+
+	// Commands forced on this character are not carried over to the next one the player selects
+	for drained := false; !drained; {
+		select {
+		case <-c.Player.Forced:
+		default:
+			drained = true
+		}
+	}
+
+	CancelTrade(c, fmt.Sprintf("%s has left", c.Name))
+
+	// A dropped connection leaves the character in the world for a while so the player can reconnect
+	if linkDead {
+		c.GoLinkDead()
+		c.Server.EndSession(c.ID, c.Player)
+		return
+	}
+
+	c.EndSnoops()
 
 	// Remove character from room and server
 	c.Room.Mutex.Lock()
@@ -224,11 +588,14 @@ func InputLoop(c *Character) {
 	c.Server.Mutex.Unlock()
 
 	// Save character state to the database
-	err := c.Server.Database.WriteCharacter(c)
+	err := c.Server.SaveCharacter(c)
 	if err != nil {
 		Logger.Error("Error saving character", "characterName", c.Name, "error", err)
 	}
 
+	// Only release the character once it is saved, so a session taking it over loads the latest copy
+	c.Server.EndSession(c.ID, c.Player)
+
 	Logger.Info("Input loop ended for character", "characterName", c.Name)
 }
 
@@ -237,18 +604,27 @@ func InputLoop(c *Character) {
 func SelectCharacter(player *Player, server *Server) (*Character, error) {
 	Logger.Info("Player is selecting a character", "playerName", player.PlayerID)
 
+	// A player whose connection dropped goes straight back to the character left behind
+	if character := server.ResumeLinkDead(player); character != nil {
+		return character, nil
+	}
+
 	var options []string
 
 	sendCharacterOptions := func() {
 		player.ToPlayer <- "Select a character:\n\r"
 		player.ToPlayer <- "0: Create a new character\n\r"
 
-		if len(player.CharacterList) > 0 {
-			i := 1
-			for name := range player.CharacterList {
-				player.ToPlayer <- fmt.Sprintf("%d: %s\n\r", i, name)
-				options = append(options, name)
-				i++
+		// Admins may rename or transfer characters while the list is shown
+		player.Mutex.Lock()
+		for name := range player.CharacterList {
+			options = append(options, name)
+		}
+		player.Mutex.Unlock()
+
+		if len(options) > 0 {
+			for i, name := range options {
+				player.ToPlayer <- fmt.Sprintf("%d: %s\n\r", i+1, name)
 			}
 			player.ToPlayer <- "X: Delete a character\n\r"
 		} else {
@@ -269,7 +645,7 @@ func SelectCharacter(player *Player, server *Server) (*Character, error) {
 
 		input = strings.TrimSpace(strings.ToUpper(input))
 
-		if input == "X" && len(player.CharacterList) > 0 {
+		if input == "X" && len(options) > 0 {
 			// Handle character deletion
 			player.ToPlayer <- "Select a character to delete:\n\r"
 			for i, name := range options {
@@ -313,15 +689,34 @@ func SelectCharacter(player *Player, server *Server) (*Character, error) {
 				player.ToPlayer <- fmt.Sprintf("\n\rError creating character: %v\n\r", err)
 				continue
 			}
+			server.startSession(character.ID, player)
 		} else if choice <= len(options) {
 			characterName := options[choice-1]
-			characterID := player.CharacterList[characterName]
-			character, err = server.Database.LoadCharacter(characterID, player, server)
+			player.Mutex.Lock()
+			characterID, owned := player.CharacterList[characterName]
+			player.Mutex.Unlock()
+			if !owned {
+				player.ToPlayer <- fmt.Sprintf("%s is no longer one of your characters.\n\r", characterName)
+				continue
+			}
+			character, err = server.OpenSession(characterID, player)
+			if errors.Is(err, ErrSessionActive) {
+				player.ToPlayer <- fmt.Sprintf("%s is already being played from another connection.\n\r", characterName)
+				continue
+			}
 			if err != nil {
 				Logger.Error("Error loading character for player", "characterName", characterName, "playerName", player.PlayerID, "error", err)
 				player.ToPlayer <- fmt.Sprintf("Error loading character: %v\n\r", err)
 				continue
 			}
+
+			// A reclaimed link-dead character is still in the world
+			server.Mutex.Lock()
+			inWorld := server.Characters[character.ID] == character
+			server.Mutex.Unlock()
+			if inWorld {
+				return character, nil
+			}
 		}
 
 		if character == nil {
@@ -349,3 +744,98 @@ func SelectCharacter(player *Player, server *Server) (*Character, error) {
 		return character, nil
 	}
 }
+
+// GoLinkDead keeps a character whose connection dropped in the world until the link-dead
+// window configured in LinkDeadSeconds expires, then removes and saves them.
+func (c *Character) GoLinkDead() {
+	grace := time.Duration(c.Server.Settings().Game.LinkDeadSeconds) * time.Second
+
+	c.Mutex.Lock()
+	c.LinkDead = true
+	c.LinkDeadTimer = time.AfterFunc(grace, c.expireLinkDead)
+	room := c.Room
+	c.Mutex.Unlock()
+
+	// Save now in case the character is never reclaimed
+	if err := c.Server.SaveCharacter(c); err != nil {
+		Logger.Error("Error saving link-dead character", "characterName", c.Name, "error", err)
+	}
+
+	Logger.Info("Character is link-dead", "characterName", c.Name, "grace", grace)
+	if room != nil {
+		SendRoomMessage(room, fmt.Sprintf("\n\r%s stares blankly into the distance.\n\r", c.Name))
+	}
+}
+
+// expireLinkDead removes a character whose link-dead window ended without a reconnection.
+func (c *Character) expireLinkDead() {
+	c.Mutex.Lock()
+	if !c.LinkDead {
+		c.Mutex.Unlock()
+		return
+	}
+	c.LinkDead = false
+	c.LinkDeadTimer = nil
+	room := c.Room
+	c.Mutex.Unlock()
+
+	c.EndSnoops()
+
+	if room != nil {
+		room.Mutex.Lock()
+		delete(room.Characters, c.ID)
+		room.Mutex.Unlock()
+		SendRoomMessage(room, fmt.Sprintf("\n\r%s fades from the world.\n\r", c.Name))
+	}
+
+	c.Server.Mutex.Lock()
+	delete(c.Server.Characters, c.ID)
+	c.Server.Mutex.Unlock()
+
+	if err := c.Server.SaveCharacter(c); err != nil {
+		Logger.Error("Error saving character", "characterName", c.Name, "error", err)
+	}
+
+	Logger.Info("Link-dead character removed", "characterName", c.Name)
+}
+
+// ReclaimLinkDead hands a link-dead character back to a reconnecting player.
+// It returns nil if the character is not waiting to be reclaimed.
+func (s *Server) ReclaimLinkDead(characterID uuid.UUID, player *Player) *Character {
+	s.Mutex.Lock()
+	character, exists := s.Characters[characterID]
+	s.Mutex.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	character.Mutex.Lock()
+	if !character.LinkDead {
+		character.Mutex.Unlock()
+		return nil
+	}
+	if character.LinkDeadTimer != nil {
+		character.LinkDeadTimer.Stop()
+		character.LinkDeadTimer = nil
+	}
+	character.LinkDead = false
+	character.Player = player
+	room := character.Room
+
+	// Combat carried on while the connection was down, so say where it stands
+	message := fmt.Sprintf("\n\rYou reconnect to %s.\n\r", character.Name)
+	if character.IsInCombat() && character.Facing != nil {
+		message += fmt.Sprintf("You are still fighting %s!\n\r", character.Facing.Name)
+	} else if character.IsInCombat() {
+		message += "You are still in combat!\n\r"
+	}
+	character.Mutex.Unlock()
+
+	player.Send(message)
+	Logger.Info("Player reclaimed link-dead character", "playerName", player.PlayerID, "characterName", character.Name)
+	if room != nil {
+		sendToRoomExcept(room, fmt.Sprintf("\n\r%s snaps back to attention.\n\r", character.Name), character)
+	}
+	return character
+}
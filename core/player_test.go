@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSendDuringTeardown(t *testing.T) {
+	tests := []struct {
+		name   string
+		player func() *Player
+		want   bool
+	}{
+		{
+			name: "open session",
+			player: func() *Player {
+				return &Player{PlayerID: "open", ToPlayer: make(chan string, 1)}
+			},
+			want: true,
+		},
+		{
+			name: "closed session",
+			player: func() *Player {
+				player := &Player{PlayerID: "closed", ToPlayer: make(chan string, 1)}
+				player.CloseOutput()
+				return player
+			},
+		},
+		{
+			name: "output closed before the session is marked",
+			player: func() *Player {
+				player := &Player{PlayerID: "closing", ToPlayer: make(chan string, 1)}
+				close(player.ToPlayer)
+				return player
+			},
+		},
+		{
+			name:   "no output channel",
+			player: func() *Player { return &Player{PlayerID: "none"} },
+		},
+		{
+			name:   "no player",
+			player: func() *Player { return nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.player().Send("hello"); got != tt.want {
+				t.Errorf("Send() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendRoomMessageSkipsClosedSessions(t *testing.T) {
+	server := newTestServer(t, 1)
+	room := server.Rooms[1]
+
+	listener := &Character{ID: uuid.New(), Name: "Listener", Player: &Player{PlayerID: "listener", ToPlayer: make(chan string, 2)}}
+	leaving := &Character{ID: uuid.New(), Name: "Leaving", Player: &Player{PlayerID: "leaving", ToPlayer: make(chan string, 2)}}
+	leaving.Player.CloseOutput()
+	room.Characters[listener.ID] = listener
+	room.Characters[leaving.ID] = leaving
+
+	SendRoomMessage(room, "\n\rThunder rolls overhead.\n\r")
+
+	if got := len(listener.Player.ToPlayer); got != 2 {
+		t.Errorf("listener received %d messages, want the message and a prompt", got)
+	}
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+)
+
+// Positions a character can take. Only standing characters can move, and the lower positions
+// recover health and essence faster.
+const (
+	PositionStanding = "standing"
+	PositionSitting  = "sitting"
+	PositionResting  = "resting"
+	PositionSleeping = "sleeping"
+)
+
+// positionMessages holds what the character and the room are told on taking each position.
+var positionMessages = map[string][2]string{
+	PositionStanding: {"You stand up.", "%s stands up."},
+	PositionSitting:  {"You sit down.", "%s sits down."},
+	PositionResting:  {"You sit back and rest.", "%s sits back and rests."},
+	PositionSleeping: {"You lie down and go to sleep.", "%s lies down and goes to sleep."},
+}
+
+// GetPosition returns the character's position, treating an unset position as standing.
+func (c *Character) GetPosition() string {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if c.Position == "" {
+		return PositionStanding
+	}
+	return c.Position
+}
+
+// IsStanding reports whether the character is on their feet.
+func (c *Character) IsStanding() bool {
+	return c.GetPosition() == PositionStanding
+}
+
+// SetPosition changes the character's position, returning an error if they cannot take it now.
+func (c *Character) SetPosition(position string) error {
+	current := c.GetPosition()
+	if current == position {
+		return fmt.Errorf("you are already %s", position)
+	}
+	if position != PositionStanding && c.IsInCombat() {
+		return fmt.Errorf("you are fighting for your life")
+	}
+
+	c.Mutex.Lock()
+	c.Position = position
+	c.Mutex.Unlock()
+
+	return nil
+}
+
+// StandUp puts the character back on their feet without a command, as when attacked or moved by force.
+func (c *Character) StandUp() {
+	c.Mutex.Lock()
+	wasDown := c.Position != "" && c.Position != PositionStanding
+	c.Position = PositionStanding
+	c.Mutex.Unlock()
+
+	if wasDown {
+		c.Player.Send("\n\rYou scramble to your feet!\n\r")
+	}
+}
+
+// changePosition handles the position commands.
+func changePosition(character *Character, position string) bool {
+	if err := character.SetPosition(position); err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return false
+	}
+
+	Logger.Info("Character changed position", "characterName", character.Name, "position", position)
+	messages := positionMessages[position]
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", messages[0])
+	sendToRoomExcept(character.Room, fmt.Sprintf("\n\r"+messages[1]+"\n\r", character.Name), character)
+	return false
+}
+
+func ExecuteStandCommand(character *Character, tokens []string) bool {
+	return changePosition(character, PositionStanding)
+}
+
+func ExecuteSitCommand(character *Character, tokens []string) bool {
+	return changePosition(character, PositionSitting)
+}
+
+func ExecuteRestCommand(character *Character, tokens []string) bool {
+	return changePosition(character, PositionResting)
+}
+
+func ExecuteSleepCommand(character *Character, tokens []string) bool {
+	return changePosition(character, PositionSleeping)
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Where metrics are published, set by Logging.Metrics.
+const (
+	MetricsCloudWatch = "cloudwatch" // PutMetricData every minute
+	MetricsPrometheus = "prometheus" // served for scraping on Server.MetricsPort
+	MetricsNone       = "none"
+)
+
+// MetricsBackend returns the configured metrics backend, CloudWatch unless another is chosen.
+func (s *Server) MetricsBackend() string {
+	switch strings.ToLower(s.Config.Logging.Metrics) {
+	case MetricsPrometheus:
+		return MetricsPrometheus
+	case MetricsNone:
+		return MetricsNone
+	default:
+		return MetricsCloudWatch
+	}
+}
+
+// StartMetricsServer starts the HTTP listener serving /metrics in the Prometheus text format.
+// It does nothing when no metrics port is configured.
+func (s *Server) StartMetricsServer() error {
+	port := s.Config.Server.MetricsPort
+	if port == 0 {
+		Logger.Warn("Prometheus metrics selected but no metrics port configured")
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics port %d: %w", port, err)
+	}
+
+	s.MetricsServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.MetricsServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Logger.Error("Metrics server stopped unexpectedly", "error", err)
+		}
+	}()
+
+	Logger.Info("Metrics server listening", "port", port)
+	return nil
+}
+
+// StopMetricsServer shuts down the metrics listener if it is running.
+func (s *Server) StopMetricsServer(ctx context.Context) error {
+	if s.MetricsServer == nil {
+		return nil
+	}
+
+	Logger.Info("Closing metrics server...")
+	if err := s.MetricsServer.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down metrics server: %w", err)
+	}
+	return nil
+}
+
+// handleMetrics writes the same gauges and counters that SendMetrics publishes to CloudWatch,
+// with counters running from startup as Prometheus expects.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+
+	s.Mutex.Lock()
+	players := len(s.Characters)
+	rooms := len(s.Rooms)
+	s.Mutex.Unlock()
+
+	cache := s.Database.Cache.Stats()
+	totals := snapshotTotals()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	writeMetric(w, "mud_players", "gauge", "Characters in the world.", float64(players))
+	writeMetric(w, "mud_rooms", "gauge", "Rooms loaded.", float64(rooms))
+	writeMetric(w, "mud_memory_bytes", "gauge", "Heap memory in use.", float64(memory.Alloc))
+	writeMetric(w, "mud_goroutines", "gauge", "Running goroutines.", float64(runtime.NumGoroutine()))
+	writeMetric(w, "mud_uptime_seconds", "gauge", "Seconds since the server started.", time.Since(s.StartTime).Seconds())
+
+	writeMetric(w, "mud_cache_hits_total", "counter", "Records read from the cache.", float64(cache.Hits))
+	writeMetric(w, "mud_cache_misses_total", "counter", "Records not found in the cache.", float64(cache.Misses))
+	writeMetric(w, "mud_cache_size", "gauge", "Records held in the cache.", float64(cache.Size))
+
+	writeMetric(w, "mud_connections_accepted_total", "counter", "Connections accepted by the SSH listener.", float64(totals.connections))
+	writeMetric(w, "mud_connection_errors_total", "counter", "Failures to accept a connection.", float64(totals.connectionErrors))
+	writeMetric(w, "mud_handshake_failures_total", "counter", "Connections dropped during the SSH handshake.", float64(totals.handshakeFailures))
+	writeMetric(w, "mud_auth_failures_total", "counter", "Rejected logins.", float64(totals.authFailures))
+
+	writeMetric(w, "mud_commands_total", "counter", "Player commands run.", float64(totals.commands))
+	fmt.Fprintln(w, "# HELP mud_command_duration_seconds Time spent in command handlers.")
+	fmt.Fprintln(w, "# TYPE mud_command_duration_seconds histogram")
+	for i, bound := range commandLatencyBuckets {
+		fmt.Fprintf(w, "mud_command_duration_seconds_bucket{le=%q} %d\n", formatFloat(bound), totals.commandBuckets[i])
+	}
+	fmt.Fprintf(w, "mud_command_duration_seconds_bucket{le=\"+Inf\"} %d\n", totals.commands)
+	fmt.Fprintf(w, "mud_command_duration_seconds_sum %s\n", formatFloat(totals.commandSeconds))
+	fmt.Fprintf(w, "mud_command_duration_seconds_count %d\n", totals.commands)
+
+	operations := make([]string, 0, len(totals.database))
+	for operation := range totals.database {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+
+	fmt.Fprintln(w, "# HELP mud_database_request_duration_seconds Time spent in DynamoDB requests.")
+	fmt.Fprintln(w, "# TYPE mud_database_request_duration_seconds summary")
+	for _, operation := range operations {
+		fmt.Fprintf(w, "mud_database_request_duration_seconds_sum{operation=%q} %s\n", operation, formatFloat(totals.database[operation].seconds))
+		fmt.Fprintf(w, "mud_database_request_duration_seconds_count{operation=%q} %d\n", operation, totals.database[operation].calls)
+	}
+	for _, counter := range []struct {
+		name  string
+		help  string
+		value func(*databaseTotals) int64
+	}{
+		{"mud_database_errors_total", "Failed DynamoDB requests.", func(t *databaseTotals) int64 { return t.errors }},
+		{"mud_database_retries_total", "DynamoDB requests sent again after throttling.", func(t *databaseTotals) int64 { return t.retries }},
+	} {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		for _, operation := range operations {
+			fmt.Fprintf(w, "%s{operation=%q} %d\n", counter.name, operation, counter.value(totals.database[operation]))
+		}
+	}
+}
+
+// writeMetric writes a metric without labels with its help and type lines.
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, formatFloat(value))
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
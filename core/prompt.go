@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPrompt is shown to players who have not set their own prompt.
+const DefaultPrompt = "> "
+
+// MaxPromptLength limits the length of a player's prompt format.
+const MaxPromptLength = 80
+
+// PromptHelp describes the tokens that may be used in a prompt format.
+var PromptHelp = "\n\rPrompt tokens:" +
+	"\n\r%h - Health" +
+	"\n\r%H - Maximum health" +
+	"\n\r%e - Essence" +
+	"\n\r%E - Maximum essence" +
+	"\n\r%r - Room title" +
+	"\n\r%n - Character name" +
+	"\n\r%% - A percent sign"
+
+// promptStats is the snapshot of a character's state shown in prompts. It is stored atomically,
+// as prompts are rendered by code that may hold the character's or the room's lock.
+type promptStats struct {
+	Health     float64
+	MaxHealth  float64
+	Essence    float64
+	MaxEssence float64
+	RoomTitle  string
+}
+
+// refreshPrompt stores a new prompt snapshot of the character. The caller must hold c.Mutex.
+func (c *Character) refreshPrompt() {
+	stats := &promptStats{
+		Health:     c.Health,
+		MaxHealth:  c.MaxHealth,
+		Essence:    c.Essence,
+		MaxEssence: c.MaxEssence,
+	}
+	if c.Room != nil {
+		stats.RoomTitle = c.Room.Title
+	}
+	c.stats.Store(stats)
+}
+
+// RenderPrompt expands the player's prompt format with the current state of their character.
+func (p *Player) RenderPrompt() string {
+	p.Mutex.Lock()
+	format := p.Prompt
+	character := p.Character
+	p.Mutex.Unlock()
+
+	if format == "" {
+		format = DefaultPrompt
+	}
+
+	prompt := format
+	if strings.Contains(format, "%") {
+		prompt = expandPrompt(format, character)
+	}
+
+	p.lastPrompt.Store(prompt)
+	return prompt
+}
+
+// SendPromptIfChanged sends the player's prompt only when it reads differently from the one last
+// rendered, so periodic updates do not repeat a prompt that shows nothing new.
+func (p *Player) SendPromptIfChanged() {
+	last, _ := p.lastPrompt.Load().(string)
+	if prompt := p.RenderPrompt(); prompt != last {
+		p.Send(prompt)
+	}
+}
+
+// isPrompt reports whether the message is the prompt most recently rendered for the player.
+func (p *Player) isPrompt(message string) bool {
+	last, _ := p.lastPrompt.Load().(string)
+	return last != "" && message == last
+}
+
+// expandPrompt replaces the tokens in a prompt format from the character's prompt snapshot, so
+// no lock is taken.
+func expandPrompt(format string, character *Character) string {
+	var prompt strings.Builder
+
+	var stats *promptStats
+	if character != nil {
+		stats = character.stats.Load()
+	}
+
+	runes := []rune(format)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' || i == len(runes)-1 {
+			prompt.WriteRune(runes[i])
+			continue
+		}
+
+		i++
+		token := runes[i]
+		if token == '%' {
+			prompt.WriteRune('%')
+			continue
+		}
+
+		// Before a character is selected the stat tokens have nothing to show
+		if stats == nil {
+			continue
+		}
+
+		switch token {
+		case 'h':
+			prompt.WriteString(fmt.Sprintf("%.0f", stats.Health))
+		case 'H':
+			prompt.WriteString(fmt.Sprintf("%.0f", stats.MaxHealth))
+		case 'e':
+			prompt.WriteString(fmt.Sprintf("%.0f", stats.Essence))
+		case 'E':
+			prompt.WriteString(fmt.Sprintf("%.0f", stats.MaxEssence))
+		case 'r':
+			prompt.WriteString(stats.RoomTitle)
+		case 'n':
+			prompt.WriteString(character.Name)
+		default:
+			// Unknown tokens are shown as typed
+			prompt.WriteRune('%')
+			prompt.WriteRune(token)
+		}
+	}
+
+	return prompt.String()
+}
+
+func ExecutePromptCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is changing their prompt", "playerName", character.Player.PlayerID)
+
+	player := character.Player
+
+	if len(tokens) < 2 {
+		player.Mutex.Lock()
+		format := player.Prompt
+		player.Mutex.Unlock()
+
+		player.ToPlayer <- fmt.Sprintf("\n\rYour prompt is: %s\n\rUsage: prompt <format|default>%s\n\r", format, PromptHelp)
+		return false
+	}
+
+	format := DefaultPrompt
+	if !(len(tokens) == 2 && strings.EqualFold(tokens[1], "default")) {
+		var err error
+		format, err = SanitizeText(strings.Join(tokens[1:], " "), MaxPromptLength)
+		if err != nil {
+			player.ToPlayer <- fmt.Sprintf("\n\rInvalid prompt: %v\n\r", err)
+			return false
+		}
+		// Leave a space between the prompt and what the player types
+		format += " "
+	}
+
+	player.Mutex.Lock()
+	player.Prompt = format
+	player.Mutex.Unlock()
+
+	if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+		Logger.Error("Error saving player prompt", "playerName", player.PlayerID, "error", err)
+	}
+
+	player.ToPlayer <- "\n\rPrompt updated.\n\r"
+	return false
+}
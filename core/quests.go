@@ -0,0 +1,348 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Objective types a quest can require.
+const (
+	ObjectiveKill    = "kill"    // slay the named character
+	ObjectiveCollect = "collect" // carry distinct items with the given name
+	ObjectiveVisit   = "visit"   // enter the room with the given ID
+)
+
+// MaxActiveQuests is how many quests a character can have in their log at once.
+const MaxActiveQuests = 10
+
+// QuestObjective is one step of a quest, complete once Count matching events have happened.
+type QuestObjective struct {
+	Type   string `json:"Type" dynamodbav:"Type"`
+	Target string `json:"Target" dynamodbav:"Target"`
+	Count  int    `json:"Count" dynamodbav:"Count"`
+}
+
+// QuestData represents the structure for storing quest definitions in DynamoDB.
+type QuestData struct {
+	QuestID          string           `json:"QuestID" dynamodbav:"QuestID"`
+	Title            string           `json:"Title" dynamodbav:"Title"`
+	Description      string           `json:"Description" dynamodbav:"Description"`
+	MinLevel         int              `json:"MinLevel,omitempty" dynamodbav:"MinLevel,omitempty"`
+	Objectives       []QuestObjective `json:"Objectives" dynamodbav:"Objectives"`
+	RewardExperience int              `json:"RewardExperience,omitempty" dynamodbav:"RewardExperience,omitempty"`
+	RewardCoins      uint64           `json:"RewardCoins,omitempty" dynamodbav:"RewardCoins,omitempty"`
+	RewardItems      []string         `json:"RewardItems,omitempty" dynamodbav:"RewardItems,omitempty"` // prototype names or IDs
+}
+
+// LoadQuests retrieves all quest definitions from the DynamoDB table.
+func (s *Server) LoadQuests() error {
+	var quests []QuestData
+	err := s.Database.Scan(s.Context, "quests", &quests)
+	if err != nil {
+		return fmt.Errorf("error scanning quests table: %w", err)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.Quests = make(map[string]*QuestData, len(quests))
+	for _, quest := range quests {
+		questCopy := quest
+		s.Quests[quest.QuestID] = &questCopy
+		Logger.Debug("Loaded quest", "questID", quest.QuestID, "title", quest.Title)
+	}
+
+	return nil
+}
+
+// findQuest looks up a quest by ID or title, ignoring case.
+func (s *Server) findQuest(name string) *QuestData {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	for _, quest := range s.Quests {
+		if strings.EqualFold(quest.QuestID, name) || strings.EqualFold(quest.Title, name) {
+			return quest
+		}
+	}
+	return nil
+}
+
+// matches reports whether an event of the given type and target counts toward the objective.
+func (o QuestObjective) matches(objectiveType, target string) bool {
+	if o.Type != objectiveType {
+		return false
+	}
+	if o.Type == ObjectiveCollect {
+		return strings.Contains(strings.ToLower(target), strings.ToLower(o.Target))
+	}
+	return strings.EqualFold(o.Target, target)
+}
+
+// describe returns a short description of the objective for the quest log.
+func (o QuestObjective) describe(progress int) string {
+	switch o.Type {
+	case ObjectiveKill:
+		return fmt.Sprintf("Slay %s (%d/%d)", o.Target, progress, o.Count)
+	case ObjectiveCollect:
+		return fmt.Sprintf("Collect %s (%d/%d)", o.Target, progress, o.Count)
+	case ObjectiveVisit:
+		return fmt.Sprintf("Visit room %s (%d/%d)", o.Target, progress, o.Count)
+	}
+	return fmt.Sprintf("%s %s (%d/%d)", o.Type, o.Target, progress, o.Count)
+}
+
+// RecordObjective advances the character's active quests for an event, completing any that are finished.
+func (c *Character) RecordObjective(objectiveType, target string) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	c.recordObjective(objectiveType, target)
+}
+
+// recordObjective advances the character's active quests for an event. The caller must hold c.Mutex.
+func (c *Character) recordObjective(objectiveType, target string) {
+	for questID, progress := range c.Quests {
+		quest := c.Server.findQuest(questID)
+		if quest == nil {
+			continue
+		}
+
+		advanced := false
+		for i, objective := range quest.Objectives {
+			if i >= len(progress) || progress[i] >= objective.Count || !objective.matches(objectiveType, target) {
+				continue
+			}
+
+			// Collecting counts the matching items carried, so picking up the same item again
+			// makes no progress
+			count := progress[i] + 1
+			if objective.Type == ObjectiveCollect {
+				count = c.carried(objective.Target)
+				if count > objective.Count {
+					count = objective.Count
+				}
+				if count <= progress[i] {
+					continue
+				}
+			}
+
+			progress[i] = count
+			advanced = true
+			c.Player.Send(fmt.Sprintf("\n\r[%s] %s\n\r", quest.Title, objective.describe(progress[i])))
+		}
+		if !advanced {
+			continue
+		}
+		c.markDirty(DirtyStats)
+
+		if questComplete(quest, progress) {
+			c.completeQuest(quest)
+		}
+	}
+}
+
+// carried counts the distinct items the character holds or wears whose names contain target,
+// ignoring case. The caller must hold c.Mutex.
+func (c *Character) carried(target string) int {
+	target = strings.ToLower(target)
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range c.Inventory {
+		if item != nil && !seen[item.ID] && strings.Contains(strings.ToLower(item.Name), target) {
+			seen[item.ID] = true
+		}
+	}
+	return len(seen)
+}
+
+// questComplete reports whether every objective of the quest has been met.
+func questComplete(quest *QuestData, progress []int) bool {
+	for i, objective := range quest.Objectives {
+		if i >= len(progress) || progress[i] < objective.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// completeQuest moves the quest to the completed list and grants its rewards. The caller must hold c.Mutex.
+func (c *Character) completeQuest(quest *QuestData) {
+	delete(c.Quests, quest.QuestID)
+	if c.CompletedQuests == nil {
+		c.CompletedQuests = make(map[string]bool)
+	}
+	c.CompletedQuests[quest.QuestID] = true
+
+	Logger.Info("Character completed quest", "characterName", c.Name, "questID", quest.QuestID)
+	c.Player.Send(c.Player.Colorize(ColorTitle, fmt.Sprintf("\n\rQuest complete: %s!\n\r", quest.Title)))
+
+	if quest.RewardCoins > 0 {
+		c.Coins += quest.RewardCoins
+		c.Player.Send(fmt.Sprintf("\n\rYou receive %d coins.\n\r", quest.RewardCoins))
+	}
+
+	for _, name := range quest.RewardItems {
+		prototype := findPrototype(c.Server, name)
+		if prototype == nil {
+			Logger.Warn("Quest reward prototype not found", "questID", quest.QuestID, "prototype", name)
+			continue
+		}
+		item, err := c.Server.CreateItemFromPrototype(prototype.ID)
+		if err != nil {
+			Logger.Error("Error creating quest reward", "questID", quest.QuestID, "prototypeID", prototype.ID, "error", err)
+			continue
+		}
+
+		// Rewards go into a free hand, or at the character's feet when the character can't hold them
+		if _, err := c.holdItem(item); err != nil {
+			c.Room.DropItem(item)
+			c.Player.Send(fmt.Sprintf("\n\rYour hands are full, so %s is placed at your feet.\n\r", item.Name))
+			continue
+		}
+		c.Player.Send(fmt.Sprintf("\n\rYou receive %s.\n\r", item.Name))
+	}
+
+	c.addExperience(quest.RewardExperience, "completing "+quest.Title)
+	c.markDirty(DirtyStats | DirtyInventory)
+}
+
+// AcceptQuest adds the quest to the character's log.
+func (c *Character) AcceptQuest(quest *QuestData) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if _, active := c.Quests[quest.QuestID]; active {
+		return fmt.Errorf("you are already on %s", quest.Title)
+	}
+	if c.CompletedQuests[quest.QuestID] {
+		return fmt.Errorf("you have already completed %s", quest.Title)
+	}
+	if c.Level < quest.MinLevel {
+		return fmt.Errorf("you must be level %d to take on %s", quest.MinLevel, quest.Title)
+	}
+	if len(c.Quests) >= MaxActiveQuests {
+		return fmt.Errorf("your quest log is full")
+	}
+
+	if c.Quests == nil {
+		c.Quests = make(map[string][]int)
+	}
+	c.Quests[quest.QuestID] = make([]int, len(quest.Objectives))
+	c.markDirty(DirtyStats)
+	return nil
+}
+
+// AbandonQuest removes the quest and its progress from the character's log.
+func (c *Character) AbandonQuest(questID string) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if _, active := c.Quests[questID]; !active {
+		return false
+	}
+	delete(c.Quests, questID)
+	c.markDirty(DirtyStats)
+	return true
+}
+
+// questLog describes the character's active quests and the ones they can take on.
+func questLog(character *Character) string {
+	server := character.Server
+
+	server.Mutex.Lock()
+	quests := make([]*QuestData, 0, len(server.Quests))
+	for _, quest := range server.Quests {
+		quests = append(quests, quest)
+	}
+	server.Mutex.Unlock()
+	sort.Slice(quests, func(i, j int) bool { return quests[i].QuestID < quests[j].QuestID })
+
+	var active, available strings.Builder
+
+	character.Mutex.Lock()
+	for _, quest := range quests {
+		if progress, ok := character.Quests[quest.QuestID]; ok {
+			active.WriteString(fmt.Sprintf("%s (%s)\n\r", quest.Title, quest.QuestID))
+			for i, objective := range quest.Objectives {
+				if i < len(progress) {
+					active.WriteString("  " + objective.describe(progress[i]) + "\n\r")
+				}
+			}
+			continue
+		}
+		if !character.CompletedQuests[quest.QuestID] && character.Level >= quest.MinLevel {
+			available.WriteString(fmt.Sprintf("%s (%s) - %s\n\r", quest.Title, quest.QuestID, quest.Description))
+		}
+	}
+	character.Mutex.Unlock()
+
+	var output strings.Builder
+	output.WriteString("\n\rActive quests:\n\r")
+	if active.Len() == 0 {
+		output.WriteString("None\n\r")
+	}
+	output.WriteString(active.String())
+	output.WriteString("\n\rAvailable quests:\n\r")
+	if available.Len() == 0 {
+		output.WriteString("None\n\r")
+	}
+	output.WriteString(available.String())
+	return output.String()
+}
+
+func ExecuteQuestCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is using the quest log", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 || strings.EqualFold(tokens[1], "list") {
+		character.Player.ToPlayer <- questLog(character)
+		return false
+	}
+
+	if len(tokens) < 3 {
+		character.Player.ToPlayer <- "\n\rUsage: quest [list | accept <quest> | abandon <quest>]\n\r"
+		return false
+	}
+
+	name := strings.Join(tokens[2:], " ")
+	quest := character.Server.findQuest(name)
+	if quest == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rThere is no quest called %s.\n\r", name)
+		return false
+	}
+
+	switch strings.ToLower(tokens[1]) {
+	case "accept":
+		if err := character.AcceptQuest(quest); err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rUnable to accept quest: %v.\n\r", err)
+			return false
+		}
+		Logger.Info("Character accepted quest", "characterName", character.Name, "questID", quest.QuestID)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou accept %s.\n\r%s\n\r", quest.Title, quest.Description)
+	case "abandon":
+		if !character.AbandonQuest(quest.QuestID) {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are not on %s.\n\r", quest.Title)
+			return false
+		}
+		Logger.Info("Character abandoned quest", "characterName", character.Name, "questID", quest.QuestID)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou abandon %s.\n\r", quest.Title)
+	default:
+		character.Player.ToPlayer <- "\n\rUsage: quest [list | accept <quest> | abandon <quest>]\n\r"
+		return false
+	}
+
+	return false
+}
+
+// completedQuestsToData lists the character's completed quests for storage. The caller must hold c.Mutex.
+func (c *Character) completedQuestsToData() []string {
+	quests := make([]string, 0, len(c.CompletedQuests))
+	for questID := range c.CompletedQuests {
+		quests = append(quests, questID)
+	}
+	sort.Strings(quests)
+	return quests
+}
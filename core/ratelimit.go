@@ -0,0 +1,79 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// Defaults used when rate limiting is enabled without a burst or flood limit.
+const (
+	DefaultCommandBurst = 10
+	DefaultFloodLimit   = 20
+)
+
+// TokenBucket limits how often something may happen. Tokens refill at a steady rate up to
+// the bucket's capacity and each allowed action spends one.
+type TokenBucket struct {
+	Capacity float64
+	Rate     float64 // tokens added per second
+	tokens   float64
+	last     time.Time
+	mutex    sync.Mutex
+}
+
+// NewTokenBucket creates a full bucket that refills at rate tokens per second.
+func NewTokenBucket(rate, capacity float64) *TokenBucket {
+	return &TokenBucket{
+		Capacity: capacity,
+		Rate:     rate,
+		tokens:   capacity,
+		last:     time.Now(),
+	}
+}
+
+// Allow spends a token if one is available. A nil bucket allows everything.
+func (b *TokenBucket) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.Rate
+	if b.tokens > b.Capacity {
+		b.tokens = b.Capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// NewInputLimiter creates the bucket limiting a player's input from the server configuration.
+// It returns nil, allowing all input, when CommandRate is not configured.
+func (s *Server) NewInputLimiter() *TokenBucket {
+	rate := s.Settings().Server.CommandRate
+	if rate <= 0 {
+		return nil
+	}
+
+	burst := float64(s.Settings().Server.CommandBurst)
+	if burst == 0 {
+		burst = DefaultCommandBurst
+	}
+
+	return NewTokenBucket(rate, burst)
+}
+
+// floodLimit returns how many lines in a row may be dropped before a player is disconnected.
+func (s *Server) floodLimit() int {
+	if s.Settings().Server.FloodLimit == 0 {
+		return DefaultFloodLimit
+	}
+	return int(s.Settings().Server.FloodLimit)
+}
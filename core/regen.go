@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math"
+	"time"
+)
+
+// Regeneration defaults used when the server configuration leaves them unset.
+const (
+	DefaultRegenSeconds      = 10
+	DefaultHealthRegenRate   = 1.0 // percent of maximum health restored per tick while standing
+	DefaultEssenceRegenRate  = 1.0 // percent of maximum essence restored per tick while standing
+	RestfulRoomRegenModifier = 2.0
+)
+
+// RoomFlagRestful marks rooms such as inns and shrines where characters recover faster.
+const RoomFlagRestful = "restful"
+
+// PositionRegen multiplies the regeneration rates for each position.
+var PositionRegen = map[string]float64{
+	PositionStanding: 1,
+	PositionSitting:  1.5,
+	PositionResting:  2,
+	PositionSleeping: 3,
+}
+
+// regenRates returns the configured percentages of maximum health and essence restored each tick.
+func (s *Server) regenRates() (health, essence float64) {
+	health, essence = s.Settings().Game.HealthRegenRate, s.Settings().Game.EssenceRegenRate
+	if health == 0 {
+		health = DefaultHealthRegenRate
+	}
+	if essence == 0 {
+		essence = DefaultEssenceRegenRate
+	}
+	return health, essence
+}
+
+// RegenLoop restores health and essence every RegenSeconds until the server context is cancelled.
+func RegenLoop(s *Server) {
+	tickSeconds := time.Duration(s.Settings().Game.RegenSeconds)
+	if tickSeconds == 0 {
+		tickSeconds = DefaultRegenSeconds
+	}
+
+	Logger.Info("Starting regeneration loop", "tickSeconds", int(tickSeconds))
+
+	ticker := time.NewTicker(tickSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.TickRegen()
+		case <-s.Context.Done():
+			Logger.Info("Stopping regeneration loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// TickRegen restores health and essence to every active character who is not fighting.
+// The rates are scaled by the character's position and by restful rooms.
+func (s *Server) TickRegen() {
+	s.Mutex.Lock()
+	characters := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		characters = append(characters, character)
+	}
+	s.Mutex.Unlock()
+
+	healthRate, essenceRate := s.regenRates()
+
+	for _, character := range characters {
+		if !character.IsActive() || character.IsInCombat() {
+			continue
+		}
+
+		if character.regenerate(healthRate, essenceRate) {
+			character.Player.SendPromptIfChanged()
+		}
+	}
+}
+
+// regenerate applies one tick of recovery at the given percentages of the character's maximums,
+// returning true if anything was restored.
+func (c *Character) regenerate(healthRate, essenceRate float64) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	position := c.Position
+	if position == "" {
+		position = PositionStanding
+	}
+	modifier := PositionRegen[position] / 100
+	if c.Room != nil && c.Room.HasFlag(RoomFlagRestful) {
+		modifier *= RestfulRoomRegenModifier
+	}
+
+	// Dead characters wait to respawn rather than recovering
+	if c.Health <= 0 {
+		return false
+	}
+
+	health := math.Min(c.MaxHealth, c.Health+c.MaxHealth*healthRate*modifier)
+	essence := math.Min(c.MaxEssence, c.Essence+c.MaxEssence*essenceRate*modifier)
+	if health <= c.Health && essence <= c.Essence {
+		return false
+	}
+
+	c.Health, c.Essence = math.Max(c.Health, health), math.Max(c.Essence, essence)
+	c.markDirty(DirtyStats)
+	return true
+}
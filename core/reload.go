@@ -0,0 +1,128 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reload re-reads the configuration through ConfigLoader and applies the values that may change
+// while the server runs, then reloads archetypes and prototypes from the database. It is run
+// on SIGHUP and by the reload command.
+func (s *Server) Reload() error {
+	Logger.Info("Reloading configuration and world data")
+
+	var errs []error
+	if s.ConfigLoader != nil {
+		config, err := s.ConfigLoader()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading configuration: %w", err))
+		} else {
+			s.ApplyConfig(config)
+		}
+	}
+
+	if err := s.LoadArchetypes(); err != nil {
+		errs = append(errs, fmt.Errorf("error reloading archetypes: %w", err))
+	}
+
+	if err := s.LoadPrototypes(); err != nil {
+		errs = append(errs, fmt.Errorf("error reloading prototypes: %w", err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		Logger.Error("Reload finished with errors", "error", err)
+		return err
+	}
+
+	Logger.Info("Reload completed", "archetypes", len(s.Archetypes()), "prototypes", len(s.Prototypes))
+	return nil
+}
+
+// Settings returns the configuration in force. Reload publishes a new copy rather than changing
+// this one, so callers may keep and read the returned value without holding a lock, but must
+// not modify it.
+func (s *Server) Settings() *Configuration {
+	if settings := s.settings.Load(); settings != nil {
+		return settings
+	}
+	return &s.Config
+}
+
+// ApplyConfig takes the tunable values from a newly read configuration. Settings only used at
+// startup, such as the ports, storage, data files and AWS resources, keep their running values.
+func (s *Server) ApplyConfig(config Configuration) {
+	s.Mutex.Lock()
+	next := *s.Settings()
+	next.Server.Admins = config.Server.Admins
+	next.Server.CommandRate = config.Server.CommandRate
+	next.Server.CommandBurst = config.Server.CommandBurst
+	next.Server.FloodLimit = config.Server.FloodLimit
+	next.Server.ShutdownCountdown = config.Server.ShutdownCountdown
+	next.Game = config.Game
+	next.Logging.LogLevel = config.Logging.LogLevel
+	next.Logging.PublishReports = config.Logging.PublishReports
+	next.Logging.SubsystemLevels = config.Logging.SubsystemLevels
+	s.settings.Store(&next)
+
+	// Channels added to the configuration are opened; removed ones stay until the next restart
+	var added []string
+	for _, name := range config.Game.Channels {
+		if !s.Channels[strings.ToLower(strings.TrimSpace(name))] {
+			added = append(added, name)
+		}
+	}
+	s.Mutex.Unlock()
+
+	LogLevel.Set(ConfigLogLevel(config.Logging.LogLevel))
+	ApplySubsystemLevels(config.Logging.SubsystemLevels)
+
+	for _, name := range added {
+		if err := s.CreateChannel(name); err != nil {
+			Logger.Error("Failed to register configured channel", "channel", name, "error", err)
+		}
+	}
+
+	Logger.Info("Applied configuration", "balance", config.Game.Balance, "autoSave", config.Game.AutoSave, "logLevel", LogLevel.Level())
+}
+
+// LoadPrototypes replaces the server's item prototypes with those stored in the database.
+func (s *Server) LoadPrototypes() error {
+	prototypes, err := s.Database.LoadPrototypes(s.Context)
+	if err != nil {
+		return err
+	}
+
+	s.Mutex.Lock()
+	previous := s.Prototypes
+	s.Prototypes = prototypes
+	s.Mutex.Unlock()
+
+	// Drop cached copies read by LoadPrototype so changed prototypes are read afresh
+	for id := range previous {
+		s.Database.Cache.invalidate("prototypes", id.String())
+	}
+	for id := range prototypes {
+		s.Database.Cache.invalidate("prototypes", id.String())
+	}
+	return nil
+}
+
+// reload opens channels, which checks their names against CommandHandlers, so it is registered
+// here to avoid an initialization cycle.
+func init() {
+	CommandHandlers["reload"] = ExecuteReloadCommand
+}
+
+func ExecuteReloadCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is reloading the server", "playerName", character.Player.PlayerID)
+
+	if err := character.Server.Reload(); err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rReload finished with errors: %v\n\r", err)
+		return false
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rReloaded the configuration, %d archetypes and %d prototypes.\n\r", len(character.Server.Archetypes()), len(character.Server.Prototypes))
+	return false
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/google/uuid"
+)
+
+// MaxReportLength limits the length of a bug, typo or idea report.
+const MaxReportLength = 1000
+
+// ReportData is a bug, typo or idea filed by a player, as stored in DynamoDB.
+type ReportData struct {
+	ReportID   string `json:"ReportID" dynamodbav:"ReportID"`
+	Kind       string `json:"Kind" dynamodbav:"Kind"`
+	Character  string `json:"Character" dynamodbav:"Character"`
+	PlayerID   string `json:"PlayerID" dynamodbav:"PlayerID"`
+	RoomID     int64  `json:"RoomID" dynamodbav:"RoomID"`
+	Text       string `json:"Text" dynamodbav:"Text"`
+	Reported   int64  `json:"Reported" dynamodbav:"Reported"` // unix time the report was filed
+	Resolved   bool   `json:"Resolved,omitempty" dynamodbav:"Resolved,omitempty"`
+	ResolvedBy string `json:"ResolvedBy,omitempty" dynamodbav:"ResolvedBy,omitempty"`
+}
+
+// WriteReport stores a report in the DynamoDB table.
+func (kp *KeyPair) WriteReport(ctx context.Context, report *ReportData) error {
+	if err := kp.Put(ctx, "reports", report); err != nil {
+		return fmt.Errorf("error storing report: %w", err)
+	}
+	return nil
+}
+
+// LoadReports returns every report in the database, oldest first.
+func (kp *KeyPair) LoadReports(ctx context.Context) ([]*ReportData, error) {
+	var reports []*ReportData
+	if err := kp.Scan(ctx, "reports", &reports); err != nil {
+		return nil, fmt.Errorf("error scanning reports table: %w", err)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Reported < reports[j].Reported
+	})
+	return reports, nil
+}
+
+// publishReport counts a new report in CloudWatch so filed reports can be alarmed on.
+func publishReport(s *Server, kind string) {
+	if s.CloudWatch == nil {
+		Logger.Warn("No CloudWatch client for report metric", "kind", kind)
+		return
+	}
+
+	_, err := s.CloudWatch.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(s.Config.Logging.MetricNamespace),
+		MetricData: []types.MetricDatum{
+			{
+				MetricName: aws.String("Reports"),
+				Unit:       types.StandardUnitCount,
+				Value:      aws.Float64(1),
+				Dimensions: []types.Dimension{
+					{
+						Name:  aws.String("Kind"),
+						Value: aws.String(kind),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		Logger.Error("Failed to send report metric to CloudWatch", "kind", kind, "error", err)
+	}
+}
+
+// findReport returns the report whose ID starts with the given text, or an error if none or several match.
+func findReport(reports []*ReportData, prefix string) (*ReportData, error) {
+	var found *ReportData
+	for _, report := range reports {
+		if strings.HasPrefix(report.ReportID, strings.ToLower(prefix)) {
+			if found != nil {
+				return nil, fmt.Errorf("%s matches more than one report", prefix)
+			}
+			found = report
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("there is no report %s", prefix)
+	}
+	return found, nil
+}
+
+func ExecuteReportCommand(character *Character, tokens []string) bool {
+
+	kind := strings.ToLower(tokens[0])
+
+	Logger.Info("Player is filing a report", "playerName", character.Player.PlayerID, "kind", kind)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: %s <text>\n\r", kind)
+		return false
+	}
+
+	text, err := SanitizeText(strings.Join(tokens[1:], " "), MaxReportLength)
+	if err != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rInvalid report: %v\n\r", err)
+		return false
+	}
+
+	character.Mutex.Lock()
+	var roomID int64
+	if character.Room != nil {
+		roomID = character.Room.RoomID
+	}
+	character.Mutex.Unlock()
+
+	report := &ReportData{
+		ReportID:  uuid.New().String(),
+		Kind:      kind,
+		Character: character.Name,
+		PlayerID:  character.Player.PlayerID,
+		RoomID:    roomID,
+		Text:      text,
+		Reported:  time.Now().Unix(),
+	}
+
+	if err := character.Server.Database.WriteReport(character.Context(), report); err != nil {
+		Logger.Error("Error saving report", "playerName", character.Player.PlayerID, "error", err)
+		character.Player.ToPlayer <- "\n\rYour report could not be saved. Please try again later.\n\r"
+		return false
+	}
+
+	Logger.Info("Player filed report", "playerName", character.Player.PlayerID, "kind", kind, "reportID", report.ReportID, "roomID", roomID)
+
+	if character.Server.Settings().Logging.PublishReports {
+		go publishReport(character.Server, kind)
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rThank you, your %s report has been recorded.\n\r", kind)
+	return false
+}
+
+func ExecuteReportsCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Admin is reviewing reports", "playerName", character.Player.PlayerID)
+
+	usage := "\n\rUsage: @reports [all] | @reports resolve <id>\n\r"
+
+	reports, err := character.Server.Database.LoadReports(character.Context())
+	if err != nil {
+		Logger.Error("Error loading reports", "error", err)
+		character.Player.ToPlayer <- "\n\rThe reports could not be read.\n\r"
+		return false
+	}
+
+	if len(tokens) >= 2 && strings.EqualFold(tokens[1], "resolve") {
+		if len(tokens) != 3 {
+			character.Player.ToPlayer <- usage
+			return false
+		}
+
+		report, err := findReport(reports, tokens[2])
+		if err != nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+			return false
+		}
+		if report.Resolved {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rReport %s was already resolved by %s.\n\r", report.ReportID[:8], report.ResolvedBy)
+			return false
+		}
+
+		report.Resolved = true
+		report.ResolvedBy = character.Name
+		if err := character.Server.Database.WriteReport(character.Context(), report); err != nil {
+			Logger.Error("Error resolving report", "reportID", report.ReportID, "error", err)
+			character.Player.ToPlayer <- "\n\rThe report could not be resolved.\n\r"
+			return false
+		}
+
+		Logger.Info("Admin resolved report", "playerName", character.Player.PlayerID, "reportID", report.ReportID)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rReport %s has been resolved.\n\r", report.ReportID[:8])
+		return false
+	}
+
+	showAll := len(tokens) == 2 && strings.EqualFold(tokens[1], "all")
+	if len(tokens) > 1 && !showAll {
+		character.Player.ToPlayer <- usage
+		return false
+	}
+
+	var output strings.Builder
+	if showAll {
+		output.WriteString("\n\rAll reports:\n\r")
+	} else {
+		output.WriteString("\n\rOpen reports:\n\r")
+	}
+
+	shown := 0
+	for _, report := range reports {
+		if report.Resolved && !showAll {
+			continue
+		}
+		status := ""
+		if report.Resolved {
+			status = fmt.Sprintf(" (resolved by %s)", report.ResolvedBy)
+		}
+		output.WriteString(fmt.Sprintf("%s  %s  %-4s  %s in room %d%s\n\r    %s\n\r",
+			report.ReportID[:8], time.Unix(report.Reported, 0).Format("2006-01-02"), report.Kind,
+			report.Character, report.RoomID, status, report.Text))
+		shown++
+	}
+	if shown == 0 {
+		output.WriteString("There are none.\n\r")
+	}
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
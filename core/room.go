@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -8,13 +9,12 @@ import (
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 )
 
 // StoreRooms stores all rooms into the DynamoDB database.
-func (kp *KeyPair) StoreRooms(rooms map[int64]*Room) error {
+func (kp *KeyPair) StoreRooms(ctx context.Context, rooms map[int64]*Room) error {
 
 	for _, room := range rooms {
 		room.Mutex.Lock()
@@ -22,7 +22,7 @@ func (kp *KeyPair) StoreRooms(rooms map[int64]*Room) error {
 		// Cleanup nil items before saving
 		room.CleanupNilItems()
 
-		err := kp.WriteRoom(room)
+		err := kp.WriteRoom(ctx, room)
 		if err != nil {
 			Logger.Error("Error storing room", "room_id", room.RoomID, "error", err)
 			return fmt.Errorf("error storing room %d: %w", room.RoomID, err)
@@ -38,11 +38,11 @@ func (kp *KeyPair) StoreRooms(rooms map[int64]*Room) error {
 }
 
 // LoadRooms retrieves all rooms from the DynamoDB database and returns them as a map of Room instances.
-func (kp *KeyPair) LoadRooms() (map[int64]*Room, error) {
+func (kp *KeyPair) LoadRooms(ctx context.Context) (map[int64]*Room, error) {
 	rooms := make(map[int64]*Room)
 
 	var roomsData []RoomData
-	err := kp.Scan("rooms", &roomsData)
+	err := kp.ParallelScan(ctx, "rooms", DefaultScanSegments, &roomsData)
 	if err != nil {
 		Logger.Error("Error scanning rooms", "error", err)
 		return nil, fmt.Errorf("error scanning rooms: %w", err)
@@ -51,18 +51,27 @@ func (kp *KeyPair) LoadRooms() (map[int64]*Room, error) {
 	// First pass: create all rooms without exits or items
 	for _, roomData := range roomsData {
 		room := NewRoom(roomData.RoomID, roomData.Area, roomData.Title, roomData.Description)
+		for _, flag := range roomData.Flags {
+			room.Flags[flag] = true
+		}
+		room.Spawns = roomData.Spawns
+		room.SpawnPoints = roomData.SpawnPoints
+		room.Ambience = roomData.Ambience
+		room.AmbienceEnabled = roomData.AmbienceEnabled
+		room.Light = roomData.Light
+		room.Version = roomData.Version
 		rooms[room.RoomID] = room
 	}
 
 	// Load all exits
-	allExits, err := kp.LoadAllExits()
+	allExits, err := kp.LoadAllExits(ctx)
 	if err != nil {
 		Logger.Error("Error loading exits", "error", err)
 		return nil, fmt.Errorf("error loading exits: %w", err)
 	}
 
 	// Load all items
-	allItems, err := kp.LoadAllItems()
+	allItems, err := kp.LoadAllItems(ctx)
 	if err != nil {
 		Logger.Error("Error loading items", "error", err)
 		return nil, fmt.Errorf("error loading items: %w", err)
@@ -121,10 +130,10 @@ func findRoomData(roomsData []RoomData, roomID int64) (RoomData, bool) {
 }
 
 // LoadAllExits loads all exits for all rooms.
-func (kp *KeyPair) LoadAllExits() (map[string]*Exit, error) {
+func (kp *KeyPair) LoadAllExits(ctx context.Context) (map[string]*Exit, error) {
 	var exitsData []ExitData
 
-	err := kp.Scan("exits", &exitsData)
+	err := kp.ParallelScan(ctx, "exits", DefaultScanSegments, &exitsData)
 	if err != nil {
 		Logger.Error("Error scanning exits", "error", err)
 		return nil, fmt.Errorf("error scanning exits: %w", err)
@@ -143,6 +152,12 @@ func (kp *KeyPair) LoadAllExits() (map[string]*Exit, error) {
 			Direction:  exitData.Direction,
 			TargetRoom: &Room{RoomID: exitData.TargetRoom}, // Temporary Room object, will be resolved later
 			Visible:    exitData.Visible,
+			TravelVerb: exitData.TravelVerb,
+			Door:       exitData.Door,
+			Closed:     exitData.Door && exitData.Closed,
+			Locked:     exitData.Door && exitData.Locked,
+			KeyID:      exitData.KeyID,
+			Condition:  exitData.Condition,
 			LastSaved:  time.Now(),
 			LastEdited: time.Now(),
 		}
@@ -164,7 +179,7 @@ func DisplayRooms(rooms map[int64]*Room) {
 }
 
 // WriteRoom stores a single room into the DynamoDB database.
-func (kp *KeyPair) WriteRoom(room *Room) error {
+func (kp *KeyPair) WriteRoom(ctx context.Context, room *Room) error {
 	if room == nil {
 		return fmt.Errorf("cannot write nil room")
 	}
@@ -174,13 +189,7 @@ func (kp *KeyPair) WriteRoom(room *Room) error {
 
 	// Write exits separately
 	for _, exit := range room.Exits {
-		exitData := ExitData{
-			ExitID:     exit.ExitID.String(),
-			Direction:  exit.Direction,
-			TargetRoom: exit.TargetRoom.RoomID,
-			Visible:    exit.Visible,
-		}
-		err := kp.Put("exits", exitData)
+		err := kp.Put(ctx, "exits", exit.toData())
 		if err != nil {
 			Logger.Error("Error writing exit data", "room_id", room.RoomID, "direction", exit.Direction, "error", err)
 			return fmt.Errorf("error writing exit data: %w", err)
@@ -189,21 +198,80 @@ func (kp *KeyPair) WriteRoom(room *Room) error {
 		exit.LastSaved = time.Now()
 	}
 
-	roomData := room.ToData()
-	err := kp.Put("rooms", roomData)
+	roomData := room.toData()
+	err := kp.putVersioned(ctx, "rooms", roomKey(room.RoomID), roomData, &roomData.Version)
 	if err != nil {
 		Logger.Error("Error writing room data", "room_id", room.RoomID, "error", err)
 		return fmt.Errorf("error writing room data: %w", err)
 	}
 
+	room.Version = roomData.Version
 	room.LastSaved = time.Now()
 
 	Logger.Info("Successfully wrote room and exits to database", "room_id", room.RoomID)
 	return nil
 }
 
+// roomKey is the database key of the room with the given ID.
+func roomKey(roomID int64) map[string]ddbtypes.AttributeValue {
+	return map[string]ddbtypes.AttributeValue{
+		"RoomID": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(roomID, 10)},
+	}
+}
+
+// toData converts an Exit into an ExitData struct for database storage.
+func (exit *Exit) toData() *ExitData {
+	return &ExitData{
+		ExitID:     exit.ExitID.String(),
+		Direction:  exit.Direction,
+		TargetRoom: exit.TargetRoom.RoomID,
+		Visible:    exit.Visible,
+		TravelVerb: exit.TravelVerb,
+		Door:       exit.Door,
+		Closed:     exit.Closed,
+		Locked:     exit.Locked,
+		KeyID:      exit.KeyID,
+		Condition:  exit.Condition,
+	}
+}
+
+// DeleteExit removes an exit's record from the DynamoDB table.
+func (kp *KeyPair) DeleteExit(ctx context.Context, exit *Exit) error {
+	key := map[string]ddbtypes.AttributeValue{
+		"ExitID": &ddbtypes.AttributeValueMemberS{Value: exit.ExitID.String()},
+	}
+
+	err := kp.Delete(ctx, "exits", key)
+	if err != nil {
+		Logger.Error("Error deleting exit data", "exit_id", exit.ExitID, "direction", exit.Direction, "error", err)
+		return fmt.Errorf("error deleting exit data: %w", err)
+	}
+
+	Logger.Info("Successfully deleted exit", "exit_id", exit.ExitID, "direction", exit.Direction)
+	return nil
+}
+
+// AddNewRoom creates a room with the ID following the highest room ID in use and adds it to the
+// world. The ID is chosen and the room added under the server mutex, so two builders creating
+// rooms at once are never given the same ID.
+func (s *Server) AddNewRoom(area string, title string, description string) *Room {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var highest int64
+	for roomID := range s.Rooms {
+		if roomID > highest {
+			highest = roomID
+		}
+	}
+
+	room := NewRoom(highest+1, area, title, description)
+	s.Rooms[room.RoomID] = room
+	return room
+}
+
 // SaveActiveRooms saves all active rooms to the database if they have been edited since the last save.
-func (s *Server) SaveActiveRooms() error {
+func (s *Server) SaveActiveRooms(ctx context.Context) error {
 	if s == nil {
 		return fmt.Errorf("server is nil")
 	}
@@ -213,30 +281,57 @@ func (s *Server) SaveActiveRooms() error {
 
 	Logger.Info("Starting to save active rooms...")
 
+	var edited []*Room
+	var exitData []interface{}
+	var roomWrites []versionedWrite
 	for roomID, room := range s.Rooms {
 		if room == nil {
 			Logger.Warn("Skipping nil room", "room_id", roomID)
 			continue
 		}
 
+		room.Mutex.Lock()
 		// Check if LastEdited is after LastSaved, skip if it is not
-		if !room.LastEdited.After(room.LastSaved) {
-			Logger.Info("Room not edited since last save, skipping", "room_id", roomID)
-			continue
+		if room.LastEdited.After(room.LastSaved) {
+			for _, exit := range room.Exits {
+				exitData = append(exitData, exit.toData())
+			}
+			data := room.toData()
+			roomWrites = append(roomWrites, versionedWrite{Key: roomKey(room.RoomID), Data: data, Version: &data.Version})
+			edited = append(edited, room)
 		}
+		room.Mutex.Unlock()
+	}
 
-		// Attempt to write the room to the database
-		if err := s.Database.WriteRoom(room); err != nil {
-			Logger.Error("Error saving room", "room_id", roomID, "error", err)
-			// Continue saving other rooms even if one fails
-		} else {
-			// Update LastSaved after successful save
-			room.LastSaved = time.Now()
-			Logger.Info("Successfully saved room", "room_id", roomID)
+	// Exits go first so a saved room never lists an exit that is missing from the database
+	if err := s.Database.BatchPut(ctx, "exits", exitData); err != nil {
+		Logger.Error("Error saving exits", "count", len(exitData), "error", err)
+		return fmt.Errorf("error saving exits: %w", err)
+	}
+	err := s.Database.batchPutVersioned(ctx, "rooms", roomWrites)
+
+	now := time.Now()
+	saved := 0
+	for i, room := range edited {
+		if !roomWrites[i].Written {
+			continue
 		}
+		saved++
+		room.Mutex.Lock()
+		room.Version = *roomWrites[i].Version
+		room.LastSaved = now
+		for _, exit := range room.Exits {
+			exit.LastSaved = now
+		}
+		room.Mutex.Unlock()
+	}
+
+	if err != nil {
+		Logger.Error("Error saving rooms", "count", len(roomWrites), "saved", saved, "error", err)
+		return fmt.Errorf("error saving rooms: %w", err)
 	}
 
-	Logger.Info("Finished saving active rooms")
+	Logger.Info("Finished saving active rooms", "saved", saved)
 	return nil
 }
 
@@ -250,6 +345,8 @@ func NewRoom(roomID int64, area string, title string, description string) *Room
 		Exits:       make(map[string]*Exit),
 		Characters:  make(map[uuid.UUID]*Character),
 		Items:       make(map[uuid.UUID]*Item),
+		NPCs:        make(map[uuid.UUID]*NPC),
+		Flags:       make(map[string]bool),
 		Mutex:       sync.Mutex{},
 		LastSaved:   time.Now(),
 		LastEdited:  time.Now(),
@@ -258,6 +355,65 @@ func NewRoom(roomID int64, area string, title string, description string) *Room
 	return room
 }
 
+// Room looks up a loaded room by ID. Builders add rooms while the game runs, so the world map
+// is only read under the server mutex; callers must not hold it.
+func (s *Server) Room(roomID int64) (*Room, bool) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	room, exists := s.Rooms[roomID]
+	return room, exists && room != nil
+}
+
+// VoidRoomID is the default room, "The Void", that holds characters whose own room is missing.
+// Players never walk into it, so it is left out of connectivity checks.
+const VoidRoomID int64 = 0
+
+// FallbackRoom returns the room used when a character's own room cannot be found.
+// It prefers the default room (ID 0) and then the configured start room.
+func (s *Server) FallbackRoom() (*Room, error) {
+	if room, exists := s.Room(VoidRoomID); exists {
+		return room, nil
+	}
+
+	Logger.Error("Default room not found, falling back to configured start room", "defaultRoomID", 0, "startRoomID", s.Settings().Game.StartRoom)
+
+	if room, exists := s.Room(s.Settings().Game.StartRoom); exists {
+		return room, nil
+	}
+
+	Logger.Error("Configured start room not found", "startRoomID", s.Settings().Game.StartRoom)
+	return nil, fmt.Errorf("neither the default room nor start room %d exist", s.Settings().Game.StartRoom)
+}
+
+// RespawnRoom returns the room a character who dies in the given area respawns in. The area's
+// own respawn room is used first, then the configured RespawnRooms, then the global start room.
+func (s *Server) RespawnRoom(area string) (*Room, error) {
+	s.Mutex.Lock()
+	definition := s.Areas[area]
+	s.Mutex.Unlock()
+
+	if definition != nil && definition.RespawnRoom != 0 {
+		if room, exists := s.Room(definition.RespawnRoom); exists {
+			return room, nil
+		}
+		Logger.Warn("Area respawn room not found", "area", area, "roomID", definition.RespawnRoom)
+	}
+
+	if roomID, configured := s.Settings().Game.RespawnRooms[area]; configured {
+		if room, exists := s.Room(roomID); exists {
+			return room, nil
+		}
+		Logger.Warn("Configured respawn room not found for area", "area", area, "roomID", roomID)
+	}
+
+	if room, exists := s.Room(s.Settings().Game.StartRoom); exists {
+		return room, nil
+	}
+
+	return s.FallbackRoom()
+}
+
 // AddExit adds an exit to the room's exits map.
 func (r *Room) AddExit(exit *Exit) {
 	r.Mutex.Lock()
@@ -283,8 +439,76 @@ func SendRoomMessage(r *Room, message string) {
 	defer r.Mutex.Unlock()
 
 	for _, character := range r.Characters {
-		character.Player.ToPlayer <- message
-		character.Player.ToPlayer <- character.Player.Prompt
+		if !character.IsActive() {
+			Logger.Warn("Skipping inactive character in room broadcast", "room_id", r.RoomID)
+			continue
+		}
+		if character.Player.Send(message) {
+			character.Player.Send(character.Player.RenderPrompt())
+		}
+	}
+}
+
+// ThirdPerson conjugates a travel verb for use in messages seen by others, e.g. "climb" becomes "climbs".
+func ThirdPerson(verb string) string {
+	for _, suffix := range []string{"s", "sh", "ch", "x", "z"} {
+		if strings.HasSuffix(verb, suffix) {
+			return verb + "es"
+		}
+	}
+	return verb + "s"
+}
+
+// Room event kinds that are coalesced when many characters move at once.
+const (
+	RoomEventArrival   = "arrival"
+	RoomEventDeparture = "departure"
+)
+
+// RoomEventWindow is how long further events of the same kind are buffered after one is sent.
+const RoomEventWindow = 500 * time.Millisecond
+
+// QueueRoomEvent sends a movement message to the room, coalescing bursts of the same kind.
+// The first event is sent immediately; any that follow within the window are buffered and
+// sent when it closes, either individually or as the summary if more than one arrived.
+func QueueRoomEvent(r *Room, kind, message, summary string) {
+	r.Mutex.Lock()
+	if r.Events == nil {
+		r.Events = make(map[string]*RoomEvent)
+	}
+
+	if event, pending := r.Events[kind]; pending {
+		event.Messages = append(event.Messages, message)
+		r.Mutex.Unlock()
+		return
+	}
+
+	r.Events[kind] = &RoomEvent{Summary: summary}
+	r.Mutex.Unlock()
+
+	SendRoomMessage(r, message)
+
+	time.AfterFunc(RoomEventWindow, func() { flushRoomEvent(r, kind) })
+}
+
+// flushRoomEvent closes the coalescing window for an event kind and sends anything buffered.
+func flushRoomEvent(r *Room, kind string) {
+	r.Mutex.Lock()
+	event := r.Events[kind]
+	delete(r.Events, kind)
+	r.Mutex.Unlock()
+
+	if event == nil {
+		return
+	}
+
+	switch len(event.Messages) {
+	case 0:
+	case 1:
+		SendRoomMessage(r, event.Messages[0])
+	default:
+		Logger.Info("Coalesced room events", "room_id", r.RoomID, "kind", kind, "count", len(event.Messages))
+		SendRoomMessage(r, event.Summary)
 	}
 }
 
@@ -301,11 +525,26 @@ func RoomInfo(r *Room, character *Character) string {
 
 	var roomInfo strings.Builder
 
+	// Without light nothing can be made out, not even the way out
+	if !character.CanSee() {
+		roomInfo.WriteString("\n\rIt is too dark to see anything here. You need a light.\n\r")
+		return roomInfo.String()
+	}
+
 	// Room Title and Description
-	roomInfo.WriteString(ApplyColor("bright_white", fmt.Sprintf("\n\r[%s]\n\r", r.Title)) + fmt.Sprintf("%s\n\r", r.Description))
+	roomInfo.WriteString(character.Player.Colorize(ColorTitle, fmt.Sprintf("\n\r[%s]\n\r", r.Title)) + fmt.Sprintf("%s\n\r", r.Description))
+
+	if r.HasFlag(RoomFlagOutdoor) {
+		if character.Server.IsNight() {
+			roomInfo.WriteString("Night has fallen.\n\r")
+		} else {
+			roomInfo.WriteString("It is daytime.\n\r")
+		}
+		roomInfo.WriteString(WeatherDescriptions[character.Server.CurrentWeather(r)] + "\n\r")
+	}
 
 	// Exits
-	visibleExits := getVisibleExits(r)
+	visibleExits := character.Player.colorizeList(ColorExits, getVisibleExits(r))
 	if len(visibleExits) == 0 {
 		roomInfo.WriteString("There are no visible exits.\n\r")
 	} else {
@@ -315,7 +554,7 @@ func RoomInfo(r *Room, character *Character) string {
 	}
 
 	// Characters in the room
-	otherCharacters := getOtherCharacters(r, character)
+	otherCharacters := character.Player.colorizeList(ColorCharacters, getOtherCharacters(r, character))
 	if len(otherCharacters) > 0 {
 		roomInfo.WriteString("Also here: ")
 		roomInfo.WriteString(strings.Join(otherCharacters, ", "))
@@ -329,7 +568,7 @@ func RoomInfo(r *Room, character *Character) string {
 	if len(items) > 0 {
 		roomInfo.WriteString("Items in the room:\n\r")
 		for _, item := range items {
-			roomInfo.WriteString(fmt.Sprintf("- %s\n\r", item))
+			roomInfo.WriteString(fmt.Sprintf("- %s\n\r", character.Player.Colorize(ColorItems, item)))
 		}
 	}
 
@@ -347,7 +586,9 @@ func getVisibleExits(r *Room) []string {
 
 	visibleExits := make([]string, 0, len(r.Exits))
 	for direction, exit := range r.Exits {
-		if exit.Visible {
+		if exit.Visible && exit.Closed {
+			visibleExits = append(visibleExits, direction+" (closed)")
+		} else if exit.Visible {
 			visibleExits = append(visibleExits, direction)
 		}
 	}
@@ -360,6 +601,11 @@ func (r *Room) ToData() *RoomData {
 	r.Mutex.Lock()
 	defer r.Mutex.Unlock()
 
+	return r.toData()
+}
+
+// toData converts a Room to RoomData; the caller must hold the room's mutex.
+func (r *Room) toData() *RoomData {
 	exitIDs := make([]string, 0, len(r.Exits))
 	for _, exit := range r.Exits {
 		exitIDs = append(exitIDs, exit.ExitID.String())
@@ -370,13 +616,28 @@ func (r *Room) ToData() *RoomData {
 		itemIDs = append(itemIDs, itemID.String())
 	}
 
+	flags := make([]string, 0, len(r.Flags))
+	for flag, set := range r.Flags {
+		if set {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Strings(flags)
+
 	return &RoomData{
-		RoomID:      r.RoomID,
-		Area:        r.Area,
-		Title:       r.Title,
-		Description: r.Description,
-		ExitIDs:     exitIDs,
-		ItemIDs:     itemIDs,
+		RoomID:          r.RoomID,
+		Area:            r.Area,
+		Title:           r.Title,
+		Description:     r.Description,
+		ExitIDs:         exitIDs,
+		ItemIDs:         itemIDs,
+		Flags:           flags,
+		Spawns:          r.Spawns,
+		SpawnPoints:     r.SpawnPoints,
+		Ambience:        r.Ambience,
+		AmbienceEnabled: r.AmbienceEnabled,
+		Light:           r.Light,
+		Version:         r.Version,
 	}
 }
 
@@ -390,6 +651,16 @@ func (r *Room) FromData(data *RoomData, exits map[string]*Exit, items map[string
 	r.Title = data.Title
 	r.Description = data.Description
 
+	r.Flags = make(map[string]bool, len(data.Flags))
+	for _, flag := range data.Flags {
+		r.Flags[flag] = true
+	}
+	r.Spawns = data.Spawns
+	r.SpawnPoints = data.SpawnPoints
+	r.Ambience = data.Ambience
+	r.AmbienceEnabled = data.AmbienceEnabled
+	r.Light = data.Light
+
 	r.Exits = make(map[string]*Exit)
 	for _, direction := range data.ExitIDs {
 		if exit, ok := exits[direction]; ok {
@@ -408,13 +679,13 @@ func (r *Room) FromData(data *RoomData, exits map[string]*Exit, items map[string
 }
 
 // LoadItemsForRoom loads all items for a specific room
-func (kp *KeyPair) LoadItemsForRoom(roomID int64) (map[uuid.UUID]*Item, error) {
+func (kp *KeyPair) LoadItemsForRoom(ctx context.Context, roomID int64) (map[uuid.UUID]*Item, error) {
 	items := make(map[uuid.UUID]*Item)
 
 	var itemsData []ItemData
 	// Assume we have a way to query items by room ID
-	err := kp.Query("items", "RoomID = :roomID", map[string]*dynamodb.AttributeValue{
-		":roomID": {N: aws.String(strconv.FormatInt(roomID, 10))},
+	err := kp.Query(ctx, "items", "RoomID = :roomID", map[string]ddbtypes.AttributeValue{
+		":roomID": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(roomID, 10)},
 	}, &itemsData)
 
 	if err != nil {
@@ -422,7 +693,7 @@ func (kp *KeyPair) LoadItemsForRoom(roomID int64) (map[uuid.UUID]*Item, error) {
 	}
 
 	for _, itemData := range itemsData {
-		item, err := kp.itemFromData(&itemData)
+		item, err := kp.itemFromData(ctx, &itemData)
 		if err != nil {
 			Logger.Error("Error creating item from data", "item_id", itemData.ItemID, "error", err)
 			continue
@@ -447,3 +718,128 @@ func (r *Room) CleanupNilItems() {
 
 	r.LastEdited = time.Now()
 }
+
+// AreaRooms returns the rooms in the given area, sorted by room ID.
+func (s *Server) AreaRooms(area string) []*Room {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	rooms := make([]*Room, 0)
+	for _, room := range s.Rooms {
+		if room != nil && room.Area == area {
+			rooms = append(rooms, room)
+		}
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].RoomID < rooms[j].RoomID
+	})
+
+	return rooms
+}
+
+// ConnectivityReport summarizes the reachability of the room graph from a starting room.
+type ConnectivityReport struct {
+	StartRoom   int64
+	TotalRooms  int
+	Reachable   int
+	Unreachable []int64
+	DeadEnds    []int64  // rooms with no exits leading anywhere
+	OneWay      []string // exits whose target has no exit leading back
+	Broken      []string // exits whose target room does not exist
+}
+
+// CheckConnectivity walks the live room graph breadth first from the start room. The void room
+// is not counted unless the walk starts there.
+func (s *Server) CheckConnectivity(startRoomID int64) (*ConnectivityReport, error) {
+	s.Mutex.Lock()
+	rooms := make(map[int64]*Room, len(s.Rooms))
+	for id, room := range s.Rooms {
+		if room != nil {
+			rooms[id] = room
+		}
+	}
+	s.Mutex.Unlock()
+
+	if _, exists := rooms[startRoomID]; !exists {
+		return nil, fmt.Errorf("start room %d does not exist", startRoomID)
+	}
+
+	// Snapshot the exits so the walk does not hold room locks
+	targets := make(map[int64]map[string]int64, len(rooms))
+	for id, room := range rooms {
+		room.Mutex.Lock()
+		exits := make(map[string]int64, len(room.Exits))
+		for direction, exit := range room.Exits {
+			if exit != nil && exit.TargetRoom != nil {
+				exits[direction] = exit.TargetRoom.RoomID
+			}
+		}
+		room.Mutex.Unlock()
+		targets[id] = exits
+	}
+
+	_, hasVoid := rooms[VoidRoomID]
+	skipVoid := hasVoid && startRoomID != VoidRoomID
+
+	report := &ConnectivityReport{StartRoom: startRoomID, TotalRooms: len(rooms)}
+	if skipVoid {
+		report.TotalRooms--
+	}
+
+	visited := map[int64]bool{startRoomID: true}
+	queue := []int64{startRoomID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, target := range targets[current] {
+			if _, exists := rooms[target]; exists && !visited[target] {
+				visited[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	report.Reachable = len(visited)
+	if skipVoid && visited[VoidRoomID] {
+		report.Reachable--
+	}
+
+	for id, exits := range targets {
+		if skipVoid && id == VoidRoomID {
+			continue
+		}
+		if !visited[id] {
+			report.Unreachable = append(report.Unreachable, id)
+		}
+
+		leadsAnywhere := false
+		for direction, target := range exits {
+			if _, exists := rooms[target]; !exists {
+				report.Broken = append(report.Broken, fmt.Sprintf("%d %s -> %d", id, direction, target))
+				continue
+			}
+			leadsAnywhere = true
+
+			returns := false
+			for _, back := range targets[target] {
+				if back == id {
+					returns = true
+					break
+				}
+			}
+			if !returns {
+				report.OneWay = append(report.OneWay, fmt.Sprintf("%d %s -> %d", id, direction, target))
+			}
+		}
+		if !leadsAnywhere {
+			report.DeadEnds = append(report.DeadEnds, id)
+		}
+	}
+
+	sort.Slice(report.Unreachable, func(i, j int) bool { return report.Unreachable[i] < report.Unreachable[j] })
+	sort.Slice(report.DeadEnds, func(i, j int) bool { return report.DeadEnds[i] < report.DeadEnds[j] })
+	sort.Strings(report.OneWay)
+	sort.Strings(report.Broken)
+
+	return report, nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFallbackRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		rooms   []int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "void room", rooms: []int64{0, 1}, want: 0},
+		{name: "start room without a void", rooms: []int64{1, 2}, want: 1},
+		{name: "neither room", rooms: []int64{2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.rooms...)
+
+			room, err := server.FallbackRoom()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FallbackRoom() = room %d, want an error", room.RoomID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FallbackRoom() error = %v", err)
+			}
+			if room.RoomID != tt.want {
+				t.Errorf("FallbackRoom() = room %d, want room %d", room.RoomID, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckConnectivitySkipsVoid(t *testing.T) {
+	tests := []struct {
+		name            string
+		start           int64
+		wantTotal       int
+		wantReachable   int
+		wantUnreachable []int64
+	}{
+		{name: "from the start room", start: 1, wantTotal: 3, wantReachable: 2, wantUnreachable: []int64{3}},
+		{name: "from the void", start: 0, wantTotal: 4, wantReachable: 1, wantUnreachable: []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, 0, 1, 2, 3)
+			linkRooms(server.Rooms[1], "north", server.Rooms[2])
+			linkRooms(server.Rooms[2], "south", server.Rooms[1])
+
+			report, err := server.CheckConnectivity(tt.start)
+			if err != nil {
+				t.Fatalf("CheckConnectivity() error = %v", err)
+			}
+			if report.TotalRooms != tt.wantTotal || report.Reachable != tt.wantReachable {
+				t.Errorf("CheckConnectivity() reached %d of %d rooms, want %d of %d", report.Reachable, report.TotalRooms, tt.wantReachable, tt.wantTotal)
+			}
+			if fmt.Sprint(report.Unreachable) != fmt.Sprint(tt.wantUnreachable) {
+				t.Errorf("Unreachable = %v, want %v", report.Unreachable, tt.wantUnreachable)
+			}
+		})
+	}
+}
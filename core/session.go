@@ -0,0 +1,194 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ways of handling a player selecting a character that another session is already playing,
+// set by Game.DuplicateLogin.
+const (
+	DuplicateLoginReject   = "reject"   // keep the session already playing and turn the new one away
+	DuplicateLoginTakeover = "takeover" // disconnect the session already playing and hand the character over
+)
+
+// sessionTakeoverTimeout bounds how long a new session waits for the one it replaces to let go.
+const sessionTakeoverTimeout = 10 * time.Second
+
+// ErrSessionActive is returned when a character is already being played by another session.
+var ErrSessionActive = errors.New("character is already in play from another session")
+
+// AddPlayer records a connected player, so account changes reach them before they choose a character.
+func (s *Server) AddPlayer(player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if s.Players == nil {
+		s.Players = make(map[*Player]bool)
+	}
+	s.Players[player] = true
+}
+
+// RemovePlayer forgets a player once their connection has closed and their data is saved.
+func (s *Server) RemovePlayer(player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	delete(s.Players, player)
+}
+
+// LivePlayers returns every in-memory player for the account: connected players, whether or not
+// they are playing a character, and the players of characters still in the world. Changes made
+// to the stored account must be applied to each, or their next WritePlayer undoes them.
+func (s *Server) LivePlayers(playerID string) []*Player {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	seen := make(map[*Player]bool)
+	for player := range s.Players {
+		if player.PlayerID == playerID {
+			seen[player] = true
+		}
+	}
+	for _, character := range s.Characters {
+		if character.Player != nil && character.Player.PlayerID == playerID {
+			seen[character.Player] = true
+		}
+	}
+
+	players := make([]*Player, 0, len(seen))
+	for player := range seen {
+		players = append(players, player)
+	}
+	return players
+}
+
+// Session records the player session in control of a character. The entry is made before the
+// character is loaded, so two sessions choosing the same character cannot both load it.
+type Session struct {
+	Player   *Player
+	Started  time.Time
+	released chan struct{} // closed when the session ends
+}
+
+// duplicateLoginPolicy returns the configured policy, rejecting new sessions by default.
+func (s *Server) duplicateLoginPolicy() string {
+	if s.Settings().Game.DuplicateLogin == DuplicateLoginTakeover {
+		return DuplicateLoginTakeover
+	}
+	return DuplicateLoginReject
+}
+
+// startSession makes player the session in control of the character, returning the session
+// already holding it when there is one.
+func (s *Server) startSession(characterID uuid.UUID, player *Player) *Session {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if session, exists := s.Sessions[characterID]; exists && session.Player != player {
+		return session
+	}
+	s.Sessions[characterID] = &Session{Player: player, Started: time.Now(), released: make(chan struct{})}
+	return nil
+}
+
+// EndSession releases the character from the player's session. It does nothing when another
+// session has since taken the character.
+func (s *Server) EndSession(characterID uuid.UUID, player *Player) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	session, exists := s.Sessions[characterID]
+	if !exists || session.Player != player {
+		return
+	}
+	delete(s.Sessions, characterID)
+	close(session.released)
+}
+
+// OpenSession gives the player control of one of their stored characters. A link-dead character
+// is reclaimed and any other character is loaded from the database. When another session is
+// playing the character, the new session is refused with ErrSessionActive or, under the takeover
+// policy, the other session is disconnected and the character handed over once it has let go.
+func (s *Server) OpenSession(characterID uuid.UUID, player *Player) (*Character, error) {
+	for tookOver := false; ; tookOver = true {
+		active := s.startSession(characterID, player)
+		if active == nil {
+			break
+		}
+		if tookOver || s.duplicateLoginPolicy() != DuplicateLoginTakeover {
+			Logger.Warn("Refused second session for character", "playerName", player.PlayerID, "characterID", characterID)
+			return nil, ErrSessionActive
+		}
+
+		Logger.Info("Taking over character from another session", "playerName", player.PlayerID, "characterID", characterID)
+		active.Player.Send("\n\rThis character has been taken over by a new connection. Goodbye.\n\r")
+		if active.Player.Connection != nil {
+			active.Player.Connection.Close()
+		}
+
+		select {
+		case <-active.released:
+		case <-time.After(sessionTakeoverTimeout):
+			return nil, fmt.Errorf("timed out waiting for the other session to close")
+		}
+	}
+
+	// Reconnecting to a link-dead character picks up where the player left off
+	if character := s.ReclaimLinkDead(characterID, player); character != nil {
+		return character, nil
+	}
+
+	character, err := s.Database.LoadCharacter(s.Context, characterID, player, s)
+	if err != nil {
+		s.EndSession(characterID, player)
+		return nil, err
+	}
+	return character, nil
+}
+
+// LinkDeadCharacter returns a character of the player's account that is waiting to be reclaimed.
+func (s *Server) LinkDeadCharacter(playerID string) *Character {
+	s.Mutex.Lock()
+	characters := make([]*Character, 0, len(s.Characters))
+	for _, character := range s.Characters {
+		characters = append(characters, character)
+	}
+	s.Mutex.Unlock()
+
+	for _, character := range characters {
+		character.Mutex.Lock()
+		waiting := character.LinkDead && character.Player != nil && character.Player.PlayerID == playerID
+		character.Mutex.Unlock()
+		if waiting {
+			return character
+		}
+	}
+	return nil
+}
+
+// ResumeLinkDead reattaches a player who logs back in within the link-dead window to the
+// character their dropped connection left in the world, skipping character selection. It
+// returns nil when the account has no character waiting.
+func (s *Server) ResumeLinkDead(player *Player) *Character {
+	waiting := s.LinkDeadCharacter(player.PlayerID)
+	if waiting == nil {
+		return nil
+	}
+	if active := s.startSession(waiting.ID, player); active != nil {
+		return nil
+	}
+
+	character := s.ReclaimLinkDead(waiting.ID, player)
+	if character == nil {
+		// The link-dead window closed while the session was being set up
+		s.EndSession(waiting.ID, player)
+		return nil
+	}
+
+	Logger.Info("Resumed link-dead character", "playerName", player.PlayerID, "characterName", character.Name)
+	return character
+}
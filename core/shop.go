@@ -0,0 +1,271 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StartingCoins is the purse a new character begins with.
+const StartingCoins = 50
+
+// SellRate is the fraction of an item's value a merchant pays for it.
+const SellRate = 0.5
+
+// findMerchant returns an NPC in the room with goods for sale, or nil if there is none.
+func findMerchant(room *Room) *NPC {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	for _, npc := range room.NPCs {
+		if len(npc.Sells) > 0 {
+			return npc
+		}
+	}
+	return nil
+}
+
+// merchantGoods returns the prototypes the merchant sells, skipping any that no longer exist.
+func merchantGoods(server *Server, merchant *NPC) []*Prototype {
+	goods := make([]*Prototype, 0, len(merchant.Sells))
+	for _, name := range merchant.Sells {
+		prototype := findPrototype(server, name)
+		if prototype == nil {
+			Logger.Warn("Merchant sells unknown prototype", "npcID", merchant.TemplateID, "prototype", name)
+			continue
+		}
+		goods = append(goods, prototype)
+	}
+	return goods
+}
+
+// SalePrice returns what a merchant pays for the item.
+func SalePrice(item *Item) uint64 {
+	return uint64(float64(item.Value) * SellRate)
+}
+
+// heldStack returns a stack of the prototype held by the character, or nil when they hold none.
+func (c *Character) heldStack(prototype *Prototype) *Item {
+	if !prototype.Stackable {
+		return nil
+	}
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	for _, item := range c.equipment().Held {
+		if item.PrototypeID == prototype.ID && item.Stackable {
+			return item
+		}
+	}
+	return nil
+}
+
+// GetCoins returns the number of coins the character is carrying.
+func (c *Character) GetCoins() uint64 {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	return c.Coins
+}
+
+// SpendCoins deducts the amount from the character's purse, refusing if they cannot afford it.
+func (c *Character) SpendCoins(amount uint64) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if c.Coins < amount {
+		return false
+	}
+	c.Coins -= amount
+	c.markDirty(DirtyInventory)
+	return true
+}
+
+// AddCoins adds the amount to the character's purse.
+func (c *Character) AddCoins(amount uint64) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	c.Coins += amount
+	c.markDirty(DirtyInventory)
+}
+
+func ExecuteListCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is listing a merchant's goods", "playerName", character.Player.PlayerID)
+
+	merchant := findMerchant(character.Room)
+	if merchant == nil {
+		character.Player.ToPlayer <- "\n\rThere is no one here to trade with.\n\r"
+		return false
+	}
+
+	goods := merchantGoods(character.Server, merchant)
+	if len(goods) == 0 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s has nothing for sale.\n\r", merchant.Name)
+		return false
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n\r%s has for sale:\n\r", merchant.Name))
+	for _, prototype := range goods {
+		output.WriteString(fmt.Sprintf("%-25s %6d coins\n\r", prototype.Name, prototype.Value))
+	}
+	output.WriteString(fmt.Sprintf("You have %d coins.\n\r", character.GetCoins()))
+
+	character.Player.ToPlayer <- output.String()
+	return false
+}
+
+func ExecuteBuyCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is buying an item", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: buy <item name>\n\r"
+		return false
+	}
+
+	merchant := findMerchant(character.Room)
+	if merchant == nil {
+		character.Player.ToPlayer <- "\n\rThere is no one here to trade with.\n\r"
+		return false
+	}
+
+	itemName := strings.ToLower(strings.Join(tokens[1:], " "))
+	var prototype *Prototype
+	for _, candidate := range merchantGoods(character.Server, merchant) {
+		if strings.Contains(strings.ToLower(candidate.Name), itemName) {
+			prototype = candidate
+			break
+		}
+	}
+
+	if prototype == nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s doesn't sell that.\n\r", merchant.Name)
+		return false
+	}
+
+	stack := character.heldStack(prototype)
+	if stack == nil && !character.CanHold(prototype.TwoHanded) {
+		character.Player.ToPlayer <- "\n\rYour hands are full. You need a free hand to take what you buy.\n\r"
+		return false
+	}
+
+	if !character.SpendCoins(prototype.Value) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s costs %d coins and you only have %d.\n\r", prototype.Name, prototype.Value, character.GetCoins())
+		return false
+	}
+
+	// Goods that stack are added to a stack already in hand while it has room
+	if stack != nil {
+		quantity := prototype.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if err := character.Server.ModifyItemQuantity(stack, int(quantity)); err == nil {
+			Logger.Info("Character bought item onto a stack", "characterName", character.Name, "itemID", stack.ID, "price", prototype.Value, "npcID", merchant.TemplateID)
+
+			sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s buys %s from %s.\n\r", character.Name, prototype.Name, merchant.Name), character)
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou buy %s from %s for %d coins and add it to your %s.\n\r", prototype.Name, merchant.Name, prototype.Value, stack.Name)
+			return false
+		}
+		if !character.CanHold(prototype.TwoHanded) {
+			character.AddCoins(prototype.Value)
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYour %s is full and you have no free hand to take more.\n\r", stack.Name)
+			return false
+		}
+	}
+
+	item, err := character.Server.CreateItemFromPrototype(prototype.ID)
+	if err != nil {
+		Logger.Error("Error creating purchased item", "prototypeID", prototype.ID, "error", err)
+		character.AddCoins(prototype.Value)
+		character.Player.ToPlayer <- "\n\rThe sale could not be completed.\n\r"
+		return false
+	}
+
+	if _, err := character.HoldItem(item); err != nil {
+		Logger.Error("Error holding purchased item", "itemID", item.ID, "error", err)
+		character.Room.DropItem(item)
+	}
+
+	if err := character.Server.SaveCharacter(character); err != nil {
+		Logger.Error("Error saving character after purchase", "characterName", character.Name, "error", err)
+	}
+
+	Logger.Info("Character bought item", "characterName", character.Name, "itemID", item.ID, "price", prototype.Value, "npcID", merchant.TemplateID)
+
+	sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s buys %s from %s.\n\r", character.Name, item.Name, merchant.Name), character)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou buy %s from %s for %d coins.\n\r", item.Name, merchant.Name, prototype.Value)
+	if character.IsEncumbered() {
+		character.Player.ToPlayer <- "\n\rYou are weighed down by your load and will move slowly.\n\r"
+	}
+	return false
+}
+
+func ExecuteSellCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is selling an item", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: sell <item name>\n\r"
+		return false
+	}
+
+	merchant := findMerchant(character.Room)
+	if merchant == nil {
+		character.Player.ToPlayer <- "\n\rThere is no one here to trade with.\n\r"
+		return false
+	}
+
+	// Only items in hand can be sold, so nothing worn or packed away is sold by accident
+	item := character.HeldItem(strings.Join(tokens[1:], " "))
+	if item == nil {
+		character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
+		return false
+	}
+
+	item.Mutex.Lock()
+	empty := len(item.Contents) == 0
+	item.Mutex.Unlock()
+	if !empty {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rEmpty %s before selling it.\n\r", item.Name)
+		return false
+	}
+
+	price := SalePrice(item)
+	if price == 0 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s isn't interested in %s.\n\r", merchant.Name, item.Name)
+		return false
+	}
+
+	// A stack is sold one at a time, anything else leaves the game with the sale
+	item.Mutex.Lock()
+	stacked := item.Stackable && item.Quantity > 1
+	item.Mutex.Unlock()
+
+	var err error
+	if stacked {
+		err = character.Server.ModifyItemQuantity(item, -1)
+	} else {
+		err = character.Server.DestroyItem(item)
+	}
+	if err != nil {
+		Logger.Error("Error removing sold item", "itemID", item.ID, "error", err)
+		character.Player.ToPlayer <- "\n\rThe sale could not be completed.\n\r"
+		return false
+	}
+
+	character.AddCoins(price)
+
+	if err := character.Server.SaveCharacter(character); err != nil {
+		Logger.Error("Error saving character after sale", "characterName", character.Name, "error", err)
+	}
+
+	Logger.Info("Character sold item", "characterName", character.Name, "itemID", item.ID, "price", price, "npcID", merchant.TemplateID)
+
+	sendToRoomExcept(character.Room, fmt.Sprintf("\n\r%s sells %s to %s.\n\r", character.Name, item.Name, merchant.Name), character)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou sell %s to %s for %d coins.\n\r", item.Name, merchant.Name, price)
+	return false
+}
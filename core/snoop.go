@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AddSnooper starts mirroring the character's output to the snooper.
+func (c *Character) AddSnooper(snooper *Character) {
+	c.SnoopMutex.Lock()
+	if c.Snoopers == nil {
+		c.Snoopers = make(map[uuid.UUID]*Character)
+	}
+	c.Snoopers[snooper.ID] = snooper
+	c.SnoopMutex.Unlock()
+
+	snooper.SnoopMutex.Lock()
+	snooper.Snooping = c
+	snooper.SnoopMutex.Unlock()
+}
+
+// RemoveSnooper stops mirroring the character's output to the snooper.
+func (c *Character) RemoveSnooper(snooper *Character) {
+	c.SnoopMutex.Lock()
+	delete(c.Snoopers, snooper.ID)
+	c.SnoopMutex.Unlock()
+
+	snooper.SnoopMutex.Lock()
+	if snooper.Snooping == c {
+		snooper.Snooping = nil
+	}
+	snooper.SnoopMutex.Unlock()
+}
+
+// SnoopTarget returns the character being snooped, if any.
+func (c *Character) SnoopTarget() *Character {
+	c.SnoopMutex.Lock()
+	defer c.SnoopMutex.Unlock()
+
+	return c.Snooping
+}
+
+// snooperList returns a copy of the characters snooping on this character.
+func (c *Character) snooperList() []*Character {
+	c.SnoopMutex.Lock()
+	defer c.SnoopMutex.Unlock()
+
+	snoopers := make([]*Character, 0, len(c.Snoopers))
+	for _, snooper := range c.Snoopers {
+		snoopers = append(snoopers, snooper)
+	}
+	return snoopers
+}
+
+// EndSnoops ends any snooping by or on the character, used when they leave the game.
+func (c *Character) EndSnoops() {
+	if target := c.SnoopTarget(); target != nil {
+		target.RemoveSnooper(c)
+	}
+
+	for _, snooper := range c.snooperList() {
+		c.RemoveSnooper(snooper)
+		snooper.Player.Send(fmt.Sprintf("\n\r%s has left the game, snoop ended.\n\r", c.Name))
+		Logger.Info("Snoop ended by disconnect", "snooper", snooper.Name, "target", c.Name)
+	}
+}
+
+// mirrorToSnoopers sends a tagged copy of an output message to anyone snooping on the player's character.
+// Only output is mirrored; typed input, including passwords, is echoed directly and never reaches snoopers.
+func (p *Player) mirrorToSnoopers(message string) {
+	if p.isPrompt(message) {
+		return
+	}
+
+	p.Mutex.Lock()
+	character := p.Character
+	p.Mutex.Unlock()
+
+	if character == nil {
+		return
+	}
+
+	tag := fmt.Sprintf("[%s]", character.Name)
+	for _, snooper := range character.snooperList() {
+		snooper.Player.Send(fmt.Sprintf("\n\r%s %s", snooper.Player.Colorize(ColorSnoop, tag), strings.TrimLeft(message, "\n\r")))
+	}
+}
+
+func ExecuteSnoopCommand(character *Character, tokens []string) bool {
+
+	if len(tokens) != 2 {
+		character.Player.ToPlayer <- "\n\rUsage: snoop <character|off>\n\r"
+		return false
+	}
+
+	if current := character.SnoopTarget(); current != nil {
+		current.RemoveSnooper(character)
+		Logger.Info("Admin stopped snooping", "playerName", character.Player.PlayerID, "target", current.Name)
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou stop snooping on %s.\n\r", current.Name)
+	} else if strings.EqualFold(tokens[1], "off") {
+		character.Player.ToPlayer <- "\n\rYou are not snooping on anyone.\n\r"
+	}
+
+	if strings.EqualFold(tokens[1], "off") {
+		return false
+	}
+
+	target := character.Server.FindOnlineCharacter(tokens[1])
+	if target == nil || !target.IsActive() {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is not online.\n\r", tokens[1])
+		return false
+	}
+
+	if target == character {
+		character.Player.ToPlayer <- "\n\rYou cannot snoop on yourself.\n\r"
+		return false
+	}
+
+	// Refuse chains so mirrored output can never loop back to its source
+	if target.SnoopTarget() != nil {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is already snooping on someone.\n\r", target.Name)
+		return false
+	}
+
+	target.AddSnooper(character)
+	Logger.Info("Admin started snooping", "playerName", character.Player.PlayerID, "target", target.Name)
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rYou begin snooping on %s. Use 'snoop off' to stop.\n\r", target.Name)
+	return false
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Social describes the messages of a canned emote. In each message %[1]s is the actor's name
+// and %[2]s is the target's name.
+type Social struct {
+	Alone        string // shown to the actor when there is no target
+	AloneRoom    string // shown to the room when there is no target
+	Targeted     string // shown to the actor when there is a target
+	TargetedYou  string // shown to the target
+	TargetedRoom string // shown to everyone else in the room
+}
+
+// Socials holds the canned emotes, each of which is also a command.
+var Socials = map[string]Social{
+	"smile": {
+		Alone:        "You smile.",
+		AloneRoom:    "%[1]s smiles.",
+		Targeted:     "You smile at %[2]s.",
+		TargetedYou:  "%[1]s smiles at you.",
+		TargetedRoom: "%[1]s smiles at %[2]s.",
+	},
+	"wave": {
+		Alone:        "You wave.",
+		AloneRoom:    "%[1]s waves.",
+		Targeted:     "You wave to %[2]s.",
+		TargetedYou:  "%[1]s waves to you.",
+		TargetedRoom: "%[1]s waves to %[2]s.",
+	},
+	"bow": {
+		Alone:        "You bow deeply.",
+		AloneRoom:    "%[1]s bows deeply.",
+		Targeted:     "You bow before %[2]s.",
+		TargetedYou:  "%[1]s bows before you.",
+		TargetedRoom: "%[1]s bows before %[2]s.",
+	},
+	"nod": {
+		Alone:        "You nod.",
+		AloneRoom:    "%[1]s nods.",
+		Targeted:     "You nod to %[2]s.",
+		TargetedYou:  "%[1]s nods to you.",
+		TargetedRoom: "%[1]s nods to %[2]s.",
+	},
+}
+
+// Every social is dispatched through the same handler, so register them from the table.
+func init() {
+	for name := range Socials {
+		CommandHandlers[name] = ExecuteSocialCommand
+	}
+}
+
+// SocialNames returns the names of the canned emotes in alphabetical order.
+func SocialNames() []string {
+	names := make([]string, 0, len(Socials))
+	for name := range Socials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ExecuteSocialCommand(character *Character, tokens []string) bool {
+
+	verb := strings.ToLower(tokens[0])
+	social, exists := Socials[verb]
+	if !exists {
+		character.Player.ToPlayer <- "\n\rCommand not yet implemented or recognized.\n\r"
+		return false
+	}
+
+	Logger.Info("Player is using a social", "playerName", character.Player.PlayerID, "social", verb)
+
+	room := character.Room
+
+	if len(tokens) < 2 {
+		sendToRoomExcept(room, fmt.Sprintf("\n\r"+social.AloneRoom+"\n\r", character.Name), character)
+		character.Player.ToPlayer <- "\n\r" + social.Alone + "\n\r"
+		return false
+	}
+
+	targetName := strings.Join(tokens[1:], " ")
+	var target *Character
+
+	room.Mutex.Lock()
+	for _, c := range room.Characters {
+		if c != character && c.IsActive() && strings.EqualFold(c.Name, targetName) {
+			target = c
+			break
+		}
+	}
+	room.Mutex.Unlock()
+
+	// NPCs can be the target of a social but have no player to tell
+	name := ""
+	if target != nil {
+		name = target.Name
+	} else if npc := room.FindNPC(targetName); npc != nil {
+		name = npc.Name
+	} else {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", targetName)
+		return false
+	}
+
+	if target != nil && target.Player.SendFrom(character, fmt.Sprintf("\n\r"+social.TargetedYou+"\n\r", character.Name, name)) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+
+	sendToRoomExcept(room, fmt.Sprintf("\n\r"+social.TargetedRoom+"\n\r", character.Name, name), character, target)
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r"+social.Targeted+"\n\r", character.Name, name)
+	return false
+}
+
+func ExecuteEmoteCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is emoting", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- "\n\rUsage: emote <text>\n\r"
+		return false
+	}
+
+	action := strings.Join(tokens[1:], " ")
+	rawMessage := fmt.Sprintf("\n\r%s %s\n\r", character.Name, action)
+	filteredMessage := fmt.Sprintf("\n\r%s %s\n\r", character.Name, FilterProfanity(character.Server, action))
+
+	room := character.Room
+	room.Mutex.Lock()
+	others := make([]*Character, 0, len(room.Characters))
+	for _, c := range room.Characters {
+		if c != character && c.IsActive() {
+			others = append(others, c)
+		}
+	}
+	room.Mutex.Unlock()
+
+	for _, c := range others {
+		if c.Player.SendFrom(character, c.Player.MessageFor(rawMessage, filteredMessage)) {
+			c.Player.Send(c.Player.RenderPrompt())
+		}
+	}
+
+	character.Player.ToPlayer <- rawMessage
+	return false
+}
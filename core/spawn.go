@@ -0,0 +1,201 @@
+package core
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpawnSweepInterval is how often spawn points are checked for missing items and NPCs.
+const SpawnSweepInterval = 10 * time.Second
+
+// Kinds of thing a spawn point can create.
+const (
+	SpawnItem = "item"
+	SpawnNPC  = "npc"
+)
+
+// SpawnPoint keeps up to MaxCount items or NPCs in a room, creating more once RespawnSeconds
+// have passed since it last spawned.
+type SpawnPoint struct {
+	Kind           string `json:"kind" dynamodbav:"Kind"`
+	ID             string `json:"id" dynamodbav:"ID"` // prototype name or ID for items, NPC ID for NPCs
+	MaxCount       int    `json:"maxCount" dynamodbav:"MaxCount"`
+	RespawnSeconds int    `json:"respawnSeconds" dynamodbav:"RespawnSeconds"`
+	Condition      string `json:"condition,omitempty" dynamodbav:"Condition,omitempty"` // day phase or weather spawning is limited to
+
+	LastSpawn time.Time          `json:"-" dynamodbav:"-"`
+	Instances map[uuid.UUID]bool `json:"-" dynamodbav:"-"` // items and NPCs this spawn point created
+}
+
+// placeNPC creates an NPC from its definition and puts it in the room. The caller adds it to s.NPCs.
+func placeNPC(room *Room, template *NPCData) *NPC {
+	npc := NewNPC(template)
+	npc.Room = room
+	npc.HomeArea = room.Area
+
+	room.Mutex.Lock()
+	if room.NPCs == nil {
+		room.NPCs = make(map[uuid.UUID]*NPC)
+	}
+	room.NPCs[npc.ID] = npc
+	room.Mutex.Unlock()
+
+	return npc
+}
+
+// SpawnLoop refills the spawn points of every room every SpawnSweepInterval until the server context is cancelled.
+func SpawnLoop(s *Server) {
+	Logger.Info("Starting spawn loop", "sweepInterval", SpawnSweepInterval)
+
+	ticker := time.NewTicker(SpawnSweepInterval)
+	defer ticker.Stop()
+
+	s.RefillSpawnPoints()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.RefillSpawnPoints()
+		case <-s.Context.Done():
+			Logger.Info("Stopping spawn loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// RefillSpawnPoints creates items and NPCs for every spawn point that is below its maximum
+// and whose respawn interval has passed.
+func (s *Server) RefillSpawnPoints() {
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0, len(s.Rooms))
+	for _, room := range s.Rooms {
+		if room != nil && len(room.SpawnPoints) > 0 {
+			rooms = append(rooms, room)
+		}
+	}
+	s.Mutex.Unlock()
+
+	now := time.Now()
+	for _, room := range rooms {
+		room.Mutex.Lock()
+		points := append([]*SpawnPoint(nil), room.SpawnPoints...)
+		room.Mutex.Unlock()
+
+		for _, point := range points {
+			if point.Instances == nil {
+				s.adoptSpawnedItems(room, point)
+			}
+			if now.Before(point.LastSpawn.Add(s.spawnInterval(room, point))) || !s.ConditionMet(room, point.Condition) {
+				continue
+			}
+
+			missing := point.MaxCount - s.liveInstances(room, point)
+			if missing <= 0 {
+				continue
+			}
+
+			for i := 0; i < missing; i++ {
+				if !s.spawnFrom(room, point) {
+					break
+				}
+			}
+			point.LastSpawn = now
+		}
+	}
+}
+
+// adoptSpawnedItems claims items of the spawn point's prototype already lying in the room, such as
+// those saved with the room before a restart, so they are not spawned a second time.
+func (s *Server) adoptSpawnedItems(room *Room, point *SpawnPoint) {
+	point.Instances = make(map[uuid.UUID]bool)
+	if point.Kind != SpawnItem {
+		return
+	}
+
+	prototype := findPrototype(s, point.ID)
+	if prototype == nil {
+		return
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	for id, item := range room.Items {
+		if len(point.Instances) >= point.MaxCount {
+			break
+		}
+		if item.PrototypeID == prototype.ID && item.DroppedAt.IsZero() {
+			point.Instances[id] = true
+		}
+	}
+}
+
+// liveInstances counts the spawn point's creations that remain. Items count while they are still
+// lying in the room, so taking one lets it respawn, while NPCs count for as long as they exist.
+func (s *Server) liveInstances(room *Room, point *SpawnPoint) int {
+	s.Mutex.Lock()
+	room.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	defer room.Mutex.Unlock()
+
+	for id := range point.Instances {
+		var live bool
+		switch point.Kind {
+		case SpawnItem:
+			_, live = room.Items[id]
+		case SpawnNPC:
+			_, live = s.NPCs[id]
+		}
+		if !live {
+			delete(point.Instances, id)
+		}
+	}
+	return len(point.Instances)
+}
+
+// spawnFrom creates one item or NPC for the spawn point, returning false if it could not.
+func (s *Server) spawnFrom(room *Room, point *SpawnPoint) bool {
+	switch point.Kind {
+	case SpawnItem:
+		prototype := findPrototype(s, point.ID)
+		if prototype == nil {
+			Logger.Warn("Prototype not found for spawn point", "room_id", room.RoomID, "prototype", point.ID)
+			return false
+		}
+		item, err := s.CreateItemFromPrototype(prototype.ID)
+		if err != nil {
+			Logger.Error("Error spawning item", "room_id", room.RoomID, "prototypeID", prototype.ID, "error", err)
+			return false
+		}
+		room.AddItem(item)
+		point.Instances[item.ID] = true
+		Logger.Info("Spawned item", "itemName", item.Name, "itemID", item.ID, "room_id", room.RoomID)
+
+	case SpawnNPC:
+		s.Mutex.Lock()
+		template, exists := s.NPCTemplates[point.ID]
+		s.Mutex.Unlock()
+		if !exists {
+			Logger.Warn("NPC template not found for spawn point", "room_id", room.RoomID, "npcID", point.ID)
+			return false
+		}
+		npc := placeNPC(room, template)
+
+		s.Mutex.Lock()
+		if s.NPCs == nil {
+			s.NPCs = make(map[uuid.UUID]*NPC)
+		}
+		s.NPCs[npc.ID] = npc
+		s.Mutex.Unlock()
+
+		point.Instances[npc.ID] = true
+		Logger.Info("Spawned NPC", "name", npc.Name, "npcID", point.ID, "room_id", room.RoomID)
+
+	default:
+		Logger.Warn("Unknown spawn point kind", "room_id", room.RoomID, "kind", point.Kind)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,315 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SpellDifficulty is the score a caster is challenged against when casting a beneficial spell.
+const SpellDifficulty = 2.0
+
+// Spell describes an ability that can be used with the cast command.
+type Spell struct {
+	Name      string
+	Attribute string        // attribute added to the skill when casting
+	Skill     string        // ability that powers the spell
+	Cost      float64       // essence spent on each cast
+	Cooldown  time.Duration // time before the caster can cast the spell again
+	Harmful   bool          // harmful spells need another target, who resists with Presence and Dodge
+	SelfOnly  bool          // the spell can only be cast on the caster
+	// Effect applies the spell to the target. Power is the casting outcome, at least 1.
+	Effect func(caster, target *Character, power float64)
+}
+
+// Spells holds every spell that can be cast, keyed by name.
+var Spells = map[string]*Spell{
+	"heal": {
+		Name:      "heal",
+		Attribute: "Intelligence",
+		Skill:     "FirstAid",
+		Cost:      1,
+		Cooldown:  10 * time.Second,
+		Effect:    healEffect,
+	},
+	"bolt": {
+		Name:      "bolt",
+		Attribute: "Intelligence",
+		Skill:     "Arcane",
+		Cost:      2,
+		Cooldown:  5 * time.Second,
+		Harmful:   true,
+		Effect:    boltEffect,
+	},
+	"focus": {
+		Name:      "focus",
+		Attribute: "Presence",
+		Skill:     "Mythos",
+		Cost:      0,
+		Cooldown:  60 * time.Second,
+		SelfOnly:  true,
+		Effect:    focusEffect,
+	},
+	"ward": {
+		Name:      "ward",
+		Attribute: "Presence",
+		Skill:     "Arcane",
+		Cost:      1,
+		Cooldown:  60 * time.Second,
+		Effect:    wardEffect,
+	},
+	"venom": {
+		Name:      "venom",
+		Attribute: "Intelligence",
+		Skill:     "Arcane",
+		Cost:      2,
+		Cooldown:  15 * time.Second,
+		Harmful:   true,
+		Effect:    venomEffect,
+	},
+	"renew": {
+		Name:      "renew",
+		Attribute: "Presence",
+		Skill:     "FirstAid",
+		Cost:      2,
+		Cooldown:  30 * time.Second,
+		Effect:    renewEffect,
+	},
+}
+
+// SpellNames returns the names of the spells in alphabetical order.
+func SpellNames() []string {
+	names := make([]string, 0, len(Spells))
+	for name := range Spells {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// spellPower limits a casting outcome to the range spells scale over.
+func spellPower(outcome float64) float64 {
+	return math.Min(outcome, MaxHitMultiplier)
+}
+
+// announce tells the caster, the target and everyone else in the room what a spell did.
+// The target message is skipped when the caster targets themselves.
+func announce(caster, target *Character, toCaster, toTarget, toRoom string) {
+	caster.Player.Send(caster.Player.Colorize(ColorCombat, "\n\r"+toCaster+"\n\r"))
+	if target != caster && target.Player.Send(target.Player.Colorize(ColorCombat, "\n\r"+toTarget+"\n\r")) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+	sendColoredToRoomExcept(caster.Room, ColorCombat, "\n\r"+toRoom+"\n\r", caster, target)
+}
+
+func healEffect(caster, target *Character, power float64) {
+	amount := 2 * spellPower(power)
+
+	target.Mutex.Lock()
+	target.Health = math.Min(target.Health+amount, target.MaxHealth)
+	target.markDirty(DirtyStats)
+	target.Mutex.Unlock()
+
+	if target == caster {
+		announce(caster, target, "Warmth spreads through your wounds.", "", fmt.Sprintf("%s's wounds close before your eyes.", caster.Name))
+		return
+	}
+	announce(caster, target,
+		fmt.Sprintf("You lay your hands on %s and their wounds close.", target.Name),
+		fmt.Sprintf("%s lays their hands on you and your wounds close.", caster.Name),
+		fmt.Sprintf("%s lays their hands on %s, whose wounds close.", caster.Name, target.Name))
+}
+
+func boltEffect(caster, target *Character, power float64) {
+	damage := math.Max(1+2*spellPower(power)-target.Absorb(), 0)
+
+	target.Mutex.Lock()
+	target.Health -= damage
+	health := target.Health
+	target.markDirty(DirtyStats)
+	target.Mutex.Unlock()
+
+	announce(caster, target,
+		fmt.Sprintf("A bolt of force leaps from your hand and strikes %s for %.1f damage.", target.Name, damage),
+		fmt.Sprintf("A bolt of force from %s strikes you for %.1f damage.", caster.Name, damage),
+		fmt.Sprintf("A bolt of force from %s strikes %s.", caster.Name, target.Name))
+
+	if health <= 0 {
+		Slay(caster, target)
+	}
+}
+
+func focusEffect(caster, target *Character, power float64) {
+	caster.Mutex.Lock()
+	caster.Essence = math.Min(caster.Essence+spellPower(power), caster.MaxEssence)
+	caster.markDirty(DirtyStats)
+	caster.Mutex.Unlock()
+
+	announce(caster, target, "You clear your mind and your essence returns.", "", fmt.Sprintf("%s closes their eyes in deep concentration.", caster.Name))
+}
+
+func wardEffect(caster, target *Character, power float64) {
+	if target == caster {
+		announce(caster, target, "You weave a ward around yourself.", "", fmt.Sprintf("A shimmering ward surrounds %s.", caster.Name))
+	} else {
+		announce(caster, target,
+			fmt.Sprintf("You weave a shimmering ward around %s.", target.Name),
+			fmt.Sprintf("%s weaves a shimmering ward around you.", caster.Name),
+			fmt.Sprintf("%s weaves a shimmering ward around %s.", caster.Name, target.Name))
+	}
+	applyEffect(target, "warded", power)
+}
+
+func venomEffect(caster, target *Character, power float64) {
+	announce(caster, target,
+		fmt.Sprintf("Green mist streams from your fingers and envelops %s.", target.Name),
+		fmt.Sprintf("Green mist from %s envelops you.", caster.Name),
+		fmt.Sprintf("Green mist from %s envelops %s.", caster.Name, target.Name))
+	applyEffect(target, "poisoned", power)
+}
+
+func renewEffect(caster, target *Character, power float64) {
+	if target == caster {
+		announce(caster, target, "You call on your inner strength.", "", fmt.Sprintf("%s glows with a soft light.", caster.Name))
+	} else {
+		announce(caster, target,
+			fmt.Sprintf("You touch %s, who begins to glow with a soft light.", target.Name),
+			fmt.Sprintf("%s touches you and you begin to glow with a soft light.", caster.Name),
+			fmt.Sprintf("%s touches %s, who begins to glow with a soft light.", caster.Name, target.Name))
+	}
+	applyEffect(target, "regenerating", power)
+}
+
+// applyEffect applies an effect from a spell, lasting longer the stronger the casting.
+func applyEffect(target *Character, name string, power float64) {
+	duration := time.Duration(float64(Effects[name].Duration) * spellPower(power))
+	if err := target.ApplyEffect(name, duration); err != nil {
+		Logger.Error("Error applying spell effect", "characterName", target.Name, "effect", name, "error", err)
+	}
+}
+
+// CooldownRemaining returns how long until the character may cast the spell again.
+func (c *Character) CooldownRemaining(spell string) time.Duration {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	remaining := time.Until(c.Cooldowns[spell])
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// startCooldown records that the character has just cast the spell.
+func (c *Character) startCooldown(spell *Spell) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if c.Cooldowns == nil {
+		c.Cooldowns = make(map[string]time.Time)
+	}
+	c.Cooldowns[spell.Name] = time.Now().Add(spell.Cooldown)
+}
+
+// spendEssence deducts the cost from the character's essence, refusing if they have too little.
+func (c *Character) spendEssence(cost float64) bool {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	if c.Essence < cost {
+		return false
+	}
+	c.Essence -= cost
+	c.markDirty(DirtyStats)
+	return true
+}
+
+// findCharacterInRoom returns the active character in the room with the given name, ignoring case.
+func findCharacterInRoom(room *Room, name string) *Character {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+
+	for _, c := range room.Characters {
+		if c.IsActive() && strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func ExecuteCastCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is casting a spell", "playerName", character.Player.PlayerID)
+
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rUsage: cast <spell> [target]\n\rSpells: %s\n\r", strings.Join(SpellNames(), ", "))
+		return false
+	}
+
+	spell, exists := Spells[strings.ToLower(tokens[1])]
+	if !exists {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't know a spell called %s.\n\rSpells: %s\n\r", tokens[1], strings.Join(SpellNames(), ", "))
+		return false
+	}
+
+	// Spells other than harmful ones default to the caster
+	target := character
+	if len(tokens) > 2 {
+		target = findCharacterInRoom(character.Room, strings.Join(tokens[2:], " "))
+		if target == nil {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", strings.Join(tokens[2:], " "))
+			return false
+		}
+	}
+
+	switch {
+	case spell.SelfOnly && target != character:
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou can only cast %s on yourself.\n\r", spell.Name)
+		return false
+	case spell.Harmful && target == character:
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rWho do you want to cast %s at?\n\r", spell.Name)
+		return false
+	case spell.Harmful && target.IsProtected():
+		character.Player.ToPlayer <- fmt.Sprintf("\n\r%s is protected and cannot be harmed yet.\n\r", target.Name)
+		return false
+	case spell.Harmful && !character.Server.AllowsPvP(character.Room):
+		character.Player.ToPlayer <- "\n\rFighting other adventurers is not allowed here.\n\r"
+		return false
+	}
+
+	if remaining := character.CooldownRemaining(spell.Name); remaining > 0 {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou must wait %d more seconds before casting %s again.\n\r", int(math.Ceil(remaining.Seconds())), spell.Name)
+		return false
+	}
+
+	if !character.spendEssence(spell.Cost) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou need %.0f essence to cast %s.\n\r", spell.Cost, spell.Name)
+		return false
+	}
+	character.startCooldown(spell)
+
+	// Harmful spells are resisted by the target, the rest only need to overcome the spell's difficulty
+	casterScore := character.Score(spell.Attribute) + character.Score(spell.Skill)
+	resistance := SpellDifficulty
+	if spell.Harmful {
+		resistance = target.Score("Presence") + target.Score("Dodge")
+		Provoke(character, target)
+	}
+
+	outcome := Challenge(casterScore, resistance, character.Server.Settings().Game.Balance)
+
+	Logger.Info("Spell cast", "caster", character.Name, "target", target.Name, "spell", spell.Name, "outcome", outcome)
+
+	if outcome < 1 {
+		announce(character, target,
+			fmt.Sprintf("Your %s spell fizzles.", spell.Name),
+			fmt.Sprintf("%s's spell fizzles before it reaches you.", character.Name),
+			fmt.Sprintf("%s's spell fizzles.", character.Name))
+		return false
+	}
+
+	spell.Effect(character, target, outcome)
+	return false
+}
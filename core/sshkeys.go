@@ -0,0 +1,222 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// MaxSSHKeys is the most public keys a player can register.
+const MaxSSHKeys = 10
+
+// AuthorizePublicKey reports whether the key is registered to the player, so they can log in
+// without a password. A matching key is only accepted while the Cognito account is active, so
+// disabling an account or requiring a password reset also stops key logins.
+func (s *Server) AuthorizePublicKey(ctx context.Context, playerID string, key ssh.PublicKey) bool {
+	player, err := s.Database.ReadPlayer(ctx, playerID)
+	if err != nil {
+		return false
+	}
+
+	marshaled := key.Marshal()
+	registered := false
+	for _, line := range player.SSHKeys {
+		stored, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			Logger.Warn("Skipping unreadable SSH key", "playerName", playerID, "error", err)
+			continue
+		}
+		if bytes.Equal(marshaled, stored.Marshal()) {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return false
+	}
+
+	active, err := AccountActive(ctx, playerID, s.Config)
+	if err != nil {
+		NetworkLog.Error("Could not check account status for key login", "playerName", playerID, "error", err)
+		return false
+	}
+	if !active {
+		NetworkLog.Warn("Refused key login for an inactive account", "playerName", playerID)
+	}
+	return active
+}
+
+// RevokeSSHKeys removes every key registered to a player, from the stored record and from any
+// session they have open, so that a password change or reset also ends key logins. It returns
+// how many keys were removed from the stored record.
+func (s *Server) RevokeSSHKeys(ctx context.Context, playerID string) (int, error) {
+	// Clear open sessions first, so that their next save cannot restore the keys
+	for _, player := range s.LivePlayers(playerID) {
+		player.Mutex.Lock()
+		player.SSHKeys = nil
+		player.Mutex.Unlock()
+	}
+
+	key := map[string]ddbtypes.AttributeValue{
+		"PlayerID": &ddbtypes.AttributeValueMemberS{Value: playerID},
+	}
+
+	var pd PlayerData
+	err := s.Database.Get(ctx, "players", key, &pd)
+	if errors.Is(err, ErrItemNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading player %s: %w", playerID, err)
+	}
+	if len(pd.SSHKeys) == 0 {
+		return 0, nil
+	}
+
+	if err := s.Database.Update(ctx, "players", key, nil, []string{"SSHKeys"}); err != nil {
+		return 0, fmt.Errorf("error removing SSH keys of player %s: %w", playerID, err)
+	}
+
+	Logger.Info("Revoked SSH keys", "playerName", playerID, "count", len(pd.SSHKeys))
+	return len(pd.SSHKeys), nil
+}
+
+// parseSSHKey reads a key in authorized_keys format and returns it with its comment.
+func parseSSHKey(line string) (ssh.PublicKey, string, error) {
+	key, comment, _, rest, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return nil, "", fmt.Errorf("that is not a valid public key, paste a line such as the contents of ~/.ssh/id_ed25519.pub")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, "", fmt.Errorf("add one key at a time")
+	}
+	return key, comment, nil
+}
+
+// listSSHKeys returns the player's keys, numbered, with their fingerprints.
+func (p *Player) listSSHKeys() string {
+	p.Mutex.Lock()
+	keys := append([]string(nil), p.SSHKeys...)
+	p.Mutex.Unlock()
+
+	if len(keys) == 0 {
+		return "\n\rYou have no SSH keys.\n\rUsage: sshkey add <public key>\n\r"
+	}
+
+	var output strings.Builder
+	output.WriteString("\n\rYour SSH keys:\n\r")
+	for i, line := range keys {
+		key, comment, err := parseSSHKey(line)
+		if err != nil {
+			output.WriteString(fmt.Sprintf("  %d. (unreadable)\n\r", i+1))
+			continue
+		}
+		output.WriteString(fmt.Sprintf("  %d. %s %s %s\n\r", i+1, key.Type(), ssh.FingerprintSHA256(key), comment))
+	}
+	return output.String()
+}
+
+func ExecuteSSHKeyCommand(character *Character, tokens []string) bool {
+
+	player := character.Player
+	usage := "\n\rUsage: sshkey [list] | sshkey add <public key> | sshkey remove <number|fingerprint>\n\r"
+
+	if len(tokens) < 2 || strings.ToLower(tokens[1]) == "list" {
+		player.ToPlayer <- player.listSSHKeys()
+		return false
+	}
+
+	switch strings.ToLower(tokens[1]) {
+	case "add":
+		if len(tokens) < 3 {
+			player.ToPlayer <- usage
+			return false
+		}
+
+		key, comment, err := parseSSHKey(strings.Join(tokens[2:], " "))
+		if err != nil {
+			player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+			return false
+		}
+		fingerprint := ssh.FingerprintSHA256(key)
+
+		// Store the key without options so only the key and its comment are kept
+		line := strings.TrimSpace(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + " " + comment)
+
+		player.Mutex.Lock()
+		for _, existing := range player.SSHKeys {
+			if stored, _, err := parseSSHKey(existing); err == nil && bytes.Equal(stored.Marshal(), key.Marshal()) {
+				player.Mutex.Unlock()
+				player.ToPlayer <- "\n\rThat key is already registered.\n\r"
+				return false
+			}
+		}
+		if len(player.SSHKeys) >= MaxSSHKeys {
+			player.Mutex.Unlock()
+			player.ToPlayer <- fmt.Sprintf("\n\rYou cannot have more than %d SSH keys.\n\r", MaxSSHKeys)
+			return false
+		}
+		player.SSHKeys = append(player.SSHKeys, line)
+		player.Mutex.Unlock()
+
+		if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+			Logger.Error("Error saving player SSH keys", "playerName", player.PlayerID, "error", err)
+			player.ToPlayer <- "\n\rYour key could not be saved.\n\r"
+			return false
+		}
+
+		character.Server.AuditPlayer(character.Context(), player, AuditSSHKey, player.PlayerID, "added "+fingerprint)
+		player.ToPlayer <- fmt.Sprintf("\n\rAdded the %s key %s. You can now log in with it instead of your password.\n\r", key.Type(), fingerprint)
+
+	case "remove", "delete":
+		if len(tokens) != 3 {
+			player.ToPlayer <- usage
+			return false
+		}
+
+		player.Mutex.Lock()
+		index := -1
+		if n, err := strconv.Atoi(tokens[2]); err == nil {
+			if n >= 1 && n <= len(player.SSHKeys) {
+				index = n - 1
+			}
+		} else {
+			for i, existing := range player.SSHKeys {
+				if key, _, err := parseSSHKey(existing); err == nil && ssh.FingerprintSHA256(key) == tokens[2] {
+					index = i
+					break
+				}
+			}
+		}
+		if index < 0 {
+			player.Mutex.Unlock()
+			player.ToPlayer <- "\n\rYou have no such SSH key. Use sshkey list to see your keys.\n\r"
+			return false
+		}
+		removed := player.SSHKeys[index]
+		player.SSHKeys = append(player.SSHKeys[:index:index], player.SSHKeys[index+1:]...)
+		player.Mutex.Unlock()
+
+		if err := character.Server.Database.WritePlayer(character.Context(), player); err != nil {
+			Logger.Error("Error saving player SSH keys", "playerName", player.PlayerID, "error", err)
+		}
+
+		fingerprint := "unreadable key"
+		if key, _, err := parseSSHKey(removed); err == nil {
+			fingerprint = ssh.FingerprintSHA256(key)
+		}
+		character.Server.AuditPlayer(character.Context(), player, AuditSSHKey, player.PlayerID, "removed "+fingerprint)
+		player.ToPlayer <- fmt.Sprintf("\n\rRemoved the SSH key %s.\n\r", fingerprint)
+
+	default:
+		player.ToPlayer <- usage
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// StatusStats is the body returned by the /stats endpoint.
+type StatusStats struct {
+	Uptime        string     `json:"uptime"`
+	UptimeSeconds float64    `json:"uptimeSeconds"`
+	PlayerCount   int        `json:"playerCount"`
+	RoomCount     int        `json:"roomCount"`
+	Cache         CacheStats `json:"cache"`
+}
+
+// ServerStatus is the body returned by the /statusz endpoint.
+type ServerStatus struct {
+	Uptime          string          `json:"uptime"`
+	UptimeSeconds   float64         `json:"uptimeSeconds"`
+	Started         time.Time       `json:"started"`
+	PlayerCount     int             `json:"playerCount"`
+	RoomsLoaded     int             `json:"roomsLoaded"`
+	ShuttingDown    bool            `json:"shuttingDown"`
+	AutoSaveMinutes uint16          `json:"autoSaveMinutes"`
+	LastAutoSave    *AutoSaveStatus `json:"lastAutoSave,omitempty"` // absent until the first auto-save
+}
+
+// StartStatusServer starts the read-only HTTP status endpoint on the configured port.
+// It does nothing when no status port is configured.
+func (s *Server) StartStatusServer() error {
+	port := s.Config.Server.StatusPort
+	if port == 0 {
+		Logger.Info("Status server disabled, no port configured")
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/statusz", s.handleStatusz)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on status port %d: %w", port, err)
+	}
+
+	s.StatusServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.StatusServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Logger.Error("Status server stopped unexpectedly", "error", err)
+		}
+	}()
+
+	Logger.Info("Status server listening", "port", port)
+	return nil
+}
+
+// StopStatusServer shuts down the HTTP status endpoint if it is running.
+func (s *Server) StopStatusServer(ctx context.Context) error {
+	if s.StatusServer == nil {
+		return nil
+	}
+
+	Logger.Info("Closing status server...")
+	if err := s.StatusServer.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down status server: %w", err)
+	}
+	return nil
+}
+
+// handleHealthz reports healthy when the SSH listener is up and DynamoDB is reachable.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.Listener == nil {
+		http.Error(w, "ssh listener is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Take the server out of rotation as soon as shutdown begins
+	if s.ShuttingDown.Load() {
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	if s.Database == nil {
+		http.Error(w, "database is not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := s.Database.Ping(s.Context); err != nil {
+		Logger.Warn("Health check failed to reach database", "error", err)
+		http.Error(w, "database is unreachable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// handleStats returns the server uptime, player count, room count, and cache counters as JSON.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	uptime := time.Since(s.StartTime).Truncate(time.Second)
+
+	s.Mutex.Lock()
+	stats := StatusStats{
+		Uptime:        uptime.String(),
+		UptimeSeconds: uptime.Seconds(),
+		PlayerCount:   len(s.Characters),
+		RoomCount:     len(s.Rooms),
+	}
+	s.Mutex.Unlock()
+
+	stats.Cache = s.Database.Cache.Stats()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		Logger.Error("Error encoding status stats", "error", err)
+	}
+}
+
+// handleStatusz returns the server uptime, player and room counts, and the outcome of the last
+// auto-save as JSON.
+func (s *Server) handleStatusz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	uptime := time.Since(s.StartTime).Truncate(time.Second)
+
+	s.Mutex.Lock()
+	status := ServerStatus{
+		Uptime:          uptime.String(),
+		UptimeSeconds:   uptime.Seconds(),
+		Started:         s.StartTime,
+		PlayerCount:     len(s.Characters),
+		RoomsLoaded:     len(s.Rooms),
+		AutoSaveMinutes: s.Settings().Game.AutoSave,
+	}
+	if !s.LastAutoSave.Started.IsZero() {
+		lastAutoSave := s.LastAutoSave
+		status.LastAutoSave = &lastAutoSave
+	}
+	s.Mutex.Unlock()
+
+	status.ShuttingDown = s.ShuttingDown.Load()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		Logger.Error("Error encoding server status", "error", err)
+	}
+}
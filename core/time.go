@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Game hours at which daylight begins and ends.
+const (
+	DawnHour = 6
+	DuskHour = 20
+)
+
+// DefaultSecondsPerHour is the length of a game hour in real seconds when not configured.
+const DefaultSecondsPerHour = 60
+
+// Room flags that affect lighting.
+const (
+	RoomFlagOutdoor = "outdoor"
+	RoomFlagDark    = "dark"
+)
+
+// GameHour returns the current in-game hour, starting at dawn when the server starts.
+func (s *Server) GameHour() int {
+	secondsPerHour := time.Duration(s.Settings().Game.SecondsPerHour)
+	if secondsPerHour == 0 {
+		secondsPerHour = DefaultSecondsPerHour
+	}
+
+	elapsed := time.Since(s.StartTime)
+	return (DawnHour + int(elapsed/(secondsPerHour*time.Second))) % 24
+}
+
+// IsNight reports whether it is currently night in the game world.
+func (s *Server) IsNight() bool {
+	hour := s.GameHour()
+	return hour < DawnHour || hour >= DuskHour
+}
+
+// DayPhase returns "day" or "night" for the current game hour.
+func (s *Server) DayPhase() string {
+	if s.IsNight() {
+		return "night"
+	}
+	return "day"
+}
+
+// HasFlag checks if the room has the given flag set.
+func (r *Room) HasFlag(flag string) bool {
+	return r.Flags[flag]
+}
+
+// IsDark reports whether the room is unlit without a light source, either always or because it is outdoors at night.
+func (s *Server) IsDark(r *Room) bool {
+	return s.BaseLight(r) <= LightDark
+}
+
+// HasLight checks if the character is carrying an item that gives off light.
+func (c *Character) HasLight() bool {
+	for _, item := range c.Inventory {
+		if item != nil && item.GivesLight() {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSee reports whether the character can see in their current room.
+func (c *Character) CanSee() bool {
+	return c.LightLevel() > LightDark
+}
+
+func ExecuteTimeCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is checking the time", "playerName", character.Player.PlayerID)
+
+	hour := character.Server.GameHour()
+	character.Player.ToPlayer <- fmt.Sprintf("\n\rIt is %02d:00, %s.\n\r", hour, character.Server.DayPhase())
+	return false
+}
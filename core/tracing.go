@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-xray-sdk-go/instrumentation/awsv2"
+	"github.com/aws/aws-xray-sdk-go/xray"
+)
+
+// tracingEnabled is set by EnableXRay. Until then no segments are made and AWS clients are
+// left uninstrumented.
+var tracingEnabled atomic.Bool
+
+// traceName names the segments of this server in the X-Ray service map.
+var traceName = "mud"
+
+// commandTrace holds the context of the command a character is running.
+type commandTrace struct {
+	ctx context.Context
+}
+
+// StartTrace begins an X-Ray segment for one unit of work, such as a player command or a login,
+// annotated with the key and value pairs given. AWS calls made with the returned context are
+// recorded as subsegments. The returned function closes the segment. When tracing is disabled
+// ctx is returned unchanged.
+func StartTrace(ctx context.Context, operation string, annotations ...string) (context.Context, func(error)) {
+	if !tracingEnabled.Load() {
+		return ctx, func(error) {}
+	}
+
+	ctx, segment := xray.BeginSegment(ctx, traceName)
+	if err := segment.AddAnnotation("operation", operation); err != nil {
+		Logger.Debug("Failed to annotate trace", "operation", operation, "error", err)
+	}
+	for i := 0; i+1 < len(annotations); i += 2 {
+		if err := segment.AddAnnotation(annotations[i], annotations[i+1]); err != nil {
+			Logger.Debug("Failed to annotate trace", "operation", operation, "error", err)
+		}
+	}
+	return ctx, segment.Close
+}
+
+// instrumentAWS records the calls made by clients built from cfg as X-Ray subsegments of the
+// segment in their request context.
+func instrumentAWS(cfg *aws.Config) {
+	if tracingEnabled.Load() {
+		awsv2.AWSV2Instrumentor(&cfg.APIOptions)
+	}
+}
+
+// traceCommand runs a command handler inside its own segment, annotated with the player, the
+// character and the command, and makes the segment's context the one the character's database
+// calls use until the handler returns.
+func traceCommand(character *Character, verb string, handler CommandHandler, tokens []string) bool {
+	if !tracingEnabled.Load() {
+		return handler(character, tokens)
+	}
+
+	ctx, end := StartTrace(character.Server.Context, "command",
+		"command", verb,
+		"player", character.Player.PlayerID,
+		"character", character.Name,
+		"characterID", character.ID.String(),
+	)
+	// Commands can run other commands, as aliases and force do, so the outer trace is restored
+	previous := character.trace.Swap(&commandTrace{ctx: ctx})
+
+	defer func() {
+		character.trace.Store(previous)
+		if r := recover(); r != nil {
+			end(fmt.Errorf("command %s panicked: %v", verb, r))
+			panic(r)
+		}
+		end(nil)
+	}()
+
+	return handler(character, tokens)
+}
+
+// Context returns the context for work done on behalf of the character: that of the command
+// being traced when there is one, otherwise the server's.
+func (c *Character) Context() context.Context {
+	if trace := c.trace.Load(); trace != nil {
+		return trace.ctx
+	}
+	return c.Server.Context
+}
@@ -0,0 +1,391 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TradeRequestTimeout is how long a trade request waits for the other character to agree.
+const TradeRequestTimeout = time.Minute
+
+// TradeOffer is what one side of a trade is putting up.
+type TradeOffer struct {
+	Items     []*Item
+	Coins     uint64
+	Confirmed bool
+}
+
+// Trade is a negotiation between two characters. Nothing changes hands until both confirm,
+// and any change to either offer clears both confirmations.
+type Trade struct {
+	Parties [2]*Character
+	Offers  map[uuid.UUID]*TradeOffer
+	Open    bool      // false while waiting for the second character to agree
+	Started time.Time // when the trade was requested
+}
+
+var (
+	trades      = make(map[uuid.UUID]*Trade) // each character's current trade, keyed by character ID
+	tradesMutex sync.Mutex                   // guards trades and every Trade in it
+)
+
+// other returns the character on the other side of the trade.
+func (t *Trade) other(c *Character) *Character {
+	if t.Parties[0] == c {
+		return t.Parties[1]
+	}
+	return t.Parties[0]
+}
+
+// CancelTrade ends any trade the character is part of, telling the other party why.
+// It takes no character locks, so it is safe to call while holding c.Mutex.
+func CancelTrade(c *Character, reason string) {
+	tradesMutex.Lock()
+	trade, exists := trades[c.ID]
+	if exists {
+		for _, party := range trade.Parties {
+			delete(trades, party.ID)
+		}
+	}
+	tradesMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	Logger.Info("Trade cancelled", "characterName", c.Name, "reason", reason)
+
+	other := trade.other(c)
+	if other.Player.Send(fmt.Sprintf("\n\rThe trade with %s is cancelled: %s.\n\r", c.Name, reason)) {
+		other.Player.Send(other.Player.RenderPrompt())
+	}
+	c.Player.Send(fmt.Sprintf("\n\rThe trade with %s is cancelled: %s.\n\r", other.Name, reason))
+}
+
+// describeTrade lists both offers for the character. The caller must hold tradesMutex.
+func describeTrade(trade *Trade, viewer *Character) string {
+	var output strings.Builder
+	output.WriteString("\n\r")
+	for _, party := range []*Character{viewer, trade.other(viewer)} {
+		offer := trade.Offers[party.ID]
+		name := party.Name
+		if party == viewer {
+			name = "You"
+		}
+
+		items := make([]string, 0, len(offer.Items))
+		for _, item := range offer.Items {
+			items = append(items, item.Name)
+		}
+		sort.Strings(items)
+		if len(items) == 0 {
+			items = append(items, "no items")
+		}
+
+		status := "not confirmed"
+		if offer.Confirmed {
+			status = "confirmed"
+		}
+		output.WriteString(fmt.Sprintf("%s: %s and %d coins (%s)\n\r", name, strings.Join(items, ", "), offer.Coins, status))
+	}
+	return output.String()
+}
+
+// changeOffer applies a change to the character's offer, clears both confirmations, and shows both sides the new terms.
+func changeOffer(c *Character, change func(offer *TradeOffer) error) {
+	tradesMutex.Lock()
+	trade, exists := trades[c.ID]
+	if !exists || !trade.Open {
+		tradesMutex.Unlock()
+		c.Player.ToPlayer <- "\n\rYou are not trading with anyone.\n\r"
+		return
+	}
+
+	if err := change(trade.Offers[c.ID]); err != nil {
+		tradesMutex.Unlock()
+		c.Player.ToPlayer <- fmt.Sprintf("\n\r%s.\n\r", capitalizeError(err))
+		return
+	}
+
+	for _, offer := range trade.Offers {
+		offer.Confirmed = false
+	}
+	other := trade.other(c)
+	mine, theirs := describeTrade(trade, c), describeTrade(trade, other)
+	tradesMutex.Unlock()
+
+	c.Player.ToPlayer <- mine
+	if other.Player.Send(theirs) {
+		other.Player.Send(other.Player.RenderPrompt())
+	}
+}
+
+// requestTrade asks the target to trade, or opens the trade if the target has already asked.
+func requestTrade(c, target *Character) {
+	tradesMutex.Lock()
+	if _, busy := trades[c.ID]; busy {
+		tradesMutex.Unlock()
+		c.Player.ToPlayer <- "\n\rYou are already trading. Use 'trade cancel' first.\n\r"
+		return
+	}
+
+	if trade, pending := trades[target.ID]; pending {
+		if trade.Open || trade.other(target) != c || time.Since(trade.Started) > TradeRequestTimeout {
+			tradesMutex.Unlock()
+			c.Player.ToPlayer <- fmt.Sprintf("\n\r%s is busy trading with someone else.\n\r", target.Name)
+			return
+		}
+
+		trade.Open = true
+		trades[c.ID] = trade
+		tradesMutex.Unlock()
+
+		Logger.Info("Trade opened", "first", target.Name, "second", c.Name)
+		c.Player.ToPlayer <- fmt.Sprintf("\n\rYou begin trading with %s.\n\r", target.Name)
+		if target.Player.Send(fmt.Sprintf("\n\r%s agrees to trade with you.\n\r", c.Name)) {
+			target.Player.Send(target.Player.RenderPrompt())
+		}
+		return
+	}
+
+	trades[c.ID] = &Trade{
+		Parties: [2]*Character{c, target},
+		Offers:  map[uuid.UUID]*TradeOffer{c.ID: {}, target.ID: {}},
+		Started: time.Now(),
+	}
+	tradesMutex.Unlock()
+
+	c.Player.ToPlayer <- fmt.Sprintf("\n\rYou ask %s to trade.\n\r", target.Name)
+	if target.Player.Send(fmt.Sprintf("\n\r%s wants to trade with you. Type 'trade %s' to agree.\n\r", c.Name, c.Name)) {
+		target.Player.Send(target.Player.RenderPrompt())
+	}
+}
+
+// confirmTrade records the character's agreement, completing the trade once both sides have confirmed.
+func confirmTrade(c *Character) {
+	tradesMutex.Lock()
+	trade, exists := trades[c.ID]
+	if !exists || !trade.Open {
+		tradesMutex.Unlock()
+		c.Player.ToPlayer <- "\n\rYou are not trading with anyone.\n\r"
+		return
+	}
+
+	trade.Offers[c.ID].Confirmed = true
+	other := trade.other(c)
+	if !trade.Offers[other.ID].Confirmed {
+		tradesMutex.Unlock()
+		c.Player.ToPlayer <- fmt.Sprintf("\n\rYou confirm the trade. Waiting for %s.\n\r", other.Name)
+		if other.Player.Send(fmt.Sprintf("\n\r%s has confirmed the trade. Type 'trade confirm' to complete it.\n\r", c.Name)) {
+			other.Player.Send(other.Player.RenderPrompt())
+		}
+		return
+	}
+
+	// Both sides agree, so the trade is over whatever the outcome of the exchange
+	delete(trades, c.ID)
+	delete(trades, other.ID)
+	tradesMutex.Unlock()
+
+	// The trade is no longer shared, so the exchange can run without tradesMutex,
+	// which must never be taken after a character lock is held elsewhere
+	err := exchange(trade)
+
+	if err != nil {
+		Logger.Warn("Trade failed", "first", trade.Parties[0].Name, "second", trade.Parties[1].Name, "error", err)
+		for _, party := range trade.Parties {
+			party.Player.Send(fmt.Sprintf("\n\rThe trade could not be completed: %v.\n\r", err))
+		}
+		other.Player.Send(other.Player.RenderPrompt())
+		return
+	}
+
+	for _, party := range trade.Parties {
+		if err := party.Server.SaveCharacter(party); err != nil {
+			Logger.Error("Error saving character after trade", "characterName", party.Name, "error", err)
+		}
+	}
+
+	Logger.Info("Trade completed", "first", trade.Parties[0].Name, "second", trade.Parties[1].Name)
+	c.Player.ToPlayer <- fmt.Sprintf("\n\rYou complete the trade with %s.\n\r", other.Name)
+	if other.Player.Send(fmt.Sprintf("\n\rYou complete the trade with %s.\n\r", c.Name)) {
+		other.Player.Send(other.Player.RenderPrompt())
+	}
+}
+
+// exchange swaps the offers of a confirmed trade. Both characters are locked for the whole
+// exchange, so either everything changes hands or, when anything is no longer possible, nothing does.
+func exchange(trade *Trade) error {
+	first, second := trade.Parties[0], trade.Parties[1]
+	if first.Room != second.Room {
+		return fmt.Errorf("you are no longer in the same place")
+	}
+
+	// Lock in a fixed order so two exchanges can never wait on each other
+	if first.ID.String() > second.ID.String() {
+		first, second = second, first
+	}
+	first.Mutex.Lock()
+	defer first.Mutex.Unlock()
+	second.Mutex.Lock()
+	defer second.Mutex.Unlock()
+
+	// Everything offered must still be held, and the coins still in the purse
+	for _, party := range trade.Parties {
+		offer := trade.Offers[party.ID]
+		if party.Coins < offer.Coins {
+			return fmt.Errorf("%s no longer has %d coins", party.Name, offer.Coins)
+		}
+		held := party.equipment().Held
+		for _, item := range offer.Items {
+			found := false
+			for _, h := range held {
+				found = found || h == item
+			}
+			if !found {
+				return fmt.Errorf("%s is no longer holding %s", party.Name, item.Name)
+			}
+		}
+	}
+
+	// Remember the hands so they can be restored if the receiving side has no room
+	saved := make(map[*Character]map[string]*Item, 2)
+	for _, party := range trade.Parties {
+		saved[party] = map[string]*Item{RightHand: party.Inventory[RightHand], LeftHand: party.Inventory[LeftHand]}
+		for _, item := range trade.Offers[party.ID].Items {
+			party.releaseItem(item)
+		}
+	}
+
+	restore := func() {
+		for party, hands := range saved {
+			for slot, item := range hands {
+				if item == nil {
+					delete(party.Inventory, slot)
+				} else {
+					party.Inventory[slot] = item
+				}
+			}
+		}
+	}
+
+	for _, party := range trade.Parties {
+		for _, item := range trade.Offers[trade.other(party).ID].Items {
+			if _, err := party.holdItem(item); err != nil {
+				restore()
+				return fmt.Errorf("%s has no free hand for %s", party.Name, item.Name)
+			}
+		}
+		if party.carriedMass() > party.carryCapacity() {
+			restore()
+			return fmt.Errorf("%s cannot carry that much", party.Name)
+		}
+	}
+
+	for _, party := range trade.Parties {
+		received := trade.Offers[trade.other(party).ID].Coins
+		party.Coins = party.Coins - trade.Offers[party.ID].Coins + received
+		party.markDirty(DirtyInventory)
+	}
+
+	return nil
+}
+
+func ExecuteTradeCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is trading", "playerName", character.Player.PlayerID)
+
+	usage := "\n\rUsage: trade <character> | trade offer <item> | trade withdraw <item> | trade coins <amount> | trade show | trade confirm | trade cancel\n\r"
+	if len(tokens) < 2 {
+		character.Player.ToPlayer <- usage
+		return false
+	}
+
+	argument := strings.Join(tokens[2:], " ")
+
+	switch strings.ToLower(tokens[1]) {
+	case "offer":
+		item := character.HeldItem(argument)
+		if argument == "" || item == nil {
+			character.Player.ToPlayer <- "\n\rYou're not holding that item.\n\r"
+			return false
+		}
+		changeOffer(character, func(offer *TradeOffer) error {
+			for _, offered := range offer.Items {
+				if offered == item {
+					return fmt.Errorf("you have already offered %s", item.Name)
+				}
+			}
+			offer.Items = append(offer.Items, item)
+			return nil
+		})
+
+	case "withdraw":
+		changeOffer(character, func(offer *TradeOffer) error {
+			for i, offered := range offer.Items {
+				if argument != "" && strings.Contains(strings.ToLower(offered.Name), strings.ToLower(argument)) {
+					offer.Items = append(offer.Items[:i], offer.Items[i+1:]...)
+					return nil
+				}
+			}
+			return fmt.Errorf("you have not offered that")
+		})
+
+	case "coins":
+		amount, err := strconv.ParseUint(argument, 10, 64)
+		if err != nil {
+			character.Player.ToPlayer <- "\n\rUsage: trade coins <amount>\n\r"
+			return false
+		}
+		if amount > character.GetCoins() {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou only have %d coins.\n\r", character.GetCoins())
+			return false
+		}
+		changeOffer(character, func(offer *TradeOffer) error {
+			offer.Coins = amount
+			return nil
+		})
+
+	case "show":
+		tradesMutex.Lock()
+		trade, exists := trades[character.ID]
+		if !exists || !trade.Open {
+			tradesMutex.Unlock()
+			character.Player.ToPlayer <- "\n\rYou are not trading with anyone.\n\r"
+			return false
+		}
+		summary := describeTrade(trade, character)
+		tradesMutex.Unlock()
+		character.Player.ToPlayer <- summary
+
+	case "confirm":
+		confirmTrade(character)
+
+	case "cancel":
+		tradesMutex.Lock()
+		_, exists := trades[character.ID]
+		tradesMutex.Unlock()
+		if !exists {
+			character.Player.ToPlayer <- "\n\rYou are not trading with anyone.\n\r"
+			return false
+		}
+		CancelTrade(character, fmt.Sprintf("%s cancelled it", character.Name))
+
+	default:
+		name := strings.Join(tokens[1:], " ")
+		target := findCharacterInRoom(character.Room, name)
+		if target == nil || target == character {
+			character.Player.ToPlayer <- fmt.Sprintf("\n\rYou don't see %s here.\n\r", name)
+			return false
+		}
+		requestTrade(character, target)
+	}
+
+	return false
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 	"log/slog"
 	"net"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh"
@@ -22,12 +23,24 @@ type Index struct {
 
 type Configuration struct {
 	Server struct {
-		Port           uint16 `yaml:"Port"`
-		PrivateKeyPath string `yaml:"PrivateKeyPath"`
+		Port              uint16   `yaml:"Port"`
+		PrivateKeyPath    string   `yaml:"PrivateKeyPath"`
+		Admins            []string `yaml:"Admins"`
+		StatusPort        uint16   `yaml:"StatusPort"`        // HTTP status endpoint, disabled when 0
+		MetricsPort       uint16   `yaml:"MetricsPort"`       // Prometheus /metrics endpoint, used when Logging.Metrics is prometheus
+		CommandRate       float64  `yaml:"CommandRate"`       // Lines per second a player may sustain, unlimited when 0
+		CommandBurst      uint16   `yaml:"CommandBurst"`      // Lines a player may send at once before being limited
+		FloodLimit        uint16   `yaml:"FloodLimit"`        // Dropped lines in a row before a player is disconnected
+		ShutdownCountdown uint16   `yaml:"ShutdownCountdown"` // Seconds players are warned before a shutdown logs them out
 	} `yaml:"Server"`
 	Aws struct {
 		Region string `yaml:"Region"`
 	} `yaml:"Aws"`
+	Storage struct {
+		Backend      string `yaml:"Backend"`      // dynamodb (the default) or bolt to run offline from a local file
+		Path         string `yaml:"Path"`         // Database file for the bolt backend
+		CacheSeconds int    `yaml:"CacheSeconds"` // How long loaded items and prototypes are cached, 0 for the default and -1 to disable
+	} `yaml:"Storage"`
 	Cognito struct {
 		UserPoolID     string `yaml:"UserPoolId"`
 		ClientSecret   string `yaml:"UserPoolClientSecret"`
@@ -36,98 +49,202 @@ type Configuration struct {
 		UserPoolArn    string `yaml:"UserPoolArn"`
 	} `yaml:"Cognito"`
 	Game struct {
-		Balance         float64 `yaml:"Balance"`
-		AutoSave        uint16  `yaml:"AutoSave"`
-		StartingEssence uint16  `yaml:"StartingEssence"`
-		StartingHealth  uint16  `yaml:"StartingHealth"`
+		Balance          float64          `yaml:"Balance"`
+		AutoSave         uint16           `yaml:"AutoSave"`
+		StartingEssence  uint16           `yaml:"StartingEssence"`
+		StartingHealth   uint16           `yaml:"StartingHealth"`
+		StartRoom        int64            `yaml:"StartRoom"`
+		ProfanityFilter  bool             `yaml:"ProfanityFilter"`
+		Channels         []string         `yaml:"Channels"`
+		RespawnRooms     map[string]int64 `yaml:"RespawnRooms"`     // Area name to the room characters respawn in
+		SecondsPerHour   uint16           `yaml:"SecondsPerHour"`   // Real seconds in one game hour
+		NPCTickSeconds   uint16           `yaml:"NPCTickSeconds"`   // Real seconds between NPC actions
+		IdleWarnMinutes  uint16           `yaml:"IdleWarnMinutes"`  // Minutes without input before a warning, 0 disables
+		IdleTimeout      uint16           `yaml:"IdleTimeout"`      // Minutes without input before disconnecting, 0 disables
+		LinkDeadSeconds  uint16           `yaml:"LinkDeadSeconds"`  // Seconds a dropped character stays in the world, 0 removes at once
+		DuplicateLogin   string           `yaml:"DuplicateLogin"`   // reject or takeover when a character in play is selected again
+		ItemDecayMinutes uint16           `yaml:"ItemDecayMinutes"` // Minutes an item dropped on the ground lasts, 0 disables decay
+		RegenSeconds     uint16           `yaml:"RegenSeconds"`     // Real seconds between health and essence regeneration ticks
+		HealthRegenRate  float64          `yaml:"HealthRegenRate"`  // Percent of maximum health restored per tick while standing
+		EssenceRegenRate float64          `yaml:"EssenceRegenRate"` // Percent of maximum essence restored per tick while standing
+		WeatherSeconds   uint16           `yaml:"WeatherSeconds"`   // Real seconds between weather changes
 	} `yaml:"Game"`
+	Data struct {
+		NamesFile     string `yaml:"NamesFile"`
+		ObscenityFile string `yaml:"ObscenityFile"`
+		BannerFile    string `yaml:"BannerFile"`
+		Strict        bool   `yaml:"Strict"` // Fail startup when a data file is missing
+	} `yaml:"Data"`
 	Logging struct {
-		ApplicationName string `yaml:"ApplicationName"`
-		LogLevel        int    `yaml:"LogLevel"`
-		LogGroup        string `yaml:"LogGroup"`
-		LogStream       string `yaml:"LogStream"`
-		MetricNamespace string `yaml:"MetricNamespace"`
+		ApplicationName string         `yaml:"ApplicationName"`
+		LogLevel        int            `yaml:"LogLevel"`
+		LogGroup        string         `yaml:"LogGroup"`
+		LogStream       string         `yaml:"LogStream"`
+		MetricNamespace string         `yaml:"MetricNamespace"`
+		Metrics         string         `yaml:"Metrics"`         // cloudwatch, prometheus or none
+		XRay            bool           `yaml:"XRay"`            // Trace commands, logins and AWS calls with AWS X-Ray
+		PublishReports  bool           `yaml:"PublishReports"`  // Also count bug, typo and idea reports as a CloudWatch metric
+		SubsystemLevels map[string]int `yaml:"SubsystemLevels"` // Log levels for the database, combat and network subsystems, numbered as LogLevel
 	} `yaml:"Logging"`
 }
 
 type KeyPair struct {
-	db    *dynamodb.DynamoDB
-	Mutex sync.Mutex
+	DataStore
+	Cache    *Cache // items and prototypes read recently, nil when caching is disabled
+	Instance string // identifies this process in the Owner attribute of the versioned records it writes
+	Mutex    sync.Mutex
 }
 
 type Server struct {
 	Port                 uint16
 	Listener             net.Listener
+	StatusServer         *http.Server
+	MetricsServer        *http.Server
 	SSHConfig            *ssh.ServerConfig
 	PlayerCount          uint64
 	Config               Configuration
 	StartTime            time.Time
 	Rooms                map[int64]*Room
 	Database             *KeyPair
+	CloudWatch           *cloudwatch.Client // publishes metrics, created once at startup
 	PlayerIndex          *Index
 	CharacterBloomFilter *bloom.BloomFilter
+	ReservedNames        map[string]bool
+	Obscenities          map[string]bool
 	Characters           map[uuid.UUID]*Character
-	Balance              float64
-	AutoSave             uint16
+	Sessions             map[uuid.UUID]*Session // player session controlling each character in play
+	Players              map[*Player]bool       // connected players, including those still choosing a character
 	ArcheTypes           map[string]*Archetype
-	Health               uint16
-	Essence              uint16
 	Items                map[uuid.UUID]*Item
 	Prototypes           map[uuid.UUID]*Prototype
+	NPCTemplates         map[string]*NPCData
+	Quests               map[string]*QuestData
+	Areas                map[string]*AreaData
+	Weather              map[string]string      // current weather by area ID, guarded by weatherMutex
+	Boards               map[string][]*PostData // bulletin board posts keyed by board ID, oldest first
+	NPCs                 map[uuid.UUID]*NPC
 	Context              context.Context
 	Mutex                sync.Mutex
 	ActiveMotDs          []*MOTD
+	Banner               string
+	Channels             map[string]bool
 	WaitGroup            sync.WaitGroup
+	ShuttingDown         atomic.Bool                   // set once shutdown begins, so dropped connections do not go link-dead
+	ConfigLoader         func() (Configuration, error) // re-reads the configuration file for Reload
+	LastAutoSave         AutoSaveStatus                // outcome of the most recent auto-save, guarded by Mutex
+	settings             atomic.Pointer[Configuration] // configuration published by the last reload, read through Settings
+}
+
+// AutoSaveStatus describes the most recent auto-save, as reported by the /statusz endpoint.
+type AutoSaveStatus struct {
+	Started   time.Time `json:"started"`
+	Completed time.Time `json:"completed"`
+	Error     string    `json:"error,omitempty"` // failures of the run, empty when everything was saved
 }
 
 type Player struct {
-	PlayerID      string
-	Index         uint64
-	ToPlayer      chan string
-	FromPlayer    chan string
-	PlayerError   chan error
-	Echo          bool
-	Prompt        string
-	Connection    ssh.Channel
-	Server        *Server
-	ConsoleWidth  int
-	ConsoleHeight int
-	CharacterList map[string]uuid.UUID
-	Character     *Character
-	LoginTime     time.Time
-	PasswordHash  string
-	Mutex         sync.Mutex
-	SeenMotD      []uuid.UUID
+	PlayerID        string
+	Index           uint64
+	ToPlayer        chan string
+	FromPlayer      chan string
+	PlayerError     chan error
+	Echo            atomic.Bool  // whether typed input is echoed back to the player
+	Prompt          string       // prompt format, expanded by RenderPrompt
+	lastPrompt      atomic.Value // the most recently rendered prompt, so snooping can skip it
+	Connection      ssh.Channel
+	RemoteAddr      string // IP address the player connected from, recorded in the audit log
+	Server          *Server
+	ConsoleWidth    int
+	ConsoleHeight   int
+	CharacterList   map[string]uuid.UUID
+	Character       *Character
+	LoginTime       time.Time
+	LastInput       atomic.Int64 // unix nanoseconds of the most recent input line, used for idle time
+	PasswordHash    string
+	Mutex           sync.Mutex
+	SeenMotD        []uuid.UUID
+	ShowProfanity   bool
+	Channels        map[string]bool
+	OfferedChannels map[string]bool    // default channels already given, so one the player left is not joined again
+	Muted           map[string]bool    // lower-case names of characters whose channel messages are hidden
+	Ignored         map[string]bool    // lower-case names of characters whose messages are all hidden
+	Friends         map[string]bool    // lower-case names of characters listed by "who friends"
+	Aliases         map[string]string  // personal command shortcuts, keyed by lower-case name
+	Role            string             // permission role such as "builder" or "admin", a plain player when empty
+	Closed          atomic.Bool        // set once the session is tearing down and ToPlayer is closed
+	Color           atomic.Bool        // whether output is colored with ANSI codes
+	ColorTheme      string             // name of the color theme, DefaultColorTheme when empty
+	SSHKeys         []string           // public keys in authorized_keys format that can log in without a password
+	Paging          atomic.Bool        // set while long output is held back behind a --More-- prompt
+	Limiter         *TokenBucket       // limits how quickly input is accepted, nil when unlimited
+	PageControl     chan bool          // true shows the next page of held output, false discards it
+	Forced          chan ForcedCommand // commands forced by an admin, run by the input loop
 }
 
 type PlayerData struct {
-	PlayerID      string            `json:"PlayerID" dynamodbav:"PlayerID"`
-	CharacterList map[string]string `json:"characterList" dynamodbav:"CharacterList"`
-	SeenMotDs     []string          `json:"seenMotD" dynamodbav:"SeenMotD"`
+	PlayerID        string            `json:"PlayerID" dynamodbav:"PlayerID"`
+	CharacterList   map[string]string `json:"characterList" dynamodbav:"CharacterList"`
+	SeenMotDs       []string          `json:"seenMotD" dynamodbav:"SeenMotD"`
+	Channels        []string          `json:"channels" dynamodbav:"Channels"`
+	OfferedChannels []string          `json:"offeredChannels,omitempty" dynamodbav:"OfferedChannels,omitempty"`
+	Muted           []string          `json:"muted,omitempty" dynamodbav:"Muted,omitempty"`
+	Ignored         []string          `json:"ignored,omitempty" dynamodbav:"Ignored,omitempty"`
+	Friends         []string          `json:"friends,omitempty" dynamodbav:"Friends,omitempty"`
+	EchoOff         bool              `json:"echoOff,omitempty" dynamodbav:"EchoOff,omitempty"`
+	Role            string            `json:"role,omitempty" dynamodbav:"Role,omitempty"`
+	Aliases         map[string]string `json:"aliases,omitempty" dynamodbav:"Aliases,omitempty"`
+	Prompt          string            `json:"prompt,omitempty" dynamodbav:"Prompt,omitempty"`
+	ColorOff        bool              `json:"colorOff,omitempty" dynamodbav:"ColorOff,omitempty"`
+	ColorTheme      string            `json:"colorTheme,omitempty" dynamodbav:"ColorTheme,omitempty"`
+	ShowProfanity   bool              `json:"showProfanity,omitempty" dynamodbav:"ShowProfanity,omitempty"`
+	SSHKeys         []string          `json:"sshKeys,omitempty" dynamodbav:"SSHKeys,omitempty"`
 }
 
 // Room represents the in-memory structure for a room
 type Room struct {
-	RoomID      int64
-	Area        string
-	Title       string
-	Description string
-	Exits       map[string]*Exit
-	Characters  map[uuid.UUID]*Character
-	Items       map[uuid.UUID]*Item
-	Mutex       sync.Mutex
-	LastEdited  time.Time
-	LastSaved   time.Time
+	RoomID          int64
+	Area            string
+	Title           string
+	Description     string
+	Exits           map[string]*Exit
+	Characters      map[uuid.UUID]*Character
+	Items           map[uuid.UUID]*Item
+	NPCs            map[uuid.UUID]*NPC
+	Spawns          []string // IDs of the NPC definitions spawned here at startup
+	SpawnPoints     []*SpawnPoint
+	Flags           map[string]bool
+	Events          map[string]*RoomEvent
+	Ambience        []string // messages played at random while the room is occupied
+	AmbienceEnabled bool
+	Light           int       // added to the room's light level, negative for dim places
+	NextAmbience    time.Time // when the next ambient message is due, zero while none is scheduled
+	Version         int64     // version of the stored record, bumped by every save
+	Mutex           sync.Mutex
+	LastEdited      time.Time
+	LastSaved       time.Time
+}
+
+// RoomEvent buffers broadcasts of one kind that arrive within the coalescing window.
+type RoomEvent struct {
+	Messages []string
+	Summary  string
 }
 
 // RoomData represents the structure for storing room data in DynamoDB
 type RoomData struct {
-	RoomID      int64    `json:"roomID" dynamodbav:"RoomID"`
-	Area        string   `json:"area" dynamodbav:"Area"`
-	Title       string   `json:"title" dynamodbav:"Title"`
-	Description string   `json:"description" dynamodbav:"Description"`
-	ExitIDs     []string `json:"exitID" dynamodbav:"ExitID"`
-	ItemIDs     []string `json:"itemID" dynamodbav:"ItemID"`
+	RoomID          int64         `json:"roomID" dynamodbav:"RoomID"`
+	Area            string        `json:"area" dynamodbav:"Area"`
+	Title           string        `json:"title" dynamodbav:"Title"`
+	Description     string        `json:"description" dynamodbav:"Description"`
+	ExitIDs         []string      `json:"exitID" dynamodbav:"ExitID"`
+	ItemIDs         []string      `json:"itemID" dynamodbav:"ItemID"`
+	Flags           []string      `json:"flags,omitempty" dynamodbav:"Flags,omitempty"`
+	Spawns          []string      `json:"spawns,omitempty" dynamodbav:"Spawns,omitempty"`
+	SpawnPoints     []*SpawnPoint `json:"spawnPoints,omitempty" dynamodbav:"SpawnPoints,omitempty"`
+	Ambience        []string      `json:"ambience,omitempty" dynamodbav:"Ambience,omitempty"`
+	AmbienceEnabled bool          `json:"ambienceEnabled,omitempty" dynamodbav:"AmbienceEnabled,omitempty"`
+	Light           int           `json:"light,omitempty" dynamodbav:"Light,omitempty"`
+	Version         int64         `json:"version" dynamodbav:"Version"`
 }
 
 // Exit represents the in-memory structure for an exit
@@ -136,6 +253,12 @@ type Exit struct {
 	Direction  string
 	TargetRoom *Room
 	Visible    bool
+	TravelVerb string // optional verb such as "climb" used in movement messages
+	Door       bool   // the exit can be closed
+	Closed     bool
+	Locked     bool
+	KeyID      string // prototype ID of the key that locks and unlocks the door
+	Condition  string // day phase or weather the exit can only be used in, such as "day" or "!storm"
 	LastEdited time.Time
 	LastSaved  time.Time
 }
@@ -146,37 +269,108 @@ type ExitData struct {
 	Direction  string `json:"Direction" dynamodbav:"Direction"`
 	TargetRoom int64  `json:"TargetRoom" dynamodbav:"TargetRoom"`
 	Visible    bool   `json:"Visible" dynamodbav:"Visible"`
+	TravelVerb string `json:"TravelVerb,omitempty" dynamodbav:"TravelVerb,omitempty"`
+	Door       bool   `json:"Door,omitempty" dynamodbav:"Door,omitempty"`
+	Closed     bool   `json:"Closed,omitempty" dynamodbav:"Closed,omitempty"`
+	Locked     bool   `json:"Locked,omitempty" dynamodbav:"Locked,omitempty"`
+	KeyID      string `json:"KeyID,omitempty" dynamodbav:"KeyID,omitempty"`
+	Condition  string `json:"Condition,omitempty" dynamodbav:"Condition,omitempty"`
 }
 
-type Character struct {
+// NPC represents a non-player character spawned into a room
+type NPC struct {
 	ID          uuid.UUID
-	Player      *Player
+	TemplateID  string
 	Name        string
-	Attributes  map[string]float64
-	Abilities   map[string]float64
-	Essence     float64
-	Health      float64
+	Description string
+	Emotes      []string
+	Wanders     bool
+	HomeArea    string   // wandering NPCs never leave the area they spawned in
+	Sells       []string // prototypes a merchant has for sale, by name or ID
 	Room        *Room
-	Inventory   map[string]*Item
-	Server      *Server
 	Mutex       sync.Mutex
-	Facing      *Character
-	CombatRange map[uuid.UUID]int // nil when not in combat
-	LastEdited  time.Time
-	LastSaved   time.Time
+}
+
+// NPCData represents the structure for storing NPC definitions in DynamoDB
+type NPCData struct {
+	NPCID       string   `json:"NPCID" dynamodbav:"NPCID"`
+	Name        string   `json:"Name" dynamodbav:"Name"`
+	Description string   `json:"Description" dynamodbav:"Description"`
+	Emotes      []string `json:"Emotes,omitempty" dynamodbav:"Emotes,omitempty"`
+	Wanders     bool     `json:"Wanders" dynamodbav:"Wanders"`
+	Sells       []string `json:"Sells,omitempty" dynamodbav:"Sells,omitempty"`
+}
+
+type Character struct {
+	ID              uuid.UUID
+	Player          *Player
+	Name            string
+	Description     string // shown to others who look at the character
+	Title           string // shown after the name in whois
+	Archetype       string // archetype chosen at creation, empty for characters created without one
+	Attributes      map[string]float64
+	Abilities       map[string]float64
+	Essence         float64
+	Health          float64
+	MaxEssence      float64
+	MaxHealth       float64
+	Room            *Room
+	Inventory       map[string]*Item
+	Server          *Server
+	Mutex           sync.Mutex
+	Facing          *Character
+	CombatRange     map[uuid.UUID]int        // nil when not in combat
+	Attacking       bool                     // attacks the faced character every combat round
+	ProtectedUntil  time.Time                // spawn protection expires at this time
+	Position        string                   // standing, sitting, resting or sleeping; empty means standing
+	Effects         map[string]*ActiveEffect // timed effects, keyed by name
+	Experience      int
+	Level           int
+	TrainingPoints  int            // unspent points for the train command
+	Explored        map[int64]bool // rooms the character has visited
+	Coins           uint64
+	Quests          map[string][]int // progress on each objective of the active quests, keyed by quest ID
+	CompletedQuests map[string]bool
+	Cooldowns       map[string]time.Time     // when each spell may next be cast
+	NextMove        time.Time                // an encumbered character may not move again before this time
+	Snoopers        map[uuid.UUID]*Character // admins mirroring this character's output
+	Snooping        *Character               // character this admin is snooping on
+	SnoopMutex      sync.Mutex               // guards Snoopers and Snooping
+	LinkDead        bool                     // the connection dropped and the character is waiting to be reclaimed
+	LinkDeadTimer   *time.Timer              // removes a link-dead character from the world when it fires
+	Dirty           DirtySection             // sections changed since the last save
+	Version         int64                    // version of the stored record, bumped by every save
+	LastEdited      time.Time
+	LastSaved       time.Time
+	trace           atomic.Pointer[commandTrace] // command being traced, read by Context
+	stats           atomic.Pointer[promptStats]  // state shown in the prompt, refreshed by markDirty
 }
 
 // CharacterData for unmarshalling character.
 type CharacterData struct {
-	CharacterID   string             `json:"CharacterID" dynamodbav:"CharacterID"`
-	PlayerID      string             `json:"PlayerID" dynamodbav:"PlayerID"`
-	CharacterName string             `json:"Name" dynamodbav:"Name"`
-	Attributes    map[string]float64 `json:"Attributes" dynamodbav:"Attributes"`
-	Abilities     map[string]float64 `json:"Abilities" dynamodbav:"Abilities"`
-	Essence       float64            `json:"Essence" dynamodbav:"Essence"`
-	Health        float64            `json:"Health" dynamodbav:"Health"`
-	RoomID        int64              `json:"RoomID" dynamodbav:"RoomID"`
-	Inventory     map[string]string  `json:"Inventory" dynamodbav:"Inventory"`
+	CharacterID     string             `json:"CharacterID" dynamodbav:"CharacterID"`
+	PlayerID        string             `json:"PlayerID" dynamodbav:"PlayerID"`
+	CharacterName   string             `json:"Name" dynamodbav:"Name"`
+	Attributes      map[string]float64 `json:"Attributes" dynamodbav:"Attributes"`
+	Abilities       map[string]float64 `json:"Abilities" dynamodbav:"Abilities"`
+	Essence         float64            `json:"Essence" dynamodbav:"Essence"`
+	Health          float64            `json:"Health" dynamodbav:"Health"`
+	MaxEssence      float64            `json:"MaxEssence" dynamodbav:"MaxEssence"`
+	MaxHealth       float64            `json:"MaxHealth" dynamodbav:"MaxHealth"`
+	RoomID          int64              `json:"RoomID" dynamodbav:"RoomID"`
+	Inventory       map[string]string  `json:"Inventory" dynamodbav:"Inventory"`
+	Effects         []EffectData       `json:"Effects,omitempty" dynamodbav:"Effects,omitempty"`
+	Experience      int                `json:"Experience" dynamodbav:"Experience"`
+	Level           int                `json:"Level" dynamodbav:"Level"`
+	TrainingPoints  int                `json:"TrainingPoints" dynamodbav:"TrainingPoints"`
+	Explored        []int64            `json:"Explored,omitempty" dynamodbav:"Explored,omitempty,numberset"`
+	Coins           uint64             `json:"Coins" dynamodbav:"Coins"`
+	Quests          map[string][]int   `json:"Quests,omitempty" dynamodbav:"Quests,omitempty"`
+	CompletedQuests []string           `json:"CompletedQuests,omitempty" dynamodbav:"CompletedQuests,omitempty"`
+	Description     string             `json:"Description,omitempty" dynamodbav:"Description,omitempty"`
+	Title           string             `json:"Title,omitempty" dynamodbav:"Title,omitempty"`
+	Archetype       string             `json:"Archetype,omitempty" dynamodbav:"Archetype,omitempty"`
+	Version         int64              `json:"Version" dynamodbav:"Version"`
 }
 
 type Archetype struct {
@@ -185,12 +379,15 @@ type Archetype struct {
 	Attributes    map[string]float64 `json:"Attributes" dynamodbav:"Attributes"`
 	Abilities     map[string]float64 `json:"Abilities" dynamodbav:"Abilities"`
 	StartRoom     int64              `json:"StartRoom" dynamodbav:"StartRoom"`
+	StartHealth   float64            `json:"StartHealth,omitempty" dynamodbav:"StartHealth,omitempty"`
+	StartEssence  float64            `json:"StartEssence,omitempty" dynamodbav:"StartEssence,omitempty"`
 }
 
 type Item struct {
 	ID          uuid.UUID
 	PrototypeID uuid.UUID
 	Name        string
+	ShortDesc   string
 	Description string
 	Mass        float64
 	Value       uint64
@@ -199,6 +396,16 @@ type Item struct {
 	Quantity    uint32
 	Wearable    bool
 	WornOn      []string
+	Layer       int     // clothing layer, items on different layers may share a location
+	MinDamage   float64 // weapon damage range, items with no MaxDamage are not weapons
+	MaxDamage   float64
+	DamageType  string
+	Absorb      float64 // damage soaked up when worn as armor
+	Capacity    float64 // mass a container can hold, DefaultContainerCapacity when 0
+	NoDecay     bool    // the item never decays when left on the ground
+	TwoHanded   bool    // the item fills both hands when held
+	LightSource bool    // the item can be lit and extinguished
+	Lit         bool    // the light source is burning
 	Verbs       map[string]string
 	Overrides   map[string]string
 	TraitMods   map[string]int8
@@ -207,6 +414,9 @@ type Item struct {
 	IsWorn      bool
 	CanPickUp   bool
 	Metadata    map[string]string
+	DroppedAt   time.Time // when the item was dropped on the ground, zero for items placed there by builders
+	DecayWarned bool      // the room has been warned the item is about to decay
+	Version     int64     // version of the stored record, bumped by every save
 	Mutex       sync.Mutex
 	LastEdited  time.Time
 	LastSaved   time.Time
@@ -216,6 +426,7 @@ type ItemData struct {
 	ItemID      string            `json:"itemId" dynamodbav:"ItemID"`
 	PrototypeID string            `json:"prototypeID" dynamodbav:"PrototypeID"`
 	Name        string            `json:"name" dynamodbav:"Name"`
+	ShortDesc   string            `json:"short_desc,omitempty" dynamodbav:"ShortDesc,omitempty"`
 	Description string            `json:"description" dynamodbav:"Description"`
 	Mass        float64           `json:"mass" dynamodbav:"Mass"`
 	Value       uint64            `json:"value" dynamodbav:"Value"`
@@ -224,6 +435,17 @@ type ItemData struct {
 	Quantity    uint32            `json:"quantity" dynamodbav:"Quantity"`
 	Wearable    bool              `json:"wearable" dynamodbav:"Wearable"`
 	WornOn      []string          `json:"worn_on" dynamodbav:"WornOn"`
+	Layer       int               `json:"layer,omitempty" dynamodbav:"Layer,omitempty"`
+	MinDamage   float64           `json:"min_damage,omitempty" dynamodbav:"MinDamage,omitempty"`
+	MaxDamage   float64           `json:"max_damage,omitempty" dynamodbav:"MaxDamage,omitempty"`
+	DamageType  string            `json:"damage_type,omitempty" dynamodbav:"DamageType,omitempty"`
+	Absorb      float64           `json:"absorb,omitempty" dynamodbav:"Absorb,omitempty"`
+	Capacity    float64           `json:"capacity,omitempty" dynamodbav:"Capacity,omitempty"`
+	NoDecay     bool              `json:"no_decay,omitempty" dynamodbav:"NoDecay,omitempty"`
+	TwoHanded   bool              `json:"two_handed,omitempty" dynamodbav:"TwoHanded,omitempty"`
+	LightSource bool              `json:"light_source,omitempty" dynamodbav:"LightSource,omitempty"`
+	Lit         bool              `json:"lit,omitempty" dynamodbav:"Lit,omitempty"`
+	DroppedAt   int64             `json:"dropped_at,omitempty" dynamodbav:"DroppedAt,omitempty"` // Unix time
 	Verbs       map[string]string `json:"verbs" dynamodbav:"Verbs"`
 	Overrides   map[string]string `json:"overrides" dynamodbav:"Overrides"`
 	TraitMods   map[string]int8   `json:"trait_mods" dynamodbav:"TraitMods"`
@@ -232,6 +454,7 @@ type ItemData struct {
 	IsWorn      bool              `json:"is_worn" dynamodbav:"IsWorn"`
 	CanPickUp   bool              `json:"can_pick_up" dynamodbav:"CanPickUp"`
 	Metadata    map[string]string `json:"metadata" dynamodbav:"Metadata"`
+	Version     int64             `json:"version" dynamodbav:"Version"`
 }
 
 type Prototype struct {
@@ -245,6 +468,15 @@ type Prototype struct {
 	Quantity    uint32
 	Wearable    bool
 	WornOn      []string
+	Layer       int     // clothing layer, items on different layers may share a location
+	MinDamage   float64 // weapon damage range, items with no MaxDamage are not weapons
+	MaxDamage   float64
+	DamageType  string
+	Absorb      float64 // damage soaked up when worn as armor
+	Capacity    float64 // mass a container can hold, DefaultContainerCapacity when 0
+	NoDecay     bool    // items made from the prototype never decay on the ground
+	TwoHanded   bool    // items made from the prototype fill both hands when held
+	LightSource bool    // items made from the prototype can be lit and extinguished
 	Verbs       map[string]string
 	Overrides   map[string]string
 	TraitMods   map[string]int8
@@ -258,7 +490,7 @@ type Prototype struct {
 }
 
 type PrototypeData struct {
-	PrototypeID string            `json:"id" dynamodbav:"prototypeID"`
+	PrototypeID string            `json:"id" dynamodbav:"PrototypeID"`
 	Name        string            `json:"name" dynamodbav:"name"`
 	Description string            `json:"description" dynamodbav:"description"`
 	Mass        float64           `json:"mass" dynamodbav:"mass"`
@@ -268,6 +500,15 @@ type PrototypeData struct {
 	Quantity    uint32            `json:"quantity" dynamodbav:"quantity"`
 	Wearable    bool              `json:"wearable" dynamodbav:"wearable"`
 	WornOn      []string          `json:"worn_on" dynamodbav:"worn_on"`
+	Layer       int               `json:"layer,omitempty" dynamodbav:"layer,omitempty"`
+	MinDamage   float64           `json:"min_damage,omitempty" dynamodbav:"min_damage,omitempty"`
+	MaxDamage   float64           `json:"max_damage,omitempty" dynamodbav:"max_damage,omitempty"`
+	DamageType  string            `json:"damage_type,omitempty" dynamodbav:"damage_type,omitempty"`
+	Absorb      float64           `json:"absorb,omitempty" dynamodbav:"absorb,omitempty"`
+	Capacity    float64           `json:"capacity,omitempty" dynamodbav:"capacity,omitempty"`
+	NoDecay     bool              `json:"no_decay,omitempty" dynamodbav:"no_decay,omitempty"`
+	TwoHanded   bool              `json:"two_handed,omitempty" dynamodbav:"two_handed,omitempty"`
+	LightSource bool              `json:"light_source,omitempty" dynamodbav:"light_source,omitempty"`
 	Verbs       map[string]string `json:"verbs" dynamodbav:"verbs"`
 	Overrides   map[string]string `json:"overrides" dynamodbav:"overrides"`
 	TraitMods   map[string]int8   `json:"trait_mods" dynamodbav:"trait_mods"`
@@ -278,12 +519,8 @@ type PrototypeData struct {
 }
 
 type CloudWatchHandler struct {
-	client      *cloudwatchlogs.Client
-	logGroup    string
-	logStream   string
-	attrs       []slog.Attr
-	mutex       sync.Mutex
-	initialized bool
+	writer *cloudWatchWriter
+	attrs  []slog.Attr
 }
 
 type MultiHandler struct {
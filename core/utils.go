@@ -2,12 +2,14 @@ package core
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"math"
-	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 )
 
 func Challenge(attacker, defender, balance float64) float64 {
@@ -17,8 +19,8 @@ func Challenge(attacker, defender, balance float64) float64 {
 	// Simplified sigmoid function evaluation at x=0 with shift
 	sigmoidValue := 1 / (1 + math.Exp(balance*diff))
 
-	// Generate a random float64 number
-	randomNumber := rand.Float64()
+	// Generate a random float64 number from the seedable combat source
+	randomNumber := CombatFloat64()
 
 	// Divide the random number by the sigmoid value
 	result := randomNumber / sigmoidValue
@@ -31,33 +33,45 @@ func AutoSave(server *Server) {
 
 	for {
 		// Sleep for the configured duration
-		time.Sleep(time.Duration(server.AutoSave) * time.Minute)
+		time.Sleep(time.Duration(server.Settings().Game.AutoSave) * time.Minute)
 
 		Logger.Info("Starting auto-save process...")
+		status := AutoSaveStatus{Started: time.Now()}
+		var errs []error
 
 		// Save active characters
-		if err := server.SaveActiveCharacters(); err != nil {
+		if err := server.SaveActiveCharacters(server.Context); err != nil {
 			Logger.Error("Failed to save characters", "error", err)
+			errs = append(errs, err)
 		} else {
 			Logger.Info("Active characters saved successfully")
 		}
 
 		// Save active items
-		if err := server.SaveActiveItems(); err != nil {
+		if err := server.SaveActiveItems(server.Context); err != nil {
 			Logger.Error("Failed to save items", "error", err)
+			errs = append(errs, err)
 		} else {
 			Logger.Info("Active items saved successfully")
 		}
 
-		Logger.Info("Auto-save process completed")
-
 		// Save active rooms
-		if err := server.SaveActiveRooms(); err != nil {
+		if err := server.SaveActiveRooms(server.Context); err != nil {
 			Logger.Error("Failed to save rooms", "error", err)
+			errs = append(errs, err)
 		} else {
 			Logger.Info("Active rooms saved successfully")
 		}
 
+		Logger.Info("Auto-save process completed")
+
+		status.Completed = time.Now()
+		if err := errors.Join(errs...); err != nil {
+			status.Error = err.Error()
+		}
+		server.Mutex.Lock()
+		server.LastAutoSave = status
+		server.Mutex.Unlock()
 	}
 }
 
@@ -112,6 +126,45 @@ func (i *Index) SetID(id uint64) {
 	}
 }
 
+// Default locations of the data files, relative to the server's working directory.
+const (
+	DefaultNamesFile     = "../data/names.txt"
+	DefaultObscenityFile = "../data/obscenity.txt"
+	DefaultBannerFile    = "../data/banner.txt"
+)
+
+// ResolveDataPath returns an absolute path for a data file. Relative paths are resolved
+// against the working directory first and then against the directory of the executable,
+// so the server can be launched from anywhere.
+func ResolveDataPath(path, defaultPath string) string {
+	if path == "" {
+		path = defaultPath
+	}
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if absPath, err := filepath.Abs(path); err == nil {
+			return absPath
+		}
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(executable), path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	// Fall back to the working directory so errors report a meaningful path
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
+
 // loadNamesFromFile reads a file line by line and returns a slice of names.
 func loadNamesFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
@@ -136,3 +189,48 @@ func loadNamesFromFile(filePath string) ([]string, error) {
 
 	return names, nil
 }
+
+// loadWordSet reads a file of names or words and returns them as an exact lookup set.
+func loadWordSet(filePath string) (map[string]bool, error) {
+	words, err := loadNamesFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+
+	return set, nil
+}
+
+// SanitizeText strips control characters and surrounding whitespace from player supplied text
+// and checks that the result is not empty and no longer than maxLength characters.
+func SanitizeText(text string, maxLength int) (string, error) {
+	cleaned := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text))
+
+	if cleaned == "" {
+		return "", fmt.Errorf("text cannot be empty")
+	}
+
+	if len([]rune(cleaned)) > maxLength {
+		return "", fmt.Errorf("text must be %d characters or fewer", maxLength)
+	}
+
+	return cleaned, nil
+}
+
+// capitalizeError formats an error for a player, starting with a capital letter.
+func capitalizeError(err error) string {
+	message := err.Error()
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
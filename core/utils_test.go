@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/google/uuid"
+)
+
+func TestMain(m *testing.M) {
+	// Logging is set up by the server from its configuration, so tests discard it
+	Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
+
+// newTestServer returns a server backed by a BoltDB file in a temporary directory, holding a
+// room for each of the given IDs. New characters start with 10 health and 3 essence in room 1.
+func newTestServer(t *testing.T, roomIDs ...int64) *Server {
+	t.Helper()
+
+	database, err := NewBoltKeyPair(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	server := &Server{
+		Context:              context.Background(),
+		Database:             database,
+		Rooms:                make(map[int64]*Room),
+		Characters:           make(map[uuid.UUID]*Character),
+		ArcheTypes:           make(map[string]*Archetype),
+		CharacterBloomFilter: bloom.NewWithEstimates(100, 0.01),
+	}
+	server.Config.Game.StartingHealth = 10
+	server.Config.Game.StartingEssence = 3
+	server.Config.Game.StartRoom = 1
+
+	for _, roomID := range roomIDs {
+		server.Rooms[roomID] = &Room{
+			RoomID:     roomID,
+			Title:      fmt.Sprintf("Room %d", roomID),
+			Exits:      make(map[string]*Exit),
+			Characters: make(map[uuid.UUID]*Character),
+			Items:      make(map[uuid.UUID]*Item),
+		}
+	}
+
+	return server
+}
+
+// newTestPlayer returns a player whose output is read and thrown away until the test ends.
+// Input lines are queued on FromPlayer before the code under test reads them.
+func newTestPlayer(t *testing.T, playerID string, input ...string) *Player {
+	t.Helper()
+
+	player := &Player{
+		PlayerID:   playerID,
+		ToPlayer:   make(chan string),
+		FromPlayer: make(chan string, len(input)),
+	}
+	for _, line := range input {
+		player.FromPlayer <- line
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-player.ToPlayer:
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { close(done) })
+
+	return player
+}
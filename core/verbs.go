@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item verb actions are a list of statements separated by semicolons. Each statement starts with one of
+// the keywords below; a statement without a keyword is shown to the character, so plain verb text such as
+// "You light the torch." still works. Keywords are lowercase, so sentences are not mistaken for them. $n in a statement is replaced with the character's name.
+//
+//	msg <text>          show text to the character
+//	room <text>         show text to everyone else in the room
+//	open <direction>    reveal, unlock and open the exit in that direction, and its far side
+//	spawn <prototype>   leave an item made from a prototype name or ID on the ground
+//	teleport <roomID>   move the character to another room
+//	effect <name> [s]   apply a timed effect to the character, for its default or the given seconds
+const (
+	ActionMessage  = "msg"
+	ActionRoom     = "room"
+	ActionOpen     = "open"
+	ActionSpawn    = "spawn"
+	ActionTeleport = "teleport"
+	ActionEffect   = "effect"
+)
+
+// ItemVerb returns the action for a verb typed at the item, following its overrides,
+// and whether the item understands the verb at all.
+func (i *Item) ItemVerb(verb string) (string, bool) {
+	if action, exists := i.Verbs[verb]; exists {
+		return action, true
+	}
+	if alias, exists := i.Overrides[verb]; exists {
+		action, exists := i.Verbs[alias]
+		return action, exists
+	}
+	return "", false
+}
+
+// VerbNames returns the sorted verbs that can be used on the item, including overrides.
+func (i *Item) VerbNames() []string {
+	verbs := make([]string, 0, len(i.Verbs)+len(i.Overrides))
+	for verb := range i.Verbs {
+		verbs = append(verbs, verb)
+	}
+	for verb, alias := range i.Overrides {
+		if _, exists := i.Verbs[alias]; exists {
+			verbs = append(verbs, verb)
+		}
+	}
+	sort.Strings(verbs)
+	return verbs
+}
+
+// itemsInReach returns the items the character is carrying followed by those lying in the room.
+func (c *Character) itemsInReach() []*Item {
+	c.Mutex.Lock()
+	items := make([]*Item, 0, len(c.Inventory))
+	seen := make(map[*Item]bool)
+	for _, item := range c.Inventory {
+		if !seen[item] {
+			items = append(items, item)
+			seen[item] = true
+		}
+	}
+	room := c.Room
+	c.Mutex.Unlock()
+
+	if room != nil {
+		room.Mutex.Lock()
+		for _, item := range room.Items {
+			items = append(items, item)
+		}
+		room.Mutex.Unlock()
+	}
+
+	return items
+}
+
+// findItemInReach returns the first item being carried, then lying in the room, whose name contains the given text.
+func (c *Character) findItemInReach(name string) *Item {
+	lowercaseName := strings.ToLower(name)
+	for _, item := range c.itemsInReach() {
+		if strings.Contains(strings.ToLower(item.Name), lowercaseName) {
+			return item
+		}
+	}
+	return nil
+}
+
+// UseItemVerb runs an item verb such as "pull lever" for input that is not a command.
+// It returns false when no item in reach understands the verb, so the input can be rejected as usual.
+func (c *Character) UseItemVerb(tokens []string) bool {
+	if len(tokens) < 2 {
+		return false
+	}
+
+	verb := strings.ToLower(tokens[0])
+	target := strings.ToLower(strings.Join(tokens[1:], " "))
+
+	for _, item := range c.itemsInReach() {
+		if !strings.Contains(strings.ToLower(item.Name), target) {
+			continue
+		}
+		action, exists := item.ItemVerb(verb)
+		if !exists {
+			continue
+		}
+
+		Logger.Info("Player used item verb", "playerName", c.Player.PlayerID, "verb", verb, "itemName", item.Name)
+		c.runAction(item, action)
+		return true
+	}
+
+	return false
+}
+
+// runAction executes the statements of an item verb action in order, stopping at the first that fails.
+func (c *Character) runAction(item *Item, action string) {
+	for _, statement := range strings.Split(action, ";") {
+		statement = strings.TrimSpace(strings.ReplaceAll(statement, "$n", c.Name))
+		if statement == "" {
+			continue
+		}
+
+		keyword, argument, _ := strings.Cut(statement, " ")
+		argument = strings.TrimSpace(argument)
+
+		var err error
+		switch keyword {
+		case ActionMessage:
+			c.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", argument)
+		case ActionRoom:
+			sendToRoomExcept(c.Room, fmt.Sprintf("\n\r%s\n\r", argument), c)
+		case ActionOpen:
+			err = c.actionOpen(strings.ToLower(argument))
+		case ActionSpawn:
+			err = c.actionSpawn(argument)
+		case ActionTeleport:
+			room, found := parseRoomID(c.Server, argument)
+			if !found {
+				err = fmt.Errorf("there is no room %s", argument)
+			} else {
+				c.Teleport(room)
+			}
+		case ActionEffect:
+			err = c.actionEffect(argument)
+		default:
+			c.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", statement)
+		}
+
+		if err != nil {
+			Logger.Warn("Item verb action failed", "itemName", item.Name, "prototypeID", item.PrototypeID, "statement", statement, "error", err)
+			c.Player.ToPlayer <- "\n\rNothing happens.\n\r"
+			return
+		}
+	}
+}
+
+// actionOpen reveals, unlocks and opens an exit from the character's room, along with its far side.
+func (c *Character) actionOpen(direction string) error {
+	room := c.Room
+
+	room.Mutex.Lock()
+	exit, exists := room.Exits[direction]
+	room.Mutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("there is no exit %s", direction)
+	}
+
+	back := farSide(room, exit)
+
+	for _, side := range []struct {
+		room *Room
+		exit *Exit
+	}{{room, exit}, {exit.TargetRoom, back}} {
+		if side.exit == nil {
+			continue
+		}
+
+		side.room.Mutex.Lock()
+		side.exit.Visible = true
+		side.exit.Closed = false
+		side.exit.Locked = false
+		side.exit.LastEdited = time.Now()
+		side.room.LastEdited = time.Now()
+		side.room.Mutex.Unlock()
+
+		if err := c.Server.Database.WriteRoom(c.Server.Context, side.room); err != nil {
+			Logger.Error("Error saving room after item opened exit", "room_id", side.room.RoomID, "direction", side.exit.Direction, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// actionEffect applies the named effect to the character, optionally for a number of seconds.
+func (c *Character) actionEffect(argument string) error {
+	fields := strings.Fields(argument)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("expected an effect name and optional seconds, got %q", argument)
+	}
+
+	var duration time.Duration
+	if len(fields) == 2 {
+		seconds, err := strconv.Atoi(fields[1])
+		if err != nil || seconds <= 0 {
+			return fmt.Errorf("invalid effect duration %q", fields[1])
+		}
+		duration = time.Duration(seconds) * time.Second
+	}
+
+	return c.ApplyEffect(strings.ToLower(fields[0]), duration)
+}
+
+// actionSpawn leaves a new item made from a prototype on the ground of the character's room.
+func (c *Character) actionSpawn(prototypeName string) error {
+	prototype := findPrototype(c.Server, prototypeName)
+	if prototype == nil {
+		return fmt.Errorf("there is no prototype called %s", prototypeName)
+	}
+
+	item, err := c.Server.CreateItemFromPrototype(prototype.ID)
+	if err != nil {
+		return fmt.Errorf("unable to create item: %w", err)
+	}
+
+	c.Room.DropItem(item)
+	return nil
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Weather conditions an area can have.
+const (
+	WeatherClear  = "clear"
+	WeatherCloudy = "cloudy"
+	WeatherFog    = "fog"
+	WeatherRain   = "rain"
+	WeatherStorm  = "storm"
+	WeatherSnow   = "snow"
+)
+
+// DefaultWeatherSeconds is how often the weather may change when not configured.
+const DefaultWeatherSeconds = 300
+
+// DefaultClimate is the weather areas without their own list move between. Repeated
+// conditions are more likely.
+var DefaultClimate = []string{WeatherClear, WeatherClear, WeatherCloudy, WeatherRain}
+
+// WeatherDescriptions describe each condition in outdoor rooms.
+var WeatherDescriptions = map[string]string{
+	WeatherClear:  "The sky is clear.",
+	WeatherCloudy: "Clouds hang low overhead.",
+	WeatherFog:    "A thick fog clings to the ground.",
+	WeatherRain:   "Rain patters down all around you.",
+	WeatherStorm:  "Thunder rumbles as a storm lashes the land.",
+	WeatherSnow:   "Snow drifts softly down.",
+}
+
+// WeatherChanges announce each condition to characters outdoors when it begins.
+var WeatherChanges = map[string]string{
+	WeatherClear:  "The skies clear.",
+	WeatherCloudy: "Clouds gather overhead.",
+	WeatherFog:    "A fog rolls in.",
+	WeatherRain:   "It begins to rain.",
+	WeatherStorm:  "A storm breaks overhead.",
+	WeatherSnow:   "It begins to snow.",
+}
+
+// weatherMutex guards Server.Weather. It is never held while taking another lock, so the
+// weather can be read while holding room or character locks.
+var weatherMutex sync.Mutex
+
+// CurrentWeather returns the weather in the room's area.
+func (s *Server) CurrentWeather(room *Room) string {
+	if room == nil {
+		return WeatherClear
+	}
+
+	weatherMutex.Lock()
+	defer weatherMutex.Unlock()
+
+	if weather, exists := s.Weather[room.Area]; exists {
+		return weather
+	}
+	return WeatherClear
+}
+
+// ConditionMet reports whether a condition on an exit or spawn point holds in the room. A condition
+// is a day phase ("day" or "night") or a weather condition, and a leading "!" negates it.
+// An empty condition always holds.
+func (s *Server) ConditionMet(room *Room, condition string) bool {
+	if condition == "" {
+		return true
+	}
+
+	negate := strings.HasPrefix(condition, "!")
+	condition = strings.ToLower(strings.TrimPrefix(condition, "!"))
+
+	met := condition == s.DayPhase() || condition == s.CurrentWeather(room)
+	return met != negate
+}
+
+// WeatherLoop changes the weather every WeatherSeconds and announces dawn and dusk until the
+// server context is cancelled.
+func WeatherLoop(s *Server) {
+	seconds := s.Settings().Game.WeatherSeconds
+	if seconds == 0 {
+		seconds = DefaultWeatherSeconds
+	}
+	interval := time.Duration(seconds) * time.Second
+
+	Logger.Info("Starting weather loop", "interval", interval)
+
+	s.ChangeWeather()
+
+	// Dawn and dusk are checked every game minute, so they are announced close to the hour
+	secondsPerHour := time.Duration(s.Settings().Game.SecondsPerHour)
+	if secondsPerHour == 0 {
+		secondsPerHour = DefaultSecondsPerHour
+	}
+	phaseTicker := time.NewTicker(secondsPerHour * time.Second / 60)
+	defer phaseTicker.Stop()
+
+	weatherTicker := time.NewTicker(interval)
+	defer weatherTicker.Stop()
+
+	phase := s.DayPhase()
+	for {
+		select {
+		case <-weatherTicker.C:
+			s.ChangeWeather()
+		case <-phaseTicker.C:
+			if current := s.DayPhase(); current != phase {
+				phase = current
+				message := "\n\rThe sun rises.\n\r"
+				if phase == "night" {
+					message = "\n\rThe sun sets and night falls.\n\r"
+				}
+				s.announceOutdoors(func(*Room) bool { return true }, message)
+			}
+		case <-s.Context.Done():
+			Logger.Info("Stopping weather loop due to context cancellation")
+			return
+		}
+	}
+}
+
+// ChangeWeather picks new weather for every area from its climate and tells characters outdoors
+// in the areas where it changed.
+func (s *Server) ChangeWeather() {
+	s.Mutex.Lock()
+	climates := make(map[string][]string)
+	for _, room := range s.Rooms {
+		if room == nil {
+			continue
+		}
+		if _, exists := climates[room.Area]; exists {
+			continue
+		}
+		climate := DefaultClimate
+		if area, exists := s.Areas[room.Area]; exists && len(area.Weather) > 0 {
+			climate = area.Weather
+		}
+		climates[room.Area] = climate
+	}
+	s.Mutex.Unlock()
+
+	changed := make(map[string]string)
+
+	weatherMutex.Lock()
+	if s.Weather == nil {
+		s.Weather = make(map[string]string)
+	}
+	for area, climate := range climates {
+		weather := climate[rand.Intn(len(climate))]
+		if previous, exists := s.Weather[area]; exists && previous != weather {
+			changed[area] = weather
+		}
+		s.Weather[area] = weather
+	}
+	weatherMutex.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+
+	Logger.Info("Weather changed", "areas", len(changed))
+	for area, weather := range changed {
+		message := fmt.Sprintf("\n\r%s\n\r", WeatherChanges[weather])
+		s.announceOutdoors(func(room *Room) bool { return room.Area == area }, message)
+	}
+}
+
+// announceOutdoors sends a message to every outdoor room the filter accepts.
+func (s *Server) announceOutdoors(filter func(*Room) bool, message string) {
+	s.Mutex.Lock()
+	rooms := make([]*Room, 0)
+	for _, room := range s.Rooms {
+		if room != nil && room.HasFlag(RoomFlagOutdoor) && filter(room) {
+			rooms = append(rooms, room)
+		}
+	}
+	s.Mutex.Unlock()
+
+	for _, room := range rooms {
+		SendRoomMessage(room, message)
+	}
+}
+
+func ExecuteWeatherCommand(character *Character, tokens []string) bool {
+
+	Logger.Info("Player is checking the weather", "playerName", character.Player.PlayerID)
+
+	room := character.Room
+	weather := character.Server.CurrentWeather(room)
+
+	if room == nil || !room.HasFlag(RoomFlagOutdoor) {
+		character.Player.ToPlayer <- fmt.Sprintf("\n\rYou are indoors. Outside, the weather is %s.\n\r", weather)
+		return false
+	}
+
+	character.Player.ToPlayer <- fmt.Sprintf("\n\r%s\n\r", WeatherDescriptions[weather])
+	return false
+}
@@ -112,10 +112,19 @@ def create_new_item_from_prototype(prototype: dict) -> dict:
         "Quantity": Decimal("1"),
         "Wearable": prototype.get("Wearable", False),
         "WornOn": prototype.get("WornOn", []),
+        "Layer": Decimal(str(prototype.get("Layer", 0))),
+        "MinDamage": Decimal(str(prototype.get("MinDamage", 0))),
+        "MaxDamage": Decimal(str(prototype.get("MaxDamage", 0))),
+        "DamageType": prototype.get("DamageType", ""),
+        "Absorb": Decimal(str(prototype.get("Absorb", 0))),
         "Verbs": prototype.get("Verbs", {}),
         "Overrides": prototype.get("Overrides", {}),
         "TraitMods": {k: Decimal(str(v)) for k, v in prototype.get("TraitMods", {}).items()},
         "Container": prototype.get("Container", False),
+        "Capacity": Decimal(str(prototype.get("Capacity", 0))),
+        "NoDecay": prototype.get("NoDecay", False),
+        "TwoHanded": prototype.get("TwoHanded", False),
+        "LightSource": prototype.get("LightSource", False),
         "Contents": prototype.get("Contents", []),
         "IsWorn": False,
         "CanPickUp": prototype.get("CanPickUp", True),
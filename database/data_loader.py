@@ -63,6 +63,16 @@ def store_exits(dynamodb, exits_data):
                     "TargetRoom": exit_data["TargetRoom"],
                     "Visible": exit_data["Visible"],
                 }
+                if exit_data.get("TravelVerb"):
+                    exit_item["TravelVerb"] = exit_data["TravelVerb"]
+                if exit_data.get("Door"):
+                    exit_item["Door"] = True
+                    exit_item["Closed"] = exit_data.get("Closed", False)
+                    exit_item["Locked"] = exit_data.get("Locked", False)
+                    if exit_data.get("KeyID"):
+                        exit_item["KeyID"] = exit_data["KeyID"]
+                if exit_data.get("Condition"):
+                    exit_item["Condition"] = exit_data["Condition"]
                 exits_batch.put_item(Item=convert_to_dynamodb_format(exit_item))
         print("Exit data stored in DynamoDB successfully")
     except ClientError as e:
@@ -71,6 +81,33 @@ def store_exits(dynamodb, exits_data):
         logging.error(f"An unexpected error occurred while storing exits: {str(e)}")
 
 
+def store_areas(dynamodb, areas_data):
+    """
+    Stores area definitions into the 'areas' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+        areas_data (dict): The area data to store.
+    """
+    table = dynamodb.Table("areas")
+    try:
+        with table.batch_writer() as batch:
+            for area in areas_data.get("areas", []):
+                area_item = {
+                    "AreaID": area["AreaID"],
+                    "Name": area.get("Name", area["AreaID"]),
+                }
+                for key in ("MinLevel", "MaxLevel", "PvP", "RespawnRoom", "SpawnScale", "Weather"):
+                    if area.get(key):
+                        area_item[key] = area[key]
+                batch.put_item(Item=convert_to_dynamodb_format(area_item))
+        print("Area data stored in DynamoDB successfully")
+    except ClientError as e:
+        logging.error(f"An error occurred while storing areas: {e.response['Error']['Message']}")
+    except Exception as e:
+        logging.error(f"An unexpected error occurred while storing areas: {str(e)}")
+
+
 def store_rooms(dynamodb, rooms_data):
     """
     Stores room data into the 'rooms' DynamoDB table.
@@ -91,6 +128,15 @@ def store_rooms(dynamodb, rooms_data):
                     "ExitID": room["ExitID"],
                     "ItemID": room.get("ItemID", []),
                 }
+                if room.get("Flags"):
+                    room_item["Flags"] = room["Flags"]
+                if room.get("Spawns"):
+                    room_item["Spawns"] = room["Spawns"]
+                if room.get("SpawnPoints"):
+                    room_item["SpawnPoints"] = room["SpawnPoints"]
+                if room.get("Ambience"):
+                    room_item["Ambience"] = room["Ambience"]
+                    room_item["AmbienceEnabled"] = room.get("AmbienceEnabled", True)
                 rooms_batch.put_item(Item=convert_to_dynamodb_format(room_item))
         print("Room data stored in DynamoDB successfully")
     except ClientError as e:
@@ -147,6 +193,65 @@ def store_item_prototypes(dynamodb, prototypes_data):
         logging.error(f"An unexpected error occurred while storing item prototypes: {str(err)}")
 
 
+def store_npcs(dynamodb, npcs_data):
+    """
+    Stores NPC definitions into the 'npcs' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+        npcs_data (dict): The NPC data to store.
+    """
+    table = dynamodb.Table("npcs")
+    try:
+        with table.batch_writer() as batch:
+            for npc in npcs_data.get("npcs", []):
+                npc_item = {
+                    "NPCID": npc["NPCID"],
+                    "Name": npc["Name"],
+                    "Description": npc.get("Description", ""),
+                    "Wanders": npc.get("Wanders", False),
+                }
+                if npc.get("Emotes"):
+                    npc_item["Emotes"] = npc["Emotes"]
+                if npc.get("Sells"):
+                    npc_item["Sells"] = npc["Sells"]
+                batch.put_item(Item=convert_to_dynamodb_format(npc_item))
+        print("NPC data stored in DynamoDB successfully")
+    except ClientError as e:
+        logging.error(f"An error occurred while storing NPCs: {e.response['Error']['Message']}")
+    except Exception as e:
+        logging.error(f"An unexpected error occurred while storing NPCs: {str(e)}")
+
+
+def store_quests(dynamodb, quests_data):
+    """
+    Stores quest definitions into the 'quests' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+        quests_data (dict): The quest data to store.
+    """
+    table = dynamodb.Table("quests")
+    try:
+        with table.batch_writer() as batch:
+            for quest in quests_data.get("quests", []):
+                quest_item = {
+                    "QuestID": quest["QuestID"],
+                    "Title": quest["Title"],
+                    "Description": quest.get("Description", ""),
+                    "Objectives": quest.get("Objectives", []),
+                }
+                for key in ("MinLevel", "RewardExperience", "RewardCoins", "RewardItems"):
+                    if quest.get(key):
+                        quest_item[key] = quest[key]
+                batch.put_item(Item=convert_to_dynamodb_format(quest_item))
+        print("Quest data stored in DynamoDB successfully")
+    except ClientError as e:
+        logging.error(f"An error occurred while storing quests: {e.response['Error']['Message']}")
+    except Exception as e:
+        logging.error(f"An unexpected error occurred while storing quests: {str(e)}")
+
+
 def load_exits(dynamodb):
     """
     Loads exit data from the 'exits' DynamoDB table.
@@ -171,6 +276,27 @@ def load_exits(dynamodb):
         return {}
 
 
+def load_areas(dynamodb):
+    """
+    Loads area definitions from the 'areas' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+
+    Returns:
+        dict: A dictionary of area data.
+    """
+    table = dynamodb.Table("areas")
+    try:
+        response = table.scan()
+        areas = {item["AreaID"]: item for item in response.get("Items", [])}
+        print("Area data loaded from DynamoDB successfully")
+        return areas
+    except ClientError as e:
+        logging.error(f"An error occurred while loading areas: {e.response['Error']['Message']}")
+        return {}
+
+
 def load_rooms(dynamodb):
     """
     Loads room data from the 'rooms' DynamoDB table.
@@ -243,6 +369,30 @@ def load_item_prototypes(dynamodb):
         return {}
 
 
+def load_npcs(dynamodb):
+    """
+    Loads NPC definitions from the 'npcs' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+
+    Returns:
+        dict: A dictionary of NPC data.
+    """
+    table = dynamodb.Table("npcs")
+    try:
+        response = table.scan()
+        npcs = {item["NPCID"]: item for item in response.get("Items", [])}
+        print("NPC data loaded from DynamoDB successfully")
+        return npcs
+    except ClientError as e:
+        logging.error(f"An error occurred while loading NPCs: {e.response['Error']['Message']}")
+        return {}
+    except Exception as e:
+        logging.error(f"An unexpected error occurred while loading NPCs: {str(e)}")
+        return {}
+
+
 def display_exits(exits):
     """
     Displays exit information.
@@ -256,6 +406,31 @@ def display_exits(exits):
         print(f"  Direction: {exit_data['Direction']}")
         print(f"  Target Room: {exit_data['TargetRoom']}")
         print(f"  Visible: {exit_data['Visible']}")
+        if exit_data.get("TravelVerb"):
+            print(f"  Travel Verb: {exit_data['TravelVerb']}")
+        if exit_data.get("Door"):
+            print(f"  Door: closed={exit_data.get('Closed', False)} locked={exit_data.get('Locked', False)} key={exit_data.get('KeyID', '')}")
+        print()
+
+
+def display_areas(areas):
+    """
+    Displays area information.
+
+    Args:
+        areas (dict): The area data to display.
+    """
+    print("Areas:")
+    for area_id, area in areas.items():
+        print(f"Area {area_id}: {area.get('Name', 'No Name')}")
+        print(f"  Levels: {area.get('MinLevel', 0)}-{area.get('MaxLevel', 0)}")
+        print(f"  PvP: {area.get('PvP', False)}")
+        if area.get("RespawnRoom"):
+            print(f"  Respawn Room: {area['RespawnRoom']}")
+        if area.get("SpawnScale"):
+            print(f"  Spawn Scale: {area['SpawnScale']}")
+        if area.get("Weather"):
+            print(f"  Weather: {', '.join(area['Weather'])}")
         print()
 
 
@@ -273,6 +448,15 @@ def display_rooms(rooms):
         print(f"  Description: {room.get('Description', 'No description')}")
         print(f"  Exits: {', '.join(room.get('ExitID', []))}")
         print(f"  Items: {', '.join(room.get('ItemID', []))}")
+        if room.get("Spawns"):
+            print(f"  Spawns: {', '.join(room['Spawns'])}")
+        for point in room.get("SpawnPoints", []):
+            print(f"  Spawn point: {point.get('Kind')} {point.get('ID')} (max {point.get('MaxCount')}, every {point.get('RespawnSeconds')}s)")
+        if room.get("Ambience"):
+            state = "on" if room.get("AmbienceEnabled") else "off"
+            print(f"  Ambience ({state}):")
+            for message in room["Ambience"]:
+                print(f"    {message}")
         print()
 
 
@@ -316,6 +500,65 @@ def display_item_prototypes(prototypes):
         print()
 
 
+def load_quests(dynamodb):
+    """
+    Loads quest definitions from the 'quests' DynamoDB table.
+
+    Args:
+        dynamodb: The DynamoDB resource object.
+
+    Returns:
+        dict: A dictionary of quest data.
+    """
+    table = dynamodb.Table("quests")
+    try:
+        response = table.scan()
+        quests = {item["QuestID"]: item for item in response.get("Items", [])}
+        print("Quest data loaded from DynamoDB successfully")
+        return quests
+    except ClientError as e:
+        logging.error(f"An error occurred while loading quests: {e.response['Error']['Message']}")
+        return {}
+
+
+def display_npcs(npcs):
+    """
+    Displays NPC information.
+
+    Args:
+        npcs (dict): The NPC data to display.
+    """
+    print("NPCs:")
+    for npc_id, npc in npcs.items():
+        print(f"NPC {npc_id}: {npc.get('Name', 'No Name')}")
+        print(f"  Description: {npc.get('Description', 'No description')}")
+        print(f"  Wanders: {npc.get('Wanders', False)}")
+        for emote in npc.get("Emotes", []):
+            print(f"  Emote: {emote}")
+        for good in npc.get("Sells", []):
+            print(f"  Sells: {good}")
+        print()
+
+
+def display_quests(quests):
+    """
+    Displays quest information.
+
+    Args:
+        quests (dict): The quest data to display.
+    """
+    print("Quests:")
+    for quest_id, quest in quests.items():
+        print(f"Quest {quest_id}: {quest.get('Title', 'No Title')}")
+        print(f"  Description: {quest.get('Description', 'No description')}")
+        for objective in quest.get("Objectives", []):
+            print(f"  Objective: {objective.get('Type')} {objective.get('Target')} x{objective.get('Count')}")
+        print(f"  Rewards: {quest.get('RewardExperience', 0)} experience, {quest.get('RewardCoins', 0)} coins")
+        for item in quest.get("RewardItems", []):
+            print(f"  Reward item: {item}")
+        print()
+
+
 def main():
     """
     Main function to load game data from JSON files and store it in DynamoDB.
@@ -326,10 +569,13 @@ def main():
     - Loads data back from DynamoDB and displays it.
     """
     parser = argparse.ArgumentParser(description="Load and store game data in DynamoDB.")
+    parser.add_argument("-z", "--areas", default="../data/test_areas.json", help="Path to the Areas JSON file.")
     parser.add_argument("-r", "--rooms", default="../data/test_rooms.json", help="Path to the Rooms JSON file.")
     parser.add_argument("-e", "--exits", default="../data/test_exits.json", help="Path to the Exits JSON file.")
     parser.add_argument("-a", "--archetypes", default="../data/test_archetypes.json", help="Path to the Archetypes JSON file.")
     parser.add_argument("-p", "--prototypes", default="../data/test_prototypes.json", help="Path to the Prototypes JSON file.")
+    parser.add_argument("-n", "--npcs", default="../data/test_npcs.json", help="Path to the NPCs JSON file.")
+    parser.add_argument("-q", "--quests", default="../data/test_quests.json", help="Path to the Quests JSON file.")
     parser.add_argument("-region", default="us-east-1", help="AWS region for DynamoDB.")
     args = parser.parse_args()
 
@@ -342,6 +588,10 @@ def main():
         exits_data = load_json(args.exits)
         store_exits(dynamodb, exits_data)
 
+        # Load and store areas
+        areas_data = load_json(args.areas)
+        store_areas(dynamodb, areas_data)
+
         # Load and store rooms
         rooms_data = load_json(args.rooms)
         store_rooms(dynamodb, rooms_data)
@@ -354,10 +604,21 @@ def main():
         prototypes_data = load_json(args.prototypes)
         store_item_prototypes(dynamodb, prototypes_data)
 
+        # Load and store NPCs
+        npcs_data = load_json(args.npcs)
+        store_npcs(dynamodb, npcs_data)
+
+        # Load and store quests
+        quests_data = load_json(args.quests)
+        store_quests(dynamodb, quests_data)
+
         # Load data from DynamoDB and display
         loaded_exits = load_exits(dynamodb)
         display_exits(loaded_exits)
 
+        loaded_areas = load_areas(dynamodb)
+        display_areas(loaded_areas)
+
         loaded_rooms = load_rooms(dynamodb)
         display_rooms(loaded_rooms)
 
@@ -367,6 +628,12 @@ def main():
         loaded_prototypes = load_item_prototypes(dynamodb)
         display_item_prototypes(loaded_prototypes)
 
+        loaded_npcs = load_npcs(dynamodb)
+        display_npcs(loaded_npcs)
+
+        loaded_quests = load_quests(dynamodb)
+        display_quests(loaded_quests)
+
     except Exception as e:
         logging.error(f"An unexpected error occurred: {str(e)}")
 
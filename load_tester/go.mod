@@ -0,0 +1,7 @@
+module github.com/robinje/multi-user-dungeon/load_tester
+
+go 1.22
+
+require golang.org/x/crypto v0.24.0
+
+require golang.org/x/sys v0.21.0 // indirect
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Account holds the credentials used by a single bot.
+type Account struct {
+	Username string
+	Password string
+}
+
+// Results collects command latencies and failures across all bots.
+type Results struct {
+	Latencies []time.Duration
+	Failures  int
+	Timeouts  int
+	Mutex     sync.Mutex
+}
+
+// Bot is a simulated player connected to the server over SSH.
+type Bot struct {
+	ID      int
+	Account Account
+	Client  *ssh.Client
+	Session *ssh.Session
+	Input   *bufio.Writer
+	Prompts chan struct{}
+}
+
+var directions = []string{"north", "south", "east", "west", "up", "down"}
+
+var phrases = []string{"hello", "anyone here?", "testing, testing", "what a lovely room", "onward"}
+
+// loadAccounts reads whitespace separated username/password pairs from a file, one per line.
+func loadAccounts(filePath string) ([]Account, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	var accounts []Account
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid account line in %s: %q", filePath, line)
+		}
+		accounts = append(accounts, Account{Username: fields[0], Password: fields[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filePath, err)
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts found in %s", filePath)
+	}
+
+	return accounts, nil
+}
+
+// Connect opens the SSH connection and interactive shell for the bot.
+func (b *Bot) Connect(address, prompt string) error {
+	config := &ssh.ClientConfig{
+		User:            b.Account.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(b.Account.Password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	}
+
+	client, err := ssh.Dial("tcp", address, config)
+	if err != nil {
+		return fmt.Errorf("failed to connect as %s: %w", b.Account.Username, err)
+	}
+	b.Client = client
+
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	b.Session = session
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		b.Close()
+		return fmt.Errorf("failed to open stdin: %w", err)
+	}
+	b.Input = bufio.NewWriter(stdin)
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		b.Close()
+		return fmt.Errorf("failed to open stdout: %w", err)
+	}
+
+	if err := session.RequestPty("xterm", 50, 120, ssh.TerminalModes{}); err != nil {
+		b.Close()
+		return fmt.Errorf("failed to request pty: %w", err)
+	}
+
+	if err := session.Shell(); err != nil {
+		b.Close()
+		return fmt.Errorf("failed to start shell: %w", err)
+	}
+
+	// Signal every time the server finishes a response with the prompt
+	b.Prompts = make(chan struct{}, 16)
+	go func() {
+		defer close(b.Prompts)
+		buffer := make([]byte, 4096)
+		for {
+			n, err := stdout.Read(buffer)
+			if n > 0 && strings.HasSuffix(strings.TrimRight(string(buffer[:n]), "\r\n "), prompt) {
+				select {
+				case b.Prompts <- struct{}{}:
+				default:
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Send writes a single line of input to the server.
+func (b *Bot) Send(line string) error {
+	if _, err := b.Input.WriteString(line + "\r"); err != nil {
+		return err
+	}
+	return b.Input.Flush()
+}
+
+// drainPrompts discards any prompts already received, such as those caused by room broadcasts.
+func (b *Bot) drainPrompts() {
+	for {
+		select {
+		case <-b.Prompts:
+		default:
+			return
+		}
+	}
+}
+
+// Close ends the bot's session and connection.
+func (b *Bot) Close() {
+	if b.Session != nil {
+		b.Session.Close()
+	}
+	if b.Client != nil {
+		b.Client.Close()
+	}
+}
+
+// randomCommand returns a randomized look, move, or say command.
+func randomCommand() string {
+	switch rand.Intn(3) {
+	case 0:
+		return "look"
+	case 1:
+		return "go " + directions[rand.Intn(len(directions))]
+	default:
+		return "say " + phrases[rand.Intn(len(phrases))]
+	}
+}
+
+// Run logs the bot in, selects a character, and issues commands until the deadline.
+func (b *Bot) Run(address, prompt, selection string, rate float64, deadline time.Time, timeout time.Duration, results *Results) {
+	if err := b.Connect(address, prompt); err != nil {
+		slog.Error("Bot failed to connect", "bot", b.ID, "error", err)
+		results.Mutex.Lock()
+		results.Failures++
+		results.Mutex.Unlock()
+		return
+	}
+	defer b.Close()
+
+	// Choose a character from the selection menu
+	time.Sleep(2 * time.Second)
+	if err := b.Send(selection); err != nil {
+		slog.Error("Bot failed to select character", "bot", b.ID, "error", err)
+		return
+	}
+	time.Sleep(2 * time.Second)
+	b.drainPrompts()
+
+	interval := time.Duration(float64(time.Second) / rate)
+
+	for time.Now().Before(deadline) {
+		command := randomCommand()
+
+		b.drainPrompts()
+		start := time.Now()
+		if err := b.Send(command); err != nil {
+			slog.Error("Bot failed to send command", "bot", b.ID, "command", command, "error", err)
+			results.Mutex.Lock()
+			results.Failures++
+			results.Mutex.Unlock()
+			return
+		}
+
+		select {
+		case _, ok := <-b.Prompts:
+			if !ok {
+				slog.Warn("Bot connection closed", "bot", b.ID)
+				results.Mutex.Lock()
+				results.Failures++
+				results.Mutex.Unlock()
+				return
+			}
+			results.Mutex.Lock()
+			results.Latencies = append(results.Latencies, time.Since(start))
+			results.Mutex.Unlock()
+		case <-time.After(timeout):
+			results.Mutex.Lock()
+			results.Timeouts++
+			results.Mutex.Unlock()
+		}
+
+		// Jitter the pacing so the bots do not act in lockstep
+		time.Sleep(time.Duration(float64(interval) * (0.5 + rand.Float64())))
+	}
+
+	b.Send("q!")
+}
+
+// percentile returns the latency at the given percentile of a sorted slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * p / 100)
+	return sorted[index]
+}
+
+// Report prints the latency percentiles and error counts.
+func (r *Results) Report(elapsed time.Duration) {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	sort.Slice(r.Latencies, func(i, j int) bool { return r.Latencies[i] < r.Latencies[j] })
+
+	fmt.Printf("Duration:   %s\n", elapsed.Round(time.Millisecond))
+	fmt.Printf("Commands:   %d\n", len(r.Latencies))
+	fmt.Printf("Throughput: %.2f commands/s\n", float64(len(r.Latencies))/elapsed.Seconds())
+	fmt.Printf("Timeouts:   %d\n", r.Timeouts)
+	fmt.Printf("Failures:   %d\n", r.Failures)
+	fmt.Printf("p50:        %s\n", percentile(r.Latencies, 50).Round(time.Millisecond))
+	fmt.Printf("p90:        %s\n", percentile(r.Latencies, 90).Round(time.Millisecond))
+	fmt.Printf("p99:        %s\n", percentile(r.Latencies, 99).Round(time.Millisecond))
+	fmt.Printf("max:        %s\n", percentile(r.Latencies, 100).Round(time.Millisecond))
+}
+
+func main() {
+	address := flag.String("address", "localhost:9050", "Server address")
+	accountsFile := flag.String("accounts", "accounts.txt", "File of 'username password' lines for test accounts, one per bot")
+	bots := flag.Int("bots", 10, "Number of concurrent bots, at most one per account since a character may only be played by one session")
+	rate := flag.Float64("rate", 0.5, "Commands per second issued by each bot")
+	duration := flag.Duration("duration", time.Minute, "How long to run the test")
+	timeout := flag.Duration("timeout", 10*time.Second, "How long to wait for a response to a command")
+	selection := flag.String("select", "1", "Character selection menu choice sent after login")
+	prompt := flag.String("prompt", ">", "Prompt that marks the end of a server response")
+	flag.Parse()
+
+	if *bots < 1 || *rate <= 0 {
+		fmt.Println("Both -bots and -rate must be greater than zero")
+		os.Exit(1)
+	}
+
+	accounts, err := loadAccounts(*accountsFile)
+	if err != nil {
+		fmt.Printf("Error loading accounts: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Bots sharing an account would select the same character and be turned away as duplicate logins
+	if *bots > len(accounts) {
+		fmt.Printf("-bots is %d but %s has only %d accounts; each bot needs its own account\n", *bots, *accountsFile, len(accounts))
+		os.Exit(1)
+	}
+
+	slog.Info("Starting load test", "address", *address, "bots", *bots, "rate", *rate, "duration", *duration)
+
+	results := &Results{}
+	start := time.Now()
+	deadline := start.Add(*duration)
+
+	var wg sync.WaitGroup
+	for i := 0; i < *bots; i++ {
+		bot := &Bot{ID: i, Account: accounts[i]}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bot.Run(*address, *prompt, *selection, *rate, deadline, *timeout, results)
+		}()
+
+		// Stagger logins so authentication is not a single burst
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	wg.Wait()
+
+	results.Report(time.Since(start))
+}
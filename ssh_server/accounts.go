@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robinje/multi-user-dungeon/core"
+	"golang.org/x/crypto/ssh"
+)
+
+// User names that start an account flow instead of a login.
+const (
+	RegisterUser = "new"   // create and confirm a new account
+	ResetUser    = "reset" // reset a forgotten password
+)
+
+// playerExtension is the permissions extension naming the player a connection logs in as,
+// when that differs from the SSH user name.
+const playerExtension = "player"
+
+// maxAccountAttempts is how many times a player may retry each step before the connection is refused.
+const maxAccountAttempts = 3
+
+// Register walks a player connected as RegisterUser through creating a Cognito account and
+// confirming it with the code emailed to them, then logs them in as the new account.
+func Register(server *core.Server, conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+	core.NetworkLog.Info("Starting account registration", "address", conn.RemoteAddr().String())
+
+	var email, password string
+	var confirmed bool
+	instruction := "Create a new account. A confirmation code will be emailed to you."
+	for attempt := 0; ; attempt++ {
+		if attempt == maxAccountAttempts {
+			return nil, fmt.Errorf("registration abandoned after %d attempts", attempt)
+		}
+
+		answers, err := client("Register", instruction, []string{"Email: ", "Password: ", "Confirm password: "}, []bool{true, false, false})
+		if err != nil {
+			return nil, err
+		}
+		if len(answers) != 3 {
+			return nil, fmt.Errorf("expected 3 answers, got %d", len(answers))
+		}
+
+		email = strings.TrimSpace(answers[0])
+		password = answers[1]
+		if !strings.Contains(email, "@") {
+			instruction = "Please enter a valid email address."
+			continue
+		}
+		if password != answers[2] {
+			instruction = "The passwords did not match, please try again."
+			continue
+		}
+
+		output, err := core.SignUpUser(server.Context, email, password, server.Config)
+		if errors.Is(err, core.ErrAccountExists) {
+			// Carry on to the code prompt as for a new account, so registering never shows
+			// whether an email already has an account
+			core.NetworkLog.Info("Registration attempted for an existing account", "player_name", email)
+			break
+		}
+		if err != nil {
+			instruction = fmt.Sprintf("Could not create the account: %v.", err)
+			continue
+		}
+		confirmed = output.UserConfirmed
+		break
+	}
+
+	if !confirmed {
+		instruction = fmt.Sprintf("A confirmation code has been sent to %s.", email)
+		for attempt := 0; ; attempt++ {
+			if attempt == maxAccountAttempts {
+				return nil, fmt.Errorf("confirmation abandoned after %d attempts", attempt)
+			}
+
+			answers, err := client("Confirm", instruction, []string{"Confirmation code: "}, []bool{true})
+			if err != nil {
+				return nil, err
+			}
+			if len(answers) != 1 {
+				return nil, fmt.Errorf("expected 1 answer, got %d", len(answers))
+			}
+
+			if _, err := core.ConfirmUser(server.Context, email, strings.TrimSpace(answers[0]), server.Config); err != nil {
+				instruction = fmt.Sprintf("Could not confirm the account: %v.", err)
+				continue
+			}
+			break
+		}
+	}
+
+	server.Audit(server.Context, core.AuditAccountCreate, email, email, "registered over SSH", core.SourceIP(conn.RemoteAddr()))
+
+	// Hand off to the normal login with the new credentials
+	if !Authenticate(server.Context, email, password, server.Config) {
+		return nil, fmt.Errorf("could not log in as the new account %q", email)
+	}
+
+	core.NetworkLog.Info("Player registered and authenticated", "player_name", email)
+	return &ssh.Permissions{Extensions: map[string]string{playerExtension: email}}, nil
+}
+
+// ResetPassword walks a player connected as ResetUser through resetting a forgotten password with
+// the code Cognito emails to them, then logs them in with the new password.
+func ResetPassword(server *core.Server, conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+	core.NetworkLog.Info("Starting password reset", "address", conn.RemoteAddr().String())
+
+	var email string
+	instruction := "Enter the email address of your account. A reset code will be emailed to it."
+	for attempt := 0; ; attempt++ {
+		if attempt == maxAccountAttempts {
+			return nil, fmt.Errorf("password reset abandoned after %d attempts", attempt)
+		}
+
+		answers, err := client("Reset Password", instruction, []string{"Email: "}, []bool{true})
+		if err != nil {
+			return nil, err
+		}
+		if len(answers) != 1 {
+			return nil, fmt.Errorf("expected 1 answer, got %d", len(answers))
+		}
+
+		email = strings.TrimSpace(answers[0])
+		if !strings.Contains(email, "@") {
+			instruction = "Please enter a valid email address."
+			continue
+		}
+
+		if _, err := core.ForgotPassword(server.Context, email, server.Config); err != nil {
+			instruction = fmt.Sprintf("Could not send a reset code: %v.", err)
+			continue
+		}
+		break
+	}
+
+	var password string
+	instruction = fmt.Sprintf("A reset code has been sent to %s.", email)
+	for attempt := 0; ; attempt++ {
+		if attempt == maxAccountAttempts {
+			return nil, fmt.Errorf("password reset abandoned after %d attempts", attempt)
+		}
+
+		answers, err := client("Reset Password", instruction, []string{"Reset code: ", "New password: ", "Confirm new password: "}, []bool{true, false, false})
+		if err != nil {
+			return nil, err
+		}
+		if len(answers) != 3 {
+			return nil, fmt.Errorf("expected 3 answers, got %d", len(answers))
+		}
+
+		password = answers[1]
+		if password != answers[2] {
+			instruction = "The passwords did not match, please try again."
+			continue
+		}
+
+		if _, err := core.ConfirmForgotPassword(server.Context, email, strings.TrimSpace(answers[0]), password, server.Config); err != nil {
+			server.Audit(server.Context, core.AuditPasswordChange, email, email, "reset failed: "+err.Error(), core.SourceIP(conn.RemoteAddr()))
+			instruction = fmt.Sprintf("Could not reset the password: %v.", err)
+			continue
+		}
+		break
+	}
+
+	server.Audit(server.Context, core.AuditPasswordChange, email, email, "reset with an emailed code", core.SourceIP(conn.RemoteAddr()))
+
+	// Keys registered before the reset could belong to whoever took over the account
+	revoked, err := server.RevokeSSHKeys(server.Context, email)
+	if err != nil {
+		core.NetworkLog.Error("Failed to revoke SSH keys after a password reset", "player_name", email, "error", err)
+	}
+	if revoked > 0 {
+		server.Audit(server.Context, core.AuditSSHKey, email, email, fmt.Sprintf("revoked %d keys after a password reset", revoked), core.SourceIP(conn.RemoteAddr()))
+	}
+
+	// Hand off to the normal login with the new password
+	if !Authenticate(server.Context, email, password, server.Config) {
+		return nil, fmt.Errorf("could not log in as %q after the reset", email)
+	}
+
+	core.NetworkLog.Info("Player reset their password and authenticated", "player_name", email)
+	return &ssh.Permissions{Extensions: map[string]string{playerExtension: email}}, nil
+}
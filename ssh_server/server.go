@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -31,21 +32,26 @@ func NewServer(config core.Configuration) (*core.Server, error) {
 		StartTime:   time.Now(),
 		Rooms:       make(map[int64]*core.Room),
 		Characters:  make(map[uuid.UUID]*core.Character),
-		Balance:     config.Game.Balance,
-		AutoSave:    config.Game.AutoSave,
-		Health:      config.Game.StartingHealth,
-		Essence:     config.Game.StartingEssence,
+		Sessions:    make(map[uuid.UUID]*core.Session),
+		Players:     make(map[*core.Player]bool),
+		NPCs:        make(map[uuid.UUID]*core.NPC),
 	}
 
 	core.Logger.Info("Initializing database...")
 
 	// Initialize the database connection
 	var err error
-	server.Database, err = core.NewKeyPair(config.Aws.Region)
+	server.Database, err = core.OpenKeyPair(server.Context, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %v", err)
 	}
 
+	// Create the CloudWatch client once for metrics and report counts
+	server.CloudWatch, err = core.NewCloudWatchClient(server.Context, config.Aws.Region)
+	if err != nil {
+		core.Logger.Error("Error creating CloudWatch client", "error", err)
+	}
+
 	// Initialize the player index
 	server.PlayerIndex.IndexID = 1
 
@@ -58,6 +64,13 @@ func NewServer(config core.Configuration) (*core.Server, error) {
 		return nil, fmt.Errorf("failed to initialize bloom filter: %v", err)
 	}
 
+	// Load the connection banner
+	err = server.LoadBanner()
+	if err != nil {
+		core.Logger.Error("Error loading banner", "error", err)
+		// Proceeding without a banner if it failed to load
+	}
+
 	// Load archetypes from the database
 	core.Logger.Info("Loading archetypes from database...")
 	err = server.LoadArchetypes()
@@ -67,6 +80,22 @@ func NewServer(config core.Configuration) (*core.Server, error) {
 		return nil, fmt.Errorf("failed to load archetypes: %v", err)
 	}
 
+	// Load item prototypes
+	core.Logger.Info("Loading prototypes from database...")
+	err = server.LoadPrototypes()
+	if err != nil {
+		core.Logger.Error("Error loading prototypes from database", "error", err)
+		// Proceeding without prototypes; items read them from the database as needed
+	}
+
+	// Load area definitions
+	core.Logger.Info("Loading areas from database...")
+	err = server.LoadAreas()
+	if err != nil {
+		core.Logger.Error("Error loading areas from database", "error", err)
+		// Proceeding without area definitions if they failed to load
+	}
+
 	// Add a default room if none exist
 	if len(server.Rooms) == 0 {
 		core.Logger.Info("Adding default room...")
@@ -75,7 +104,7 @@ func NewServer(config core.Configuration) (*core.Server, error) {
 
 	// Load rooms from the database
 	core.Logger.Info("Loading rooms from database...")
-	loadedRooms, err := server.Database.LoadRooms()
+	loadedRooms, err := server.Database.LoadRooms(server.Context)
 	if err != nil {
 		core.Logger.Error("Error loading rooms from database", "error", err)
 		// Proceeding with default room(s) if rooms failed to load
@@ -86,9 +115,39 @@ func NewServer(config core.Configuration) (*core.Server, error) {
 		}
 	}
 
+	// Load NPC definitions and populate the rooms with them
+	core.Logger.Info("Loading NPCs from database...")
+	err = server.LoadNPCTemplates()
+	if err != nil {
+		core.Logger.Error("Error loading NPCs from database", "error", err)
+		// Proceeding without NPCs if they failed to load
+	} else {
+		server.SpawnNPCs()
+	}
+
+	// Load quest definitions
+	core.Logger.Info("Loading quests from database...")
+	err = server.LoadQuests()
+	if err != nil {
+		core.Logger.Error("Error loading quests from database", "error", err)
+		// Proceeding without quests if they failed to load
+	}
+
+	// Load bulletin board posts
+	core.Logger.Info("Loading bulletin board posts from database...")
+	err = server.LoadPosts()
+	if err != nil {
+		core.Logger.Error("Error loading posts from database", "error", err)
+		// Proceeding with empty boards if they failed to load
+	}
+
+	// Register the built-in and configured chat channels
+	core.Logger.Info("Registering chat channels...")
+	server.RegisterChannels()
+
 	// Load active MOTDs from the database
 	core.Logger.Info("Loading active MOTDs from database...")
-	activeMOTDs, err := server.Database.GetAllMOTDs()
+	activeMOTDs, err := server.Database.GetAllMOTDs(server.Context)
 	if err != nil {
 		core.Logger.Error("Failed to load active MOTDs", "error", err)
 		// Proceeding without MOTDs if failed to load
@@ -137,21 +196,44 @@ func main() {
 
 	core.Logger.Info("Configuration loaded", "config", config)
 
+	// Tracing must be configured before the AWS clients are created
+	if config.Logging.XRay {
+		if err := core.EnableXRay(&config); err != nil {
+			core.Logger.Error("Continuing without X-Ray tracing", "error", err)
+		}
+	}
+
 	// Create a new server instance
 	server, err := NewServer(config)
 	if err != nil {
 		core.Logger.Error("Failed to create server", "error", err)
 		os.Exit(1)
 	}
+	server.ConfigLoader = func() (core.Configuration, error) {
+		return loadConfiguration(*configFile)
+	}
 
-	// Create a context that we can cancel
-	_, cancel := context.WithCancel(context.Background())
+	// Create a context that we can cancel; background loops and database calls run under it
+	var cancel context.CancelFunc
+	server.Context, cancel = context.WithCancel(server.Context)
 	defer cancel()
 
 	// Create a channel to listen for interrupt signals
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
+	// Reload the configuration and world data on SIGHUP
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			core.Logger.Info("SIGHUP received, reloading")
+			if err := server.Reload(); err != nil {
+				core.Logger.Error("Error reloading on SIGHUP", "error", err)
+			}
+		}
+	}()
+
 	// Start the SSH server to accept incoming connections in a goroutine
 	go func() {
 		if err := StartSSHServer(server); err != nil {
@@ -160,35 +242,81 @@ func main() {
 		}
 	}()
 
-	// Start sending metrics in a separate goroutine
+	// Start the optional HTTP status endpoint used by health checks
+	if err := server.StartStatusServer(); err != nil {
+		core.Logger.Error("Failed to start status server", "error", err)
+	}
+
+	// Publish metrics to the configured backend
 	metricsDone := make(chan struct{})
-	go func() {
-		defer close(metricsDone)
-		if err := core.SendMetrics(server, 1*time.Minute); err != nil {
-			core.Logger.Error("Error in SendMetrics", "error", err)
+	switch server.MetricsBackend() {
+	case core.MetricsCloudWatch:
+		go func() {
+			defer close(metricsDone)
+			if err := core.SendMetrics(server, 1*time.Minute); err != nil {
+				core.Logger.Error("Error in SendMetrics", "error", err)
+			}
+		}()
+	case core.MetricsPrometheus:
+		close(metricsDone)
+		if err := server.StartMetricsServer(); err != nil {
+			core.Logger.Error("Failed to start metrics server", "error", err)
 		}
-	}()
+	default:
+		close(metricsDone)
+		core.Logger.Info("Metrics publishing disabled")
+	}
 
 	// Start the auto-save routine in a separate goroutine
 	go core.AutoSave(server)
 
+	// Start the NPC loop in a separate goroutine
+	go core.NPCLoop(server)
+
+	// Start resolving combat rounds in a separate goroutine
+	go core.CombatLoop(server)
+
+	// Tick timed effects in a separate goroutine
+	go core.EffectLoop(server)
+
+	// Regenerate health and essence in a separate goroutine
+	go core.RegenLoop(server)
+
+	// Sweep decayed items from the ground in a separate goroutine
+	go core.DecayLoop(server)
+
+	// Refill room spawn points in a separate goroutine
+	go core.SpawnLoop(server)
+
+	// Play ambient room messages in a separate goroutine
+	go core.AmbienceLoop(server)
+
+	// Change the weather and announce dawn and dusk in a separate goroutine
+	go core.WeatherLoop(server)
+
 	// Wait for interrupt signal
 	<-stop
 
 	core.Logger.Info("Interrupt received, initiating graceful shutdown...")
 
-	// Cancel the context to signal all goroutines to stop
-	cancel()
-
-	// Create a timeout context for shutdown operations
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Create a timeout context for shutdown operations, allowing for the countdown so that it
+	// does not use up the time the saves are given
+	countdown := time.Duration(server.Settings().Server.ShutdownCountdown) * time.Second
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), countdown+ShutdownSaveTimeout)
 	defer shutdownCancel()
 
+	// Cancel in-flight database calls made under the server context if shutdown overruns
+	stopAfter := context.AfterFunc(shutdownCtx, cancel)
+	defer stopAfter()
+
 	// Perform graceful shutdown
 	if err := GracefulShutdown(shutdownCtx, server); err != nil {
 		core.Logger.Error("Error during shutdown", "error", err)
 	}
 
+	// Cancel the context to signal all goroutines to stop
+	cancel()
+
 	// Wait for metrics goroutine to finish
 	select {
 	case <-metricsDone:
@@ -202,14 +330,16 @@ func main() {
 
 // Authenticate checks the provided username and password against the authentication system.
 // Returns true if authentication is successful, false otherwise.
-func Authenticate(username, password string, config core.Configuration) bool {
-	core.Logger.Info("Authenticating user", "username", username)
+func Authenticate(ctx context.Context, username, password string, config core.Configuration) bool {
+	core.NetworkLog.Info("Authenticating user", "username", username)
 
-	response, err := core.SignInUser(username, password, config)
-	core.Logger.Debug("Authentication response", "response", response)
+	ctx, endTrace := core.StartTrace(ctx, "login", "player", username)
+	response, err := core.SignInUser(ctx, username, password, config)
+	endTrace(err)
+	core.NetworkLog.Debug("Authentication response", "response", response)
 
 	if err != nil {
-		core.Logger.Error("Authentication attempt failed for user", "username", username, "error", err)
+		core.NetworkLog.Error("Authentication attempt failed for user", "username", username, "error", err)
 		return false
 	}
 	return true
@@ -217,7 +347,7 @@ func Authenticate(username, password string, config core.Configuration) bool {
 
 // StartSSHServer starts the SSH server to accept incoming player connections.
 func StartSSHServer(server *core.Server) error {
-	core.Logger.Info("Starting SSH server", "port", server.Port)
+	core.NetworkLog.Info("Starting SSH server", "port", server.Port)
 
 	// Read the private key from disk
 	privateKeyPath := server.Config.Server.PrivateKeyPath
@@ -239,14 +369,33 @@ func StartSSHServer(server *core.Server) error {
 	server.SSHConfig = &ssh.ServerConfig{
 		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
 			// Authenticate the player
-			authenticated := Authenticate(conn.User(), string(password), server.Config)
+			authenticated := Authenticate(server.Context, conn.User(), string(password), server.Config)
 			if authenticated {
-				core.Logger.Info("Player authenticated", "player_name", conn.User())
+				core.NetworkLog.Info("Player authenticated", "player_name", conn.User())
 				return nil, nil
 			}
-			core.Logger.Warn("Player failed authentication", "player_name", conn.User())
+			core.NetworkLog.Warn("Player failed authentication", "player_name", conn.User())
+			core.RecordAuthFailure()
+			server.Audit(server.Context, core.AuditLoginFailure, conn.User(), conn.User(), "password rejected", core.SourceIP(conn.RemoteAddr()))
 			return nil, fmt.Errorf("password rejected for %q", conn.User())
 		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if server.AuthorizePublicKey(server.Context, conn.User(), key) {
+				core.NetworkLog.Info("Player authenticated with public key", "player_name", conn.User(), "fingerprint", ssh.FingerprintSHA256(key))
+				return &ssh.Permissions{Extensions: map[string]string{"pubkey-fp": ssh.FingerprintSHA256(key)}}, nil
+			}
+			// Clients offer each of their keys in turn, so an unknown key is not a failed login
+			return nil, fmt.Errorf("public key not registered for %q", conn.User())
+		},
+		KeyboardInteractiveCallback: func(conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			switch conn.User() {
+			case RegisterUser:
+				return Register(server, conn, client)
+			case ResetUser:
+				return ResetPassword(server, conn, client)
+			}
+			return nil, fmt.Errorf("keyboard-interactive is only used to register or reset a password")
+		},
 	}
 
 	// Add the host key to the SSH configuration
@@ -260,7 +409,7 @@ func StartSSHServer(server *core.Server) error {
 	}
 
 	server.Listener = listener
-	core.Logger.Info("SSH server listening", "port", server.Port)
+	core.NetworkLog.Info("SSH server listening", "port", server.Port)
 
 	// Start accepting connections in a separate goroutine
 	go func() {
@@ -269,12 +418,14 @@ func StartSSHServer(server *core.Server) error {
 			if err != nil {
 				if errors.Is(err, net.ErrClosed) {
 					// The listener has been closed, exit the goroutine
-					core.Logger.Info("SSH server listener closed, stopping accept loop")
+					core.NetworkLog.Info("SSH server listener closed, stopping accept loop")
 					return
 				}
-				core.Logger.Error("Error accepting connection", "error", err)
+				core.NetworkLog.Error("Error accepting connection", "error", err)
+				core.RecordConnectionError()
 				continue
 			}
+			core.RecordConnection()
 
 			// Increment the WaitGroup before starting the goroutine
 			server.WaitGroup.Add(1)
@@ -292,7 +443,8 @@ func handleConnection(server *core.Server, conn net.Conn) {
 	// Perform SSH handshake
 	sshConn, chans, reqs, err := ssh.NewServerConn(conn, server.SSHConfig)
 	if err != nil {
-		core.Logger.Error("Failed to perform SSH handshake", "error", err)
+		core.NetworkLog.Error("Failed to perform SSH handshake", "error", err)
+		core.RecordHandshakeFailure()
 		return
 	}
 	defer sshConn.Close()
@@ -306,55 +458,83 @@ func handleConnection(server *core.Server, conn net.Conn) {
 
 // handleChannels handles the channels opened by the SSH client.
 func handleChannels(server *core.Server, sshConn *ssh.ServerConn, channels <-chan ssh.NewChannel) {
-	core.Logger.Info("New connection", "address", sshConn.RemoteAddr().String(), "user", sshConn.User())
+	core.NetworkLog.Info("New connection", "address", sshConn.RemoteAddr().String(), "user", sshConn.User())
 
 	for newChannel := range channels {
 		// Accept the channel
 		channel, requests, err := newChannel.Accept()
 		if err != nil {
-			core.Logger.Error("Could not accept channel", "error", err)
+			core.NetworkLog.Error("Could not accept channel", "error", err)
 			continue
 		}
 
 		playerName := sshConn.User()
+		if sshConn.Permissions != nil && sshConn.Permissions.Extensions[playerExtension] != "" {
+			playerName = sshConn.Permissions.Extensions[playerExtension]
+		}
 		playerIndex := server.PlayerIndex.GetID()
 
 		// Attempt to read the player from the database
-		_, characterList, seenMotD, err := server.Database.ReadPlayer(playerName)
+		storedPlayer, err := server.Database.ReadPlayer(server.Context, playerName)
 		if err != nil {
 			if err.Error() == "player not found" {
 				// Create a new player record if not found
-				core.Logger.Info("Creating new player record", "player_name", playerName)
-				characterList = make(map[string]uuid.UUID)
-				seenMotD = []uuid.UUID{} // Initialize an empty slice for new players
-				err = server.Database.WritePlayer(&core.Player{
+				core.NetworkLog.Info("Creating new player record", "player_name", playerName)
+				storedPlayer = &core.Player{
 					PlayerID:      playerName,
-					CharacterList: characterList,
-					SeenMotD:      seenMotD,
-				})
+					CharacterList: make(map[string]uuid.UUID),
+					SeenMotD:      []uuid.UUID{}, // Initialize an empty slice for new players
+					Prompt:        core.DefaultPrompt,
+				}
+				storedPlayer.Echo.Store(true)
+				storedPlayer.Color.Store(true)
+				err = server.Database.WritePlayer(server.Context, storedPlayer)
 				if err != nil {
-					core.Logger.Error("Error creating player record", "error", err)
+					core.NetworkLog.Error("Error creating player record", "error", err)
 					continue
 				}
 			} else {
-				core.Logger.Error("Error reading player from database", "error", err)
+				core.NetworkLog.Error("Error reading player from database", "error", err)
 				continue
 			}
 		}
 
 		// Create the Player struct with data from the database or as a new player
 		player := &core.Player{
-			PlayerID:      playerName,
-			Index:         playerIndex,
-			ToPlayer:      make(chan string),
-			FromPlayer:    make(chan string),
-			PlayerError:   make(chan error),
-			Echo:          true,
-			Prompt:        "> ",
-			Connection:    channel,
-			Server:        server,
-			CharacterList: characterList,
-			SeenMotD:      seenMotD,
+			PlayerID:        playerName,
+			Index:           playerIndex,
+			ToPlayer:        make(chan string),
+			FromPlayer:      make(chan string),
+			PlayerError:     make(chan error),
+			PageControl:     make(chan bool, 1),
+			Forced:          make(chan core.ForcedCommand, core.MaxForcedCommands),
+			Limiter:         server.NewInputLimiter(),
+			Prompt:          storedPlayer.Prompt,
+			Connection:      channel,
+			RemoteAddr:      core.SourceIP(sshConn.RemoteAddr()),
+			Server:          server,
+			CharacterList:   storedPlayer.CharacterList,
+			SeenMotD:        storedPlayer.SeenMotD,
+			Channels:        storedPlayer.Channels,
+			OfferedChannels: storedPlayer.OfferedChannels,
+			Muted:           storedPlayer.Muted,
+			Ignored:         storedPlayer.Ignored,
+			Friends:         storedPlayer.Friends,
+			Role:            storedPlayer.Role,
+			Aliases:         storedPlayer.Aliases,
+			ColorTheme:      storedPlayer.ColorTheme,
+			ShowProfanity:   storedPlayer.ShowProfanity,
+			SSHKeys:         storedPlayer.SSHKeys,
+		}
+		player.Echo.Store(storedPlayer.Echo.Load())
+		player.Color.Store(storedPlayer.Color.Load())
+		server.AddPlayer(player)
+
+		// New players, and players whose records predate a default channel, are subscribed to it
+		if player.OfferDefaultChannels() {
+			if err := server.Database.WritePlayer(server.Context, player); err != nil {
+				core.NetworkLog.Error("Error saving default channels", "player_name", playerName, "error", err)
+			}
 		}
 
 		// Handle SSH requests (pty-req, shell, window-change)
@@ -364,11 +544,14 @@ func handleChannels(server *core.Server, sshConn *ssh.ServerConn, channels <-cha
 		go core.PlayerInput(player)
 		go core.PlayerOutput(player)
 
-		// Initialize player session
+		// Initialize player session, counted so shutdown waits for its final save
+		server.WaitGroup.Add(1)
 		go func(p *core.Player) {
+			defer server.WaitGroup.Done()
+			defer server.RemovePlayer(p)
 			defer p.Connection.Close()
 
-			core.Logger.Info("Player connected", "player_name", p.PlayerID)
+			core.NetworkLog.Info("Player connected", "player_name", p.PlayerID)
 
 			// Send welcome message
 			core.DisplayUnseenMOTDs(server, p)
@@ -376,7 +559,7 @@ func handleChannels(server *core.Server, sshConn *ssh.ServerConn, channels <-cha
 			// Character Selection Dialog
 			character, err := core.SelectCharacter(p, server)
 			if err != nil {
-				core.Logger.Error("Error during character selection", "error", err)
+				core.NetworkLog.Error("Error during character selection", "error", err)
 				return
 			}
 
@@ -384,20 +567,25 @@ func handleChannels(server *core.Server, sshConn *ssh.ServerConn, channels <-cha
 			core.InputLoop(character)
 
 			// Close the player's output channel
-			close(player.ToPlayer)
+			player.CloseOutput()
 
-			// Save the player's character and data to the database
-			err = server.Database.WriteCharacter(character)
-			if err != nil {
-				core.Logger.Error("Error saving character", "character_id", character.ID, "error", err)
+			// Save the player's character and data to the database. A link-dead character stays in
+			// the world and is saved when its link-dead window expires.
+			character.Mutex.Lock()
+			if !character.LinkDead {
+				err = server.Database.WriteCharacter(server.Context, character)
+				if err != nil {
+					core.Logger.Error("Error saving character", "character_id", character.ID, "error", err)
+				}
 			}
+			character.Mutex.Unlock()
 
-			err = server.Database.WritePlayer(player)
+			err = server.Database.WritePlayer(server.Context, player)
 			if err != nil {
 				core.Logger.Error("Error saving player data", "player_name", player.PlayerID, "error", err)
 			}
 
-			core.Logger.Info("Player disconnected", "player_name", p.PlayerID)
+			core.NetworkLog.Info("Player disconnected", "player_name", p.PlayerID)
 		}(player)
 	}
 }
@@ -411,7 +599,7 @@ func parseDims(b []byte) (width, height int) {
 
 // HandleSSHRequests handles SSH requests from the client.
 func HandleSSHRequests(player *core.Player, requests <-chan *ssh.Request) {
-	core.Logger.Debug("Handling SSH requests for player", "player_name", player.PlayerID)
+	core.NetworkLog.Debug("Handling SSH requests for player", "player_name", player.PlayerID)
 
 	for req := range requests {
 		switch req.Type {
@@ -435,58 +623,214 @@ func HandleSSHRequests(player *core.Player, requests <-chan *ssh.Request) {
 	}
 }
 
-func GracefulShutdown(ctx context.Context, server *core.Server) error {
-	core.Logger.Info("Initiating graceful shutdown...")
+// shutdownSummary counts what GracefulShutdown saved, for the closing log line.
+type shutdownSummary struct {
+	CharactersSaved  int
+	CharactersFailed int
+	CharactersMissed int // saves still running when the shutdown context ended
+	RoomsSaved       bool
+	ItemsSaved       bool
+	ConnectionsDone  bool
+}
+
+// activeCharacters returns the characters in the world at the time of the call.
+func activeCharacters(server *core.Server) []*core.Character {
+	server.Mutex.Lock()
+	defer server.Mutex.Unlock()
 
-	// Notify all players of impending shutdown
+	characters := make([]*core.Character, 0, len(server.Characters))
 	for _, character := range server.Characters {
-		character.Player.ToPlayer <- "\n\rServer is shutting down. You will be logged out shortly.\n\r"
-		character.Player.ToPlayer <- character.Player.Prompt
+		characters = append(characters, character)
 	}
+	return characters
+}
 
-	// Wait a moment for messages to be sent
-	time.Sleep(10 * time.Second)
+// broadcastShutdown sends a message to every connected player.
+func broadcastShutdown(server *core.Server, message string) {
+	for _, character := range activeCharacters(server) {
+		if !character.IsActive() {
+			continue
+		}
+		character.Player.Send(message)
+		character.Player.Send(character.Player.RenderPrompt())
+	}
+}
 
-	// Use ExecuteQuitCommand for each character
-	for _, character := range server.Characters {
-		core.Logger.Info("Logging out character", "characterName", character.Name)
-		core.ExecuteQuitCommand(character, []string{"quit"})
+// shutdownCountdown warns players for the configured number of seconds before they are logged
+// out, announcing the time left every few seconds. It ends early if ctx is done.
+func shutdownCountdown(ctx context.Context, server *core.Server) {
+	remaining := int(server.Settings().Server.ShutdownCountdown)
+	if remaining == 0 {
+		broadcastShutdown(server, "\n\rServer is shutting down now.\n\r")
+		return
 	}
 
-	// Perform final auto-save
-	core.Logger.Info("Performing final auto-save...")
-	if err := server.SaveActiveRooms(); err != nil {
-		core.Logger.Error("Error saving rooms during shutdown", "error", err)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for ; remaining > 0; remaining-- {
+		if remaining%10 == 0 || remaining == 5 || remaining <= 3 {
+			broadcastShutdown(server, fmt.Sprintf("\n\rServer is shutting down in %d seconds. You will be logged out and your character saved.\n\r", remaining))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			core.Logger.Warn("Shutdown countdown cut short", "remaining", remaining)
+			return
+		}
 	}
-	if err := server.SaveActiveItems(); err != nil {
-		core.Logger.Error("Error saving items during shutdown", "error", err)
+}
+
+// saveCharacters saves every character in the world in parallel and waits, for no longer than
+// ctx allows, until each save has finished.
+func saveCharacters(ctx context.Context, server *core.Server, summary *shutdownSummary) {
+	characters := activeCharacters(server)
+	results := make(chan error, len(characters))
+
+	var wg sync.WaitGroup
+	for _, character := range characters {
+		wg.Add(1)
+		go func(character *core.Character) {
+			defer wg.Done()
+			err := server.SaveCharacter(character)
+			if err != nil {
+				core.Logger.Error("Error saving character during shutdown", "characterName", character.Name, "error", err)
+				character.Player.Send("\n\rYour character could not be saved.\n\r")
+			} else {
+				character.Player.Send("\n\rYour character has been saved.\n\r")
+			}
+			results <- err
+		}(character)
 	}
 
-	// Close the server listener
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		core.Logger.Warn("Timed out waiting for character saves")
+	}
+
+	// Count the saves that finished; any left are still in flight
+	for finished := len(results); finished > 0; finished-- {
+		if err := <-results; err != nil {
+			summary.CharactersFailed++
+		} else {
+			summary.CharactersSaved++
+		}
+	}
+	summary.CharactersMissed = len(characters) - summary.CharactersSaved - summary.CharactersFailed
+}
+
+// ShutdownSaveTimeout bounds the saves made during shutdown, after the countdown has ended.
+const ShutdownSaveTimeout = 30 * time.Second
+
+// GracefulShutdown stops new connections, counts down to give players warning, saves every
+// character and the world, then disconnects everyone and closes the database. Each step is
+// bounded by ctx, and a summary of what was saved is logged at the end.
+func GracefulShutdown(ctx context.Context, server *core.Server) error {
+	core.Logger.Info("Initiating graceful shutdown...")
+
+	var summary shutdownSummary
+	var errs []error
+
+	// Stop accepting connections before anything else, so nobody logs in to a closing server
+	server.ShuttingDown.Store(true)
 	if server.Listener != nil {
 		core.Logger.Info("Closing server listener...")
 		if err := server.Listener.Close(); err != nil {
 			core.Logger.Error("Error closing server listener", "error", err)
 		}
+	}
 
-		// Wait for ongoing connections to finish (with a timeout)
-		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
+	shutdownCountdown(ctx, server)
 
-		done := make(chan struct{})
-		go func() {
-			server.WaitGroup.Wait()
-			close(done)
-		}()
+	saveCharacters(ctx, server, &summary)
+	if summary.CharactersFailed > 0 || summary.CharactersMissed > 0 {
+		errs = append(errs, fmt.Errorf("%d characters failed to save and %d did not finish", summary.CharactersFailed, summary.CharactersMissed))
+	}
 
-		select {
-		case <-done:
-			core.Logger.Info("All connections closed successfully")
-		case <-shutdownCtx.Done():
-			core.Logger.Warn("Timed out waiting for connections to close")
+	// Save the world while every character is still in it
+	core.Logger.Info("Performing final auto-save...")
+	if err := server.SaveActiveRooms(ctx); err != nil {
+		core.Logger.Error("Error saving rooms during shutdown", "error", err)
+		errs = append(errs, err)
+	} else {
+		summary.RoomsSaved = true
+	}
+	if err := server.SaveActiveItems(ctx); err != nil {
+		core.Logger.Error("Error saving items during shutdown", "error", err)
+		errs = append(errs, err)
+	} else {
+		summary.ItemsSaved = true
+	}
+
+	// Disconnect everyone. Input loops see their connection close and remove their characters
+	// without going link-dead, writing any change made since the save above.
+	for _, character := range activeCharacters(server) {
+		core.Logger.Info("Logging out character", "characterName", character.Name)
+		character.Mutex.Lock()
+		if character.LinkDeadTimer != nil {
+			character.LinkDeadTimer.Stop()
+			character.LinkDeadTimer = nil
+		}
+		player := character.Player
+		character.Mutex.Unlock()
+
+		if player != nil && player.Connection != nil {
+			player.Send("\n\rGoodbye!\n\r")
+			player.Connection.Close()
 		}
 	}
 
+	// Wait for sessions and connections to finish
+	done := make(chan struct{})
+	go func() {
+		server.WaitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		summary.ConnectionsDone = true
+		core.Logger.Info("All connections closed successfully")
+	case <-ctx.Done():
+		core.Logger.Warn("Timed out waiting for connections to close")
+		errs = append(errs, errors.New("timed out waiting for connections to close"))
+	}
+
+	// Stop the status endpoint so health checks report the server as down
+	if err := server.StopStatusServer(ctx); err != nil {
+		core.Logger.Error("Error closing status server", "error", err)
+	}
+	if err := server.StopMetricsServer(ctx); err != nil {
+		core.Logger.Error("Error closing metrics server", "error", err)
+	}
+
+	// Close the database once nothing else will write to it
+	if err := server.Database.Close(); err != nil {
+		core.Logger.Error("Error closing database", "error", err)
+		errs = append(errs, err)
+	}
+
+	core.Logger.Info("Shutdown summary",
+		"charactersSaved", summary.CharactersSaved,
+		"charactersFailed", summary.CharactersFailed,
+		"charactersUnfinished", summary.CharactersMissed,
+		"roomsSaved", summary.RoomsSaved,
+		"itemsSaved", summary.ItemsSaved,
+		"connectionsClosed", summary.ConnectionsDone,
+	)
+
 	core.Logger.Info("Graceful shutdown completed")
-	return nil
+
+	// Ship the last log records, including the summary above, before the process exits
+	if err := core.CloseLogging(ctx); err != nil {
+		fmt.Printf("Error flushing logs to CloudWatch: %v\n", err)
+	}
+	return errors.Join(errs...)
 }
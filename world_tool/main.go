@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robinje/multi-user-dungeon/core"
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the format version written into exported bundles.
+const BundleVersion = 1
+
+// Bundle is a snapshot of the world: everything the server loads at startup apart from players
+// and their characters.
+type Bundle struct {
+	Version    int                  `json:"version"`
+	Exported   time.Time            `json:"exported"`
+	Archetypes []core.Archetype     `json:"archetypes"`
+	Prototypes []core.PrototypeData `json:"prototypes"`
+	Rooms      []core.RoomData      `json:"rooms"`
+	Exits      []core.ExitData      `json:"exits"`
+	Items      []core.ItemData      `json:"items"` // items on the ground, including container contents
+}
+
+// importOrder writes records before the records that refer to them.
+var importOrder = []string{"archetypes", "prototypes", "items", "exits", "rooms"}
+
+// TableDiff lists the record IDs that differ between a bundle and the database.
+type TableDiff struct {
+	Added     []string // in the bundle only
+	Changed   []string // in both with different contents
+	Removed   []string // in the database only, left alone on import
+	Unchanged int
+}
+
+func loadConfiguration(configFile string) (core.Configuration, error) {
+	var config core.Configuration
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return config, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return config, fmt.Errorf("error unmarshalling config: %w", err)
+	}
+
+	return config, nil
+}
+
+// exportWorld reads the world tables into a bundle sorted by ID, so exports of the same world
+// compare cleanly.
+func exportWorld(ctx context.Context, kp *core.KeyPair) (*Bundle, error) {
+	bundle := &Bundle{Version: BundleVersion, Exported: time.Now().UTC()}
+
+	if err := kp.Scan(ctx, "archetypes", &bundle.Archetypes); err != nil {
+		return nil, fmt.Errorf("error exporting archetypes: %w", err)
+	}
+	if err := kp.Scan(ctx, "prototypes", &bundle.Prototypes); err != nil {
+		return nil, fmt.Errorf("error exporting prototypes: %w", err)
+	}
+	if err := kp.ParallelScan(ctx, "rooms", core.DefaultScanSegments, &bundle.Rooms); err != nil {
+		return nil, fmt.Errorf("error exporting rooms: %w", err)
+	}
+	if err := kp.ParallelScan(ctx, "exits", core.DefaultScanSegments, &bundle.Exits); err != nil {
+		return nil, fmt.Errorf("error exporting exits: %w", err)
+	}
+
+	var items []core.ItemData
+	if err := kp.ParallelScan(ctx, "items", core.DefaultScanSegments, &items); err != nil {
+		return nil, fmt.Errorf("error exporting items: %w", err)
+	}
+	bundle.Items = worldItems(bundle.Rooms, items)
+
+	sort.Slice(bundle.Archetypes, func(i, j int) bool {
+		return bundle.Archetypes[i].ArchetypeName < bundle.Archetypes[j].ArchetypeName
+	})
+	sort.Slice(bundle.Prototypes, func(i, j int) bool {
+		return bundle.Prototypes[i].PrototypeID < bundle.Prototypes[j].PrototypeID
+	})
+	sort.Slice(bundle.Rooms, func(i, j int) bool { return bundle.Rooms[i].RoomID < bundle.Rooms[j].RoomID })
+	sort.Slice(bundle.Exits, func(i, j int) bool { return bundle.Exits[i].ExitID < bundle.Exits[j].ExitID })
+	sort.Slice(bundle.Items, func(i, j int) bool { return bundle.Items[i].ItemID < bundle.Items[j].ItemID })
+
+	return bundle, nil
+}
+
+// worldItems keeps the items lying in rooms and everything inside them. Items carried by
+// characters belong to the players and are not part of the world.
+func worldItems(rooms []core.RoomData, items []core.ItemData) []core.ItemData {
+	byID := make(map[string]core.ItemData, len(items))
+	for _, item := range items {
+		byID[item.ItemID] = item
+	}
+
+	kept := make(map[string]bool)
+	var keep func(id string)
+	keep = func(id string) {
+		item, ok := byID[id]
+		if !ok || kept[id] {
+			return
+		}
+		kept[id] = true
+		for _, contentID := range item.Contents {
+			keep(contentID)
+		}
+	}
+	for _, room := range rooms {
+		for _, id := range room.ItemIDs {
+			keep(id)
+		}
+	}
+
+	world := make([]core.ItemData, 0, len(kept))
+	for id := range kept {
+		world = append(world, byID[id])
+	}
+	return world
+}
+
+// records returns the bundle's records by table and ID.
+func (b *Bundle) records() map[string]map[string]interface{} {
+	tables := make(map[string]map[string]interface{}, len(importOrder))
+	for _, name := range importOrder {
+		tables[name] = make(map[string]interface{})
+	}
+
+	for _, archetype := range b.Archetypes {
+		tables["archetypes"][archetype.ArchetypeName] = archetype
+	}
+	for _, prototype := range b.Prototypes {
+		tables["prototypes"][prototype.PrototypeID] = prototype
+	}
+	for _, room := range b.Rooms {
+		tables["rooms"][strconv.FormatInt(room.RoomID, 10)] = room
+	}
+	for _, exit := range b.Exits {
+		tables["exits"][exit.ExitID] = exit
+	}
+	for _, item := range b.Items {
+		tables["items"][item.ItemID] = item
+	}
+
+	return tables
+}
+
+// diffWorld compares the bundle against the world currently in the database.
+func diffWorld(bundle, current *Bundle) (map[string]*TableDiff, error) {
+	want := bundle.records()
+	have := current.records()
+
+	diffs := make(map[string]*TableDiff, len(importOrder))
+	for _, name := range importOrder {
+		diff := &TableDiff{}
+		for id, record := range want[name] {
+			existing, ok := have[name][id]
+			if !ok {
+				diff.Added = append(diff.Added, id)
+				continue
+			}
+			same, err := sameRecord(record, existing)
+			if err != nil {
+				return nil, fmt.Errorf("error comparing %s %s: %w", name, id, err)
+			}
+			if same {
+				diff.Unchanged++
+			} else {
+				diff.Changed = append(diff.Changed, id)
+			}
+		}
+		for id := range have[name] {
+			if _, ok := want[name][id]; !ok {
+				diff.Removed = append(diff.Removed, id)
+			}
+		}
+		sort.Strings(diff.Added)
+		sort.Strings(diff.Changed)
+		sort.Strings(diff.Removed)
+		diffs[name] = diff
+	}
+
+	return diffs, nil
+}
+
+// sameRecord compares two records by their JSON encoding.
+func sameRecord(a, b interface{}) (bool, error) {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(left, right), nil
+}
+
+// printDiff reports the differences table by table.
+func printDiff(diffs map[string]*TableDiff) {
+	for _, name := range importOrder {
+		diff := diffs[name]
+		fmt.Printf("%s: %d added, %d changed, %d unchanged, %d only in database\n",
+			name, len(diff.Added), len(diff.Changed), diff.Unchanged, len(diff.Removed))
+		for _, id := range diff.Added {
+			fmt.Printf("  + %s\n", id)
+		}
+		for _, id := range diff.Changed {
+			fmt.Printf("  ~ %s\n", id)
+		}
+		for _, id := range diff.Removed {
+			fmt.Printf("  - %s\n", id)
+		}
+	}
+}
+
+// importWorld writes the added and changed records of the bundle. Records only in the database
+// are kept, so importing never deletes anything.
+func importWorld(ctx context.Context, kp *core.KeyPair, bundle *Bundle, diffs map[string]*TableDiff) error {
+	records := bundle.records()
+	for _, name := range importOrder {
+		diff := diffs[name]
+		items := make([]interface{}, 0, len(diff.Added)+len(diff.Changed))
+		for _, id := range append(diff.Added, diff.Changed...) {
+			items = append(items, records[name][id])
+		}
+		if err := kp.BatchPut(ctx, name, items); err != nil {
+			return fmt.Errorf("error importing %s: %w", name, err)
+		}
+		fmt.Printf("Imported %d %s\n", len(items), name)
+	}
+	return nil
+}
+
+// isYAML reports whether the bundle file should be written as YAML rather than JSON.
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// writeBundle saves the bundle as JSON, or as YAML when the file name ends in .yml or .yaml.
+func writeBundle(path string, bundle *Bundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding bundle: %w", err)
+	}
+
+	// YAML goes through JSON so both formats use the same field names
+	if isYAML(path) {
+		var tree interface{}
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return fmt.Errorf("error encoding bundle: %w", err)
+		}
+		if data, err = yaml.Marshal(tree); err != nil {
+			return fmt.Errorf("error encoding bundle: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// readBundle loads a bundle written by writeBundle.
+func readBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if isYAML(path) {
+		var tree interface{}
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", path, err)
+		}
+		if data, err = json.Marshal(tree); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", path, err)
+		}
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	if bundle.Version < 1 || bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("%s has bundle version %d, this tool reads versions 1 to %d", path, bundle.Version, BundleVersion)
+	}
+
+	return &bundle, nil
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: world_tool [flags] export|import|diff|migrate\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "       world_tool [flags] rename <character> <new name>\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "       world_tool [flags] transfer <character> <player>\n\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  export  write the world in the database to the bundle file\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  import  write the records that differ from the bundle file into the database\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  diff    show how the bundle file differs from the database\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  migrate rewrite records saved in an old schema with the current one\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  rename  rename a character that is not in play\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  transfer move a character that is not in play to another player's account\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	configFile := flag.String("config", "config.yml", "Server configuration file, used for the region and storage backend")
+	bundleFile := flag.String("file", "world.json", "Bundle file, written as YAML when it ends in .yml or .yaml")
+	dryRun := flag.Bool("dry-run", false, "Show what import or migrate would change without writing anything")
+	verbose := flag.Bool("verbose", false, "Log database activity")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+	if argCounts[command] != flag.NArg()-1 {
+		usage()
+		os.Exit(2)
+	}
+
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	core.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	config, err := loadConfiguration(*configFile)
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	kp, err := core.OpenKeyPair(ctx, config)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer kp.Close()
+
+	if err := run(ctx, kp, command, flag.Args()[1:], *bundleFile, *dryRun); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		kp.Close()
+		os.Exit(1)
+	}
+}
+
+// argCounts is the number of arguments each command takes after its name.
+var argCounts = map[string]int{
+	"export":   0,
+	"import":   0,
+	"diff":     0,
+	"migrate":  0,
+	"rename":   2,
+	"transfer": 2,
+}
+
+// run carries out one command against the database.
+func run(ctx context.Context, kp *core.KeyPair, command string, args []string, bundleFile string, dryRun bool) error {
+	switch command {
+	case "export":
+		bundle, err := exportWorld(ctx, kp)
+		if err != nil {
+			return err
+		}
+		if err := writeBundle(bundleFile, bundle); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d rooms, %d exits, %d items, %d prototypes and %d archetypes to %s\n",
+			len(bundle.Rooms), len(bundle.Exits), len(bundle.Items), len(bundle.Prototypes), len(bundle.Archetypes), bundleFile)
+		return nil
+
+	case "import", "diff":
+		bundle, err := readBundle(bundleFile)
+		if err != nil {
+			return err
+		}
+		current, err := exportWorld(ctx, kp)
+		if err != nil {
+			return err
+		}
+		diffs, err := diffWorld(bundle, current)
+		if err != nil {
+			return err
+		}
+		printDiff(diffs)
+
+		if command == "diff" || dryRun {
+			return nil
+		}
+		return importWorld(ctx, kp, bundle, diffs)
+
+	case "migrate":
+		counts, err := kp.MigrateAll(ctx, dryRun)
+		tables := make([]string, 0, len(counts))
+		for name := range counts {
+			tables = append(tables, name)
+		}
+		sort.Strings(tables)
+		for _, name := range tables {
+			verb := "migrated"
+			if dryRun {
+				verb = "to migrate"
+			}
+			fmt.Printf("%s: %d records %s to schema version %d\n", name, counts[name], verb, core.SchemaVersion(name))
+		}
+		if err != nil || dryRun {
+			return err
+		}
+
+		applied, err := kp.AppliedMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Applied migrations:")
+		for _, migration := range applied {
+			fmt.Printf("  %s  %s  %s\n", migration.MigrationID, migration.AppliedAt, migration.Description)
+		}
+		return nil
+
+	case "rename":
+		// Reserved word and obscenity checks need the server's word lists, so only the
+		// capitalization is normalized here
+		cd, err := kp.RenameCharacter(ctx, args[0], core.NormalizeName(args[1]))
+		if cd != nil {
+			fmt.Printf("Renamed %s to %s\n", args[0], cd.CharacterName)
+		}
+		return err
+
+	case "transfer":
+		cd, err := kp.TransferCharacter(ctx, args[0], args[1])
+		if cd != nil {
+			fmt.Printf("Moved %s to player %s\n", cd.CharacterName, cd.PlayerID)
+		}
+		return err
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}